@@ -13,6 +13,9 @@ mkdir -p "$BUILD_DIR"
 
 echo "Copying vsock-server-standalone for embedding..."
 cp "$BUILD_DIR/vsock-server-standalone" pkg/firecracker/embedded/vsock-server-standalone
+for arch in x86_64 aarch64; do
+  cp "$BUILD_DIR/vsock-server-standalone-$arch" "pkg/firecracker/embedded/vsock-server-standalone-$arch"
+done
 
 echo "Building anvil CLI with embedded vsock-server..."
 go build -mod=mod \
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+package buildkernel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/kernel"
+)
+
+// batchFlags holds the --batch related flags
+type batchFlags struct {
+	file     string
+	report   string
+	parallel int
+	failFast bool
+}
+
+// runBatch builds every version listed in the batch file with the settings
+// in base and prints a summary. It fails if any entry failed.
+func runBatch(flags batchFlags, arch string, base kernel.BuildOptions) error {
+	if flags.parallel < 1 {
+		return fmt.Errorf("invalid --parallel %d: must be 1 or more", flags.parallel)
+	}
+
+	entries, err := kernel.ParseBatchFile(flags.file, arch)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	base.Context = ctx
+
+	theme := config.CurrentTheme
+	subtleStyle := theme.SubtleStyle()
+
+	fmt.Println(theme.InfoMessage(fmt.Sprintf("Batch build of %d kernel(s) from %s", len(entries), flags.file)))
+
+	var printMu sync.Mutex
+	report := kernel.BuildBatch(entries, base, config.GlobalPaths, kernel.BatchOptions{
+		Parallel: flags.parallel,
+		FailFast: flags.failFast,
+		EntryCallback: func(entry kernel.BatchEntry, result *kernel.BatchResult) {
+			printMu.Lock()
+			defer printMu.Unlock()
+
+			fmt.Println()
+			label := fmt.Sprintf("%s (%s)", entry.Version, entry.Arch)
+			switch {
+			case result == nil:
+				fmt.Println(theme.InfoMessage("Building " + label))
+			case result.Status == kernel.BatchSucceeded:
+				fmt.Println(theme.SuccessMessage(fmt.Sprintf("Built %s in %s", label, result.Duration.Round(time.Second))))
+			default:
+				fmt.Println(theme.ErrorMessage(fmt.Sprintf("Failed %s: %s", label, result.Error)))
+			}
+		},
+	})
+
+	fmt.Println()
+	fmt.Println(theme.InfoMessage(fmt.Sprintf("Batch finished in %s: %d succeeded, %d failed, %d skipped",
+		report.Duration.Round(time.Second), report.Succeeded, report.Failed, report.Skipped)))
+	for _, result := range report.Entries {
+		line := fmt.Sprintf("  • %s (%s) %s", result.Version, result.Arch, result.Status)
+		if result.CompressedPath != "" {
+			line += " " + result.CompressedPath
+		}
+		fmt.Println(subtleStyle.Render(line))
+	}
+
+	if flags.report != "" {
+		if err := writeBatchReport(flags.report, report); err != nil {
+			return err
+		}
+		fmt.Println()
+		fmt.Println(subtleStyle.Render("Report written to " + flags.report))
+	}
+
+	if report.Failed > 0 || report.Skipped > 0 {
+		return fmt.Errorf("%d of %d batch build(s) did not succeed", report.Failed+report.Skipped, len(report.Entries))
+	}
+	return nil
+}
+
+// writeBatchReport writes the batch report as indented JSON
+func writeBatchReport(path string, report *kernel.BatchReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write batch report: %w", err)
+	}
+	return nil
+}
@@ -2,14 +2,19 @@
 package buildkernel
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/Work-Fort/Anvil/cmd/cmdutil"
 	"github.com/Work-Fort/Anvil/pkg/config"
 	"github.com/Work-Fort/Anvil/pkg/kernel"
 	"github.com/Work-Fort/Anvil/pkg/ui"
+	"github.com/Work-Fort/Anvil/pkg/util"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +26,30 @@ func NewBuildKernelCmd() *cobra.Command {
 		buildVerificationLevel string
 		buildConfig            string
 		buildForceRebuild      bool
+		buildWatch             bool
+		buildWatchDebounce     time.Duration
+		buildDownloadTimeout   time.Duration
+		buildCompileTimeout    time.Duration
+		buildTimeout           time.Duration
+		buildAutoFix           bool
+		buildKeepTarball       bool
+		buildChecksums         []string
+		buildJobs              int
+		buildAllowRoot         bool
+		buildCcache            bool
+		buildSourceTarball     string
+		buildChecksumsFile     string
+		buildMirror            string
+		buildPatches           []string
+		buildCompression       string
+		buildToolchain         string
+		buildModules           bool
+		buildParallelArch      bool
+		buildJSON              bool
+		buildResume            string
+		buildGitURL            string
+		buildGitRef            string
+		batch                  batchFlags
 	)
 
 	cmd := &cobra.Command{
@@ -29,24 +58,248 @@ func NewBuildKernelCmd() *cobra.Command {
 		Long: `Build Firecracker-compatible kernel from source.
 
 Downloads kernel source from kernel.org, verifies integrity, and builds
-with Firecracker-optimized configuration.
+with Firecracker-optimized configuration. --mirror (or kernels.mirror in
+config) downloads the source and checksums from another kernel.org mirror.
 
-If no version is specified, builds the latest stable kernel.`,
+If no version is specified, builds the latest stable kernel.
+In an interactive terminal the build runs in a wizard: without a version
+you pick one from kernel.org's releases, while --version skips the picker
+and goes straight to the download, keeping the wizard's progress view
+(a cached build of the version is shown instead unless --force-rebuild).
+
+With --watch, the command stays running after the build and rebuilds
+(configure, compile, package) whenever the kernel config file or the
+source tree's .config changes. Press Ctrl-C to stop watching.
+
+--download-timeout and --compile-timeout limit individual build phases;
+a phase that runs over fails the build with an error naming the phase.
+
+If the compile fails on symbols that look missing from the kernel config
+(common when moving to a newer kernel), --auto-fix merges the config onto
+the architecture's defconfig, logs every option that changed and retries
+once. In an interactive terminal you are asked instead.
+
+--keep-tarball keeps the verified source tarball in the build cache so a
+later build of the same version reuses it (after re-checking its hash)
+instead of downloading it again.
+
+--checksum selects the checksums written next to the artifacts (sha256,
+sha512 or both); sha256 is always written. Archiving generates SHA256SUMS
+and, with sha512, SHA512SUMS; signing covers whichever sums files are
+present.
+
+--jobs sets the number of parallel make jobs for the compile. Without it,
+a -j in MAKEFLAGS is honoured, otherwise one job per CPU is used. Values
+above the core count are passed to make unchanged.
+
+When ccache is on PATH the compile goes through it (CC="ccache gcc"),
+which speeds up rebuilds of similar kernels. --ccache=false turns this
+off; --ccache fails the build if ccache isn't installed.
+
+--source-tarball builds offline from a linux-<version>.tar.xz already on
+disk instead of downloading it; the version is taken from the file name.
+Unless verification is disabled, it is checked against a local
+sha256sums.asc (next to the tarball, or given with --checksums-file).
+
+--git-url and --git-ref build from a shallow clone of a git tag or branch
+instead of a release tarball, for -rc kernels and forks. The version comes
+from a tag like v6.19-rc3, --version, or else the cloned Makefile. The
+clone can't be verified against kernel.org's checksums, so the build is
+recorded as unverified. Requires git.
+
+--patch applies a patch file with patch -p1 after the source is extracted
+and before the kernel is configured. Repeat it to apply several patches in
+order; a patch that doesn't apply fails the build.
+
+Builds refuse to run as root, since root-owned files in the build cache
+can't be cleaned by your normal user. Pass --allow-root to build as root
+anyway.
+
+--compression zstd packages the kernel as .zst instead of .xz, which
+decompresses much faster. xz stays the default so existing archives and
+downloads keep working.
+
+--toolchain llvm builds with clang and ld.lld (make LLVM=1) instead of
+gcc, for configs that need it. aarch64 builds then need a clang with the
+AArch64 target rather than aarch64-linux-gnu-gcc.
+
+--modules also builds the kernel's modules (the config must enable
+CONFIG_MODULES) and packages lib/modules/<release> as
+modules-<version>-<arch>.tar.xz with its own checksum in the artifacts.
+
+--json skips the wizard and writes newline-delimited JSON events to stdout
+for CI: "phase" (with arch), "progress" (download percent), "complete"
+(with the build stats) and "error". Build output goes to stderr.
+
+--resume <phase> continues a build of the given version that stopped
+partway, reusing what it left in the build directory and skipping the
+phases before <phase> (verify, extract, configure, compile or package).
+"--resume package" only repackages the kernel that was already compiled.
+Skipped phases aren't redone, so a resumed build's sources are not
+re-verified unless it resumes from verify. It fails if something the
+resumed phase needs is missing, naming the phase to resume from instead.
+
+--batch builds every version listed in a file, one "version[,arch]" per
+line (# starts a comment), with the other flags applied to each build.
+Failed builds don't stop the batch unless --fail-fast is set; --parallel
+runs several builds at once and --report writes a JSON summary with each
+entry's status, duration, stats and artifact paths.
+
+In an interactive terminal, if anvil.yaml has no kernel config for the
+target architecture, you are prompted to pick one from the repo and can
+save the choice to anvil.yaml.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			version := buildVersion
 			if version == "" && len(args) > 0 {
 				version = args[0]
 			}
 
-			// If interactive and no version specified, run wizard
-			// Wizard handles EVERYTHING: version selection + build + progress
-			if version == "" && cmdutil.IsInteractive() {
+			// Without --ccache, use ccache only if it is installed
+			var useCcache *bool
+			if cmd.Flags().Changed("ccache") {
+				useCcache = &buildCcache
+			}
+
+			// In a repo without a kernel config for the target arch, let the
+			// user pick one instead of failing the build
+			if buildConfig == "" && cmdutil.IsInteractive() && !buildJSON {
+				if err := resolveMissingKernelConfigs(buildArch); err != nil {
+					return err
+				}
+			}
+
+			var gitSource *kernel.GitSource
+			if buildGitURL != "" || buildGitRef != "" {
+				if buildGitURL == "" || buildGitRef == "" {
+					return fmt.Errorf("--git-url and --git-ref must be given together")
+				}
+				gitSource = &kernel.GitSource{URL: buildGitURL, Ref: buildGitRef}
+			}
+
+			if buildJSON && (buildWatch || batch.file != "") {
+				return fmt.Errorf("--json can't be combined with --watch or --batch")
+			}
+
+			var resumeFrom kernel.BuildPhase
+			if buildResume != "" {
+				phase, ok := kernel.ParseBuildPhase(buildResume)
+				if !ok {
+					return fmt.Errorf("invalid phase to resume from: %s (must be: verify, extract, configure, compile, package)", buildResume)
+				}
+				if buildWatch || batch.file != "" {
+					return fmt.Errorf("--resume can't be combined with --watch or --batch")
+				}
+				if version == "" && buildSourceTarball == "" && gitSource == nil {
+					return fmt.Errorf("--resume needs the version of the build to resume")
+				}
+				resumeFrom = phase
+			}
+
+			if batch.file == "" && (batch.report != "" || batch.failFast || cmd.Flags().Changed("parallel")) {
+				return fmt.Errorf("--report, --fail-fast and --parallel require --batch")
+			}
+			if batch.file != "" {
+				if version != "" {
+					return fmt.Errorf("--batch reads versions from the batch file; don't pass a version")
+				}
+				if buildWatch || buildSourceTarball != "" || gitSource != nil {
+					return fmt.Errorf("--batch can't be combined with --watch, --source-tarball or --git-url")
+				}
+				return runBatch(batch, buildArch, kernel.BuildOptions{
+					VerificationLevel:  buildVerificationLevel,
+					ConfigFile:         buildConfig,
+					DownloadTimeout:    buildDownloadTimeout,
+					CompileTimeout:     buildCompileTimeout,
+					Timeout:            buildTimeout,
+					AutoFix:            buildAutoFix,
+					KeepTarball:        buildKeepTarball,
+					ChecksumAlgorithms: buildChecksums,
+					Jobs:               buildJobs,
+					AllowRoot:          buildAllowRoot,
+					UseCcache:          useCcache,
+					Mirror:             buildMirror,
+					Patches:            buildPatches,
+					Compression:        buildCompression,
+					Toolchain:          buildToolchain,
+					BuildModules:       buildModules,
+				})
+			}
+
+			// Watch mode: build once, then rebuild on config changes
+			if buildWatch {
+				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+
+				opts := kernel.BuildOptions{
+					Version:            version,
+					Arch:               buildArch,
+					VerificationLevel:  buildVerificationLevel,
+					ConfigFile:         buildConfig,
+					Context:            ctx,
+					DownloadTimeout:    buildDownloadTimeout,
+					CompileTimeout:     buildCompileTimeout,
+					Timeout:            buildTimeout,
+					AutoFix:            buildAutoFix,
+					KeepTarball:        buildKeepTarball,
+					ChecksumAlgorithms: buildChecksums,
+					Jobs:               buildJobs,
+					AllowRoot:          buildAllowRoot,
+					UseCcache:          useCcache,
+					SourceTarball:      buildSourceTarball,
+					ChecksumsFile:      buildChecksumsFile,
+					GitSource:          gitSource,
+					Mirror:             buildMirror,
+					Patches:            buildPatches,
+					Compression:        buildCompression,
+					Toolchain:          buildToolchain,
+					BuildModules:       buildModules,
+				}
+				if cmdutil.IsInteractive() {
+					opts.ConfirmConfigRepair = confirmConfigRepair
+				}
+				return cmdutil.AllowRootHint(kernel.Watch(opts, config.GlobalPaths, buildWatchDebounce))
+			}
+
+			// If interactive, run wizard
+			// Wizard handles EVERYTHING: version selection + build + progress.
+			// A given version skips the selection but keeps the progress view.
+			if buildSourceTarball == "" && gitSource == nil && buildResume == "" && cmdutil.IsInteractive() && !buildJSON {
+				if version != "" {
+					resolved, err := kernel.ResolveSourceVersion(cmd.Context(), version)
+					if err != nil {
+						return err
+					}
+					if resolved == version {
+						if err := kernel.ValidateVersion(cmd.Context(), version); err != nil {
+							return err
+						}
+					}
+					version = resolved
+				}
 				callbacks := ui.BuildKernelCallbacks{
 					BuildFn: func(opts kernel.BuildOptions) error {
-						return kernel.Build(opts, config.GlobalPaths)
+						opts.DownloadTimeout = buildDownloadTimeout
+						opts.CompileTimeout = buildCompileTimeout
+						opts.Timeout = buildTimeout
+						opts.AutoFix = buildAutoFix
+						opts.KeepTarball = buildKeepTarball
+						opts.ChecksumAlgorithms = buildChecksums
+						opts.Jobs = buildJobs
+						opts.AllowRoot = buildAllowRoot
+						opts.UseCcache = useCcache
+						opts.Mirror = buildMirror
+						opts.Patches = buildPatches
+						opts.Compression = buildCompression
+						opts.Toolchain = buildToolchain
+						opts.BuildModules = buildModules
+						opts.ParallelArch = buildParallelArch
+						return cmdutil.AllowRootHint(kernel.Build(opts, config.GlobalPaths))
 					},
 					CheckCachedFn: func(v string) (bool, string, error) {
-						return kernel.CheckCachedBuild(v, buildArch, config.GlobalPaths)
+						return checkCachedBuild(v, buildArch, buildPatches, buildCompression)
+					},
+					ListCachedFn: func() ([]kernel.BuildStats, error) {
+						return listCachedBuilds(buildArch)
 					},
 					ReadStatsFn: func(path string) (kernel.BuildStats, error) {
 						return kernel.ReadBuildStats(path)
@@ -54,28 +307,17 @@ If no version is specified, builds the latest stable kernel.`,
 					CheckInstalledFn: func(stats kernel.BuildStats) (bool, string, error) {
 						return kernel.CheckKernelInstalled(stats, config.GlobalPaths)
 					},
-					InstallFn: func(stats kernel.BuildStats, setAsDefault bool) (string, error) {
-						return kernel.InstallBuiltKernel(stats, setAsDefault, config.GlobalPaths)
+					InstallFn: func(stats kernel.BuildStats, setAsDefault bool, progressCallback func(float64)) (string, error) {
+						return kernel.InstallBuiltKernelWithProgress(stats, setAsDefault, config.GlobalPaths, progressCallback)
 					},
-					ArchiveFn: func(stats kernel.BuildStats, archiveDir string) error {
-						return kernel.ArchiveInstalledKernel(stats, archiveDir)
-					},
-					ClearBuildCacheFn: func() error {
-						buildDir := filepath.Join(config.GlobalPaths.KernelBuildDir, "build")
-						artifactsDir := filepath.Join(config.GlobalPaths.KernelBuildDir, "artifacts")
-						if err := os.RemoveAll(buildDir); err != nil {
-							return fmt.Errorf("failed to clear build directory: %w", err)
-						}
-						if err := os.RemoveAll(artifactsDir); err != nil {
-							return fmt.Errorf("failed to clear artifacts directory: %w", err)
-						}
-						return nil
+					ArchiveFn: func(stats kernel.BuildStats, archiveDir string, progressCallback func(float64)) error {
+						return kernel.ArchiveInstalledKernelWithProgress(stats, archiveDir, progressCallback)
 					},
 					GetArchiveLocationFn: func() string {
 						return config.GetKernelsArchiveLocation()
 					},
 				}
-				err := ui.RunBuildKernelWizard(config.CurrentTheme, callbacks, buildArch, buildVerificationLevel, buildConfig, buildForceRebuild)
+				err := ui.RunBuildKernelWizard(config.CurrentTheme, callbacks, version, buildArch, buildVerificationLevel, buildConfig, buildForceRebuild)
 				if err != nil {
 					// Check if user cancelled - exit gracefully without error
 					if err == ui.ErrUserCancelled {
@@ -95,33 +337,74 @@ If no version is specified, builds the latest stable kernel.`,
 			// Non-interactive path: validate and build directly
 			// If still no version, use latest (handled in kernel.Build())
 
-			// Check for cached build in non-interactive mode
-			if !buildForceRebuild {
-				hasCached, _, err := kernel.CheckCachedBuild(version, buildArch, config.GlobalPaths)
+			// With --json, stdout carries only events; failures are
+			// reported as an error event too
+			fail := func(err error) error { return err }
+			var events *jsonEmitter
+			if buildJSON {
+				events = newJSONEmitter(os.Stdout)
+				fail = events.fail
+			}
+
+			// Check for cached build in non-interactive mode. A git build's
+			// version may only be known once it is cloned.
+			if !buildForceRebuild && gitSource == nil {
+				hasCached, _, err := checkCachedBuild(version, buildArch, buildPatches, buildCompression)
 				if err != nil {
-					return fmt.Errorf("failed to check for cached build: %w", err)
+					return fail(fmt.Errorf("failed to check for cached build: %w", err))
 				}
 				if hasCached {
-					return fmt.Errorf("cached build exists. Use --force-rebuild to rebuild, or use the interactive wizard to install the cached build")
+					return fail(fmt.Errorf("cached build exists. Use --force-rebuild to rebuild, or use the interactive wizard to install the cached build"))
 				}
 			}
 
 			// Validate version against kernel.org releases if specified
-			if version != "" && version != "latest" {
-				if err := kernel.ValidateVersion(version); err != nil {
-					return err
+			// (offline builds skip this; the tarball is checked instead, and
+			// git refs need not be releases)
+			if version != "" && !kernel.IsVersionAlias(version) && buildSourceTarball == "" && gitSource == nil {
+				if err := kernel.ValidateVersion(cmd.Context(), version); err != nil {
+					return fail(err)
 				}
 			}
 
 			opts := kernel.BuildOptions{
-				Version:           version,
-				Arch:              buildArch,
-				VerificationLevel: buildVerificationLevel,
-				ConfigFile:        buildConfig,
+				Version:            version,
+				Arch:               buildArch,
+				VerificationLevel:  buildVerificationLevel,
+				ConfigFile:         buildConfig,
+				DownloadTimeout:    buildDownloadTimeout,
+				CompileTimeout:     buildCompileTimeout,
+				Timeout:            buildTimeout,
+				AutoFix:            buildAutoFix,
+				KeepTarball:        buildKeepTarball,
+				ChecksumAlgorithms: buildChecksums,
+				Jobs:               buildJobs,
+				AllowRoot:          buildAllowRoot,
+				UseCcache:          useCcache,
+				SourceTarball:      buildSourceTarball,
+				ChecksumsFile:      buildChecksumsFile,
+				GitSource:          gitSource,
+				Mirror:             buildMirror,
+				Patches:            buildPatches,
+				Compression:        buildCompression,
+				Toolchain:          buildToolchain,
+				BuildModules:       buildModules,
+				ParallelArch:       buildParallelArch,
+				ResumeFrom:         resumeFrom,
+			}
+			if events != nil {
+				// Build output goes to stderr so stdout stays parseable
+				opts.Writer = os.Stderr
+				events.attach(&opts)
+			} else if cmdutil.IsInteractive() {
+				opts.ConfirmConfigRepair = confirmConfigRepair
 			}
 
 			if err := kernel.Build(opts, config.GlobalPaths); err != nil {
-				return err
+				return fail(cmdutil.AllowRootHint(err))
+			}
+			if events != nil {
+				return nil
 			}
 
 			theme := config.CurrentTheme
@@ -135,11 +418,95 @@ If no version is specified, builds the latest stable kernel.`,
 		},
 	}
 
-	cmd.Flags().StringVarP(&buildVersion, "version", "v", "", "Kernel version to build (default: latest, shows wizard if interactive)")
+	cmd.Flags().StringVarP(&buildVersion, "version", "v", "", "Kernel version to build (default: latest, or pick in the wizard if interactive)")
 	cmd.Flags().StringVarP(&buildArch, "arch", "a", "", "Target architecture: x86_64, aarch64, or all (default: host)")
 	cmd.Flags().StringVarP(&buildVerificationLevel, "verification-level", "q", "", "Verification level: high, medium, disabled (default: high)")
 	cmd.Flags().StringVarP(&buildConfig, "config", "c", "", "Custom kernel config file")
 	cmd.Flags().BoolVarP(&buildForceRebuild, "force-rebuild", "f", false, "Force rebuild even if cached build exists")
+	cmd.Flags().BoolVarP(&buildWatch, "watch", "w", false, "Rebuild when the kernel config changes (Ctrl-C to stop)")
+	cmd.Flags().DurationVar(&buildDownloadTimeout, "download-timeout", 0, "Fail if the source download takes longer than this (0 = no limit)")
+	cmd.Flags().DurationVar(&buildCompileTimeout, "compile-timeout", 0, "Fail if the compile phase takes longer than this (0 = no limit)")
+	cmd.Flags().DurationVar(&buildTimeout, "timeout", 0, "Fail if the whole build takes longer than this (0 = no limit)")
+	cmd.Flags().BoolVar(&buildAutoFix, "auto-fix", false, "Repair the kernel config from defconfig and retry once if the compile fails on missing symbols")
+	cmd.Flags().BoolVar(&buildKeepTarball, "keep-tarball", false, "Keep the verified source tarball and reuse it for later builds of the same version")
+	cmd.Flags().StringSliceVar(&buildChecksums, "checksum", []string{"sha256"}, "Checksum algorithms for artifacts: sha256, sha512 (comma-separated)")
+	cmd.Flags().IntVarP(&buildJobs, "jobs", "j", 0, "Parallel make jobs for the compile (default: MAKEFLAGS -j, else one per CPU)")
+	cmd.Flags().BoolVar(&buildAllowRoot, "allow-root", false, "Allow building as root (files in the build cache will be root-owned)")
+	cmd.Flags().BoolVar(&buildCcache, "ccache", false, "Compile through ccache (default: when ccache is on PATH)")
+	cmd.Flags().StringVar(&buildSourceTarball, "source-tarball", "", "Build from a local linux-<version>.tar.xz instead of downloading")
+	cmd.Flags().StringVar(&buildChecksumsFile, "checksums-file", "", "Local sha256sums.asc to verify --source-tarball against (default: next to the tarball)")
+	cmd.Flags().StringVar(&buildGitURL, "git-url", "", "Build from this git repository instead of a release tarball (needs --git-ref)")
+	cmd.Flags().StringVar(&buildGitRef, "git-ref", "", "Tag or branch of --git-url to build (shallow clone)")
+	cmd.Flags().StringVar(&buildMirror, "mirror", "", "Kernel source mirror base URL (default: kernels.mirror)")
+	cmd.Flags().StringArrayVar(&buildPatches, "patch", nil, "Apply this patch (-p1) to the source before configuring (repeatable)")
+	cmd.Flags().StringVar(&buildCompression, "compression", "xz", "Compression of the packaged kernel: xz or zstd")
+	cmd.Flags().StringVar(&buildToolchain, "toolchain", "gcc", "Compiler toolchain: gcc or llvm (clang and ld.lld)")
+	cmd.Flags().BoolVar(&buildModules, "modules", false, "Also build kernel modules and package them as modules-<version>-<arch>.tar.xz")
+	cmd.Flags().BoolVar(&buildParallelArch, "parallel-arch", false, "With --arch all, build x86_64 and aarch64 at the same time")
+	cmd.Flags().BoolVar(&buildJSON, "json", false, "Emit newline-delimited JSON events on stdout instead of the wizard (build output goes to stderr)")
+	cmd.Flags().StringVar(&buildResume, "resume", "", "Resume an interrupted build from this phase: verify, extract, configure, compile or package")
+	cmd.Flags().StringVar(&batch.file, "batch", "", "Build every version[,arch] listed in this file")
+	cmd.Flags().StringVar(&batch.report, "report", "", "Write a JSON report of the batch build to this file")
+	cmd.Flags().IntVar(&batch.parallel, "parallel", 1, "Number of batch builds to run at once")
+	cmd.Flags().BoolVar(&batch.failFast, "fail-fast", false, "Stop the batch after the first failed build")
+	cmd.Flags().DurationVar(&buildWatchDebounce, "watch-debounce", kernel.DefaultWatchDebounce, "Quiet period after a config change before rebuilding")
+
+	cmd.AddCommand(newStatsCmd())
 
 	return cmd
 }
+
+// confirmConfigRepair asks whether to repair the kernel config after a
+// compile failure on missing symbols
+func confirmConfigRepair(symbols []string) bool {
+	prompt := fmt.Sprintf("Build failed on missing symbols (%s). Merge config onto defconfig and retry?", strings.Join(symbols, ", "))
+	confirmed, err := ui.Confirm(prompt)
+	if err != nil {
+		return false
+	}
+	return confirmed
+}
+
+// checkCachedBuild is kernel.CheckCachedBuild, except that a build made
+// with other patches than the ones given, or compressed in another format,
+// doesn't count as cached
+func checkCachedBuild(version, arch string, patches []string, compression string) (bool, string, error) {
+	hasCached, statsFile, err := kernel.CheckCachedBuild(version, arch, config.GlobalPaths)
+	if err != nil || !hasCached || statsFile == "" {
+		return hasCached, statsFile, err
+	}
+	// Unreadable stats are taken as an unpatched build, as Build does
+	stats, err := kernel.ReadBuildStats(statsFile)
+	if err == nil && !kernel.BuildStatsMatchPatches(stats, patches) || err != nil && len(patches) > 0 {
+		return false, "", nil
+	}
+	// A build compressed in the other format is rebuilt
+	if err == nil && util.CompressionFromPath(stats.CompressedPath) != compression {
+		return false, "", nil
+	}
+	return true, statsFile, nil
+}
+
+// listCachedBuilds returns the cached builds for arch (default: host),
+// newest first. Arch "all" has no single build to offer.
+func listCachedBuilds(arch string) ([]kernel.BuildStats, error) {
+	if arch == "" {
+		var err error
+		arch, err = config.GetArch()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	builds, err := kernel.ListCachedBuilds(config.GlobalPaths)
+	if err != nil {
+		return nil, err
+	}
+	var matching []kernel.BuildStats
+	for _, stats := range builds {
+		if stats.Arch == arch {
+			matching = append(matching, stats)
+		}
+	}
+	return matching, nil
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+package buildkernel
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/kernel"
+)
+
+// Event types written by --json
+const (
+	eventPhase    = "phase"
+	eventProgress = "progress"
+	eventComplete = "complete"
+	eventError    = "error"
+)
+
+// buildEvent is one line of --json output
+type buildEvent struct {
+	Type    string             `json:"type"`
+	Time    time.Time          `json:"time"`
+	Arch    string             `json:"arch,omitempty"`
+	Phase   string             `json:"phase,omitempty"`
+	Percent *int               `json:"percent,omitempty"` // Download or extract progress, 0-100
+	Stats   *kernel.BuildStats `json:"stats,omitempty"`
+	Error   string             `json:"error,omitempty"`
+}
+
+// jsonEmitter writes build events as newline-delimited JSON. It is safe
+// for concurrent use, since --parallel-arch reports from two builds.
+type jsonEmitter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	builds  map[string]*archProgress // by arch
+}
+
+// archProgress is what the emitter tracks for one arch's build
+type archProgress struct {
+	phase       kernel.BuildPhase
+	lastPercent int
+}
+
+func newJSONEmitter(w io.Writer) *jsonEmitter {
+	return &jsonEmitter{encoder: json.NewEncoder(w), builds: map[string]*archProgress{}}
+}
+
+func (e *jsonEmitter) emit(event buildEvent) {
+	event.Time = time.Now().UTC()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_ = e.encoder.Encode(event)
+}
+
+// build returns the progress tracked for arch. Callers hold e.mu.
+func (e *jsonEmitter) build(arch string) *archProgress {
+	b, ok := e.builds[arch]
+	if !ok {
+		b = &archProgress{phase: kernel.PhaseDownload, lastPercent: -1}
+		e.builds[arch] = b
+	}
+	return b
+}
+
+// attach wires the build callbacks in opts to the emitter
+func (e *jsonEmitter) attach(opts *kernel.BuildOptions) {
+	opts.ArchPhaseCallback = func(arch string, phase kernel.BuildPhase) {
+		e.mu.Lock()
+		b := e.build(arch)
+		b.phase = phase
+		b.lastPercent = -1
+		e.mu.Unlock()
+		e.emit(buildEvent{Type: eventPhase, Arch: arch, Phase: phase.String()})
+	}
+	opts.ArchProgressCallback = func(arch string, fraction float64) {
+		// Only whole-percent steps, so a download doesn't flood the log
+		percent := int(fraction * 100)
+		e.mu.Lock()
+		b := e.build(arch)
+		if percent == b.lastPercent {
+			e.mu.Unlock()
+			return
+		}
+		b.lastPercent = percent
+		phase := b.phase
+		e.mu.Unlock()
+		e.emit(buildEvent{Type: eventProgress, Arch: arch, Phase: phase.String(), Percent: &percent})
+	}
+	opts.StatsCallback = func(stats kernel.BuildStats) {
+		e.emit(buildEvent{Type: eventComplete, Arch: stats.Arch, Stats: &stats})
+	}
+}
+
+// fail writes an error event and returns err
+func (e *jsonEmitter) fail(err error) error {
+	e.emit(buildEvent{Type: eventError, Error: err.Error()})
+	return err
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+package buildkernel
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/kernel"
+)
+
+func decodeEvents(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var events []map[string]any
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var event map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestJSONEmitter(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := newJSONEmitter(&buf)
+	var opts kernel.BuildOptions
+	emitter.attach(&opts)
+
+	opts.ArchPhaseCallback("x86_64", kernel.PhaseDownload)
+	opts.ArchProgressCallback("x86_64", 0.101)
+	opts.ArchProgressCallback("x86_64", 0.105) // Same whole percent, dropped
+	opts.ArchProgressCallback("x86_64", 0.5)
+	opts.StatsCallback(kernel.BuildStats{KernelVersion: "6.18.9", Arch: "x86_64"})
+	err := emitter.fail(errors.New("boom"))
+	if err == nil || err.Error() != "boom" {
+		t.Fatalf("fail() = %v, want boom", err)
+	}
+
+	events := decodeEvents(t, &buf)
+	wantTypes := []string{eventPhase, eventProgress, eventProgress, eventComplete, eventError}
+	if len(events) != len(wantTypes) {
+		t.Fatalf("got %d events, want %d: %v", len(events), len(wantTypes), events)
+	}
+	for i, want := range wantTypes {
+		if events[i]["type"] != want {
+			t.Errorf("event %d type = %v, want %s", i, events[i]["type"], want)
+		}
+		if _, ok := events[i]["time"]; !ok {
+			t.Errorf("event %d has no time", i)
+		}
+	}
+
+	if events[0]["arch"] != "x86_64" || events[0]["phase"] != "download" {
+		t.Errorf("phase event = %v", events[0])
+	}
+	if events[1]["percent"] != float64(10) || events[2]["percent"] != float64(50) {
+		t.Errorf("progress percents = %v, %v", events[1]["percent"], events[2]["percent"])
+	}
+	if events[1]["arch"] != "x86_64" || events[1]["phase"] != "download" {
+		t.Errorf("progress event = %v", events[1])
+	}
+	stats, ok := events[3]["stats"].(map[string]any)
+	if !ok || stats["KernelVersion"] != "6.18.9" {
+		t.Errorf("complete event stats = %v", events[3]["stats"])
+	}
+	if events[4]["error"] != "boom" {
+		t.Errorf("error event = %v", events[4])
+	}
+}
+
+func TestJSONEmitter_ZeroPercent(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := newJSONEmitter(&buf)
+	var opts kernel.BuildOptions
+	emitter.attach(&opts)
+
+	opts.ArchProgressCallback("x86_64", 0)
+
+	events := decodeEvents(t, &buf)
+	if len(events) != 1 || events[0]["percent"] != float64(0) {
+		t.Fatalf("events = %v, want one event with percent 0", events)
+	}
+}
+
+func TestJSONEmitter_PhaseAndArch(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := newJSONEmitter(&buf)
+	var opts kernel.BuildOptions
+	emitter.attach(&opts)
+
+	// Two archs building at once (--parallel-arch), one extracting while
+	// the other downloads
+	opts.ArchPhaseCallback("x86_64", kernel.PhaseDownload)
+	opts.ArchPhaseCallback("aarch64", kernel.PhaseDownload)
+	opts.ArchProgressCallback("x86_64", 1)
+	opts.ArchPhaseCallback("x86_64", kernel.PhaseExtract)
+	opts.ArchProgressCallback("aarch64", 0.4)
+	opts.ArchProgressCallback("x86_64", 0.4)  // Same percent as aarch64, still reported
+	opts.ArchProgressCallback("aarch64", 0.4) // Repeat for aarch64, dropped
+
+	var got []string
+	for _, event := range decodeEvents(t, &buf) {
+		if event["type"] == eventProgress {
+			got = append(got, fmt.Sprintf("%v %v %v", event["arch"], event["phase"], event["percent"]))
+		}
+	}
+	want := []string{"x86_64 download 100", "aarch64 download 40", "x86_64 extract 40"}
+	if !slices.Equal(got, want) {
+		t.Errorf("progress events = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+package buildkernel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/ui"
+)
+
+// pickFile and confirm prompt the user (variables so tests can script the answers)
+var (
+	pickFile = ui.PickFile
+	confirm  = ui.Confirm
+)
+
+// resolveMissingKernelConfigs prompts for a kernel config file for each target
+// architecture that has none in anvil.yaml, and offers to save the choice to
+// the repo config. Only used in interactive repo mode; otherwise the build
+// reports the missing key itself.
+func resolveMissingKernelConfigs(arch string) error {
+	if !config.IsRepoMode() {
+		return nil
+	}
+
+	var archs []string
+	switch arch {
+	case "":
+		hostArch, err := config.GetArch()
+		if err != nil {
+			return err
+		}
+		archs = []string{hostArch}
+	case "all":
+		archs = []string{"x86_64", "aarch64"}
+	case "x86_64", "aarch64":
+		archs = []string{arch}
+	default:
+		// Invalid architectures are reported by the build
+		return nil
+	}
+
+	repoRoot, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	theme := config.CurrentTheme
+
+	for _, a := range archs {
+		if config.GetKernelsConfigForArch(a) != "" {
+			continue
+		}
+
+		key := "kernels.config." + a
+		picked, err := pickFile(
+			fmt.Sprintf("Select kernel config for %s", a),
+			fmt.Sprintf("anvil.yaml has no %s", key),
+			repoRoot,
+		)
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(repoRoot, picked)
+		if err != nil {
+			return fmt.Errorf("failed to resolve kernel config path: %w", err)
+		}
+		// kernels.config.<arch> is resolved against the repo root, so a file
+		// outside it can't be recorded as a relative path
+		if !filepath.IsLocal(relPath) {
+			return fmt.Errorf("kernel config %s is outside the repository %s; copy it into the repository and select it there", picked, repoRoot)
+		}
+
+		save, err := confirm(fmt.Sprintf("Save %s: %s to anvil.yaml?", key, relPath))
+		if err != nil {
+			return err
+		}
+
+		if save {
+			if err := config.SetConfigValue(key, relPath, config.ScopeRepo); err != nil {
+				return fmt.Errorf("failed to save kernel config to anvil.yaml: %w", err)
+			}
+			fmt.Println(theme.SuccessMessage(fmt.Sprintf("Saved %s to anvil.yaml", key)))
+			continue
+		}
+
+		// Use the selection for this build only
+		if err := config.ValidateValue(key, relPath, config.ScopeRepo); err != nil {
+			return err
+		}
+		config.SetSessionValue(key, relPath)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+package buildkernel
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestResolveMissingKernelConfigs(t *testing.T) {
+	const key = "kernels.config.x86_64"
+
+	tests := []struct {
+		name        string
+		outside     bool
+		save        bool
+		wantErr     string
+		wantSession string
+		wantSaved   string
+	}{
+		{name: "in-repo file saved", save: true, wantSession: "configs/x86_64.config", wantSaved: "configs/x86_64.config"},
+		{name: "in-repo file for this build only", wantSession: "configs/x86_64.config"},
+		{name: "outside-repo file rejected", outside: true, save: true, wantErr: "outside the repository"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := t.TempDir()
+			t.Chdir(repo)
+			t.Cleanup(viper.Reset)
+			if err := os.WriteFile("anvil.yaml", []byte("log-level: info\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			picked := filepath.Join(repo, "configs", "x86_64.config")
+			if tt.outside {
+				picked = filepath.Join(t.TempDir(), "x86_64.config")
+			}
+			if err := os.MkdirAll(filepath.Dir(picked), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(picked, []byte("CONFIG_64BIT=y\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			origPick, origConfirm := pickFile, confirm
+			t.Cleanup(func() { pickFile, confirm = origPick, origConfirm })
+			pickFile = func(title, description, dir string) (string, error) {
+				if dir != repo {
+					t.Errorf("picker rooted at %s, want %s", dir, repo)
+				}
+				return picked, nil
+			}
+			confirmed := false
+			confirm = func(prompt string) (bool, error) {
+				confirmed = true
+				return tt.save, nil
+			}
+
+			err := resolveMissingKernelConfigs("x86_64")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveMissingKernelConfigs() error = %v, want %q", err, tt.wantErr)
+				}
+				if confirmed {
+					t.Error("offered to save a file outside the repository")
+				}
+			} else if err != nil {
+				t.Fatalf("resolveMissingKernelConfigs() error = %v", err)
+			}
+
+			if got := viper.GetString(key); got != tt.wantSession {
+				t.Errorf("%s = %q, want %q", key, got, tt.wantSession)
+			}
+
+			saved := viper.New()
+			saved.SetConfigFile("anvil.yaml")
+			if err := saved.ReadInConfig(); err != nil {
+				t.Fatal(err)
+			}
+			if got := saved.GetString(key); got != tt.wantSaved {
+				t.Errorf("anvil.yaml %s = %q, want %q", key, got, tt.wantSaved)
+			}
+			if got := saved.GetString("log-level"); got != "info" {
+				t.Errorf("anvil.yaml log-level = %q, want existing value kept", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+package buildkernel
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/kernel"
+	"github.com/spf13/cobra"
+)
+
+func newStatsCmd() *cobra.Command {
+	var (
+		format     string
+		archiveDir string
+		output     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stats [stats-file...]",
+		Short: "Export build statistics of all builds as CSV or JSON",
+		Long: `Export the build statistics of every kernel build as CSV or JSON, one
+row per build, for comparing timings across kernel versions.
+
+Without arguments, the build artifacts (build-stats-<version>-<arch>.json) and the
+kernel archive (<arch>/<version>/build-stats.json) are scanned. Pass stats
+files to export only those. Rows are sorted by kernel version, then build
+time; a build found in both the artifacts and the archive is listed once.
+
+Durations are in seconds. compression_ratio is the uncompressed size
+divided by the compressed size.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "csv" && format != "json" {
+				return fmt.Errorf("invalid --format %q: must be csv or json", format)
+			}
+
+			files := args
+			if len(files) == 0 {
+				if !cmd.Flags().Changed("archive") {
+					archiveDir = config.GetKernelsArchiveLocation()
+				}
+				var err error
+				files, err = kernel.FindStatsFiles(archiveDir, config.GlobalPaths)
+				if err != nil {
+					return err
+				}
+				if len(files) == 0 {
+					return fmt.Errorf("no build stats found in %s/artifacts or the kernel archive", config.GlobalPaths.KernelBuildDir)
+				}
+			}
+
+			w := os.Stdout
+			if output != "" {
+				f, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("failed to create output file: %w", err)
+				}
+				defer f.Close()
+				w = f
+			}
+
+			if format == "csv" {
+				return kernel.ExportStatsCSV(files, w)
+			}
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(kernel.StatsRows(files))
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "csv", "Output format: csv or json")
+	cmd.Flags().StringVar(&archiveDir, "archive", "", "Kernel archive to scan (default: kernels.archive.location)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write to this file instead of stdout")
+
+	return cmd
+}
@@ -5,14 +5,14 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func newBuildKernelCmd(cleanArch *string) *cobra.Command {
+func newBuildKernelCmd(cleanArch *string, dryRun *bool) *cobra.Command {
 	return &cobra.Command{
 		Use:     "build",
 		Aliases: []string{"builds", "build-kernel"},
 		Short:   "Clean kernel source and build artifacts",
 		Long:    `Clean kernel source code and build artifacts created during kernel compilation.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return cleanBuildKernel(*cleanArch)
+			return cleanBuildKernel(*cleanArch, *dryRun)
 		},
 	}
 }
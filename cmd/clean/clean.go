@@ -6,8 +6,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/kernel"
+	"github.com/Work-Fort/Anvil/pkg/rootfs"
 	"github.com/Work-Fort/Anvil/pkg/ui"
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
@@ -20,24 +23,45 @@ func NewCleanCmd() *cobra.Command {
 		allDangerous   bool
 		force          bool
 		cleanArch      string
+		list           bool
+		prune          pruneFlags
+		dryRun         bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "clean",
 		Short: "Clean anvil data",
-		Long:  `Clean cache and optionally remove versions.`,
+		Long: `Clean cache and optionally remove versions.
+
+Use --list to see what each clean command would remove, and how much space
+it takes, before deleting anything.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if list {
+				return listCleanable()
+			}
+			return cmd.Help()
+		},
 	}
 
+	cmd.Flags().BoolVar(&list, "list", false, "List cleanable data and sizes without removing anything")
+
 	// Create subcommands
-	kernelCmd := newKernelCmd(&removeInactive, &allDangerous, &force)
-	firecrackerCmd := newFirecrackerCmd(&removeInactive, &allDangerous, &force)
-	buildKernelCmd := newBuildKernelCmd(&cleanArch)
-	rootfsCmd := newRootfsCmd()
+	kernelCmd := newKernelCmd(&removeInactive, &allDangerous, &force, &prune, &dryRun)
+	firecrackerCmd := newFirecrackerCmd(&removeInactive, &allDangerous, &force, &dryRun)
+	buildKernelCmd := newBuildKernelCmd(&cleanArch, &dryRun)
+	rootfsCmd := newRootfsCmd(&dryRun)
+
+	// Every subcommand can preview its removals
+	for _, sub := range []*cobra.Command{kernelCmd, firecrackerCmd, buildKernelCmd, rootfsCmd} {
+		sub.Flags().BoolVar(&dryRun, "dry-run", false, "List what would be removed and its size without deleting anything")
+	}
 
 	// Add flags to kernel subcommand
 	kernelCmd.Flags().BoolVarP(&removeInactive, "remove-inactive", "i", false, "Remove all non-default kernel versions")
 	kernelCmd.Flags().BoolVarP(&allDangerous, "all-dangerous", "a", false, "Remove all kernel data (requires confirmation)")
-	kernelCmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation prompt (use with --all-dangerous)")
+	kernelCmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation prompt (use with --all-dangerous, --keep-latest or --older-than)")
+	kernelCmd.Flags().IntVar(&prune.keepLatest, "keep-latest", 0, "Prune all but the N most recently installed kernels")
+	kernelCmd.Flags().StringVar(&prune.olderThan, "older-than", "", "Prune kernels installed longer ago than this (e.g. 30d, 2w, 12h)")
 
 	// Add flags to firecracker subcommand
 	firecrackerCmd.Flags().BoolVarP(&removeInactive, "remove-inactive", "i", false, "Remove all non-default Firecracker versions")
@@ -56,16 +80,15 @@ func NewCleanCmd() *cobra.Command {
 	return cmd
 }
 
-func cleanCache() error {
+func cleanCache(dryRun bool) error {
 	log.Debug("Cleaning cache directory")
 
-	theme := config.CurrentTheme
-	subtleStyle := theme.SubtleStyle()
-	itemStyle := theme.ErrorStyle()
+	out := ui.NewOutput(config.CurrentTheme)
+	r := &remover{dryRun: dryRun}
 
 	if _, err := os.Stat(config.GlobalPaths.CacheDir); os.IsNotExist(err) {
-		fmt.Println()
-		fmt.Println(theme.InfoMessage("Cache directory doesn't exist"))
+		out.Blank()
+		out.Info("Cache directory doesn't exist")
 		return nil
 	}
 
@@ -85,39 +108,44 @@ func cleanCache() error {
 
 		path := filepath.Join(config.GlobalPaths.CacheDir, entry.Name())
 		log.Debugf("Removing %s", entry.Name())
-		if err := os.RemoveAll(path); err != nil {
+		if err := r.removeAll(path); err != nil {
 			return fmt.Errorf("failed to remove %s: %w", path, err)
 		}
 		removedItems = append(removedItems, entry.Name())
 	}
 
-	fmt.Println()
+	if dryRun {
+		r.report(out, "cache")
+		return nil
+	}
+
+	out.Blank()
 
 	if len(removedItems) == 0 {
-		fmt.Println(theme.SuccessMessage("Cache empty"))
+		out.Success("Cache empty")
 	} else {
-		fmt.Println(theme.SuccessMessage("Cache cleaned"))
-		fmt.Println()
-		for _, item := range removedItems {
-			fmt.Println(subtleStyle.Render("  • ") + itemStyle.Render(item))
-		}
+		out.Success("Cache cleaned")
+		out.Blank()
+		out.Itemize(removedItems...)
 	}
 
 	return nil
 }
 
-func cleanInactiveKernels() error {
+func cleanInactiveKernels(dryRun bool) error {
 	theme := config.CurrentTheme
-	confirmed, err := ui.Confirm(theme.WarningIndicator() + "  This will remove all non-default kernel versions. Continue?")
-	if err != nil {
-		return err
-	}
+	if !dryRun {
+		confirmed, err := ui.Confirm(theme.WarningIndicator() + "  This will remove all non-default kernel versions. Continue?")
+		if err != nil {
+			return err
+		}
 
-	if !confirmed {
-		return fmt.Errorf("operation cancelled")
+		if !confirmed {
+			return fmt.Errorf("operation cancelled")
+		}
 	}
-	subtleStyle := theme.SubtleStyle()
-	itemStyle := theme.ErrorStyle()
+	out := ui.NewOutput(theme)
+	r := &remover{dryRun: dryRun}
 
 	kernelName, err := config.GetKernelName()
 	if err != nil {
@@ -152,7 +180,7 @@ func cleanInactiveKernels() error {
 			if version != defaultKernelVersion {
 				log.Debugf("Removing kernel %s", version)
 				path := filepath.Join(config.GlobalPaths.KernelsDir, version)
-				if err := os.RemoveAll(path); err != nil {
+				if err := r.removeAll(path); err != nil {
 					return fmt.Errorf("failed to remove %s: %w", path, err)
 				}
 				removedItems = append(removedItems, fmt.Sprintf("kernel %s", version))
@@ -161,36 +189,127 @@ func cleanInactiveKernels() error {
 		}
 	}
 
-	fmt.Println()
+	if dryRun {
+		r.report(out, "inactive kernel versions")
+		out.Blank()
+		return cleanCache(dryRun)
+	}
+
+	out.Blank()
 
 	if removedCount == 0 {
-		fmt.Println(theme.InfoMessage("No inactive kernel versions to remove"))
+		out.Info("No inactive kernel versions to remove")
 	} else {
-		fmt.Println(theme.SuccessMessage(fmt.Sprintf("Removed %d inactive kernel version(s)", removedCount)))
-		fmt.Println()
-		for _, item := range removedItems {
-			fmt.Println(subtleStyle.Render("  • ") + itemStyle.Render(item))
-		}
+		out.Success(fmt.Sprintf("Removed %d inactive kernel version(s)", removedCount))
+		out.Blank()
+		out.Itemize(removedItems...)
 	}
 
-	fmt.Println()
+	out.Blank()
 
 	// Also clean cache
-	return cleanCache()
+	return cleanCache(dryRun)
 }
 
-func cleanInactiveFirecracker() error {
+// pruneKernels removes installed kernels by count and age (see kernel.Prune)
+// after listing them and asking for confirmation
+func pruneKernels(keepLatest int, olderThan string, force, dryRun bool) error {
+	if keepLatest < 0 {
+		return fmt.Errorf("--keep-latest must be at least 1")
+	}
+	var age time.Duration
+	if olderThan != "" {
+		var err error
+		if age, err = kernel.ParseAge(olderThan); err != nil {
+			return fmt.Errorf("invalid --older-than: %w", err)
+		}
+		if age == 0 {
+			return fmt.Errorf("--older-than must be greater than zero")
+		}
+	}
+
 	theme := config.CurrentTheme
-	confirmed, err := ui.Confirm(theme.WarningIndicator() + "  This will remove all non-default Firecracker versions. Continue?")
+	out := ui.NewOutput(theme)
+
+	candidates, err := kernel.PruneCandidates(keepLatest, age, config.GlobalPaths)
 	if err != nil {
 		return err
 	}
+	if len(candidates) == 0 {
+		out.Blank()
+		out.Info("No kernel versions to prune")
+		return nil
+	}
 
-	if !confirmed {
-		return fmt.Errorf("operation cancelled")
+	if dryRun {
+		r := &remover{dryRun: true}
+		for _, version := range candidates {
+			r.removeAll(filepath.Join(config.GlobalPaths.KernelsDir, version))
+		}
+		r.report(out, "kernel")
+		return nil
 	}
-	subtleStyle := theme.SubtleStyle()
-	itemStyle := theme.ErrorStyle()
+
+	out.Blank()
+	out.Println(fmt.Sprintf("%d kernel version(s) will be removed:", len(candidates)))
+	out.Blank()
+	out.Itemize(candidates...)
+	out.Blank()
+
+	if !force {
+		confirmed, err := ui.Confirm(theme.WarningIndicator() + "  Remove these kernel versions?")
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return fmt.Errorf("operation cancelled")
+		}
+	}
+
+	removed, err := kernel.Prune(keepLatest, age, config.GlobalPaths)
+	if err != nil {
+		return err
+	}
+
+	out.Blank()
+	out.Success(fmt.Sprintf("Pruned %d kernel version(s)", len(removed)))
+	return nil
+}
+
+// previewDeleteVersion reports what cmdutil.DeleteVersion would remove for
+// a kernel version: its directory, and the default kernel symlink if it
+// points at that version
+func previewDeleteVersion(version string) error {
+	kernelName, err := config.GetKernelName()
+	if err != nil {
+		return err
+	}
+
+	r := &remover{dryRun: true}
+	symlinkPath := filepath.Join(config.GlobalPaths.DataDir, kernelName)
+	if target, err := os.Readlink(symlinkPath); err == nil && strings.Contains(target, version) {
+		r.remove(symlinkPath)
+	}
+	r.removeAll(filepath.Join(config.GlobalPaths.KernelsDir, version))
+
+	r.report(ui.NewOutput(config.CurrentTheme), "kernel")
+	return nil
+}
+
+func cleanInactiveFirecracker(dryRun bool) error {
+	theme := config.CurrentTheme
+	if !dryRun {
+		confirmed, err := ui.Confirm(theme.WarningIndicator() + "  This will remove all non-default Firecracker versions. Continue?")
+		if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			return fmt.Errorf("operation cancelled")
+		}
+	}
+	out := ui.NewOutput(theme)
+	r := &remover{dryRun: dryRun}
 
 	removedCount := 0
 	var removedItems []string
@@ -220,7 +339,7 @@ func cleanInactiveFirecracker() error {
 			if version != defaultFCVersion {
 				log.Debugf("Removing Firecracker %s", version)
 				path := filepath.Join(config.GlobalPaths.FirecrackerDir, version)
-				if err := os.RemoveAll(path); err != nil {
+				if err := r.removeAll(path); err != nil {
 					return fmt.Errorf("failed to remove %s: %w", path, err)
 				}
 				removedItems = append(removedItems, fmt.Sprintf("firecracker %s", version))
@@ -229,27 +348,33 @@ func cleanInactiveFirecracker() error {
 		}
 	}
 
-	fmt.Println()
+	if dryRun {
+		r.report(out, "inactive Firecracker versions")
+		out.Blank()
+		return cleanCache(dryRun)
+	}
+
+	out.Blank()
 
 	if removedCount == 0 {
-		fmt.Println(theme.InfoMessage("No inactive Firecracker versions to remove"))
+		out.Info("No inactive Firecracker versions to remove")
 	} else {
-		fmt.Println(theme.SuccessMessage(fmt.Sprintf("Removed %d inactive Firecracker version(s)", removedCount)))
-		fmt.Println()
-		for _, item := range removedItems {
-			fmt.Println(subtleStyle.Render("  • ") + itemStyle.Render(item))
-		}
+		out.Success(fmt.Sprintf("Removed %d inactive Firecracker version(s)", removedCount))
+		out.Blank()
+		out.Itemize(removedItems...)
 	}
 
-	fmt.Println()
+	out.Blank()
 
 	// Also clean cache
-	return cleanCache()
+	return cleanCache(dryRun)
 }
 
-func cleanAllKernels(skipConfirm bool) error {
+func cleanAllKernels(skipConfirm, dryRun bool) error {
 	theme := config.CurrentTheme
-	if !skipConfirm {
+	out := ui.NewOutput(theme)
+	r := &remover{dryRun: dryRun}
+	if !skipConfirm && !dryRun {
 		prompt := theme.WarningIndicator() + `  DANGER: This will remove ALL kernel data
 
 This includes:
@@ -258,6 +383,9 @@ This includes:
 
 Type 'DELETE' to confirm:`
 
+		if ui.AssumeYes() {
+			out.Info("--assume-yes does not answer this prompt; use --force to skip it")
+		}
 		confirmed, err := ui.TypedConfirm(prompt, "DELETE")
 		if err != nil {
 			return err
@@ -269,13 +397,11 @@ Type 'DELETE' to confirm:`
 	}
 
 	log.Debug("Removing all kernel data")
-	subtleStyle := theme.SubtleStyle()
-	itemStyle := theme.ErrorStyle()
 
 	var removedItems []string
 
 	// Remove kernels directory
-	if err := os.RemoveAll(config.GlobalPaths.KernelsDir); err != nil && !os.IsNotExist(err) {
+	if err := r.removeAll(config.GlobalPaths.KernelsDir); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to remove kernels: %w", err)
 	}
 	removedItems = append(removedItems, "All kernels")
@@ -285,25 +411,31 @@ Type 'DELETE' to confirm:`
 	kernelName, err := config.GetKernelName()
 	if err == nil {
 		symlinkPath := filepath.Join(config.GlobalPaths.DataDir, kernelName)
-		os.Remove(symlinkPath)
+		r.remove(symlinkPath)
 		removedItems = append(removedItems, "Kernel symlink")
 	}
 
-	fmt.Println()
-	fmt.Println(theme.SuccessMessage("All kernel data removed"))
-	fmt.Println()
-	for _, item := range removedItems {
-		fmt.Println(subtleStyle.Render("  • ") + itemStyle.Render(item))
+	if dryRun {
+		r.report(out, "kernel")
+		out.Blank()
+		return cleanCache(dryRun)
 	}
-	fmt.Println()
+
+	out.Blank()
+	out.Success("All kernel data removed")
+	out.Blank()
+	out.Itemize(removedItems...)
+	out.Blank()
 
 	// Clean cache (this preserves build-kernel directory)
-	return cleanCache()
+	return cleanCache(dryRun)
 }
 
-func cleanAllFirecracker(skipConfirm bool) error {
+func cleanAllFirecracker(skipConfirm, dryRun bool) error {
 	theme := config.CurrentTheme
-	if !skipConfirm {
+	out := ui.NewOutput(theme)
+	r := &remover{dryRun: dryRun}
+	if !skipConfirm && !dryRun {
 		prompt := theme.WarningIndicator() + `  DANGER: This will remove ALL Firecracker data
 
 This includes:
@@ -312,6 +444,9 @@ This includes:
 
 Type 'DELETE' to confirm:`
 
+		if ui.AssumeYes() {
+			out.Info("--assume-yes does not answer this prompt; use --force to skip it")
+		}
 		confirmed, err := ui.TypedConfirm(prompt, "DELETE")
 		if err != nil {
 			return err
@@ -324,13 +459,10 @@ Type 'DELETE' to confirm:`
 
 	log.Debug("Removing all Firecracker data")
 
-	subtleStyle := theme.SubtleStyle()
-	itemStyle := theme.ErrorStyle()
-
 	var removedItems []string
 
 	// Remove firecracker directory
-	if err := os.RemoveAll(config.GlobalPaths.FirecrackerDir); err != nil && !os.IsNotExist(err) {
+	if err := r.removeAll(config.GlobalPaths.FirecrackerDir); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to remove Firecracker: %w", err)
 	}
 	removedItems = append(removedItems, "All Firecracker versions")
@@ -338,25 +470,28 @@ Type 'DELETE' to confirm:`
 
 	// Remove firecracker symlink in bin
 	symlinkPath := filepath.Join(config.GlobalPaths.BinDir, "firecracker")
-	os.Remove(symlinkPath)
+	r.remove(symlinkPath)
 	removedItems = append(removedItems, "Firecracker symlink")
 
-	fmt.Println()
-	fmt.Println(theme.SuccessMessage("All Firecracker data removed"))
-	fmt.Println()
-	for _, item := range removedItems {
-		fmt.Println(subtleStyle.Render("  • ") + itemStyle.Render(item))
+	if dryRun {
+		r.report(out, "Firecracker")
+		out.Blank()
+		return cleanCache(dryRun)
 	}
-	fmt.Println()
+
+	out.Blank()
+	out.Success("All Firecracker data removed")
+	out.Blank()
+	out.Itemize(removedItems...)
+	out.Blank()
 
 	// Clean cache (this preserves build-kernel directory)
-	return cleanCache()
+	return cleanCache(dryRun)
 }
 
-func cleanBuildKernel(arch string) error {
-	theme := config.CurrentTheme
-	subtleStyle := theme.SubtleStyle()
-	itemStyle := theme.ErrorStyle()
+func cleanBuildKernel(arch string, dryRun bool) error {
+	out := ui.NewOutput(config.CurrentTheme)
+	r := &remover{dryRun: dryRun}
 
 	// Validate architecture
 	if arch != "x86_64" && arch != "aarch64" && arch != "all" {
@@ -375,7 +510,7 @@ func cleanBuildKernel(arch string) error {
 		// Remove entire build and artifacts directories
 		if _, err := os.Stat(buildDir); err == nil {
 			log.Debugf("Removing build directory: %s", buildDir)
-			if err := os.RemoveAll(buildDir); err != nil {
+			if err := r.removeAll(buildDir); err != nil {
 				return fmt.Errorf("failed to remove build directory: %w", err)
 			}
 			removedItems = append(removedItems, "Kernel source (build/)")
@@ -384,13 +519,25 @@ func cleanBuildKernel(arch string) error {
 
 		if _, err := os.Stat(artifactsDir); err == nil {
 			log.Debugf("Removing artifacts directory: %s", artifactsDir)
-			if err := os.RemoveAll(artifactsDir); err != nil {
+			if err := r.removeAll(artifactsDir); err != nil {
 				return fmt.Errorf("failed to remove artifacts directory: %w", err)
 			}
 			removedItems = append(removedItems, "Build artifacts (artifacts/)")
 			removedCount++
 		}
 	} else {
+		// Remove the architecture's per-version build directories
+		// (build/<version>-<arch>)
+		buildDirs, _ := filepath.Glob(filepath.Join(buildDir, "*-"+arch))
+		for _, path := range buildDirs {
+			log.Debugf("Removing %s build directory: %s", arch, path)
+			if err := r.removeAll(path); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", path, err)
+			}
+			removedItems = append(removedItems, fmt.Sprintf("Kernel source (build/%s/)", filepath.Base(path)))
+			removedCount++
+		}
+
 		// Remove only architecture-specific artifacts
 		if _, err := os.Stat(artifactsDir); err == nil {
 			entries, err := os.ReadDir(artifactsDir)
@@ -403,7 +550,7 @@ func cleanBuildKernel(arch string) error {
 				if strings.Contains(entry.Name(), arch) {
 					path := filepath.Join(artifactsDir, entry.Name())
 					log.Debugf("Removing %s artifact: %s", arch, entry.Name())
-					if err := os.Remove(path); err != nil {
+					if err := r.remove(path); err != nil {
 						return fmt.Errorf("failed to remove %s: %w", path, err)
 					}
 					removedItems = append(removedItems, entry.Name())
@@ -413,71 +560,79 @@ func cleanBuildKernel(arch string) error {
 		}
 	}
 
-	fmt.Println()
+	if dryRun {
+		r.report(out, "build")
+		return nil
+	}
+
+	out.Blank()
 
 	if removedCount == 0 {
 		if arch == "all" {
-			fmt.Println(theme.SuccessMessage("No build artifacts"))
+			out.Success("No build artifacts")
 		} else {
-			fmt.Println(theme.SuccessMessage(fmt.Sprintf("No build artifacts (%s)", arch)))
+			out.Success(fmt.Sprintf("No build artifacts (%s)", arch))
 		}
 	} else {
 		if arch == "all" {
-			fmt.Println(theme.SuccessMessage("Build artifacts cleaned"))
+			out.Success("Build artifacts cleaned")
 		} else {
-			fmt.Println(theme.SuccessMessage(fmt.Sprintf("Build artifacts cleaned (%s)", arch)))
-		}
-		fmt.Println()
-		for _, item := range removedItems {
-			fmt.Println(subtleStyle.Render("  • ") + itemStyle.Render(item))
+			out.Success(fmt.Sprintf("Build artifacts cleaned (%s)", arch))
 		}
+		out.Blank()
+		out.Itemize(removedItems...)
 	}
 
 	return nil
 }
 
-func cleanRootfs() error {
-	theme := config.CurrentTheme
-	subtleStyle := theme.SubtleStyle()
-	itemStyle := theme.ErrorStyle()
+func cleanRootfs(dryRun bool) error {
+	out := ui.NewOutput(config.CurrentTheme)
+	r := &remover{dryRun: dryRun}
 
 	var removedItems []string
 	removedCount := 0
 
-	// Look for rootfs files in data directory (*.ext4 files)
+	// Look for rootfs files in data directory (*.ext4 and *.squashfs files)
 	entries, err := os.ReadDir(config.GlobalPaths.DataDir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			fmt.Println()
-			fmt.Println(theme.InfoMessage("No rootfs images found"))
+			out.Blank()
+			out.Info("No rootfs images found")
 			return nil
 		}
 		return fmt.Errorf("failed to read data directory: %w", err)
 	}
 
 	for _, entry := range entries {
-		// Match .ext4 files (rootfs images)
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".ext4") {
+		// Match rootfs images
+		if !entry.IsDir() && rootfs.IsImageFile(entry.Name()) {
 			path := filepath.Join(config.GlobalPaths.DataDir, entry.Name())
 			log.Debugf("Removing rootfs: %s", entry.Name())
-			if err := os.Remove(path); err != nil {
-				return fmt.Errorf("failed to remove %s: %w", path, err)
+			if dryRun {
+				r.remove(path)
+				r.remove(path + ".sha256")
+			} else if err := rootfs.RemoveImage(path); err != nil {
+				return err
 			}
 			removedItems = append(removedItems, entry.Name())
 			removedCount++
 		}
 	}
 
-	fmt.Println()
+	if dryRun {
+		r.report(out, "rootfs")
+		return nil
+	}
+
+	out.Blank()
 
 	if removedCount == 0 {
-		fmt.Println(theme.InfoMessage("No rootfs images found"))
+		out.Info("No rootfs images found")
 	} else {
-		fmt.Println(theme.SuccessMessage(fmt.Sprintf("Removed %d rootfs image(s)", removedCount)))
-		fmt.Println()
-		for _, item := range removedItems {
-			fmt.Println(subtleStyle.Render("  • ") + itemStyle.Render(item))
-		}
+		out.Success(fmt.Sprintf("Removed %d rootfs image(s)", removedCount))
+		out.Blank()
+		out.Itemize(removedItems...)
 	}
 
 	return nil
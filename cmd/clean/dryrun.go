@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+package clean
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Work-Fort/Anvil/pkg/ui"
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+// remover deletes paths for the clean commands. In dry-run mode it deletes
+// nothing and records each existing path with its size instead.
+type remover struct {
+	dryRun bool
+	items  []cleanItem
+}
+
+// removeAll removes path and everything below it, like os.RemoveAll
+func (r *remover) removeAll(path string) error {
+	if r.dryRun {
+		r.record(path)
+		return nil
+	}
+	return os.RemoveAll(path)
+}
+
+// remove removes a single file or symlink, like os.Remove
+func (r *remover) remove(path string) error {
+	if r.dryRun {
+		r.record(path)
+		return nil
+	}
+	return os.Remove(path)
+}
+
+func (r *remover) record(path string) {
+	if _, err := os.Lstat(path); err != nil {
+		return
+	}
+	r.items = append(r.items, cleanItem{name: path, size: entrySize(path)})
+}
+
+// report prints the paths a dry run would have removed and their total size
+func (r *remover) report(out ui.Output, what string) {
+	out.Blank()
+	if len(r.items) == 0 {
+		out.Info(fmt.Sprintf("Dry run: no %s to remove", what))
+		return
+	}
+
+	var total int64
+	lines := make([]string, 0, len(r.items))
+	for _, item := range r.items {
+		total += item.size
+		lines = append(lines, fmt.Sprintf("%s (%s)", item.name, util.FormatSize(item.size)))
+	}
+	out.Info(fmt.Sprintf("Dry run: would remove %d %s item(s), %s total", len(r.items), what, util.FormatSize(total)))
+	out.Blank()
+	out.Itemize(lines...)
+}
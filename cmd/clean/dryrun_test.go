@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+package clean
+
+import (
+	"bytes"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/ui"
+)
+
+// snapshot returns every path under root with its size
+func snapshot(t *testing.T, root string) map[string]int64 {
+	t.Helper()
+	files := map[string]int64{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files[path] = info.Size()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestRemoverDryRun(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"tree/a":    "0123456789",
+		"tree/b/c":  "01234",
+		"single":    "012",
+		"untouched": "0",
+	})
+	before := snapshot(t, dir)
+
+	r := &remover{dryRun: true}
+	for _, err := range []error{
+		r.removeAll(filepath.Join(dir, "tree")),
+		r.remove(filepath.Join(dir, "single")),
+		// Missing paths aren't reported
+		r.removeAll(filepath.Join(dir, "missing")),
+	} {
+		if err != nil {
+			t.Fatalf("dry run error = %v", err)
+		}
+	}
+
+	if after := snapshot(t, dir); !maps.Equal(before, after) {
+		t.Errorf("dry run changed the tree:\nbefore %v\nafter  %v", before, after)
+	}
+
+	var out bytes.Buffer
+	r.report(ui.NewWriterOutput(&out, config.CurrentTheme, false), "cache")
+	for _, want := range []string{
+		"Dry run: would remove 2 cache item(s), 18 B total",
+		filepath.Join(dir, "tree") + " (15 B)",
+		filepath.Join(dir, "single") + " (3 B)",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report doesn't contain %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	(&remover{dryRun: true}).report(ui.NewWriterOutput(&out, config.CurrentTheme, false), "cache")
+	if !strings.Contains(out.String(), "Dry run: no cache to remove") {
+		t.Errorf("empty report = %q", out.String())
+	}
+}
+
+func TestCleanDryRun(t *testing.T) {
+	root := t.TempDir()
+	saved := config.GlobalPaths
+	config.SetPaths(root)
+	t.Cleanup(func() { config.GlobalPaths = saved })
+	paths := config.GlobalPaths
+
+	kernelName, err := config.GetKernelName()
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, paths.KernelsDir, map[string]string{
+		"6.12.9/" + kernelName + "-6.12.9": "old",
+		"6.18.9/" + kernelName + "-6.18.9": "new",
+	})
+	if err := os.Symlink(filepath.Join(paths.KernelsDir, "6.18.9", kernelName+"-6.18.9"), filepath.Join(paths.DataDir, kernelName)); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, paths.CacheDir, map[string]string{"versions/kernel.json": "[]"})
+	writeFiles(t, paths.KernelBuildDir, map[string]string{
+		"artifacts/vmlinux-6.18.9-x86_64":          "ELF",
+		"build/6.18.9-x86_64/linux-6.18.9.tar.xz":  "XZ",
+		"build/6.18.9-aarch64/linux-6.18.9.tar.xz": "XZ",
+	})
+	writeFiles(t, paths.DataDir, map[string]string{"alpine.ext4": "EXT4"})
+	before := snapshot(t, root)
+
+	// The destructive commands prompt unless run dry; a prompt would fail
+	// here without a terminal
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{"cache", func() error { return cleanCache(true) }},
+		{"inactive kernels", func() error { return cleanInactiveKernels(true) }},
+		{"all kernels", func() error { return cleanAllKernels(false, true) }},
+		{"build x86_64", func() error { return cleanBuildKernel("x86_64", true) }},
+		{"build all", func() error { return cleanBuildKernel("all", true) }},
+		{"rootfs", func() error { return cleanRootfs(true) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); err != nil {
+				t.Fatalf("dry run error = %v", err)
+			}
+			if after := snapshot(t, root); !maps.Equal(before, after) {
+				t.Errorf("dry run changed the tree:\nbefore %v\nafter  %v", before, after)
+			}
+		})
+	}
+}
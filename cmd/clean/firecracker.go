@@ -5,18 +5,18 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func newFirecrackerCmd(removeInactive, allDangerous, force *bool) *cobra.Command {
+func newFirecrackerCmd(removeInactive, allDangerous, force, dryRun *bool) *cobra.Command {
 	return &cobra.Command{
 		Use:   "firecracker",
 		Short: "Clean Firecracker data",
 		Long:  `Clean Firecracker cache and optionally remove Firecracker versions.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if *allDangerous {
-				return cleanAllFirecracker(*force)
+				return cleanAllFirecracker(*force, *dryRun)
 			} else if *removeInactive {
-				return cleanInactiveFirecracker()
+				return cleanInactiveFirecracker(*dryRun)
 			}
-			return cleanCache()
+			return cleanCache(*dryRun)
 		},
 	}
 }
@@ -2,27 +2,58 @@
 package clean
 
 import (
+	"fmt"
+
 	"github.com/Work-Fort/Anvil/cmd/cmdutil"
 	"github.com/spf13/cobra"
 )
 
-func newKernelCmd(removeInactive, allDangerous, force *bool) *cobra.Command {
+// pruneFlags holds the clean kernel flags that select versions by age or count
+type pruneFlags struct {
+	keepLatest int
+	olderThan  string
+}
+
+func (p *pruneFlags) set() bool {
+	return p.keepLatest != 0 || p.olderThan != ""
+}
+
+func newKernelCmd(removeInactive, allDangerous, force *bool, prune *pruneFlags, dryRun *bool) *cobra.Command {
 	return &cobra.Command{
-		Use:   "kernel [version]",
-		Short: "Clean kernel data",
-		Long:  `Clean kernel cache and optionally remove kernel versions.`,
+		Use:               "kernel [version]",
+		Short:             "Clean kernel data",
+		ValidArgsFunction: cmdutil.CompleteInstalledVersions("kernel"),
+		Long: `Clean kernel cache and optionally remove kernel versions.
+
+--keep-latest N and --older-than AGE prune installed kernels: a version is
+kept if it is one of the N most recently installed or was installed less
+than AGE ago (e.g. 30d, 2w, 12h). The default kernel is always kept. The
+versions to remove are listed and confirmed first, unless --force is given.
+
+--dry-run lists the paths any of these would remove, and their total size,
+without deleting anything or asking for confirmation.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if prune.set() {
+				if len(args) > 0 || *allDangerous || *removeInactive {
+					return fmt.Errorf("--keep-latest and --older-than can't be combined with a version, --remove-inactive or --all-dangerous")
+				}
+				return pruneKernels(prune.keepLatest, prune.olderThan, *force, *dryRun)
+			}
+
 			if len(args) > 0 {
 				// Remove specific version
+				if *dryRun {
+					return previewDeleteVersion(args[0])
+				}
 				return cmdutil.DeleteVersion("kernel", args[0])
 			}
 
 			if *allDangerous {
-				return cleanAllKernels(*force)
+				return cleanAllKernels(*force, *dryRun)
 			} else if *removeInactive {
-				return cleanInactiveKernels()
+				return cleanInactiveKernels(*dryRun)
 			}
-			return cleanCache()
+			return cleanCache(*dryRun)
 		},
 	}
 }
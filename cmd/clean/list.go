@@ -0,0 +1,252 @@
+// SPDX-License-Identifier: Apache-2.0
+package clean
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/firecracker"
+	"github.com/Work-Fort/Anvil/pkg/kernel"
+	"github.com/Work-Fort/Anvil/pkg/rootfs"
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+// cleanArea is one cleanable location reported by --list
+type cleanArea struct {
+	title   string
+	path    string
+	command string // clean command that removes this area
+	items   []cleanItem
+}
+
+// cleanItem is a single entry inside a cleanable area
+type cleanItem struct {
+	name string
+	size int64
+	note string
+}
+
+// size returns the total size of an area's items
+func (a cleanArea) size() int64 {
+	var total int64
+	for _, item := range a.items {
+		total += item.size
+	}
+	return total
+}
+
+// listCleanable reports the contents and sizes of everything clean can
+// remove, without deleting anything
+func listCleanable() error {
+	paths := config.GlobalPaths
+
+	areas := []cleanArea{
+		listCache(paths),
+		listBuildArtifacts(paths, "x86_64"),
+		listBuildArtifacts(paths, "aarch64"),
+		listSourceTrees(paths),
+		listKernels(paths),
+		listFirecracker(paths),
+		listRootfs(paths),
+		listKeyBackups(paths),
+	}
+
+	theme := config.CurrentTheme
+	subtleStyle := theme.SubtleStyle()
+	titleStyle := theme.InfoStyle()
+	itemStyle := theme.InfoStyle()
+
+	var total int64
+	for _, area := range areas {
+		size := area.size()
+		total += size
+
+		fmt.Println()
+		fmt.Println(titleStyle.Render(area.title) + subtleStyle.Render(fmt.Sprintf(" (%s) %s", util.FormatSize(size), area.path)))
+		if len(area.items) == 0 {
+			fmt.Println(subtleStyle.Render("  • empty"))
+			continue
+		}
+		for _, item := range area.items {
+			line := subtleStyle.Render("  • ") + itemStyle.Render(item.name) + subtleStyle.Render(" "+util.FormatSize(item.size))
+			if item.note != "" {
+				line += subtleStyle.Render(" (" + item.note + ")")
+			}
+			fmt.Println(line)
+		}
+		fmt.Println(subtleStyle.Render("    clean with: " + area.command))
+	}
+
+	fmt.Println()
+	fmt.Println(theme.InfoMessage(fmt.Sprintf("Total: %s", util.FormatSize(total))))
+
+	return nil
+}
+
+// entrySize returns the size of a file or directory, or 0 if unreadable
+func entrySize(path string) int64 {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0
+	}
+	if info.IsDir() {
+		size, _ := util.DirSize(path)
+		return size
+	}
+	return info.Size()
+}
+
+// listCache reports temporary cache entries (the build-kernel subdirectory
+// is reported separately)
+func listCache(paths *config.Paths) cleanArea {
+	area := cleanArea{title: "Cache", path: paths.CacheDir, command: "anvil clean kernel"}
+
+	entries, _ := os.ReadDir(paths.CacheDir)
+	for _, entry := range entries {
+		if entry.Name() == "build-kernel" && entry.IsDir() {
+			continue
+		}
+		area.items = append(area.items, cleanItem{
+			name: entry.Name(),
+			size: entrySize(filepath.Join(paths.CacheDir, entry.Name())),
+		})
+	}
+
+	return area
+}
+
+// listBuildArtifacts reports the build artifacts for one architecture and
+// its per-version build directories (build/<version>-<arch>), which clean
+// build removes too. The kernel sources in those directories are listed
+// under Kernel sources instead, so they aren't counted twice.
+func listBuildArtifacts(paths *config.Paths, arch string) cleanArea {
+	artifactsDir := filepath.Join(paths.KernelBuildDir, "artifacts")
+	area := cleanArea{
+		title:   fmt.Sprintf("Build artifacts (%s)", arch),
+		path:    artifactsDir,
+		command: "anvil clean build --arch " + arch,
+	}
+
+	entries, _ := os.ReadDir(artifactsDir)
+	for _, entry := range entries {
+		if !strings.Contains(entry.Name(), arch) {
+			continue
+		}
+		area.items = append(area.items, cleanItem{
+			name: entry.Name(),
+			size: entrySize(filepath.Join(artifactsDir, entry.Name())),
+		})
+	}
+
+	buildDir := filepath.Join(paths.KernelBuildDir, "build")
+	sources, _ := kernel.KernelSources(paths)
+	buildDirs, _ := filepath.Glob(filepath.Join(buildDir, "*-"+arch))
+	for _, dir := range buildDirs {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		item := cleanItem{name: filepath.Join("build", filepath.Base(dir)) + "/", size: entrySize(dir)}
+		for _, source := range sources {
+			if filepath.Dir(source) == filepath.Base(dir) {
+				item.size -= entrySize(filepath.Join(buildDir, source))
+				item.note = "sources listed under Kernel sources"
+			}
+		}
+		area.items = append(area.items, item)
+	}
+
+	return area
+}
+
+// listSourceTrees reports extracted kernel source trees, tarballs and
+// partial downloads, including those in per-version build directories
+func listSourceTrees(paths *config.Paths) cleanArea {
+	buildDir := filepath.Join(paths.KernelBuildDir, "build")
+	area := cleanArea{title: "Kernel sources", path: buildDir, command: "anvil kernel sources clean"}
+
+	sources, _ := kernel.KernelSources(paths)
+	for _, source := range sources {
+		area.items = append(area.items, cleanItem{
+			name: source,
+			size: entrySize(filepath.Join(buildDir, source)),
+		})
+	}
+
+	return area
+}
+
+// listKernels reports installed kernel versions, marking the default
+func listKernels(paths *config.Paths) cleanArea {
+	area := cleanArea{title: "Installed kernels", path: paths.KernelsDir, command: "anvil clean kernel <version> | --remove-inactive"}
+
+	kernels, _, err := kernel.List(paths)
+	if err != nil {
+		return area
+	}
+	for _, k := range kernels {
+		item := cleanItem{name: k.Version, size: entrySize(k.Path)}
+		if k.IsDefault {
+			item.note = "default"
+		}
+		area.items = append(area.items, item)
+	}
+
+	return area
+}
+
+// listFirecracker reports installed Firecracker versions, marking the default
+func listFirecracker(paths *config.Paths) cleanArea {
+	area := cleanArea{title: "Firecracker", path: paths.FirecrackerDir, command: "anvil clean firecracker <version> | --remove-inactive"}
+
+	versions, err := firecracker.List(paths)
+	if err != nil {
+		return area
+	}
+	for _, v := range versions {
+		item := cleanItem{name: v.Version, size: entrySize(filepath.Dir(v.Path))}
+		if v.IsDefault {
+			item.note = "default"
+		}
+		area.items = append(area.items, item)
+	}
+
+	return area
+}
+
+// listRootfs reports rootfs images (*.ext4, *.squashfs) in the data directory
+func listRootfs(paths *config.Paths) cleanArea {
+	area := cleanArea{title: "Rootfs images", path: paths.DataDir, command: "anvil clean rootfs"}
+
+	entries, _ := os.ReadDir(paths.DataDir)
+	for _, entry := range entries {
+		if entry.IsDir() || !rootfs.IsImageFile(entry.Name()) {
+			continue
+		}
+		area.items = append(area.items, cleanItem{
+			name: entry.Name(),
+			size: entrySize(filepath.Join(paths.DataDir, entry.Name())),
+		})
+	}
+
+	return area
+}
+
+// listKeyBackups reports signing key backups. These are never removed by
+// clean and must be deleted by hand.
+func listKeyBackups(paths *config.Paths) cleanArea {
+	backupsDir := filepath.Join(paths.KeysDir, "backups")
+	area := cleanArea{title: "Signing key backups", path: backupsDir, command: "not cleaned automatically; remove by hand"}
+
+	entries, _ := os.ReadDir(backupsDir)
+	for _, entry := range entries {
+		area.items = append(area.items, cleanItem{
+			name: entry.Name(),
+			size: entrySize(filepath.Join(backupsDir, entry.Name())),
+		})
+	}
+
+	return area
+}
@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: Apache-2.0
+package clean
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+)
+
+// writeFiles creates each named file under dir with its content
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// itemSizes returns the area's item sizes by name
+func itemSizes(area cleanArea) map[string]int64 {
+	sizes := map[string]int64{}
+	for _, item := range area.items {
+		sizes[item.name] = item.size
+	}
+	return sizes
+}
+
+func TestListSourceTrees(t *testing.T) {
+	paths := config.PathsUnder(t.TempDir())
+	buildDir := filepath.Join(paths.KernelBuildDir, "build")
+	writeFiles(t, buildDir, map[string]string{
+		"linux-6.12.9.tar.xz":                    "0123456789",
+		"6.18.9-x86_64/linux-6.18.9.tar.xz":      "01234",
+		"6.18.9-x86_64/linux-6.18.9/Makefile":    "VERSION = 6\n",
+		"6.18.9-x86_64/vmlinux-6.18.9-x86_64.xz": "XZ",
+	})
+
+	area := listSourceTrees(paths)
+	want := map[string]int64{
+		"linux-6.12.9.tar.xz": 10,
+		filepath.Join("6.18.9-x86_64", "linux-6.18.9.tar.xz"): 5,
+		filepath.Join("6.18.9-x86_64", "linux-6.18.9"):        12,
+	}
+	if len(area.items) != len(want) {
+		t.Fatalf("listSourceTrees() items = %+v, want %v", area.items, want)
+	}
+	for _, item := range area.items {
+		if size, ok := want[item.name]; !ok || item.size != size {
+			t.Errorf("item %s (%d bytes) not in %v", item.name, item.size, want)
+		}
+	}
+	if area.size() != 27 {
+		t.Errorf("area size = %d, want 27", area.size())
+	}
+	if area.path != buildDir {
+		t.Errorf("area path = %s, want %s", area.path, buildDir)
+	}
+}
+
+func TestListBuildArtifacts(t *testing.T) {
+	paths := config.PathsUnder(t.TempDir())
+	writeFiles(t, paths.KernelBuildDir, map[string]string{
+		"artifacts/vmlinux-6.18.9-x86_64":           "ELF",
+		"artifacts/vmlinux-6.18.9-x86_64.xz":        "XZ",
+		"artifacts/Image-6.18.9-aarch64":            "ARM64",
+		"build/6.18.9-x86_64/linux-6.18.9.tar.xz":   "01234",
+		"build/6.18.9-x86_64/linux-6.18.9/Makefile": "VERSION = 6\n",
+		"build/6.18.9-x86_64/build.log":             "make\n",
+		"build/6.12.9-x86_64/build.log":             "make -j4\n",
+		"build/6.18.9-aarch64/build.log":            "make\n",
+	})
+
+	tests := []struct {
+		arch string
+		want map[string]int64
+	}{
+		{"x86_64", map[string]int64{
+			"vmlinux-6.18.9-x86_64":    3,
+			"vmlinux-6.18.9-x86_64.xz": 2,
+			// The sources are left to the Kernel sources area
+			filepath.Join("build", "6.18.9-x86_64") + "/": 5,
+			filepath.Join("build", "6.12.9-x86_64") + "/": 9,
+		}},
+		{"aarch64", map[string]int64{
+			"Image-6.18.9-aarch64":                         5,
+			filepath.Join("build", "6.18.9-aarch64") + "/": 5,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arch, func(t *testing.T) {
+			area := listBuildArtifacts(paths, tt.arch)
+			if got := itemSizes(area); !maps.Equal(got, tt.want) {
+				t.Errorf("listBuildArtifacts(%s) = %v, want %v", tt.arch, got, tt.want)
+			}
+		})
+	}
+
+	// Every byte is counted once across the artifacts and sources areas
+	var total int64
+	for _, area := range []cleanArea{listBuildArtifacts(paths, "x86_64"), listBuildArtifacts(paths, "aarch64"), listSourceTrees(paths)} {
+		total += area.size()
+	}
+	if want := entrySize(paths.KernelBuildDir); total != want {
+		t.Errorf("areas total %d bytes, want %d", total, want)
+	}
+}
+
+func TestListCache(t *testing.T) {
+	paths := config.PathsUnder(t.TempDir())
+	writeFiles(t, paths.CacheDir, map[string]string{
+		"firecracker-v1.10.1-x86_64.tgz":               "0123456789",
+		"versions/kernel.json":                         "[]",
+		"build-kernel/artifacts/vmlinux-6.18.9-x86_64": "ELF",
+	})
+
+	// build-kernel has areas of its own
+	want := map[string]int64{"firecracker-v1.10.1-x86_64.tgz": 10, "versions": 2}
+	if got := itemSizes(listCache(paths)); !maps.Equal(got, want) {
+		t.Errorf("listCache() = %v, want %v", got, want)
+	}
+}
+
+func TestListEmpty(t *testing.T) {
+	paths := config.PathsUnder(t.TempDir())
+	for _, area := range []cleanArea{
+		listCache(paths),
+		listBuildArtifacts(paths, "x86_64"),
+		listSourceTrees(paths),
+		listRootfs(paths),
+		listKeyBackups(paths),
+	} {
+		if len(area.items) != 0 || area.size() != 0 {
+			t.Errorf("%s lists %+v in an empty tree", area.title, area.items)
+		}
+	}
+}
@@ -5,13 +5,13 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func newRootfsCmd() *cobra.Command {
+func newRootfsCmd(dryRun *bool) *cobra.Command {
 	return &cobra.Command{
 		Use:   "rootfs",
 		Short: "Clean rootfs images",
-		Long:  `Remove Alpine rootfs images created for Firecracker VMs.`,
+		Long:  `Remove Alpine rootfs images (*.ext4 and *.squashfs) created for Firecracker VMs.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return cleanRootfs()
+			return cleanRootfs(*dryRun)
 		},
 	}
 }
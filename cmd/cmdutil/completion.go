@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+package cmdutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/github"
+	"github.com/Work-Fort/Anvil/pkg/kernel"
+	"github.com/spf13/cobra"
+)
+
+// releaseCacheTTL is how long completion reuses the release list it
+// fetched from GitHub, so repeated tab presses don't use up the API limit
+const releaseCacheTTL = 10 * time.Minute
+
+// CompleteInstalledVersions completes the version argument of commands
+// that act on an installed version of target ("kernel" or "firecracker").
+// The default version is described as such.
+func CompleteInstalledVersions(target string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return installedVersionCompletions(target), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// CompleteAvailableVersions completes the version argument of commands
+// that download a version of target from its GitHub releases. Kernels also
+// offer the "latest" and "lts" aliases.
+func CompleteAvailableVersions(target string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var completions []cobra.Completion
+		if target == "kernel" {
+			completions = append(completions,
+				cobra.CompletionWithDesc(kernel.VersionLatest, "newest release"),
+				cobra.CompletionWithDesc(kernel.VersionLTS, "newest longterm release"))
+		}
+
+		versions, err := availableVersions(target, config.GlobalPaths.CacheDir)
+		if err != nil {
+			return completions, cobra.ShellCompDirectiveNoFileComp
+		}
+		for _, version := range versions {
+			if IsVersionDownloaded(target, version) {
+				completions = append(completions, cobra.CompletionWithDesc(version, "installed"))
+			} else {
+				completions = append(completions, version)
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// installedVersionCompletions lists the version directories of target
+func installedVersionCompletions(target string) []cobra.Completion {
+	var dir string
+	switch target {
+	case "kernel":
+		dir = config.GlobalPaths.KernelsDir
+	case "firecracker":
+		dir = config.GlobalPaths.FirecrackerDir
+	default:
+		return nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	defaultVersion := GetDefaultVersion(target)
+	var completions []cobra.Completion
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == "default" {
+			continue
+		}
+		if entry.Name() == defaultVersion {
+			completions = append(completions, cobra.CompletionWithDesc(entry.Name(), "default"))
+		} else {
+			completions = append(completions, entry.Name())
+		}
+	}
+	return completions
+}
+
+// availableVersions returns target's release versions, newest first, from
+// a cache file in cacheDir while it is younger than releaseCacheTTL, or
+// else from GitHub
+func availableVersions(target, cacheDir string) ([]string, error) {
+	var repo string
+	switch target {
+	case "kernel":
+		repo = config.GitHubRepo
+	case "firecracker":
+		repo = config.FirecrackerRepo
+	default:
+		return nil, fmt.Errorf("unknown target: %s", target)
+	}
+
+	cachePath := filepath.Join(cacheDir, fmt.Sprintf("completion-%s-versions.json", target))
+	if versions, ok := readVersionCache(cachePath); ok {
+		return versions, nil
+	}
+
+	parts := strings.Split(repo, "/")
+	client := github.NewClient(config.GetGitHubToken(), config.GitHubAPI)
+	releases, err := client.GetReleases(parts[0], parts[1], 30)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, 0, len(releases))
+	for _, release := range github.SortReleasesBySemver(releases) {
+		versions = append(versions, github.StripVersionPrefix(release.TagName))
+	}
+
+	// A failed write only costs a fetch on the next completion
+	if data, err := json.Marshal(versions); err == nil {
+		_ = os.WriteFile(cachePath, data, 0644)
+	}
+	return versions, nil
+}
+
+// readVersionCache reads a version list written by availableVersions,
+// reporting false if it is missing, unreadable or older than releaseCacheTTL
+func readVersionCache(path string) ([]string, bool) {
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > releaseCacheTTL {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var versions []string
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, false
+	}
+	return versions, true
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+package cmdutil
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func TestCompleteInstalledVersions(t *testing.T) {
+	dir := t.TempDir()
+	saved := config.GlobalPaths
+	config.SetPaths(dir)
+	t.Cleanup(func() { config.GlobalPaths = saved })
+
+	for _, version := range []string{"6.1.0", "6.12.9"} {
+		if err := os.MkdirAll(filepath.Join(config.GlobalPaths.KernelsDir, version), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	kernelName, err := config.GetKernelName()
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(config.GlobalPaths.KernelsDir, "6.12.9", kernelName)
+	if err := os.Symlink(target, filepath.Join(dir, kernelName)); err != nil {
+		t.Fatal(err)
+	}
+
+	complete := CompleteInstalledVersions("kernel")
+
+	got, directive := complete(&cobra.Command{}, nil, "")
+	want := []cobra.Completion{"6.1.0", cobra.CompletionWithDesc("6.12.9", "default")}
+	if !slices.Equal(got, want) {
+		t.Errorf("completions = %q, want %q", got, want)
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("directive = %v, want NoFileComp", directive)
+	}
+
+	if got, _ := complete(&cobra.Command{}, []string{"6.1.0"}, ""); got != nil {
+		t.Errorf("completions after the version argument = %q, want none", got)
+	}
+}
+
+func TestAvailableVersionsUsesFreshCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	cachePath := filepath.Join(cacheDir, "completion-kernel-versions.json")
+	if err := os.WriteFile(cachePath, []byte(`["6.18.2","6.12.62"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh cache is used without asking GitHub
+	got, err := availableVersions("kernel", cacheDir)
+	if err != nil {
+		t.Fatalf("availableVersions() error = %v", err)
+	}
+	if want := []string{"6.18.2", "6.12.62"}; !slices.Equal(got, want) {
+		t.Errorf("availableVersions() = %q, want %q", got, want)
+	}
+
+	stale := time.Now().Add(-2 * releaseCacheTTL)
+	if err := os.Chtimes(cachePath, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := readVersionCache(cachePath); ok {
+		t.Error("readVersionCache() accepted a cache older than releaseCacheTTL")
+	}
+}
@@ -2,6 +2,7 @@
 package cmdutil
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,13 +14,40 @@ import (
 	"github.com/Work-Fort/Anvil/pkg/kernel"
 	"github.com/Work-Fort/Anvil/pkg/ui"
 	"github.com/charmbracelet/log"
-	"golang.org/x/term"
 )
 
-// IsInteractive checks if stdin is connected to a terminal AND the user wants TUI mode
+// IsInteractive checks if stdin and stdout are connected to a terminal AND the user wants TUI mode
 func IsInteractive() bool {
 	// Check both terminal capability and user preference
-	return term.IsTerminal(int(os.Stdin.Fd())) && config.GetUseTUI()
+	return ui.HasTTY() && config.GetUseTUI()
+}
+
+// MissingVersionError is returned by commands that need a version when
+// none was given and the version selector can't be shown instead
+func MissingVersionError(target string) error {
+	if !config.GetUseTUI() {
+		return fmt.Errorf("no %s version given; pass a version (the version selector is off with --use-tui=false)", target)
+	}
+	return fmt.Errorf("interactive version selector %w; pass a %s version", ui.ErrNoTTY, target)
+}
+
+// AllowRootHint adds how to build as root anyway to an error from a build
+// that was refused for running as root
+func AllowRootHint(err error) error {
+	if errors.Is(err, kernel.ErrRunningAsRoot) {
+		return fmt.Errorf("%w\nRe-run as your normal user, or pass --allow-root if you really mean to build as root", err)
+	}
+	return err
+}
+
+// ForceDefaultHint adds how to set the kernel as default anyway to an error
+// from a kernel refused for being built for another architecture
+func ForceDefaultHint(err error) error {
+	var mismatch *kernel.ArchMismatchError
+	if errors.As(err, &mismatch) {
+		return fmt.Errorf("%w\nUse --force to set it as default anyway", err)
+	}
+	return err
 }
 
 // IsVersionDownloaded checks if a specific version is already downloaded
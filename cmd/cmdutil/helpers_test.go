@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+package cmdutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/kernel"
+)
+
+func TestAllowRootHint(t *testing.T) {
+	refused := fmt.Errorf("%w: files would be owned by root", kernel.ErrRunningAsRoot)
+	err := AllowRootHint(fmt.Errorf("failed to build for x86_64: %w", refused))
+	if !errors.Is(err, kernel.ErrRunningAsRoot) || !strings.Contains(err.Error(), "pass --allow-root") {
+		t.Errorf("AllowRootHint() = %v, want the --allow-root hint", err)
+	}
+
+	other := errors.New("make not found")
+	if err := AllowRootHint(other); err != other {
+		t.Errorf("AllowRootHint() changed an unrelated error: %v", err)
+	}
+	if err := AllowRootHint(nil); err != nil {
+		t.Errorf("AllowRootHint(nil) = %v", err)
+	}
+}
+
+func TestForceDefaultHint(t *testing.T) {
+	mismatch := &kernel.ArchMismatchError{Path: "Image-6.18.9-aarch64", KernelArch: "aarch64", HostArch: "x86_64"}
+	if strings.Contains(mismatch.Error(), "--force") {
+		t.Errorf("ArchMismatchError names a CLI flag: %v", mismatch)
+	}
+	err := ForceDefaultHint(mismatch)
+	var got *kernel.ArchMismatchError
+	if !errors.As(err, &got) || !strings.Contains(err.Error(), "Use --force") {
+		t.Errorf("ForceDefaultHint() = %v, want the --force hint", err)
+	}
+
+	other := errors.New("kernel 6.18.9 not found for x86_64")
+	if err := ForceDefaultHint(other); err != other {
+		t.Errorf("ForceDefaultHint() changed an unrelated error: %v", err)
+	}
+}
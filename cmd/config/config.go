@@ -31,7 +31,7 @@ Use --global to operate on user config instead.`,
 
   # Set global config (user preferences)
   anvil config set --global github-token ghp_xxxxx
-  anvil config set --global default-arch x86_64
+  anvil config set --global log-level info
 
   # Get configuration value
   anvil config get use-tui
@@ -50,6 +50,8 @@ Use --global to operate on user config instead.`,
 	cmd.AddCommand(newUnsetCmd())
 	cmd.AddCommand(newListCmd())
 	cmd.AddCommand(newSchemaCmd())
+	cmd.AddCommand(newMigrateCmd())
+	cmd.AddCommand(newValidateCmd())
 
 	return cmd
 }
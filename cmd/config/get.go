@@ -30,7 +30,7 @@ The source indicates where the value comes from in precedence order:
   # use-tui = true (from ENV: ANVIL_USE_TUI)
   # log-level = debug (from ./anvil.yaml)
   # github-token = ghp_xxxxx (from ~/.config/anvil/config.yaml)
-  # default-arch = x86_64 (default)`,
+  # ui.theme = default (default)`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			key := args[0]
 
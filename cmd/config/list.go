@@ -2,49 +2,77 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/Work-Fort/Anvil/pkg/config"
 	"github.com/spf13/cobra"
 )
 
 func newListCmd() *cobra.Command {
+	var outputJSON bool
+
 	cmd := &cobra.Command{
 		Use:     "list",
 		Aliases: []string{"ls"},
-		Short:   "List all configuration values",
-		Long: `List all configuration values with their sources.
+		Short:   "List all configuration keys with their effective values",
+		Long: `List every known configuration key with its effective value and the
+source of that value:
+
+  - env:     Environment variable (ANVIL_*)
+  - repo:    Local config file (./anvil.yaml)
+  - user:    User config file (~/.config/anvil/config.yaml)
+  - default: Built-in default value
 
-Shows all configuration keys currently set, along with their values
-and where they come from (ENV, local config, user config, or default).
+Keys that may not be set in a scope are marked, e.g. github-token is
+user-only so it never ends up committed in anvil.yaml.
 
-Output format: key = value (source)`,
+With --json, prints an array of objects with the fields key, type, value,
+default, source, env_var, forbidden_in and description, sorted by key.`,
 		Example: `  # List all configuration
   anvil config list
 
   # Example output:
-  # build-jobs = 8 (from ./anvil.yaml)
-  # config-save = false (default)
-  # default-arch = x86_64 (from ~/.config/anvil/config.yaml)
-  # github-token = ghp_xxxxx (from ~/.config/anvil/config.yaml)
-  # log-level = debug (default)
-  # use-tui = false (from ENV: ANVIL_USE_TUI)`,
+  #   KEY             VALUE   SOURCE   NOTES
+  #   github-token            default  not allowed in repo config
+  #   log-level       info    repo
+  #   use-tui         false   env      ANVIL_USE_TUI
+
+  # Script against the effective configuration
+  anvil config list --json | jq -r '.[] | select(.source != "default") | .key'`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Call business logic
-			values, err := config.ListConfigValues()
+			keys, err := config.ListEffective()
 			if err != nil {
 				return err
 			}
 
-			if len(values) == 0 {
-				fmt.Println("No configuration set")
-				return nil
+			if outputJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(keys)
 			}
 
-			// Display each key with its source
-			for _, cv := range values {
-				fmt.Printf("%s = %v (%s)\n", cv.Key, cv.Value, cv.Source)
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "  KEY\tVALUE\tSOURCE\tNOTES")
+			for _, k := range keys {
+				var notes []string
+				if k.Source == config.SourceEnv {
+					notes = append(notes, k.EnvVar)
+				}
+				for _, scope := range k.ForbiddenIn {
+					notes = append(notes, "not allowed in "+scope+" config")
+				}
+				value := ""
+				if k.Value != nil {
+					value = fmt.Sprintf("%v", k.Value)
+				}
+				fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", k.Key, value, k.Source, strings.Join(notes, ", "))
 			}
+			tw.Flush()
 
 			// Show configuration precedence info
 			fmt.Println("\n" + config.CurrentTheme.SubtleStyle().Render("Configuration precedence: ENV > local config > user config > defaults"))
@@ -53,5 +81,7 @@ Output format: key = value (source)`,
 		},
 	}
 
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+
 	return cmd
 }
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+package config
+
+import (
+	"fmt"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade config file to the current schema",
+		Long: `Rewrite a config file to the current configuration schema.
+
+Renamed keys are moved to their new names, retired enum values are mapped
+to their replacements and removed keys are dropped. The original file is
+backed up next to it as <file>.bak-<timestamp> before it is rewritten.`,
+		Args: cobra.NoArgs,
+		Example: `  # Migrate local config
+  anvil config migrate
+
+  # Migrate user config
+  anvil config migrate --global
+
+  # Show what would change without writing
+  anvil config migrate --dry-run`,
+		Annotations: map[string]string{
+			config.AnnotationSkipConfigValidation: "true",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Determine scope
+			scope := config.ScopeRepo
+			if globalFlag {
+				scope = config.ScopeUser
+			}
+
+			result, err := config.MigrateConfig(scope, dryRun)
+			if err != nil {
+				return err
+			}
+
+			if len(result.Changes) == 0 {
+				fmt.Printf("%s is up to date\n", result.Path)
+				return nil
+			}
+
+			for _, change := range result.Changes {
+				fmt.Printf("  - %s\n", change)
+			}
+
+			if dryRun {
+				fmt.Printf("Would apply %d change(s) to %s\n", len(result.Changes), result.Path)
+				return nil
+			}
+
+			fmt.Printf("Migrated %s (%d change(s), backup: %s)\n", result.Path, len(result.Changes), result.BackupPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show changes without writing the config file")
+	addGlobalFlag(cmd)
+	return cmd
+}
@@ -29,7 +29,7 @@ Numeric values are automatically detected and typed.`,
 
   # Set string values
   anvil config set log-level debug
-  anvil config set default-arch x86_64
+  anvil config set kernels.mirror https://mirrors.edge.kernel.org/pub/linux/kernel
 
   # Set numeric values
   anvil config set build-jobs 8
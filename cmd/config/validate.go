@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+package config
+
+import (
+	"fmt"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func newValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check the user and repo config files for problems",
+		Long: `Validate the user config (~/.config/anvil/config.yaml) and the repo config
+(./anvil.yaml) as they are on disk:
+
+  - every key must be known and allowed in the file's scope
+  - every value must have the right type and format
+  - kernel config paths must point to files in the repository
+  - a repo config must set every required key
+
+Each problem is listed with a hint on how to fix it. Exits non-zero if any
+problem is found, so it can gate CI on changes to anvil.yaml.`,
+		Args: cobra.NoArgs,
+		Example: `  # Check the configs before committing
+  anvil config validate`,
+		Annotations: map[string]string{
+			config.AnnotationSkipConfigValidation: "true",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			theme := config.CurrentTheme
+			subtleStyle := theme.SubtleStyle()
+
+			problems, err := config.ValidateConfigFiles()
+			if err != nil {
+				return err
+			}
+
+			if len(problems) == 0 {
+				fmt.Println(theme.SuccessMessage("Config files are valid"))
+				return nil
+			}
+
+			file := ""
+			for _, p := range problems {
+				if p.File != file {
+					file = p.File
+					fmt.Printf("%s (%s config)\n", file, p.Scope)
+				}
+				fmt.Printf("  - %s: %s\n", p.Key, p.Message)
+				fmt.Println(subtleStyle.Render("    hint: " + p.Hint))
+			}
+
+			return fmt.Errorf("%d problem(s) found in config files", len(problems))
+		},
+	}
+
+	return cmd
+}
@@ -2,6 +2,7 @@
 package firecracker
 
 import (
+	"fmt"
 	"path/filepath"
 
 	"github.com/Work-Fort/Anvil/pkg/config"
@@ -16,9 +17,18 @@ func newCreateRootfsCmd() *cobra.Command {
 		createRootfsForce         bool
 		createRootfsAlpineVersion string
 		createRootfsAlpinePatch   string
+		createRootfsBaseURL       string
+		createRootfsArch          string
 		createRootfsInjectBinary  bool
 		createRootfsBinaryPath    string
 		createRootfsBinaryDest    string
+		createRootfsVsockPort     uint32
+		createRootfsPackages      []string
+		createRootfsInitScript    string
+		createRootfsFormat        string
+		createRootfsSSHKeys       []string
+		createRootfsReproducible  bool
+		createRootfsSeed          string
 	)
 
 	cmd := &cobra.Command{
@@ -28,12 +38,52 @@ func newCreateRootfsCmd() *cobra.Command {
 		Long: `Create an Alpine Linux-based ext4 rootfs for Firecracker VMs.
 
 The rootfs contains:
-- Alpine Linux minirootfs (configurable version)
+- Alpine Linux minirootfs (latest stable by default, or a given version)
 - Init script that mounts essential filesystems
 - Optional binary injection with automatic vsock server startup
 
-This is useful for running Firecracker VMs with the anvil agent.`,
-		Example: `  # Create default rootfs (512MB, Alpine 3.23.3)
+This is useful for running Firecracker VMs with the anvil agent.
+
+With --arch, the rootfs is built for another architecture (e.g. an
+aarch64 image on an x86_64 host). The injected binary must match the
+target architecture; a mismatch is rejected before anything is written.
+
+--base-url (or rootfs.alpine-mirror in config) downloads the minirootfs
+from another Alpine mirror. The mirror must use https and the CDN's path
+layout; the tarball is still checked against its published SHA256.
+
+Without --alpine-version and --alpine-patch, the latest Alpine release is
+looked up in the mirror's latest-releases.yaml (cached for a day). With
+only --alpine-version, the newest patch of that branch is used.
+
+--vsock-port sets the port the init script starts the vsock server on
+(passed as ANVIL_VSOCK_PORT) and the port it reports at boot. The written
+/init is read back to check both match.
+
+--package installs extra Alpine packages (e.g. curl, ca-certificates,
+openssh) with apk inside the image. The libguestfs appliance needs network
+access to the Alpine repositories, and the target arch must match the host.
+
+--format squashfs builds a compressed, read-only squashfs image instead of
+ext4. Packages, the init script and the injected binary are added before
+the image is packed. --size is the size of the staging ext4 image and must
+fit the unpacked tree. Squashfs guests need a tmpfs or overlay for any
+path they write to.
+
+--ssh-key authorizes an OpenSSH public key (given literally or as a file
+such as ~/.ssh/id_ed25519.pub) for root. openssh-server is installed
+unless an openssh package is already listed, host keys are generated at
+build time and the built-in init starts sshd at boot.
+
+--init-script replaces the built-in /init with your own script (it must
+start with a shebang). The vsock server is then only started if your
+script starts it, and --vsock-port is not checked.
+
+--reproducible builds a deterministic image: a fixed ext4 label and a
+UUID derived from --seed (default: the Alpine release and arch), tarball
+entries extracted in sorted order, and every file timestamp set to
+SOURCE_DATE_EPOCH (default 0). --seed implies --reproducible.`,
+		Example: `  # Create default rootfs (512MB, latest stable Alpine)
   anvil firecracker create-rootfs
 
   # Inject anvil binary into rootfs
@@ -42,6 +92,33 @@ This is useful for running Firecracker VMs with the anvil agent.`,
   # Specific Alpine version
   anvil firecracker create-rootfs --alpine-version 3.23 --alpine-patch 2
 
+  # Download from a local Alpine mirror
+  anvil firecracker create-rootfs --base-url https://mirror.example.com
+
+  # aarch64 rootfs from an x86_64 host, with a cross-built agent
+  anvil firecracker create-rootfs --arch aarch64 --inject-binary \
+    --binary-path ./vsock-server-aarch64 --binary-dest /usr/bin/vsock-server
+
+  # Add packages to the image
+  anvil firecracker create-rootfs --package curl --package ca-certificates \
+    --package openssh
+
+  # Start the injected vsock server on another port
+  anvil firecracker create-rootfs --inject-binary --vsock-port 9000
+
+  # Read-only compressed image
+  anvil firecracker create-rootfs --format squashfs --inject-binary
+
+  # Guest you can SSH into as root
+  anvil firecracker create-rootfs --ssh-key ~/.ssh/id_ed25519.pub
+
+  # Boot guests with your own init
+  anvil firecracker create-rootfs --init-script ./my-init.sh
+
+  # Deterministic image for a reproducible release
+  SOURCE_DATE_EPOCH=1700000000 anvil firecracker create-rootfs \
+    --alpine-version 3.23 --alpine-patch 3 --reproducible
+
   # Custom output and size
   anvil firecracker create-rootfs --output /tmp/my-rootfs.ext4 --size 1024
 
@@ -49,9 +126,11 @@ This is useful for running Firecracker VMs with the anvil agent.`,
   anvil firecracker create-rootfs --inject-binary \
     --binary-path ./my-agent --binary-dest /usr/local/bin/agent`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			var checksum string
+
 			// Set default output path if not specified
 			if createRootfsOutput == "" {
-				createRootfsOutput = filepath.Join(config.GlobalPaths.DataDir, "alpine-rootfs.ext4")
+				createRootfsOutput = filepath.Join(config.GlobalPaths.DataDir, "alpine-rootfs."+createRootfsFormat)
 			}
 
 			opts := rootfs.CreateOptions{
@@ -59,25 +138,55 @@ This is useful for running Firecracker VMs with the anvil agent.`,
 				SizeMB:         createRootfsSizeMB,
 				AlpineVersion:  createRootfsAlpineVersion,
 				AlpinePatch:    createRootfsAlpinePatch,
+				AlpineMirror:   createRootfsBaseURL,
+				Arch:           createRootfsArch,
 				ForceOverwrite: createRootfsForce,
 				InjectBinary:   createRootfsInjectBinary,
 				BinaryPath:     createRootfsBinaryPath,
 				BinaryDestPath: createRootfsBinaryDest,
+				VsockPort:      createRootfsVsockPort,
+				Reproducible:   createRootfsReproducible || createRootfsSeed != "",
+				Seed:           createRootfsSeed,
+				Packages:       createRootfsPackages,
+				InitScriptPath: createRootfsInitScript,
+				Format:         createRootfsFormat,
+				AuthorizedKeys: createRootfsSSHKeys,
+				StatsCallback: func(stats rootfs.CreateStats) {
+					checksum = stats.SHA256
+				},
+			}
+
+			if err := rootfs.Create(opts); err != nil {
+				return err
 			}
 
-			return rootfs.Create(opts)
+			theme := config.CurrentTheme
+			fmt.Println(theme.SuccessMessage(fmt.Sprintf("SHA256 %s", checksum)))
+			fmt.Println(theme.SubtleStyle().Render(fmt.Sprintf("Written to %s.sha256", createRootfsOutput)))
+			return nil
 		},
 	}
 
 	// Add flags to create-rootfs command
-	cmd.Flags().StringVarP(&createRootfsOutput, "output", "o", "", "Output file path (default: ~/.local/share/anvil/alpine-rootfs.ext4)")
+	cmd.Flags().StringVarP(&createRootfsOutput, "output", "o", "", "Output file path (default: ~/.local/share/anvil/alpine-rootfs.<format>)")
 	cmd.Flags().IntVarP(&createRootfsSizeMB, "size", "s", 512, "Size in MB")
 	cmd.Flags().BoolVarP(&createRootfsForce, "force", "f", false, "Overwrite existing file")
-	cmd.Flags().StringVar(&createRootfsAlpineVersion, "alpine-version", "3.23", "Alpine Linux version (major.minor)")
-	cmd.Flags().StringVar(&createRootfsAlpinePatch, "alpine-patch", "3", "Alpine Linux patch version")
+	cmd.Flags().StringVar(&createRootfsAlpineVersion, "alpine-version", "", "Alpine Linux version (major.minor) (default: latest stable)")
+	cmd.Flags().StringVar(&createRootfsAlpinePatch, "alpine-patch", "", "Alpine Linux patch version (default: latest for the version)")
+	cmd.Flags().StringVar(&createRootfsBaseURL, "base-url", "", "Alpine mirror base URL (default: rootfs.alpine-mirror)")
+	cmd.Flags().StringVarP(&createRootfsArch, "arch", "a", "", "Target architecture: x86_64 or aarch64 (default: host)")
+	cmd.Flags().StringVar(&createRootfsArch, "dest-arch", "", "Target architecture (use --arch)")
+	_ = cmd.Flags().MarkDeprecated("dest-arch", "use --arch instead")
 	cmd.Flags().BoolVar(&createRootfsInjectBinary, "inject-binary", false, "Inject binary into rootfs")
 	cmd.Flags().StringVar(&createRootfsBinaryPath, "binary-path", "", "Path to binary to inject (default: current executable)")
 	cmd.Flags().StringVar(&createRootfsBinaryDest, "binary-dest", "/usr/bin/anvil", "Destination path in rootfs")
+	cmd.Flags().BoolVar(&createRootfsReproducible, "reproducible", false, "Build a deterministic image (fixed UUID, label, file order and timestamps)")
+	cmd.Flags().StringVar(&createRootfsSeed, "seed", "", "Seed for the reproducible filesystem UUID (implies --reproducible)")
+	cmd.Flags().StringArrayVar(&createRootfsPackages, "package", nil, "Extra Alpine package to install (repeatable)")
+	cmd.Flags().StringVar(&createRootfsFormat, "format", rootfs.FormatExt4, "Image format: ext4 or squashfs")
+	cmd.Flags().StringArrayVar(&createRootfsSSHKeys, "ssh-key", nil, "SSH public key or key file to authorize for root (repeatable)")
+	cmd.Flags().StringVar(&createRootfsInitScript, "init-script", "", "Custom /init script to use instead of the built-in one")
+	cmd.Flags().Uint32Var(&createRootfsVsockPort, "vsock-port", rootfs.DefaultVsockPort, "vsock port the init script starts the server on")
 
 	return cmd
 }
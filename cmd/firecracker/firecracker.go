@@ -29,6 +29,7 @@ func NewFirecrackerCmd() *cobra.Command {
 	cmd.AddCommand(newSetCmd())
 	cmd.AddCommand(newRemoveCmd())
 	cmd.AddCommand(newCreateRootfsCmd())
+	cmd.AddCommand(newResizeRootfsCmd())
 	cmd.AddCommand(newTestCmd())
 
 	return cmd
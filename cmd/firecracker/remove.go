@@ -21,7 +21,7 @@ func newRemoveCmd() *cobra.Command {
 				return cmdutil.ShowVersionSelector("firecracker")
 			}
 			if len(args) == 0 {
-				return cmd.Usage()
+				return cmdutil.MissingVersionError("firecracker")
 			}
 			if err := firecracker.Remove(args[0], config.GlobalPaths); err != nil {
 				return err
@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: Apache-2.0
+package firecracker
+
+import (
+	"fmt"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/rootfs"
+	"github.com/spf13/cobra"
+)
+
+func newResizeRootfsCmd() *cobra.Command {
+	var sizeMB int
+
+	cmd := &cobra.Command{
+		Use:   "resize-rootfs <image>",
+		Short: "Grow an existing rootfs image",
+		Long: `Grow a rootfs image created with create-rootfs and expand its ext4
+filesystem to fill the new size, without recreating the image.
+
+Only growing is supported: --size must be larger than the current image
+size, and the image is left untouched otherwise.`,
+		Example: `  # Grow the default rootfs to 1GB
+  anvil firecracker resize-rootfs ~/.local/share/anvil/alpine-rootfs.ext4 --size 1024`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := rootfs.Resize(args[0], sizeMB); err != nil {
+				return err
+			}
+			theme := config.CurrentTheme
+			fmt.Println(theme.SuccessMessage(fmt.Sprintf("Resized %s to %dMB", args[0], sizeMB)))
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&sizeMB, "size", "s", 0, "New size in MB (must be larger than the current size)")
+	cmd.MarkFlagRequired("size")
+
+	return cmd
+}
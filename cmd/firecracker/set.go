@@ -22,7 +22,7 @@ func newSetCmd() *cobra.Command {
 				return cmdutil.ShowVersionSelector("firecracker")
 			}
 			if len(args) == 0 {
-				return cmd.Usage()
+				return cmdutil.MissingVersionError("firecracker")
 			}
 			if err := firecracker.Set(args[0], config.GlobalPaths); err != nil {
 				return err
@@ -5,13 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/Work-Fort/Anvil/pkg/config"
 	initpkg "github.com/Work-Fort/Anvil/pkg/init"
 	"github.com/Work-Fort/Anvil/pkg/signing"
+	"github.com/Work-Fort/Anvil/pkg/ui"
 	"github.com/spf13/cobra"
-	"golang.org/x/term"
 )
 
 // InitFlags holds the CLI flags for non-interactive mode
@@ -20,8 +21,11 @@ type InitFlags struct {
 	KeyEmail        string
 	KeyExpiry       string
 	KeyFormat       string
+	KeyAlgorithm    string
 	HistoryFormat   string
 	ArchiveLocation string
+	KernelSeries    string
+	Force           bool
 }
 
 // package-level flag variables bound to cobra flags
@@ -30,8 +34,12 @@ var (
 	flagKeyEmail        string
 	flagKeyExpiry       string
 	flagKeyFormat       string
+	flagKeyAlgorithm    string
 	flagHistoryFormat   string
 	flagArchiveLocation string
+	flagKernelSeries    string
+	flagDryRun          bool
+	flagForce           bool
 )
 
 // GetInitCmd returns the cobra command for the init subcommand.
@@ -44,10 +52,18 @@ func GetInitCmd() *cobra.Command {
 
 Creates:
   - anvil.yaml (repo configuration)
-  - configs/ directory with minimal kernel config templates
+  - configs/ directory with Firecracker microvm kernel configs
   - Encrypted signing keys in the keys/ directory
   - .gitignore for build artifacts
 
+Use --dry-run to list the files that would be written, and which of them
+already exist, without writing anything.
+
+Re-running init in an initialized repo does not overwrite anything: keys
+missing from anvil.yaml are merged in (existing values are kept), and other
+existing files, including the signing keys, are left as they are. Use
+--force to overwrite them instead.
+
 Interactive mode (default when stdin is a terminal and use-tui is true):
   Launches a step-by-step wizard to collect settings and generate files.
 
@@ -58,6 +74,12 @@ Non-interactive mode (--key-name and --key-email required):
 		Example: `  # Interactive wizard (when stdin is a TTY)
   anvil init
 
+  # Show what would be written
+  anvil init --dry-run
+
+  # Add keys missing from an existing anvil.yaml
+  anvil init --key-name "ACME Kernels" --key-email "releases@acme.com"
+
   # Non-interactive (password via environment variable)
   ANVIL_SIGNING_PASSWORD="secret" anvil init \
     --key-name "ACME Kernels" \
@@ -74,19 +96,31 @@ Non-interactive mode (--key-name and --key-email required):
 	cmd.Flags().StringVar(&flagKeyEmail, "key-email", "", "Signing key email (required in non-interactive mode)")
 	cmd.Flags().StringVar(&flagKeyExpiry, "key-expiry", "1y", "Key expiry duration (0=never, 1y, 2y, 5y)")
 	cmd.Flags().StringVar(&flagKeyFormat, "key-format", "armored", "Private key format (armored, binary)")
+	cmd.Flags().StringVar(&flagKeyAlgorithm, "key-algorithm", "rsa4096", "Signing key algorithm (rsa4096, ed25519)")
 	cmd.Flags().StringVar(&flagHistoryFormat, "history-format", "armored", "Public key history format (armored, binary)")
 	cmd.Flags().StringVar(&flagArchiveLocation, "archive-location", "archive", "Local archive directory (must be a relative path inside the repo)")
+	cmd.Flags().StringVar(&flagKernelSeries, "kernel-series", "", "Kernel series the generated kernel configs are for, e.g. 6.1 (default: newest bundled)")
+	cmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "List the files init would write without writing anything")
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Overwrite existing files instead of merging anvil.yaml and keeping the rest")
 
 	return cmd
 }
 
 // runInit is the cobra RunE handler
 func runInit(cmd *cobra.Command, args []string) error {
-	if err := validatePreFlight(); err != nil {
+	if err := validateArchiveLocation(flagArchiveLocation); err != nil {
+		return err
+	}
+	if err := validateKernelSeries(flagKernelSeries); err != nil {
 		return err
 	}
 
-	if err := validateArchiveLocation(flagArchiveLocation); err != nil {
+	if flagDryRun {
+		printPlan(initpkg.PlanRepoFiles(flagForce))
+		return nil
+	}
+
+	if err := validatePreFlight(); err != nil {
 		return err
 	}
 
@@ -99,18 +133,21 @@ func runInit(cmd *cobra.Command, args []string) error {
 		KeyEmail:        flagKeyEmail,
 		KeyExpiry:       flagKeyExpiry,
 		KeyFormat:       flagKeyFormat,
+		KeyAlgorithm:    flagKeyAlgorithm,
 		HistoryFormat:   flagHistoryFormat,
 		ArchiveLocation: flagArchiveLocation,
+		KernelSeries:    flagKernelSeries,
+		Force:           flagForce,
 	}
 	return runNonInteractiveWithFlags(flags)
 }
 
-// isInteractive reports whether stdin is a terminal (TTY)
+// isInteractive reports whether stdin and stdout are terminals (TTY)
 func isInteractive() bool {
-	return term.IsTerminal(int(os.Stdin.Fd()))
+	return ui.HasTTY()
 }
 
-// shouldUseTUI returns true when stdin is a TTY AND use-tui is enabled in config
+// shouldUseTUI returns true when stdin and stdout are TTYs AND use-tui is enabled in config
 func shouldUseTUI() bool {
 	return isInteractive() && config.GetUseTUI()
 }
@@ -127,13 +164,22 @@ func validateArchiveLocation(location string) error {
 	return nil
 }
 
-// validatePreFlight checks whether the current directory can be initialized.
-// It returns an error if anvil.yaml already exists.
-func validatePreFlight() error {
-	if _, err := os.Stat("anvil.yaml"); err == nil {
-		return fmt.Errorf("already initialized: anvil.yaml already exists in the current directory")
+// kernelSeriesPattern matches a kernel series or version: 6.1, 6.12.9, 6.19-rc3
+var kernelSeriesPattern = regexp.MustCompile(`^\d+\.\d+(\.\d+)?(-rc\d+)?$`)
+
+// validateKernelSeries ensures --kernel-series, when given, names a kernel
+// series
+func validateKernelSeries(series string) error {
+	if series != "" && !kernelSeriesPattern.MatchString(series) {
+		return fmt.Errorf("--kernel-series must be a kernel series such as 6.1, got %q", series)
 	}
+	return nil
+}
 
+// validatePreFlight checks whether the current directory can be initialized.
+// An existing anvil.yaml is not an error: init merges into it (or overwrites
+// it with --force).
+func validatePreFlight() error {
 	// Warn (non-fatal) if the directory is not a git repository
 	if _, err := os.Stat(".git"); os.IsNotExist(err) {
 		fmt.Fprintln(os.Stderr, "warning: not a git repository - consider running 'git init' first")
@@ -151,6 +197,21 @@ func runInteractive() error {
 		return fmt.Errorf("signing config: %w", err)
 	}
 	settings.ArchiveLocation = flagArchiveLocation
+	settings.KernelSeries = flagKernelSeries
+	settings.Force = flagForce
+
+	// Show what will be written and confirm before overwriting anything
+	fmt.Println()
+	if overwrites := printPlan(initpkg.PlanRepoFiles(settings.Force)); overwrites > 0 {
+		confirmed, err := ui.Confirm(fmt.Sprintf("Overwrite %d existing file(s)?", overwrites))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println(config.CurrentTheme.InfoMessage("Init cancelled, nothing was written"))
+			return nil
+		}
+	}
 
 	// Phase 2: Key generation + summary in v2 wizard
 	p := tea.NewProgram(NewWizardModel(settings))
@@ -180,42 +241,67 @@ func runNonInteractiveWithFlags(flags InitFlags) error {
 		KeyEmail:        flags.KeyEmail,
 		KeyExpiry:       flags.KeyExpiry,
 		KeyFormat:       flags.KeyFormat,
+		KeyAlgorithm:    flags.KeyAlgorithm,
 		HistoryFormat:   flags.HistoryFormat,
 		KeyPassword:     password,
+		KernelSeries:    flags.KernelSeries,
+		Force:           flags.Force,
 	}
 
-	files, err := initpkg.GenerateRepoFiles(settings)
-	if err != nil {
-		return err
+	for _, file := range initpkg.PlanRepoFiles(settings.Force) {
+		if file.Action == initpkg.PlanOverwrite {
+			fmt.Fprintf(os.Stderr, "warning: overwriting existing %s\n", file.Path)
+		}
 	}
 
-	// Generate signing key
-	format := signing.KeyFormatArmored
-	if flags.KeyFormat == "binary" {
-		format = signing.KeyFormatBinary
-	}
-
-	keyOpts := signing.GenerateKeyOptions{
-		Name:       flags.KeyName,
-		Email:      flags.KeyEmail,
-		Expiry:     flags.KeyExpiry,
-		Format:     format,
-		Password:   password,
-		OutputDir:  "keys",
-		SkipBackup: true,
+	result, err := initpkg.GenerateRepoFilesWithResult(settings)
+	if err != nil {
+		return err
 	}
 
-	if _, err := signing.GenerateKey(keyOpts); err != nil {
-		return fmt.Errorf("failed to generate signing key: %w", err)
+	// Generate signing key, keeping an existing key pair unless forced
+	keptKey := !settings.Force && initpkg.KeysExist()
+	if !keptKey {
+		format := signing.KeyFormatArmored
+		if flags.KeyFormat == "binary" {
+			format = signing.KeyFormatBinary
+		}
+
+		keyOpts := signing.GenerateKeyOptions{
+			Name:       flags.KeyName,
+			Email:      flags.KeyEmail,
+			Expiry:     flags.KeyExpiry,
+			Format:     format,
+			Algorithm:  flags.KeyAlgorithm,
+			Password:   password,
+			OutputDir:  "keys",
+			SkipBackup: true,
+		}
+
+		if _, err := signing.GenerateKey(keyOpts); err != nil {
+			return fmt.Errorf("failed to generate signing key: %w", err)
+		}
 	}
 
 	// Print success message
 	theme := config.CurrentTheme
 	fmt.Println(theme.SuccessMessage("Repository initialized successfully"))
 	fmt.Println()
-	for _, file := range files {
+	for _, file := range result.Created {
 		fmt.Println(theme.CompleteIndicator() + " " + file)
 	}
+	for _, file := range result.Merged {
+		fmt.Println(theme.CompleteIndicator() + " " + file + " (merged)")
+		for _, key := range result.MergedKeys {
+			fmt.Println(theme.SubtleStyle().Render("    + " + key))
+		}
+	}
+	for _, file := range result.Skipped {
+		fmt.Println(theme.SubtleStyle().Render("- " + file + " (exists, kept)"))
+	}
+	if keptKey {
+		fmt.Println(theme.SubtleStyle().Render("- keys/ (existing signing key kept)"))
+	}
 	fmt.Println()
 	fmt.Println("Next steps:")
 	fmt.Println("  1. Customize kernel configs in configs/")
@@ -7,7 +7,10 @@ package init
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/signing"
 )
 
 // TestNonInteractiveMode_CreatesExpectedFiles is an integration test that verifies
@@ -35,8 +38,8 @@ import (
 //
 //	anvil.yaml   - repo configuration with signing key info
 //	.gitignore            - ignores build artifacts and local archives
-//	configs/kernel-x86_64.config  - minimal x86_64 kernel config template
-//	configs/kernel-aarch64.config - minimal aarch64 kernel config template
+//	configs/kernel-x86_64.config  - Firecracker x86_64 kernel config
+//	configs/kernel-aarch64.config - Firecracker aarch64 kernel config
 //	keys/                 - directory for signing keys
 //	keys/history/         - directory for public key history
 func TestNonInteractiveMode_CreatesExpectedFiles(t *testing.T) {
@@ -95,9 +98,9 @@ func TestNonInteractiveMode_CreatesExpectedFiles(t *testing.T) {
 	}
 }
 
-// TestNonInteractiveMode_RejectsReinit verifies that running init twice in the
-// same directory fails with an appropriate error.
-func TestNonInteractiveMode_RejectsReinit(t *testing.T) {
+// TestNonInteractiveMode_ReinitKeepsExisting verifies that running init twice
+// in the same directory keeps the existing config and signing key.
+func TestNonInteractiveMode_ReinitKeepsExisting(t *testing.T) {
 	tmpDir := t.TempDir()
 	origDir, err := os.Getwd()
 	if err != nil {
@@ -112,6 +115,8 @@ func TestNonInteractiveMode_RejectsReinit(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	t.Setenv(signing.EnvSigningPassword, "test-password-123")
+
 	flags := InitFlags{
 		KeyName:       "Test Kernels",
 		KeyEmail:      "test@example.com",
@@ -125,10 +130,26 @@ func TestNonInteractiveMode_RejectsReinit(t *testing.T) {
 		t.Fatalf("first runNonInteractiveWithFlags() failed: %v", err)
 	}
 
-	// Second init should fail with "already initialized" error
-	// validatePreFlight is called by runInit (cobra handler) not runNonInteractiveWithFlags,
-	// so we test validatePreFlight directly here.
-	if err := validatePreFlight(); err == nil {
-		t.Error("validatePreFlight() should return error when anvil.yaml already exists")
+	keyPath := filepath.Join("keys", "signing-key.asc")
+	key, err := os.ReadFile(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Second init merges instead of overwriting
+	flags.KeyName = "Other Kernels"
+	if err := runNonInteractiveWithFlags(flags); err != nil {
+		t.Fatalf("second runNonInteractiveWithFlags() failed: %v", err)
+	}
+
+	config, err := os.ReadFile("anvil.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(config), "Test Kernels") {
+		t.Errorf("re-init overwrote anvil.yaml:\n%s", config)
+	}
+	if after, _ := os.ReadFile(keyPath); string(after) != string(key) {
+		t.Error("re-init replaced the existing signing key")
 	}
 }
@@ -153,7 +153,7 @@ func TestFlagDefaults(t *testing.T) {
 	}
 }
 
-// TestValidatePreFlight verifies validatePreFlight() checks initialization state
+// TestValidatePreFlight verifies validatePreFlight() allows re-running init
 func TestValidatePreFlight(t *testing.T) {
 	tmpDir := t.TempDir()
 	origDir, err := os.Getwd()
@@ -179,8 +179,56 @@ func TestValidatePreFlight(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// Should fail when already initialized
-	if err := validatePreFlight(); err == nil {
-		t.Error("validatePreFlight() should return error when anvil.yaml exists")
+	// Re-running init merges into an existing config, so this is not an error
+	if err := validatePreFlight(); err != nil {
+		t.Errorf("validatePreFlight() returned unexpected error with existing anvil.yaml: %v", err)
+	}
+}
+
+// TestValidateKernelSeries verifies --kernel-series accepts series and versions
+func TestValidateKernelSeries(t *testing.T) {
+	for _, series := range []string{"", "6.1", "6.12.9", "6.19-rc3"} {
+		if err := validateKernelSeries(series); err != nil {
+			t.Errorf("validateKernelSeries(%q) returned unexpected error: %v", series, err)
+		}
+	}
+	for _, series := range []string{"6", "latest", "v6.1"} {
+		if err := validateKernelSeries(series); err == nil {
+			t.Errorf("validateKernelSeries(%q) should fail", series)
+		}
+	}
+}
+
+// TestRunInit_DryRun verifies --dry-run writes nothing
+func TestRunInit_DryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.Chdir(origDir); err != nil {
+			t.Errorf("failed to restore working directory: %v", err)
+		}
+	}()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Binding the flags resets them to their defaults
+	cmd := GetInitCmd()
+	flagDryRun = true
+	defer func() { flagDryRun = false }()
+
+	if err := runInit(cmd, nil); err != nil {
+		t.Fatalf("runInit() with --dry-run returned unexpected error: %v", err)
+	}
+
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("runInit() with --dry-run wrote %d entries, want none", len(entries))
 	}
 }
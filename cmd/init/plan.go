@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+package init
+
+import (
+	"fmt"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	initpkg "github.com/Work-Fort/Anvil/pkg/init"
+)
+
+// printPlan lists the files init would write and what happens to the ones
+// that already exist. Returns the number of files that would be overwritten.
+func printPlan(plan []initpkg.PlannedFile) int {
+	theme := config.CurrentTheme
+	subtleStyle := theme.SubtleStyle()
+	itemStyle := theme.InfoStyle()
+	existsStyle := theme.WarningStyle()
+
+	overwrites := 0
+	fmt.Println(subtleStyle.Render("anvil init will write:"))
+	for _, file := range plan {
+		bullet := subtleStyle.Render("  • ")
+		switch file.Action {
+		case initpkg.PlanOverwrite:
+			overwrites++
+			fmt.Println(bullet + existsStyle.Render(file.Path) + subtleStyle.Render(" (exists, will be overwritten)"))
+		case initpkg.PlanMerge:
+			fmt.Println(bullet + itemStyle.Render(file.Path) + subtleStyle.Render(" (exists, missing keys will be merged)"))
+		case initpkg.PlanKeep:
+			fmt.Println(bullet + itemStyle.Render(file.Path) + subtleStyle.Render(" (exists, will be kept)"))
+		default:
+			fmt.Println(bullet + itemStyle.Render(file.Path))
+		}
+	}
+	fmt.Println()
+
+	return overwrites
+}
@@ -22,6 +22,7 @@ type generateKeyMsg struct{}
 type keyGeneratedMsg struct {
 	keyPath       string
 	publicKeyPath string
+	existing      bool // An existing key pair was kept instead of generating one
 	err           error
 }
 
@@ -32,6 +33,7 @@ type KeygenTab struct {
 	settings   *initpkg.InitSettings
 	generating bool
 	complete   bool
+	existing   bool
 	err        error
 	spinner    spinner.Model
 }
@@ -74,6 +76,7 @@ func (t *KeygenTab) Update(msg tea.Msg) (*KeygenTab, tea.Cmd) {
 		t.err = msg.err
 		t.complete = true
 		if t.err == nil {
+			t.existing = msg.existing
 			// Update settings with generated key paths
 			t.settings.KeyPath = msg.keyPath
 			t.settings.PublicKeyPath = msg.publicKeyPath
@@ -101,6 +104,15 @@ func (t *KeygenTab) Update(msg tea.Msg) (*KeygenTab, tea.Cmd) {
 // generateKey performs the actual key generation
 func (t *KeygenTab) generateKey() tea.Cmd {
 	return func() tea.Msg {
+		// Re-running init keeps the repo's key pair unless forced
+		if !t.settings.Force && initpkg.KeysExist() {
+			return keyGeneratedMsg{
+				keyPath:       filepath.Join(keysDir, "signing-key-private.asc"),
+				publicKeyPath: filepath.Join(keysDir, "signing-key.asc"),
+				existing:      true,
+			}
+		}
+
 		// Convert format string to KeyFormat
 		format := signing.KeyFormatArmored
 		if t.settings.KeyFormat == "binary" {
@@ -112,6 +124,7 @@ func (t *KeygenTab) generateKey() tea.Cmd {
 			Email:      t.settings.KeyEmail,
 			Expiry:     t.settings.KeyExpiry,
 			Format:     format,
+			Algorithm:  t.settings.KeyAlgorithm,
 			Password:   t.settings.KeyPassword,
 			OutputDir:  keysDir,
 			SkipBackup: true, // keys live in the repo; no separate backup needed
@@ -158,17 +171,23 @@ func (t *KeygenTab) View() string {
 
 	// Show complete state with key paths
 	if t.complete && t.err == nil {
+		titleText := "Signing Key Generated"
+		intro := "Your signing key has been generated and saved:"
+		if t.existing {
+			titleText = "Using Existing Signing Key"
+			intro = "The repository already has a signing key (use --force to replace it):"
+		}
 		title := lipgloss.NewStyle().
 			Bold(true).
 			Foreground(theme.GetPrimaryColor()).
-			Render("Signing Key Generated")
+			Render(titleText)
 
 		content := lipgloss.JoinVertical(
 			lipgloss.Left,
 			"",
 			title,
 			"",
-			"Your signing key has been generated and saved:",
+			intro,
 			"",
 			theme.CompleteIndicator()+" Private key: "+t.settings.KeyPath,
 			theme.CompleteIndicator()+" Public key:  "+t.settings.PublicKeyPath,
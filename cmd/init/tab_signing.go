@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/Work-Fort/Anvil/pkg/config"
 	initpkg "github.com/Work-Fort/Anvil/pkg/init"
 	"github.com/charmbracelet/huh"
 )
@@ -20,6 +21,7 @@ func collectSigningSettings() (*initpkg.InitSettings, error) {
 		keyEmail           string
 		keyExpiry          string
 		keyFormat          string
+		keyAlgorithm       string
 		histFormat         string
 		keyPassword        string
 		keyPasswordConfirm string
@@ -40,6 +42,9 @@ func collectSigningSettings() (*initpkg.InitSettings, error) {
 	if keyFormat == "" {
 		keyFormat = "armored"
 	}
+	if keyAlgorithm == "" {
+		keyAlgorithm = config.GetSigningKeyAlgorithm()
+	}
 	if histFormat == "" {
 		histFormat = "armored"
 	}
@@ -92,6 +97,15 @@ func collectSigningSettings() (*initpkg.InitSettings, error) {
 				).
 				Value(&keyFormat),
 
+			huh.NewSelect[string]().
+				Title("Key Algorithm").
+				Description("Ed25519 keys are smaller and faster; GnuPG older than 2.1 can't read them").
+				Options(
+					huh.NewOption("RSA 4096", "rsa4096"),
+					huh.NewOption("Ed25519", "ed25519"),
+				).
+				Value(&keyAlgorithm),
+
 			huh.NewSelect[string]().
 				Title("Public Key History Format").
 				Description("Storage format for public key history").
@@ -136,6 +150,7 @@ func collectSigningSettings() (*initpkg.InitSettings, error) {
 		KeyEmail:      keyEmail,
 		KeyExpiry:     keyExpiry,
 		KeyFormat:     keyFormat,
+		KeyAlgorithm:  keyAlgorithm,
 		HistoryFormat: histFormat,
 		KeyPassword:   keyPassword,
 	}, nil
@@ -14,6 +14,9 @@ import (
 type generateFilesMsg struct{}
 type filesGeneratedMsg struct {
 	filesCreated []string
+	filesMerged  []string
+	mergedKeys   []string
+	filesSkipped []string
 	err          error
 }
 
@@ -23,6 +26,9 @@ type SummaryTab struct {
 	height       int
 	settings     *initpkg.InitSettings
 	filesCreated []string
+	filesMerged  []string
+	mergedKeys   []string
+	filesSkipped []string
 	complete     bool
 	err          error
 	spinner      spinner.Model
@@ -65,8 +71,11 @@ func (t *SummaryTab) Update(msg tea.Msg) (*SummaryTab, tea.Cmd) {
 		t.err = msg.err
 		t.complete = true
 		if t.err == nil {
-			// Store created files
+			// Store created, merged and skipped files
 			t.filesCreated = msg.filesCreated
+			t.filesMerged = msg.filesMerged
+			t.mergedKeys = msg.mergedKeys
+			t.filesSkipped = msg.filesSkipped
 		}
 		return t, nil
 
@@ -88,6 +97,9 @@ func (t *SummaryTab) Update(msg tea.Msg) (*SummaryTab, tea.Cmd) {
 					t.err = nil
 					t.complete = false
 					t.filesCreated = nil
+					t.filesMerged = nil
+					t.mergedKeys = nil
+					t.filesSkipped = nil
 					return t, tea.Batch(
 						t.spinner.Tick,
 						func() tea.Msg { return generateFilesMsg{} },
@@ -111,10 +123,15 @@ func (t *SummaryTab) Update(msg tea.Msg) (*SummaryTab, tea.Cmd) {
 // generateFiles performs the actual file generation
 func (t *SummaryTab) generateFiles() tea.Cmd {
 	return func() tea.Msg {
-		files, err := initpkg.GenerateRepoFiles(*t.settings)
+		result, err := initpkg.GenerateRepoFilesWithResult(*t.settings)
+		if err != nil {
+			return filesGeneratedMsg{err: err}
+		}
 		return filesGeneratedMsg{
-			filesCreated: files,
-			err:          err,
+			filesCreated: result.Created,
+			filesMerged:  result.Merged,
+			mergedKeys:   result.MergedKeys,
+			filesSkipped: result.Skipped,
 		}
 	}
 }
@@ -165,12 +182,30 @@ func (t *SummaryTab) View() string {
 			"",
 			title,
 			"",
-			"The following files have been created:",
-			"",
 		}
-		contentParts = append(contentParts, fileLines...)
+		if len(fileLines) > 0 {
+			contentParts = append(contentParts, "The following files have been created:", "")
+			contentParts = append(contentParts, fileLines...)
+			contentParts = append(contentParts, "")
+		}
+		if len(t.filesMerged) > 0 {
+			contentParts = append(contentParts, "Missing settings were merged into:", "")
+			for _, file := range t.filesMerged {
+				contentParts = append(contentParts, theme.CompleteIndicator()+" "+file)
+			}
+			for _, key := range t.mergedKeys {
+				contentParts = append(contentParts, theme.SubtleStyle().Render("    + "+key))
+			}
+			contentParts = append(contentParts, "")
+		}
+		if len(t.filesSkipped) > 0 {
+			contentParts = append(contentParts, "Existing files were kept (use --force to overwrite):", "")
+			for _, file := range t.filesSkipped {
+				contentParts = append(contentParts, theme.SubtleStyle().Render("- "+file))
+			}
+			contentParts = append(contentParts, "")
+		}
 		contentParts = append(contentParts,
-			"",
 			theme.TextDimStyle().Render("Press Enter or q to exit"),
 			"",
 		)
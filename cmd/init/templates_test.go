@@ -8,59 +8,38 @@ import (
 	initpkg "github.com/Work-Fort/Anvil/pkg/init"
 )
 
-func TestX86ConfigTemplate_NonEmpty(t *testing.T) {
-	if initpkg.X86ConfigTemplate == "" {
-		t.Error("initpkg.X86ConfigTemplate should not be empty")
+func TestDefaultKernelConfig_Firecracker(t *testing.T) {
+	for _, arch := range []string{"x86_64", "aarch64"} {
+		data, err := initpkg.DefaultKernelConfig(arch, "")
+		if err != nil {
+			t.Fatalf("DefaultKernelConfig(%s) error = %v", arch, err)
+		}
+		// Firecracker boots from virtio-mmio block devices
+		for _, option := range []string{"CONFIG_VIRTIO_MMIO=y", "CONFIG_VIRTIO_BLK=y", "CONFIG_EXT4_FS=y"} {
+			if !strings.Contains(string(data), option) {
+				t.Errorf("%s kernel config should set %s", arch, option)
+			}
+		}
 	}
 }
 
-func TestAarch64ConfigTemplate_NonEmpty(t *testing.T) {
-	if initpkg.Aarch64ConfigTemplate == "" {
-		t.Error("initpkg.Aarch64ConfigTemplate should not be empty")
+func TestDefaultKernelConfig_AreDifferent(t *testing.T) {
+	x86, err := initpkg.DefaultKernelConfig("x86_64", "")
+	if err != nil {
+		t.Fatal(err)
 	}
-}
-
-func TestX86ConfigTemplate_ContainsHeader(t *testing.T) {
-	if !strings.Contains(initpkg.X86ConfigTemplate, "Generated by anvil init") {
-		t.Error("initpkg.X86ConfigTemplate should contain 'Generated by anvil init'")
+	arm, err := initpkg.DefaultKernelConfig("aarch64", "")
+	if err != nil {
+		t.Fatal(err)
 	}
-}
-
-func TestAarch64ConfigTemplate_ContainsHeader(t *testing.T) {
-	if !strings.Contains(initpkg.Aarch64ConfigTemplate, "Generated by anvil init") {
-		t.Error("initpkg.Aarch64ConfigTemplate should contain 'Generated by anvil init'")
+	if string(x86) == string(arm) {
+		t.Error("x86_64 and aarch64 kernel configs should be different (architecture-specific)")
 	}
-}
-
-func TestX86ConfigTemplate_ContainsTODO(t *testing.T) {
-	if !strings.Contains(initpkg.X86ConfigTemplate, "TODO") {
-		t.Error("initpkg.X86ConfigTemplate should contain TODO comment")
-	}
-}
-
-func TestAarch64ConfigTemplate_ContainsTODO(t *testing.T) {
-	if !strings.Contains(initpkg.Aarch64ConfigTemplate, "TODO") {
-		t.Error("initpkg.Aarch64ConfigTemplate should contain TODO comment")
+	if !strings.Contains(string(x86), "Linux/x86_64") {
+		t.Error("x86_64 kernel config should be for x86_64")
 	}
-}
-
-func TestTemplates_AreDifferent(t *testing.T) {
-	if initpkg.X86ConfigTemplate == initpkg.Aarch64ConfigTemplate {
-		t.Error("initpkg.X86ConfigTemplate and initpkg.Aarch64ConfigTemplate should be different (architecture-specific)")
-	}
-}
-
-func TestX86ConfigTemplate_ContainsArchitectureReference(t *testing.T) {
-	template := strings.ToLower(initpkg.X86ConfigTemplate)
-	if !strings.Contains(template, "x86_64") && !strings.Contains(template, "x86-64") {
-		t.Error("initpkg.X86ConfigTemplate should reference x86_64 architecture")
-	}
-}
-
-func TestAarch64ConfigTemplate_ContainsArchitectureReference(t *testing.T) {
-	template := strings.ToLower(initpkg.Aarch64ConfigTemplate)
-	if !strings.Contains(template, "aarch64") && !strings.Contains(template, "arm64") {
-		t.Error("initpkg.Aarch64ConfigTemplate should reference aarch64 architecture")
+	if !strings.Contains(string(arm), "Linux/arm64") {
+		t.Error("aarch64 kernel config should be for arm64")
 	}
 }
 
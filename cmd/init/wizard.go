@@ -193,6 +193,9 @@ func mergeSettings(old, new initpkg.InitSettings) initpkg.InitSettings {
 	if new.KeyFormat != "" {
 		old.KeyFormat = new.KeyFormat
 	}
+	if new.KeyAlgorithm != "" {
+		old.KeyAlgorithm = new.KeyAlgorithm
+	}
 	if new.HistoryFormat != "" {
 		old.HistoryFormat = new.HistoryFormat
 	}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"fmt"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/kernel"
+	"github.com/Work-Fort/Anvil/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+func newArchiveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Manage the repo-local kernel archive",
+		Long:  `Manage archived kernel artifacts in the directory set by kernels.archive.location.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newArchivePruneCmd())
+
+	return cmd
+}
+
+func newArchivePruneCmd() *cobra.Command {
+	var (
+		retainCount int
+		retainDays  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove archived kernels beyond the retention policy",
+		Long: `Remove old arch/version directories from the kernel archive and update index.json.
+
+The retention policy comes from kernels.archive.retain-count and
+kernels.archive.retain-days in anvil.yaml, and can be overridden with flags.
+The newest version of each architecture is never removed.`,
+		Example: `  # Prune using the policy from anvil.yaml
+  anvil kernel archive prune
+
+  # Keep only the 3 newest versions per architecture
+  anvil kernel archive prune --retain-count 3
+
+  # Remove versions archived more than 90 days ago
+  anvil kernel archive prune --retain-days 90`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			archiveDir := config.GetKernelsArchiveLocation()
+			if archiveDir == "" {
+				return fmt.Errorf("kernels.archive.location not configured")
+			}
+
+			if !cmd.Flags().Changed("retain-count") {
+				retainCount = config.GetKernelsArchiveRetainCount()
+			}
+			if !cmd.Flags().Changed("retain-days") {
+				retainDays = config.GetKernelsArchiveRetainDays()
+			}
+
+			theme := config.CurrentTheme
+			subtleStyle := theme.SubtleStyle()
+			itemStyle := theme.ErrorStyle()
+
+			if retainCount == 0 && retainDays == 0 {
+				fmt.Println()
+				fmt.Println(theme.InfoMessage("No retention policy configured"))
+				fmt.Println()
+				fmt.Println(subtleStyle.Render("Set one in anvil.yaml:"))
+				fmt.Println(subtleStyle.Render("  anvil config set kernels.archive.retain-count 5"))
+				return nil
+			}
+
+			result, err := kernel.ArchivePrune(archiveDir, retainCount, retainDays)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println()
+			if len(result.Removed) == 0 {
+				fmt.Println(theme.InfoMessage("No archived kernels to prune"))
+				return nil
+			}
+
+			fmt.Println(theme.SuccessMessage(fmt.Sprintf("Pruned %d archived kernel version(s), freed %s",
+				len(result.Removed), util.FormatSize(result.FreedBytes))))
+			fmt.Println()
+			for _, entry := range result.Removed {
+				fmt.Println(subtleStyle.Render("  • ") + itemStyle.Render(fmt.Sprintf("%s/%s", entry.Arch, entry.Version)))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&retainCount, "retain-count", 0, "Versions to keep per architecture (default: kernels.archive.retain-count)")
+	cmd.Flags().IntVar(&retainDays, "retain-days", 0, "Remove versions older than this many days (default: kernels.archive.retain-days)")
+
+	return cmd
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/kernel"
+	"github.com/spf13/cobra"
+)
+
+func newAuditCmd() *cobra.Command {
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Check the verification state of installed kernels",
+		Long: `Scan all installed kernels and report problems with how they were
+verified:
+
+  - built with source verification disabled
+  - no verification record (installed before anvil recorded it, or
+    copied in by hand)
+  - kernel files whose SHA256 no longer matches the recorded hash
+
+Exits non-zero if any kernel has a problem.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report, err := kernel.Audit(config.GlobalPaths)
+			if err != nil {
+				return err
+			}
+
+			if outputJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return err
+				}
+			} else {
+				printAuditReport(report)
+			}
+
+			if report.Problems > 0 {
+				return fmt.Errorf("%d of %d installed kernel(s) failed the audit", report.Problems, len(report.Kernels))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output the audit as JSON")
+
+	return cmd
+}
+
+// printAuditReport prints one table row per kernel followed by the
+// problems found
+func printAuditReport(report *kernel.AuditReport) {
+	theme := config.CurrentTheme
+	titleStyle := theme.InfoStyle().Bold(true)
+	subtleStyle := theme.SubtleStyle()
+
+	fmt.Println()
+	fmt.Println(titleStyle.Render("Kernel audit"))
+	fmt.Println()
+
+	if len(report.Kernels) == 0 {
+		fmt.Println(subtleStyle.Render("  No kernels installed"))
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  VERSION\tARCH\tSOURCE\tVERIFICATION\tSTATUS")
+	for _, k := range report.Kernels {
+		version := k.Version
+		if k.IsDefault {
+			version += " (default)"
+		}
+		status := "ok"
+		if len(k.Problems) > 0 {
+			status = fmt.Sprintf("%d problem(s)", len(k.Problems))
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", version, orDash(k.Arch), k.Source, orDash(k.VerificationLevel), status)
+	}
+	tw.Flush()
+
+	if report.Problems == 0 {
+		fmt.Println()
+		fmt.Println(theme.SuccessMessage("All installed kernels passed the audit"))
+		return
+	}
+
+	fmt.Println()
+	fmt.Println(titleStyle.Render("Problems:"))
+	for _, k := range report.Kernels {
+		if len(k.Problems) == 0 {
+			continue
+		}
+		fmt.Printf("  %s\n", k.Version)
+		for _, p := range k.Problems {
+			fmt.Println(subtleStyle.Render("    • " + p))
+		}
+	}
+	fmt.Println()
+	fmt.Println(subtleStyle.Render("Rebuild affected kernels with verification enabled, or download them again:"))
+	fmt.Println(subtleStyle.Render("  anvil kernel get <version>"))
+}
+
+// orDash returns s, or "-" when it is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
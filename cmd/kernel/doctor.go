@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/kernel"
+	"github.com/Work-Fort/Anvil/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func newDoctorCmd() *cobra.Command {
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Find broken kernel symlinks and leftovers",
+		Long: `Check the installed kernels and build artifacts for:
+
+  - a default kernel symlink pointing at a kernel that no longer exists
+  - kernel version directories without a kernel binary
+  - built kernels in the artifacts directory without their build stats
+    (an interrupted build)
+
+Each issue is printed with the command that fixes it. Exits non-zero if
+any issue is found.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			issues, err := kernel.Doctor(config.GlobalPaths)
+			if err != nil {
+				return err
+			}
+
+			if outputJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(issues); err != nil {
+					return err
+				}
+			} else {
+				printDoctorIssues(issues)
+			}
+
+			if len(issues) > 0 {
+				return fmt.Errorf("found %d kernel issue(s)", len(issues))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output the issues as JSON")
+
+	return cmd
+}
+
+// printDoctorIssues prints each issue with the command that fixes it
+func printDoctorIssues(issues []kernel.Issue) {
+	theme := config.CurrentTheme
+	out := ui.NewOutput(theme)
+	subtleStyle := theme.SubtleStyle()
+
+	out.Blank()
+	out.Println(out.Render(theme.InfoStyle().Bold(true), "Kernel doctor"))
+	out.Blank()
+
+	if len(issues) == 0 {
+		out.Success("No issues found")
+		return
+	}
+
+	for _, issue := range issues {
+		out.Warn(issue.Problem)
+		out.Println(out.Render(subtleStyle, "    "+issue.Path))
+		out.Println(out.Render(subtleStyle, "    Fix: "+issue.Fix))
+	}
+}
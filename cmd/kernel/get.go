@@ -10,11 +10,14 @@ import (
 )
 
 func newGetCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "get [version]",
-		Aliases: []string{"download"},
-		Short:   "Get a kernel (download or build)",
-		Long:    `Get a Firecracker-compatible kernel from GitHub releases or build from source.`,
+	var allowRoot bool
+
+	cmd := &cobra.Command{
+		Use:               "get [version]",
+		Aliases:           []string{"download"},
+		Short:             "Get a kernel (download or build)",
+		Long:              `Get a Firecracker-compatible kernel from GitHub releases or build from source.`,
+		ValidArgsFunction: cmdutil.CompleteAvailableVersions("kernel"),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			version := ""
 			if len(args) > 0 {
@@ -29,9 +32,14 @@ func newGetCmd() *cobra.Command {
 			// Try download first, build if not available
 			client := github.NewClient(config.GetGitHubToken(), config.GitHubAPI)
 			buildOpts := kernel.BuildOptions{
-				Version: version,
+				Version:   version,
+				AllowRoot: allowRoot,
 			}
-			return kernel.Get(version, client, config.GlobalPaths, &buildOpts)
+			return cmdutil.AllowRootHint(kernel.Get(version, client, config.GlobalPaths, &buildOpts))
 		},
 	}
+
+	cmd.Flags().BoolVar(&allowRoot, "allow-root", false, "Allow building as root when falling back to a source build")
+
+	return cmd
 }
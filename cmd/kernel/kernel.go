@@ -31,6 +31,12 @@ func NewKernelCmd() *cobra.Command {
 	cmd.AddCommand(newSetCmd())
 	cmd.AddCommand(newRemoveCmd())
 	cmd.AddCommand(newVersionCheckCmd())
+	cmd.AddCommand(newArchiveCmd())
+	cmd.AddCommand(newMirrorCmd())
+	cmd.AddCommand(newSourcesCmd())
+	cmd.AddCommand(newStatsCmd())
+	cmd.AddCommand(newAuditCmd())
+	cmd.AddCommand(newDoctorCmd())
 
 	return cmd
 }
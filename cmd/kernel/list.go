@@ -7,6 +7,7 @@ import (
 	"github.com/Work-Fort/Anvil/cmd/cmdutil"
 	"github.com/Work-Fort/Anvil/pkg/config"
 	"github.com/Work-Fort/Anvil/pkg/kernel"
+	"github.com/Work-Fort/Anvil/pkg/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -14,7 +15,10 @@ func newListCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "list",
 		Short: "List installed kernels",
-		Long:  `List all locally installed kernel versions.`,
+		Long: `List all locally installed kernel versions.
+
+A default kernel symlink that points at a deleted kernel is reported as
+a warning instead of a default.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// If terminal is interactive, show TUI selector
 			if cmdutil.IsInteractive() {
@@ -27,37 +31,45 @@ func newListCmd() *cobra.Command {
 			}
 
 			theme := config.CurrentTheme
+			out := ui.NewOutput(theme)
 			titleStyle := theme.InfoStyle().Bold(true)
 			markerStyle := theme.SuccessStyle()
 			versionStyle := theme.InfoStyle()
 			subtleStyle := theme.SubtleStyle()
 
-			fmt.Println()
-			fmt.Printf("%s %s\n", titleStyle.Render("Installed kernels"), subtleStyle.Render(fmt.Sprintf("(%s)", arch)))
-			fmt.Println()
+			out.Blank()
+			out.Println(fmt.Sprintf("%s %s", out.Render(titleStyle, "Installed kernels"), out.Render(subtleStyle, fmt.Sprintf("(%s)", arch))))
+			out.Blank()
+
+			// A default whose kernel was deleted by hand isn't listed as one
+			if target, err := kernel.DanglingDefault(config.GlobalPaths); err == nil && target != "" {
+				out.Warn(fmt.Sprintf("Default kernel points at %s, which no longer exists", target))
+				out.Println(out.Render(subtleStyle, "  Run anvil kernel doctor for how to fix it"))
+				out.Blank()
+			}
 
 			if len(kernels) == 0 {
-				fmt.Println(subtleStyle.Render("  No kernels installed"))
-				fmt.Println()
-				fmt.Println(subtleStyle.Render("Download a kernel with:"))
-				fmt.Println(subtleStyle.Render("  anvil download kernel <version>"))
+				out.Println(out.Render(subtleStyle, "  No kernels installed"))
+				out.Blank()
+				out.Println(out.Render(subtleStyle, "Download a kernel with:"))
+				out.Println(out.Render(subtleStyle, "  anvil download kernel <version>"))
 				return nil
 			}
 
 			for _, ki := range kernels {
 				if ki.IsDefault {
-					fmt.Printf("  %s %s %s\n",
-						markerStyle.Render("●"),
-						versionStyle.Render(ki.Version),
-						subtleStyle.Render("(default)"))
+					out.Println(fmt.Sprintf("  %s %s %s",
+						out.Render(markerStyle, "●"),
+						out.Render(versionStyle, ki.Version),
+						out.Render(subtleStyle, "(default)")))
 				} else {
-					fmt.Printf("    %s\n", versionStyle.Render(ki.Version))
+					out.Println(fmt.Sprintf("    %s", out.Render(versionStyle, ki.Version)))
 				}
 			}
 
-			fmt.Println()
-			fmt.Println(subtleStyle.Render("Set default with:"))
-			fmt.Println(subtleStyle.Render("  anvil set kernel <version>"))
+			out.Blank()
+			out.Println(out.Render(subtleStyle, "Set default with:"))
+			out.Println(out.Render(subtleStyle, "  anvil set kernel <version>"))
 
 			return nil
 		},
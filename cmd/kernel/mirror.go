@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"fmt"
+
+	signingcmd "github.com/Work-Fort/Anvil/cmd/signing"
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/github"
+	"github.com/Work-Fort/Anvil/pkg/kernel"
+	"github.com/Work-Fort/Anvil/pkg/signing"
+	"github.com/spf13/cobra"
+)
+
+func newMirrorCmd() *cobra.Command {
+	var (
+		mirrorTo       string
+		mirrorVersions int
+		mirrorArch     string
+		mirrorOffline  bool
+		mirrorSign     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "mirror",
+		Short: "Mirror signed release kernels into a local archive",
+		Long: `Download the latest kernel releases from GitHub, verify their PGP
+signatures and checksums, and lay them out in the archive structure
+(<arch>/<version>/...) with SHA256SUMS and index.json.
+
+Each version directory keeps the release's SHA256SUMS, SHA256SUMS.asc and
+signing-key.asc so the upstream signature can be verified from the mirror.
+With --sign, SHA256SUMS is regenerated from the mirrored files and signed
+with the local signing key instead.`,
+		Example: `  # Mirror the 5 latest releases
+  anvil kernel mirror --to /srv/kernels
+
+  # Mirror the 10 latest x86_64 releases, skipping ones already present
+  anvil kernel mirror --to /srv/kernels --versions 10 --arch x86_64 --offline
+
+  # Re-sign the mirror with the local signing key
+  anvil kernel mirror --to /srv/kernels --sign`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			theme := config.CurrentTheme
+			subtleStyle := theme.SubtleStyle()
+			successStyle := theme.SuccessStyle()
+			labelStyle := theme.SubtleStyle()
+			valueStyle := theme.InfoStyle()
+
+			// Acquire password up front so a long mirror run isn't interrupted
+			var password string
+			if mirrorSign && signing.UsesPassword() {
+				var err error
+				password, err = signingcmd.GetSigningPassword(
+					signingcmd.PasswordSourceAuto,
+					"Enter password to unlock signing key",
+				)
+				if err != nil {
+					return fmt.Errorf("failed to get password: %w", err)
+				}
+			}
+
+			fmt.Println()
+			fmt.Println(subtleStyle.Render("Mirroring kernel releases..."))
+			fmt.Printf("  %s %s\n", labelStyle.Render("Directory:"), valueStyle.Render(mirrorTo))
+			fmt.Println()
+
+			client := github.NewClient(config.GetGitHubToken(), config.GitHubAPI)
+			result, err := kernel.Mirror(kernel.MirrorOptions{
+				Dest:         mirrorTo,
+				Versions:     mirrorVersions,
+				Arch:         mirrorArch,
+				SkipExisting: mirrorOffline,
+				StatusCallback: func(msg string) {
+					fmt.Println(subtleStyle.Render("  " + msg))
+				},
+			}, client, config.GlobalPaths)
+			if err != nil {
+				return err
+			}
+
+			if mirrorSign {
+				for _, dir := range result.Dirs {
+					if err := kernel.PrepareMirrorForSigning(dir); err != nil {
+						return fmt.Errorf("failed to generate SHA256SUMS for %s: %w", dir, err)
+					}
+					if err := signing.SignArtifacts(dir, password); err != nil {
+						return fmt.Errorf("failed to sign %s: %w", dir, err)
+					}
+				}
+			}
+
+			fmt.Println()
+			fmt.Printf("%s Mirror updated\n", successStyle.Render("✓"))
+			fmt.Println()
+			for _, entry := range result.Mirrored {
+				fmt.Printf("  %s %s\n", labelStyle.Render("Mirrored:"), valueStyle.Render(entry.Arch+"/"+entry.Version))
+			}
+			for _, entry := range result.Skipped {
+				fmt.Printf("  %s %s\n", labelStyle.Render("Skipped:"), valueStyle.Render(entry.Arch+"/"+entry.Version))
+			}
+			if mirrorSign && len(result.Dirs) > 0 {
+				fmt.Printf("  %s %s\n", labelStyle.Render("Signed:"), valueStyle.Render(fmt.Sprintf("%d version(s)", len(result.Dirs))))
+			}
+			fmt.Println()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&mirrorTo, "to", "", "Archive directory to mirror into (required)")
+	cmd.Flags().IntVarP(&mirrorVersions, "versions", "n", kernel.DefaultMirrorVersions, "Number of latest releases to mirror")
+	cmd.Flags().StringVarP(&mirrorArch, "arch", "a", "all", "Architecture to mirror: x86_64, aarch64, or all")
+	cmd.Flags().BoolVar(&mirrorOffline, "offline", false, "Skip versions already present in the mirror")
+	cmd.Flags().BoolVar(&mirrorSign, "sign", false, "Re-sign mirrored versions with the local signing key")
+	cmd.MarkFlagRequired("to")
+
+	return cmd
+}
@@ -8,16 +8,17 @@ import (
 
 func newRemoveCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "remove [version]",
-		Short: "Remove an installed kernel",
-		Long:  `Remove a locally installed kernel version.`,
+		Use:               "remove [version]",
+		Short:             "Remove an installed kernel",
+		Long:              `Remove a locally installed kernel version.`,
+		ValidArgsFunction: cmdutil.CompleteInstalledVersions("kernel"),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// If no version specified and terminal is interactive, show TUI selector
 			if len(args) == 0 && cmdutil.IsInteractive() {
 				return cmdutil.ShowVersionSelector("kernel")
 			}
 			if len(args) == 0 {
-				return cmd.Usage()
+				return cmdutil.MissingVersionError("kernel")
 			}
 			return cmdutil.DeleteVersion("kernel", args[0])
 		},
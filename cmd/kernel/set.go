@@ -11,25 +11,36 @@ import (
 )
 
 func newSetCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "set [version]",
-		Aliases: []string{"default"},
-		Short:   "Set default kernel version",
-		Long:    `Set a kernel version as the default.`,
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:               "set [version]",
+		Aliases:           []string{"default"},
+		Short:             "Set default kernel version",
+		ValidArgsFunction: cmdutil.CompleteInstalledVersions("kernel"),
+		Long: `Set a kernel version as the default.
+
+A version that only has a kernel for another architecture (e.g. an aarch64
+build on an x86_64 host) is refused, since Firecracker on this host could
+not boot it. Use --force to set it anyway.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// If no version specified and terminal is interactive, show TUI selector
 			if len(args) == 0 && cmdutil.IsInteractive() {
 				return cmdutil.ShowVersionSelector("kernel")
 			}
 			if len(args) == 0 {
-				return cmd.Usage()
+				return cmdutil.MissingVersionError("kernel")
 			}
 			version := args[0]
-			if err := kernel.Set(version, config.GlobalPaths); err != nil {
-				return err
+			if err := kernel.SetWithOptions(version, config.GlobalPaths, kernel.SetOptions{Force: force}); err != nil {
+				return cmdutil.ForceDefaultHint(err)
 			}
 			fmt.Printf("Kernel %s set as default\n", version)
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Set the kernel even if it was built for another architecture")
+
+	return cmd
 }
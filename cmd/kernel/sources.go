@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"fmt"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/kernel"
+	"github.com/Work-Fort/Anvil/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+func newSourcesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sources",
+		Short: "Manage downloaded kernel sources",
+		Long:  `Manage kernel source tarballs and extracted source trees in the build cache.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newSourcesCleanCmd())
+
+	return cmd
+}
+
+func newSourcesCleanCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clean",
+		Short: "Remove kernel source trees and tarballs",
+		Long: `Remove extracted kernel source trees (linux-*) and source tarballs from
+the build cache to reclaim disk space.
+
+Built artifacts and build stats are kept, so cached builds can still be
+installed. Use 'anvil clean build' to remove everything.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := kernel.CleanSources(config.GlobalPaths)
+			if err != nil {
+				return err
+			}
+
+			theme := config.CurrentTheme
+			subtleStyle := theme.SubtleStyle()
+			itemStyle := theme.ErrorStyle()
+
+			fmt.Println()
+			if len(result.Removed) == 0 {
+				fmt.Println(theme.SuccessMessage("No kernel sources"))
+				return nil
+			}
+
+			fmt.Println(theme.SuccessMessage(fmt.Sprintf("Kernel sources cleaned, freed %s", util.FormatSize(result.FreedBytes))))
+			fmt.Println()
+			for _, item := range result.Removed {
+				fmt.Println(subtleStyle.Render("  • ") + itemStyle.Render(item))
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/kernel"
+	"github.com/Work-Fort/Anvil/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+func newStatsCmd() *cobra.Command {
+	var (
+		arch       string
+		outputJSON bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stats <version>",
+		Short: "Show stored build statistics for a kernel",
+		Long: `Show the build statistics recorded for a kernel built with anvil:
+phase timings, artifact sizes and hashes.
+
+The version can be a kernel version (6.18.9) or an installed version
+name (6.18.9-20260101T120000). Stats are looked up in the build artifacts,
+then in installed kernels, then in the kernel archive.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if arch == "" {
+				var err error
+				arch, err = config.GetArch()
+				if err != nil {
+					return err
+				}
+			}
+			if arch != "x86_64" && arch != "aarch64" {
+				return fmt.Errorf("unsupported architecture: %s (supported: x86_64, aarch64)", arch)
+			}
+
+			stored, err := kernel.FindBuildStats(args[0], arch, config.GetKernelsArchiveLocation(), config.GlobalPaths)
+			if err != nil {
+				return err
+			}
+
+			if outputJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(stored)
+			}
+
+			printBuildStats(stored)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&arch, "arch", "a", "", "Kernel architecture: x86_64 or aarch64 (default: host)")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output stats as JSON")
+
+	return cmd
+}
+
+// printBuildStats prints build stats in the same layout as the build
+// wizard's completion screen
+func printBuildStats(stored *kernel.StoredBuildStats) {
+	theme := config.CurrentTheme
+	titleStyle := theme.InfoStyle().Bold(true)
+	valueStyle := theme.InfoStyle()
+	subtleStyle := theme.SubtleStyle()
+	stats := stored.Stats
+
+	fmt.Println()
+	fmt.Printf("%s %s\n", titleStyle.Render("Build stats for "+stats.KernelVersion), subtleStyle.Render(fmt.Sprintf("(%s, from %s)", stored.Arch, stored.Source)))
+	fmt.Println(subtleStyle.Render("  " + stored.Path))
+	if !stats.BuildTimestamp.IsZero() {
+		fmt.Printf("  Built:        %s\n", stats.BuildTimestamp.Format(time.RFC3339))
+	}
+
+	fmt.Println()
+	fmt.Println(titleStyle.Render("Build Timing:"))
+	fmt.Printf("  Total:     %s\n", valueStyle.Render(formatBuildDuration(stats.TotalDuration)))
+	fmt.Printf("  Download:  %s\n", formatBuildDuration(stats.DownloadDuration))
+	fmt.Printf("  Extract:   %s\n", formatBuildDuration(stats.ExtractDuration))
+	fmt.Printf("  Configure: %s\n", formatBuildDuration(stats.ConfigureDuration))
+	fmt.Printf("  Compile:   %s\n", formatBuildDuration(stats.CompileDuration))
+	fmt.Printf("  Package:   %s\n", formatBuildDuration(stats.PackageDuration))
+
+	fmt.Println()
+	fmt.Println(titleStyle.Render("Kernel Artifacts:"))
+	fmt.Printf("  Uncompressed: %s (%s)\n", util.FormatSize(stats.UncompressedSize), valueStyle.Render(stats.OutputPath))
+	fmt.Printf("    SHA256:    %s\n", stats.UncompressedHash)
+	fmt.Printf("  Compressed:   %s (%s)\n", util.FormatSize(stats.CompressedSize), valueStyle.Render(stats.CompressedPath))
+	fmt.Printf("    SHA256:    %s\n", stats.CompressedHash)
+	if stats.UncompressedSize > 0 {
+		fmt.Printf("  Compression:  %.1f%% of original size\n", float64(stats.CompressedSize)/float64(stats.UncompressedSize)*100)
+	}
+	fmt.Println()
+}
+
+// formatBuildDuration formats a phase duration like the build wizard does
+func formatBuildDuration(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return fmt.Sprintf("%dm %ds", int(d.Minutes()), int(d.Seconds())%60)
+}
@@ -28,7 +28,7 @@ If no version is specified, checks the latest stable kernel.`,
 				version = args[0]
 			}
 
-			result, err := kernel.CheckVersion(version)
+			result, err := kernel.CheckVersion(cmd.Context(), version)
 			if err != nil {
 				return err
 			}
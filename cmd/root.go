@@ -20,6 +20,7 @@ import (
 	"github.com/Work-Fort/Anvil/cmd/version"
 	"github.com/Work-Fort/Anvil/cmd/vsock"
 	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/ui"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
@@ -35,9 +36,11 @@ var (
 	// -ldflags "-X github.com/Work-Fort/Anvil/cmd.DisableUpdate=true"
 	DisableUpdate string
 
-	logLevel    string
-	useTUI      bool
-	debugLogger *log.Logger
+	logLevel     string
+	useTUI       bool
+	strictConfig bool
+	assumeYes    bool
+	debugLogger  *log.Logger
 )
 
 var rootCmd = &cobra.Command{
@@ -55,15 +58,18 @@ for organized file storage.`,
 		}
 
 		// Load config files now that directories exist
+		config.SetStrictConfig(strictConfig)
 		if err := config.LoadConfig(); err != nil {
-			return err
+			// Commands that repair config files must run even when the
+			// current files fail validation
+			if cmd.Annotations[config.AnnotationSkipConfigValidation] != "true" {
+				return err
+			}
 		}
 
-		// Apply Omarchy theme if available (silently falls back to defaults)
-		_ = config.LoadOmarchyTheme()
-
 		// Update flag values from Viper (respects config file and env vars)
 		useTUI = config.GetUseTUI()
+		ui.SetAssumeYes(assumeYes || config.GetAssumeYes())
 
 		// Handle disabled logging first
 		if logLevel == "disabled" {
@@ -131,6 +137,8 @@ func init() {
 	// Add global flags
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "l", "debug", "Log level: disabled, debug, info, warn, error")
 	rootCmd.PersistentFlags().BoolVar(&useTUI, "use-tui", true, "Enable terminal UI mode")
+	rootCmd.PersistentFlags().BoolVar(&strictConfig, "strict-config", false, "Fail on unknown keys in config files instead of warning")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "assume-yes", "y", false, "Answer yes to confirmation prompts (typed DELETE prompts still need --force)")
 
 	// Bind flags to Viper for config file and environment variable support
 	config.BindFlags(rootCmd.PersistentFlags())
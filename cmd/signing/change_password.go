@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+package signing
+
+import (
+	"fmt"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/signing"
+	"github.com/Work-Fort/Anvil/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func newChangePasswordCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "change-password",
+		Short: "Change the password on the signing key",
+		Long: `Re-encrypt the private signing key with a new password without regenerating it.
+
+The current password can be provided via:
+  - Interactive prompt (default)
+  - Environment variable: ANVIL_SIGNING_PASSWORD
+  - Stdin (for scripts)
+
+If the key is not encrypted yet, you are asked whether to encrypt it.
+A wrong current password leaves the key untouched.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			theme := config.CurrentTheme
+			subtleStyle := theme.SubtleStyle()
+			successStyle := theme.SuccessStyle()
+
+			encrypted, err := signing.PrivateKeyEncrypted()
+			if err != nil {
+				return err
+			}
+
+			fmt.Println()
+			var oldPassword string
+			if encrypted {
+				fmt.Println(subtleStyle.Render("Changing signing key password..."))
+				fmt.Println()
+
+				oldPassword, err = GetSigningPassword(
+					PasswordSourceAuto,
+					"Enter current password to unlock signing key",
+				)
+				if err != nil {
+					return fmt.Errorf("failed to get password: %w", err)
+				}
+			} else {
+				ok, err := ui.Confirm("Signing key is not encrypted. Encrypt it with a password?")
+				if err != nil {
+					return err
+				}
+				if !ok {
+					fmt.Println(theme.InfoMessage("Signing key left unencrypted"))
+					return nil
+				}
+			}
+
+			newPassword, err := ui.PasswordInputConfirm(
+				"Enter new password to encrypt signing key",
+				"Confirm new password",
+			)
+			if err != nil {
+				return fmt.Errorf("failed to get password: %w", err)
+			}
+			if newPassword == "" {
+				return fmt.Errorf("empty password")
+			}
+
+			if err := signing.ChangePassword(oldPassword, newPassword); err != nil {
+				return fmt.Errorf("failed to change password: %w", err)
+			}
+
+			if encrypted {
+				fmt.Printf("%s Signing key password changed!\n", successStyle.Render("✓"))
+			} else {
+				fmt.Printf("%s Signing key encrypted!\n", successStyle.Render("✓"))
+			}
+			fmt.Println()
+
+			return nil
+		},
+	}
+}
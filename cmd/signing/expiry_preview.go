@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+package signing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/signing"
+	"github.com/spf13/cobra"
+)
+
+func newExpiryPreviewCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "expiry-preview <value>",
+		Short: "Show the expiry date for a key expiry value",
+		Long: `Show the key lifetime and absolute expiry date that an expiry value
+(as used by --expiry and signing.key.expiry) resolves to from now.
+
+Format: 0=never, <n>=days, <n>d=days, <n>w=weeks, <n>m=months (30 days),
+<n>y=years (365 days).`,
+		Example: `  anvil signing expiry-preview 18m
+  anvil signing expiry-preview 2y`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			now := time.Now()
+			preview, err := signing.PreviewExpiry(args[0], now)
+			if err != nil {
+				return err
+			}
+
+			theme := config.CurrentTheme
+			titleStyle := theme.InfoStyle().Bold(true)
+			labelStyle := theme.SubtleStyle()
+			valueStyle := theme.InfoStyle()
+
+			fmt.Println()
+			fmt.Println(titleStyle.Render("Key expiry preview"))
+			fmt.Println()
+			fmt.Printf("  %s %s\n", labelStyle.Render("Value:"), valueStyle.Render(args[0]))
+
+			if preview.Expires.IsZero() {
+				fmt.Printf("  %s %s\n", labelStyle.Render("Lifetime:"), valueStyle.Render("never expires"))
+				fmt.Println()
+				return nil
+			}
+
+			days := int(preview.Lifetime.Hours() / 24)
+			fmt.Printf("  %s %s\n", labelStyle.Render("Lifetime:"), valueStyle.Render(fmt.Sprintf("%d days", days)))
+			fmt.Printf("  %s %s\n", labelStyle.Render("Expires:"), valueStyle.Render(preview.Expires.Format("2006-01-02 15:04 MST")))
+			fmt.Println()
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+package signing
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/signing"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func newExportPublicCmd() *cobra.Command {
+	var (
+		binary    bool
+		keyserver string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export-public",
+		Short: "Export the public signing key",
+		Long: `Write the public signing key to stdout, ASCII-armored by default, so it can
+be piped into a release asset:
+
+  anvil signing export-public > signing-key.asc
+
+With --keyserver the key is uploaded to that keyserver with GPG instead.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if keyserver != "" {
+				return sendPublicKey(keyserver)
+			}
+
+			format := signing.KeyFormatArmored
+			if binary {
+				if term.IsTerminal(int(os.Stdout.Fd())) {
+					return fmt.Errorf("refusing to write a binary key to a terminal; redirect stdout to a file")
+				}
+				format = signing.KeyFormatBinary
+			}
+
+			return signing.ExportPublicKey(os.Stdout, format)
+		},
+	}
+
+	cmd.Flags().BoolVar(&binary, "binary", false, "Write the key in binary format instead of ASCII-armored")
+	cmd.Flags().StringVar(&keyserver, "keyserver", "", "Upload the key to this keyserver (e.g. hkps://keys.openpgp.org)")
+	cmd.MarkFlagsMutuallyExclusive("binary", "keyserver")
+
+	return cmd
+}
+
+func sendPublicKey(keyserver string) error {
+	theme := config.CurrentTheme
+	subtleStyle := theme.SubtleStyle()
+	successStyle := theme.SuccessStyle()
+	labelStyle := theme.SubtleStyle()
+	valueStyle := theme.InfoStyle()
+
+	fmt.Println()
+	fmt.Println(subtleStyle.Render("Sending public key to keyserver..."))
+	fmt.Printf("  %s %s\n", labelStyle.Render("Keyserver:"), valueStyle.Render(keyserver))
+	fmt.Println()
+
+	if err := signing.SendPublicKey(keyserver); err != nil {
+		return fmt.Errorf("failed to send public key: %w", err)
+	}
+
+	fmt.Printf("%s Public key sent!\n", successStyle.Render("✓"))
+	fmt.Println()
+
+	return nil
+}
@@ -11,7 +11,7 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func newGenerateCmd(keyName, keyEmail, keyExpiry, keyFormat *string) *cobra.Command {
+func newGenerateCmd(keyName, keyEmail, keyExpiry, keyFormat, keyAlgo *string) *cobra.Command {
 	return &cobra.Command{
 		Use:   "generate",
 		Short: "Generate a new PGP signing key",
@@ -55,6 +55,10 @@ Options can be set via flags or will use defaults.`,
 			if cmd.Flags().Changed("format") {
 				fmtStr = *keyFormat
 			}
+			algorithm := config.GetSigningKeyAlgorithm()
+			if cmd.Flags().Changed("algorithm") {
+				algorithm = *keyAlgo
+			}
 
 			// Parse format
 			format := signing.KeyFormatArmored
@@ -76,11 +80,12 @@ Options can be set via flags or will use defaults.`,
 			}
 
 			opts := signing.GenerateKeyOptions{
-				Name:     name,
-				Email:    email,
-				Expiry:   expiry,
-				Format:   format,
-				Password: password,
+				Name:      name,
+				Email:     email,
+				Expiry:    expiry,
+				Format:    format,
+				Algorithm: algorithm,
+				Password:  password,
 			}
 
 			fmt.Println()
@@ -88,6 +93,7 @@ Options can be set via flags or will use defaults.`,
 			fmt.Printf("  %s %s\n", labelStyle.Render("Name:"), valueStyle.Render(name))
 			fmt.Printf("  %s %s\n", labelStyle.Render("Email:"), valueStyle.Render(email))
 			fmt.Printf("  %s %s\n", labelStyle.Render("Expiry:"), valueStyle.Render(expiry))
+			fmt.Printf("  %s %s\n", labelStyle.Render("Algorithm:"), valueStyle.Render(algorithm))
 			fmt.Println()
 
 			keyInfo, err := signing.GenerateKey(opts)
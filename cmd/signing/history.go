@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+package signing
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/signing"
+	"github.com/spf13/cobra"
+)
+
+func newHistoryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "history",
+		Short: "List historical public signing keys",
+		Long: `List every public key saved to the signing key history, oldest first,
+with its validity window. The current signing key is marked.
+
+To check an old release, verify it against the key that was valid when it
+was signed:
+
+  anvil signing verify <artifacts-dir> --key <history-file>`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			theme := config.CurrentTheme
+			titleStyle := theme.InfoStyle().Bold(true)
+			subtleStyle := theme.SubtleStyle()
+
+			history, err := signing.ListHistory()
+			if err != nil {
+				return fmt.Errorf("failed to list key history: %w", err)
+			}
+			current, err := signing.ListKeys()
+			if err != nil {
+				return fmt.Errorf("failed to list keys: %w", err)
+			}
+
+			fmt.Println()
+			fmt.Println(titleStyle.Render("Signing key history"))
+			fmt.Println()
+
+			if len(history) == 0 {
+				fmt.Println(subtleStyle.Render("  No historical keys found"))
+				fmt.Println()
+				return nil
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "  KEY ID\tCREATED\tEXPIRES\tFILE")
+			for _, key := range history {
+				keyID := key.KeyID
+				if len(current) > 0 && key.Fingerprint == current[0].Fingerprint {
+					keyID += " (current)"
+				}
+				expires := "never"
+				if !key.Expires.IsZero() {
+					expires = key.Expires.Format("2006-01-02")
+				}
+				fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", keyID, key.Created.Format("2006-01-02"), expires, key.Path)
+			}
+			tw.Flush()
+			fmt.Println()
+
+			return nil
+		},
+	}
+}
@@ -4,6 +4,7 @@ package signing
 import (
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/Work-Fort/Anvil/pkg/config"
 	"github.com/Work-Fort/Anvil/pkg/signing"
@@ -11,8 +12,13 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func newRotateCmd(keyName, keyEmail, keyExpiry, keyFormat *string) *cobra.Command {
-	return &cobra.Command{
+func newRotateCmd(keyName, keyEmail, keyExpiry, keyFormat, keyAlgo *string) *cobra.Command {
+	var (
+		ifExpiring bool
+		threshold  int
+	)
+
+	cmd := &cobra.Command{
 		Use:   "rotate",
 		Short: "Rotate the signing key",
 		Long: `Rotate the PGP signing key by generating a new key and backing up the old one.
@@ -23,7 +29,10 @@ This:
   - Updates the current signing keys
 
 You will be prompted to enter a password for the new signing key.
-The old key is backed up (encrypted) but no longer used for signing new releases.`,
+The old key is backed up (encrypted) but no longer used for signing new releases.
+
+With --if-expiring the key is only rotated if it has expired or expires within
+--threshold days, so the command can run unattended (e.g. from cron or CI).`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			theme := config.CurrentTheme
 			subtleStyle := theme.SubtleStyle()
@@ -50,6 +59,10 @@ The old key is backed up (encrypted) but no longer used for signing new releases
 			if cmd.Flags().Changed("format") {
 				fmtStr = *keyFormat
 			}
+			algorithm := config.GetSigningKeyAlgorithm()
+			if cmd.Flags().Changed("algorithm") {
+				algorithm = *keyAlgo
+			}
 
 			// Parse format
 			format := signing.KeyFormatArmored
@@ -57,6 +70,18 @@ The old key is backed up (encrypted) but no longer used for signing new releases
 				format = signing.KeyFormatBinary
 			}
 
+			// Check before prompting for a password that may not be needed
+			if ifExpiring {
+				expiring, err := keyExpiresWithin(threshold)
+				if err != nil {
+					return err
+				}
+				if !expiring {
+					fmt.Printf("%s Signing key does not expire within %d days, not rotating\n", successStyle.Render("✓"), threshold)
+					return nil
+				}
+			}
+
 			// Get password for new key encryption if enabled
 			var password string
 			var err error
@@ -71,18 +96,29 @@ The old key is backed up (encrypted) but no longer used for signing new releases
 			}
 
 			opts := signing.GenerateKeyOptions{
-				Name:     name,
-				Email:    email,
-				Expiry:   expiry,
-				Format:   format,
-				Password: password,
+				Name:      name,
+				Email:     email,
+				Expiry:    expiry,
+				Format:    format,
+				Algorithm: algorithm,
+				Password:  password,
 			}
 
 			fmt.Println()
 			fmt.Println(subtleStyle.Render("Rotating PGP signing key..."))
 			fmt.Println()
 
-			keyInfo, err := signing.RotateKey(opts)
+			var keyInfo *signing.KeyInfo
+			if ifExpiring {
+				var rotated bool
+				keyInfo, rotated, err = signing.RotateIfExpiring(opts, threshold)
+				if err == nil && !rotated {
+					fmt.Printf("%s Signing key does not expire within %d days, not rotating\n", successStyle.Render("✓"), threshold)
+					return nil
+				}
+			} else {
+				keyInfo, err = signing.RotateKey(opts)
+			}
 			if err != nil {
 				return fmt.Errorf("failed to rotate key: %w", err)
 			}
@@ -99,4 +135,23 @@ The old key is backed up (encrypted) but no longer used for signing new releases
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&ifExpiring, "if-expiring", false, "Only rotate if the key has expired or expires within --threshold days")
+	cmd.Flags().IntVar(&threshold, "threshold", 60, "Days before expiry at which --if-expiring rotates the key")
+
+	return cmd
+}
+
+// keyExpiresWithin reports whether the signing key has expired or expires
+// within days
+func keyExpiresWithin(days int) (bool, error) {
+	keys, err := signing.ListKeys()
+	if err != nil {
+		return false, fmt.Errorf("failed to list keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return false, fmt.Errorf("no signing key found")
+	}
+	expires := keys[0].Expires
+	return !expires.IsZero() && time.Until(expires) <= time.Duration(days)*24*time.Hour, nil
 }
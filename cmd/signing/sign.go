@@ -3,26 +3,41 @@ package signing
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/Work-Fort/Anvil/pkg/config"
 	"github.com/Work-Fort/Anvil/pkg/signing"
+	"github.com/Work-Fort/Anvil/pkg/util"
 	"github.com/spf13/cobra"
 )
 
 func newSignCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "sign [artifacts-dir]",
-		Short: "Sign release artifacts",
+		Use:   "sign <artifacts-dir|file>",
+		Short: "Sign release artifacts or a single file",
 		Long: `Sign the SHA256SUMS file in the artifacts directory using the current signing key.
+SHA512SUMS is signed as well when present.
 
-If the signing key is encrypted, you will be prompted to enter the password.
+When given a file instead of a directory, that file is signed and a detached
+signature is written to <file>.asc.
+
+With signing.backend set to gpg-agent, gpg signs with the key matching the
+public signing key, so the private key can stay on a smartcard.
+
+Otherwise, if the signing key is encrypted, you will be prompted to enter the password.
 The password can be provided via:
   - Interactive prompt (default)
   - Environment variable: ANVIL_SIGNING_PASSWORD
   - Stdin (for scripts)`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			artifactsDir := args[0]
+			target := args[0]
+
+			info, err := os.Stat(target)
+			if err != nil {
+				return err
+			}
 
 			theme := config.CurrentTheme
 			subtleStyle := theme.SubtleStyle()
@@ -31,26 +46,52 @@ The password can be provided via:
 			valueStyle := theme.InfoStyle()
 
 			fmt.Println()
-			fmt.Println(subtleStyle.Render("Signing artifacts..."))
-			fmt.Printf("  %s %s\n", labelStyle.Render("Directory:"), valueStyle.Render(artifactsDir))
+			if info.IsDir() {
+				fmt.Println(subtleStyle.Render("Signing artifacts..."))
+				fmt.Printf("  %s %s\n", labelStyle.Render("Directory:"), valueStyle.Render(target))
+			} else {
+				fmt.Println(subtleStyle.Render("Signing file..."))
+				fmt.Printf("  %s %s\n", labelStyle.Render("File:"), valueStyle.Render(target))
+			}
 			fmt.Println()
 
-			// Acquire password at the CLI layer (interface concern)
-			password, err := GetSigningPassword(
-				PasswordSourceAuto,
-				"Enter password to unlock signing key",
-			)
-			if err != nil {
-				return fmt.Errorf("failed to get password: %w", err)
+			// Acquire password at the CLI layer (interface concern);
+			// the gpg-agent backend asks for its own PIN via pinentry
+			var password string
+			if signing.UsesPassword() {
+				password, err = GetSigningPassword(
+					PasswordSourceAuto,
+					"Enter password to unlock signing key",
+				)
+				if err != nil {
+					return fmt.Errorf("failed to get password: %w", err)
+				}
 			}
 
-			if err := signing.SignArtifacts(artifactsDir, password); err != nil {
+			if !info.IsDir() {
+				if err := signing.SignFile(target, signing.KeyFormatArmored, password); err != nil {
+					return fmt.Errorf("failed to sign file: %w", err)
+				}
+
+				fmt.Printf("%s File signed successfully!\n", successStyle.Render("✓"))
+				fmt.Println()
+				fmt.Printf("  %s %s\n", labelStyle.Render("Signature:"), valueStyle.Render(target+".asc"))
+				fmt.Println()
+				return nil
+			}
+
+			if err := signing.SignArtifacts(target, password); err != nil {
 				return fmt.Errorf("failed to sign artifacts: %w", err)
 			}
 
 			fmt.Printf("%s Artifacts signed successfully!\n", successStyle.Render("✓"))
 			fmt.Println()
-			fmt.Printf("  %s %s\n", labelStyle.Render("Signature:"), valueStyle.Render(artifactsDir+"/SHA256SUMS.asc"))
+			for _, algo := range util.ChecksumAlgorithms {
+				sigPath := filepath.Join(target, util.SumsFileName(algo)+".asc")
+				if _, err := os.Stat(sigPath); err == nil {
+					fmt.Printf("  %s %s\n", labelStyle.Render("Signature:"), valueStyle.Render(sigPath))
+				}
+			}
 			fmt.Println()
 
 			return nil
@@ -14,6 +14,7 @@ func NewSigningCmd() *cobra.Command {
 		keyEmail  string
 		keyExpiry string
 		keyFormat string // "armored" or "binary"
+		keyAlgo   string // "rsa4096" or "ed25519"
 	)
 
 	cmd := &cobra.Command{
@@ -27,30 +28,36 @@ func NewSigningCmd() *cobra.Command {
 	}
 
 	// Create subcommands
-	generateCmd := newGenerateCmd(&keyName, &keyEmail, &keyExpiry, &keyFormat)
-	rotateCmd := newRotateCmd(&keyName, &keyEmail, &keyExpiry, &keyFormat)
+	generateCmd := newGenerateCmd(&keyName, &keyEmail, &keyExpiry, &keyFormat, &keyAlgo)
+	rotateCmd := newRotateCmd(&keyName, &keyEmail, &keyExpiry, &keyFormat, &keyAlgo)
 
 	// Add flags to generate and rotate commands (defaults from config)
 	generateCmd.Flags().StringVar(&keyName, "name", config.GetSigningKeyName(), "Key owner name")
 	generateCmd.Flags().StringVar(&keyEmail, "email", config.GetSigningKeyEmail(), "Key email")
 	generateCmd.Flags().StringVar(&keyExpiry, "expiry", config.GetSigningKeyExpiry(), "Key expiration (0=never, <n>=days, <n>w=weeks, <n>m=months, <n>y=years)")
 	generateCmd.Flags().StringVar(&keyFormat, "format", config.GetSigningKeyFormat(), "Key format: armored (ASCII .asc) or binary (.gpg)")
+	generateCmd.Flags().StringVar(&keyAlgo, "algorithm", config.GetSigningKeyAlgorithm(), "Key algorithm: rsa4096 or ed25519")
 
 	rotateCmd.Flags().StringVar(&keyName, "name", config.GetSigningKeyName(), "Key owner name")
 	rotateCmd.Flags().StringVar(&keyEmail, "email", config.GetSigningKeyEmail(), "Key email")
 	rotateCmd.Flags().StringVar(&keyExpiry, "expiry", config.GetSigningKeyExpiry(), "Key expiration (0=never, <n>=days, <n>w=weeks, <n>m=months, <n>y=years)")
 	rotateCmd.Flags().StringVar(&keyFormat, "format", config.GetSigningKeyFormat(), "Key format: armored (ASCII .asc) or binary (.gpg)")
+	rotateCmd.Flags().StringVar(&keyAlgo, "algorithm", config.GetSigningKeyAlgorithm(), "Key algorithm: rsa4096 or ed25519")
 
 	// Add all subcommands
 	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newHistoryCmd())
 	cmd.AddCommand(generateCmd)
 	cmd.AddCommand(rotateCmd)
 	cmd.AddCommand(newSignCmd())
 	cmd.AddCommand(newVerifyCmd())
 	cmd.AddCommand(newExportCmd())
+	cmd.AddCommand(newExportPublicCmd())
 	cmd.AddCommand(newImportKeyCmd())
 	cmd.AddCommand(newImportCmd())
+	cmd.AddCommand(newChangePasswordCmd())
 	cmd.AddCommand(newCheckExpiryCmd())
+	cmd.AddCommand(newExpiryPreviewCmd())
 	cmd.AddCommand(newRemoveCmd())
 
 	return cmd
@@ -3,6 +3,7 @@ package signing
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/Work-Fort/Anvil/pkg/config"
 	"github.com/Work-Fort/Anvil/pkg/signing"
@@ -10,13 +11,35 @@ import (
 )
 
 func newVerifyCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "verify [artifacts-dir]",
-		Short: "Verify release artifacts signature",
-		Long:  `Verify the PGP signature on SHA256SUMS in the artifacts directory.`,
-		Args:  cobra.ExactArgs(1),
+	var (
+		keyPaths    []string
+		withHistory bool
+		sigPath     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "verify <artifacts-dir|file>",
+		Short: "Verify release artifacts or a single file signature",
+		Long: `Verify the PGP signatures on SHA256SUMS (and SHA512SUMS, if present) in the artifacts directory.
+
+When given a file instead of a directory, its detached signature (<file>.asc,
+or --signature) is verified.
+
+Use --key (repeatable) to verify against other public keys than the current
+signing key, or --history to trust every key in the signing key history, e.g.
+while a release signed by a rotated key is checked against the old one.
+Verification succeeds if any trusted key produced the signature.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			artifactsDir := args[0]
+			target := args[0]
+
+			info, err := os.Stat(target)
+			if err != nil {
+				return err
+			}
+			if info.IsDir() && sigPath != "" {
+				return fmt.Errorf("--signature only applies when verifying a single file")
+			}
 
 			theme := config.CurrentTheme
 			subtleStyle := theme.SubtleStyle()
@@ -25,12 +48,33 @@ func newVerifyCmd() *cobra.Command {
 			valueStyle := theme.InfoStyle()
 
 			fmt.Println()
-			fmt.Println(subtleStyle.Render("Verifying artifacts signature..."))
-			fmt.Printf("  %s %s\n", labelStyle.Render("Directory:"), valueStyle.Render(artifactsDir))
+			if info.IsDir() {
+				fmt.Println(subtleStyle.Render("Verifying artifacts signature..."))
+				fmt.Printf("  %s %s\n", labelStyle.Render("Directory:"), valueStyle.Render(target))
+			} else {
+				fmt.Println(subtleStyle.Render("Verifying file signature..."))
+				fmt.Printf("  %s %s\n", labelStyle.Render("File:"), valueStyle.Render(target))
+			}
+			if withHistory {
+				historyKeys, err := signing.HistoryKeyPaths()
+				if err != nil {
+					return err
+				}
+				keyPaths = append(keyPaths, historyKeys...)
+			}
+			for _, keyPath := range keyPaths {
+				fmt.Printf("  %s %s\n", labelStyle.Render("Key:"), valueStyle.Render(keyPath))
+			}
 			fmt.Println()
 
-			if err := signing.VerifyArtifacts(artifactsDir); err != nil {
-				return fmt.Errorf("failed to verify artifacts: %w", err)
+			if info.IsDir() {
+				if err := signing.VerifyArtifactsWithKeys(target, keyPaths); err != nil {
+					return fmt.Errorf("failed to verify artifacts: %w", err)
+				}
+			} else {
+				if err := signing.VerifyFileWithKeys(target, sigPath, keyPaths); err != nil {
+					return fmt.Errorf("failed to verify file: %w", err)
+				}
 			}
 
 			fmt.Printf("%s Signature verified!\n", successStyle.Render("✓"))
@@ -39,4 +83,10 @@ func newVerifyCmd() *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().StringArrayVar(&keyPaths, "key", nil, "Trusted public key to verify against, repeatable (default: current signing key)")
+	cmd.Flags().BoolVar(&withHistory, "history", false, "Trust every public key in the signing key history")
+	cmd.Flags().StringVar(&sigPath, "signature", "", "Detached signature file (default: <file>.asc)")
+
+	return cmd
 }
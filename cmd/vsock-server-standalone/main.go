@@ -4,14 +4,23 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"math"
 	"net"
 	"os"
+	"os/exec"
 	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/mdlayher/vsock"
 )
@@ -24,22 +33,66 @@ type JSONRPCRequest struct {
 }
 
 type JSONRPCResponse struct {
-	JSONRPC string      `json:"jsonrpc"`
-	Result  interface{} `json:"result,omitempty"`
-	Error   interface{} `json:"error,omitempty"`
-	ID      interface{} `json:"id"`
+	JSONRPC string        `json:"jsonrpc"`
+	Result  interface{}   `json:"result,omitempty"`
+	Error   *JSONRPCError `json:"error,omitempty"`
+	ID      interface{}   `json:"id"`
 }
 
+type JSONRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// JSON-RPC 2.0 error codes
+const (
+	errCodeMethodNotFound = -32601
+	errCodeInvalidParams  = -32602
+	errCodeInternalError  = -32603
+)
+
+// defaultPort is used when neither -port nor a port env var is set
+const defaultPort = 8000
+
+// defaultMaxConns is how many connections are served at once by default
+const defaultMaxConns = 64
+
+// defaultExecTimeout bounds exec requests that don't set a timeout, unless
+// ANVIL_VSOCK_EXEC_TIMEOUT (seconds) overrides it
+const defaultExecTimeout = 60 * time.Second
+
 func main() {
 	logger := log.New(os.Stderr, "[vsock-server] ", log.LstdFlags)
 
-	listener, err := vsock.Listen(8000, nil)
+	portFlag := flag.Uint("port", 0, "vsock port to listen on (default: ANVIL_VSOCK_PORT, VSOCK_PORT or 8000)")
+	maxConns := flag.Int("max-conns", defaultMaxConns, "maximum connections served at once; more are rejected")
+	flag.Parse()
+
+	port, err := listenPort(*portFlag, os.Getenv)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	if *maxConns < 1 {
+		logger.Fatalf("Invalid -max-conns: %d", *maxConns)
+	}
+
+	execTimeout := defaultExecTimeout
+	if value := os.Getenv("ANVIL_VSOCK_EXEC_TIMEOUT"); value != "" {
+		seconds, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || seconds == 0 {
+			logger.Fatalf("Invalid ANVIL_VSOCK_EXEC_TIMEOUT: %q", value)
+		}
+		execTimeout = time.Duration(seconds) * time.Second
+	}
+	methods := newMethods(execTimeout)
+
+	listener, err := vsock.Listen(port, nil)
 	if err != nil {
 		logger.Fatalf("Failed to create vsock listener: %v", err)
 	}
 	defer listener.Close()
 
-	logger.Printf("vsock server listening on port 8000")
+	logger.Printf("vsock server listening on port %d (max %d connections)", port, *maxConns)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -54,6 +107,10 @@ func main() {
 		listener.Close()
 	}()
 
+	// Each served connection holds a slot; when all are taken, new
+	// connections are closed straight away rather than queued
+	slots := make(chan struct{}, *maxConns)
+
 	// Accept connections
 	for {
 		select {
@@ -71,19 +128,78 @@ func main() {
 			continue
 		}
 
+		select {
+		case slots <- struct{}{}:
+		default:
+			logger.Printf("Rejecting connection from %s: limit of %d connections reached", conn.RemoteAddr(), *maxConns)
+			conn.Close()
+			continue
+		}
+
 		logger.Printf("Accepted connection from %s", conn.RemoteAddr())
-		go handleConnection(conn, logger)
+		go func() {
+			defer func() { <-slots }()
+			handleConnection(conn, methods, logger)
+		}()
 	}
 }
 
-type PingParams struct {
-	Message string `json:"message"`
+// listenPort returns the port to listen on: the -port flag if set, else
+// ANVIL_VSOCK_PORT (passed by the rootfs init script), else VSOCK_PORT,
+// else the default
+func listenPort(flagPort uint, getenv func(string) string) (uint32, error) {
+	if flagPort != 0 {
+		if flagPort > math.MaxUint32 {
+			return 0, fmt.Errorf("invalid -port: %d", flagPort)
+		}
+		return uint32(flagPort), nil
+	}
+	for _, name := range []string{"ANVIL_VSOCK_PORT", "VSOCK_PORT"} {
+		value := getenv(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || parsed == 0 {
+			return 0, fmt.Errorf("invalid %s: %q", name, value)
+		}
+		return uint32(parsed), nil
+	}
+	return defaultPort, nil
+}
+
+// methodHandler runs one JSON-RPC method and returns its result, or an
+// error to send back to the client. ctx is cancelled when the client
+// disconnects.
+type methodHandler func(ctx context.Context, params json.RawMessage, logger *log.Logger) (interface{}, *JSONRPCError)
+
+// newMethods returns the supported JSON-RPC methods by name
+func newMethods(execTimeout time.Duration) map[string]methodHandler {
+	return map[string]methodHandler{
+		"ping": handlePing,
+		"exec": func(ctx context.Context, params json.RawMessage, logger *log.Logger) (interface{}, *JSONRPCError) {
+			return handleExec(ctx, params, execTimeout, logger)
+		},
+		"info": handleInfo,
+	}
 }
 
-func handleConnection(conn net.Conn, logger *log.Logger) {
-	defer conn.Close()
+// handleConnection reads line-delimited requests from conn and runs each
+// in its own goroutine, so a slow exec doesn't hold up later requests.
+// Responses may be sent out of order; clients match them by id. Writes are
+// serialized so responses never interleave. When the client disconnects,
+// in-flight handlers are cancelled and waited for before conn is closed.
+func handleConnection(conn net.Conn, methods map[string]methodHandler, logger *log.Logger) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+		conn.Close()
+	}()
 
 	scanner := bufio.NewScanner(conn)
+	var writeMu sync.Mutex
 	encoder := json.NewEncoder(conn)
 
 	for scanner.Scan() {
@@ -95,38 +211,154 @@ func handleConnection(conn net.Conn, logger *log.Logger) {
 
 		logger.Printf("Received request: method=%s id=%v", req.Method, req.ID)
 
-		var response JSONRPCResponse
-		response.JSONRPC = "2.0"
-		response.ID = req.ID
-
-		if req.Method == "ping" {
-			// Parse ping params to get the message
-			var params PingParams
-			if err := json.Unmarshal(req.Params, &params); err != nil {
-				logger.Printf("Failed to parse ping params: %v", err)
-				response.Error = map[string]interface{}{
-					"code":    -32602,
-					"message": "Invalid params",
-				}
-			} else {
-				// Echo back the message
-				response.Result = map[string]string{"message": params.Message}
-				logger.Printf("Echoing message: %s", params.Message)
-			}
-		} else {
-			response.Error = map[string]interface{}{
-				"code":    -32601,
-				"message": fmt.Sprintf("Method not found: %s", req.Method),
-			}
-		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response := dispatch(ctx, methods, req, logger)
 
-		if err := encoder.Encode(response); err != nil {
-			logger.Printf("Failed to send response: %v", err)
-			return
-		}
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			if err := encoder.Encode(response); err != nil {
+				logger.Printf("Failed to send response for id=%v: %v", req.ID, err)
+			}
+		}()
 	}
 
 	if err := scanner.Err(); err != nil {
 		logger.Printf("Connection error: %v", err)
 	}
 }
+
+// dispatch runs the handler for req.Method and wraps its outcome in a
+// response
+func dispatch(ctx context.Context, methods map[string]methodHandler, req JSONRPCRequest, logger *log.Logger) JSONRPCResponse {
+	response := JSONRPCResponse{JSONRPC: "2.0", ID: req.ID}
+
+	handler, ok := methods[req.Method]
+	if !ok {
+		response.Error = &JSONRPCError{
+			Code:    errCodeMethodNotFound,
+			Message: fmt.Sprintf("Method not found: %s", req.Method),
+		}
+		return response
+	}
+
+	result, rpcErr := handler(ctx, req.Params, logger)
+	if rpcErr != nil {
+		response.Error = rpcErr
+	} else {
+		response.Result = result
+	}
+	return response
+}
+
+type PingParams struct {
+	Message string `json:"message"`
+}
+
+func handlePing(_ context.Context, params json.RawMessage, logger *log.Logger) (interface{}, *JSONRPCError) {
+	// Parse ping params to get the message
+	var p PingParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		logger.Printf("Failed to parse ping params: %v", err)
+		return nil, &JSONRPCError{Code: errCodeInvalidParams, Message: "Invalid params"}
+	}
+
+	// Echo back the message
+	logger.Printf("Echoing message: %s", p.Message)
+	return map[string]string{"message": p.Message}, nil
+}
+
+type ExecParams struct {
+	Cmd     string   `json:"cmd"`
+	Args    []string `json:"args,omitempty"`
+	Timeout int      `json:"timeout,omitempty"` // Seconds (default: server exec timeout)
+}
+
+type ExecResult struct {
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exit_code"`
+	TimedOut bool   `json:"timed_out,omitempty"`
+}
+
+// handleExec runs a command in the guest and returns its output and exit
+// code. A command that can't be started is an internal error; one that
+// exits non-zero or is killed on timeout is a normal result. The command is
+// also killed when ctx is cancelled (the client disconnected).
+func handleExec(ctx context.Context, params json.RawMessage, defaultTimeout time.Duration, logger *log.Logger) (interface{}, *JSONRPCError) {
+	var p ExecParams
+	if err := json.Unmarshal(params, &p); err != nil || p.Cmd == "" {
+		return nil, &JSONRPCError{Code: errCodeInvalidParams, Message: "Invalid params: cmd is required"}
+	}
+	if p.Timeout < 0 {
+		return nil, &JSONRPCError{Code: errCodeInvalidParams, Message: "Invalid params: timeout must not be negative"}
+	}
+
+	timeout := defaultTimeout
+	if p.Timeout > 0 {
+		timeout = time.Duration(p.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	logger.Printf("Executing %s %s (timeout %s)", p.Cmd, strings.Join(p.Args, " "), timeout)
+	cmd := exec.CommandContext(ctx, p.Cmd, p.Args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	result := ExecResult{Stdout: stdout.String(), Stderr: stderr.String()}
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case ctx.Err() == context.DeadlineExceeded:
+		result.ExitCode = -1
+		result.TimedOut = true
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		logger.Printf("Failed to start %s: %v", p.Cmd, err)
+		return nil, &JSONRPCError{Code: errCodeInternalError, Message: fmt.Sprintf("Failed to run %s: %v", p.Cmd, err)}
+	}
+
+	logger.Printf("%s exited with code %d", p.Cmd, result.ExitCode)
+	return result, nil
+}
+
+type InfoResult struct {
+	KernelVersion string  `json:"kernel_version"`
+	UptimeSeconds float64 `json:"uptime_seconds"`
+}
+
+// handleInfo reports the guest kernel version and uptime from /proc
+func handleInfo(_ context.Context, _ json.RawMessage, logger *log.Logger) (interface{}, *JSONRPCError) {
+	release, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	if err != nil {
+		logger.Printf("Failed to read kernel version: %v", err)
+		return nil, &JSONRPCError{Code: errCodeInternalError, Message: "Failed to read kernel version"}
+	}
+
+	uptime, err := readUptime("/proc/uptime")
+	if err != nil {
+		logger.Printf("Failed to read uptime: %v", err)
+		return nil, &JSONRPCError{Code: errCodeInternalError, Message: "Failed to read uptime"}
+	}
+
+	return InfoResult{KernelVersion: strings.TrimSpace(string(release)), UptimeSeconds: uptime}, nil
+}
+
+// readUptime returns the first field of /proc/uptime: seconds since boot
+func readUptime(path string) (float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty %s", path)
+	}
+	return strconv.ParseFloat(fields[0], 64)
+}
@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var testLogger = log.New(io.Discard, "", 0)
+
+func call(t *testing.T, method, params string) JSONRPCResponse {
+	t.Helper()
+	req := JSONRPCRequest{JSONRPC: "2.0", Method: method, ID: 1}
+	if params != "" {
+		req.Params = json.RawMessage(params)
+	}
+	return dispatch(context.Background(), newMethods(5*time.Second), req, testLogger)
+}
+
+func TestDispatchPing(t *testing.T) {
+	resp := call(t, "ping", `{"message":"hello"}`)
+	if resp.Error != nil {
+		t.Fatalf("ping error = %+v", resp.Error)
+	}
+	if got := resp.Result.(map[string]string)["message"]; got != "hello" {
+		t.Errorf("ping result = %q, want hello", got)
+	}
+}
+
+func TestDispatchUnknownMethod(t *testing.T) {
+	resp := call(t, "reboot", "")
+	if resp.Error == nil || resp.Error.Code != errCodeMethodNotFound {
+		t.Errorf("unknown method error = %+v, want code %d", resp.Error, errCodeMethodNotFound)
+	}
+}
+
+func TestExec(t *testing.T) {
+	resp := call(t, "exec", `{"cmd":"sh","args":["-c","echo out; echo err >&2; exit 3"]}`)
+	if resp.Error != nil {
+		t.Fatalf("exec error = %+v", resp.Error)
+	}
+	result := resp.Result.(ExecResult)
+	if result.Stdout != "out\n" || result.Stderr != "err\n" || result.ExitCode != 3 || result.TimedOut {
+		t.Errorf("exec result = %+v", result)
+	}
+}
+
+func TestExecTimeout(t *testing.T) {
+	resp := dispatch(context.Background(), newMethods(100*time.Millisecond), JSONRPCRequest{
+		Method: "exec",
+		Params: json.RawMessage(`{"cmd":"sleep","args":["10"]}`),
+	}, testLogger)
+	if resp.Error != nil {
+		t.Fatalf("exec error = %+v", resp.Error)
+	}
+	if result := resp.Result.(ExecResult); !result.TimedOut || result.ExitCode != -1 {
+		t.Errorf("exec result = %+v, want timed out", result)
+	}
+}
+
+func TestExecErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		params string
+		code   int
+	}{
+		{"missing cmd", `{"args":["x"]}`, errCodeInvalidParams},
+		{"negative timeout", `{"cmd":"true","timeout":-1}`, errCodeInvalidParams},
+		{"spawn failure", `{"cmd":"/nonexistent/binary"}`, errCodeInternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := call(t, "exec", tt.params)
+			if resp.Error == nil || resp.Error.Code != tt.code {
+				t.Errorf("exec error = %+v, want code %d", resp.Error, tt.code)
+			}
+		})
+	}
+}
+
+func TestReadUptime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uptime")
+	if err := os.WriteFile(path, []byte("1234.56 789.01\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readUptime(path)
+	if err != nil || got != 1234.56 {
+		t.Errorf("readUptime() = %v, %v, want 1234.56", got, err)
+	}
+}
+
+func TestListenPort(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+
+	tests := []struct {
+		name    string
+		flag    uint
+		vars    map[string]string
+		want    uint32
+		wantErr bool
+	}{
+		{name: "default", want: defaultPort},
+		{name: "flag", flag: 9000, vars: map[string]string{"ANVIL_VSOCK_PORT": "8100"}, want: 9000},
+		{name: "anvil env", vars: map[string]string{"ANVIL_VSOCK_PORT": "8100", "VSOCK_PORT": "8200"}, want: 8100},
+		{name: "vsock env", vars: map[string]string{"VSOCK_PORT": "8200"}, want: 8200},
+		{name: "invalid env", vars: map[string]string{"VSOCK_PORT": "http"}, wantErr: true},
+		{name: "zero env", vars: map[string]string{"ANVIL_VSOCK_PORT": "0"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := listenPort(tt.flag, env(tt.vars))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("listenPort() = %d, want error", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("listenPort() = %d, %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleConnectionConcurrent(t *testing.T) {
+	server, client := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		handleConnection(server, newMethods(5*time.Second), testLogger)
+		close(done)
+	}()
+
+	// A slow exec, a malformed frame and a ping: the ping must be answered
+	// first and the bad frame must not drop the connection
+	requests := []string{
+		`{"jsonrpc":"2.0","method":"exec","params":{"cmd":"sleep","args":["0.5"]},"id":"slow"}`,
+		`{not json`,
+		`{"jsonrpc":"2.0","method":"ping","params":{"message":"hi"},"id":"fast"}`,
+	}
+	go func() {
+		for _, r := range requests {
+			client.Write([]byte(r + "\n"))
+		}
+	}()
+
+	scanner := bufio.NewScanner(client)
+	var ids []string
+	for len(ids) < 2 && scanner.Scan() {
+		var resp struct {
+			ID    string        `json:"id"`
+			Error *JSONRPCError `json:"error"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("bad response %q: %v", scanner.Text(), err)
+		}
+		if resp.Error != nil {
+			t.Errorf("response %s error = %+v", resp.ID, resp.Error)
+		}
+		ids = append(ids, resp.ID)
+	}
+	if len(ids) != 2 || ids[0] != "fast" || ids[1] != "slow" {
+		t.Errorf("response order = %v, want [fast slow]", ids)
+	}
+
+	client.Close()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("handleConnection did not return after the client disconnected")
+	}
+}
+
+func TestHandleConnectionCancelsOnDisconnect(t *testing.T) {
+	server, client := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		handleConnection(server, newMethods(time.Minute), testLogger)
+		close(done)
+	}()
+
+	client.Write([]byte(`{"jsonrpc":"2.0","method":"exec","params":{"cmd":"sleep","args":["30"]},"id":1}` + "\n"))
+	client.Close()
+
+	// The in-flight exec is killed rather than running for its timeout
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("in-flight exec was not cancelled on disconnect")
+	}
+}
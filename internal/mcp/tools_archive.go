@@ -4,7 +4,6 @@ package mcp
 import (
 	"context"
 	"fmt"
-	"path/filepath"
 
 	"github.com/Work-Fort/Anvil/pkg/config"
 	"github.com/Work-Fort/Anvil/pkg/kernel"
@@ -31,6 +30,13 @@ func registerArchiveTools(s *server.MCPServer) {
 		gomcp.WithString("arch", gomcp.Required(), gomcp.Description("Architecture: x86_64 or aarch64")),
 		gomcp.WithReadOnlyHintAnnotation(true),
 	), handleArchiveGet)
+
+	s.AddTool(gomcp.NewTool("archive_prune",
+		gomcp.WithDescription("Remove archived kernels beyond the retention policy. CLI: anvil kernel archive prune"),
+		gomcp.WithNumber("retain_count", gomcp.Description("Versions to keep per architecture (default: kernels.archive.retain-count)")),
+		gomcp.WithNumber("retain_days", gomcp.Description("Remove versions older than this many days (default: kernels.archive.retain-days)")),
+		gomcp.WithDestructiveHintAnnotation(true),
+	), handleArchivePrune)
 }
 
 func getArchiveDir() (string, error) {
@@ -51,16 +57,17 @@ func handleArchiveKernel(_ context.Context, req gomcp.CallToolRequest) (*gomcp.C
 		return errResult(err)
 	}
 
-	// Read per-arch build stats from cache
-	artifactsDir := filepath.Join(config.GlobalPaths.KernelBuildDir, "artifacts")
-	statsFile := filepath.Join(artifactsDir, kernel.BuildStatsFile(arch))
-	stats, err := kernel.ReadBuildStats(statsFile)
+	// Read the version's build stats from the cache
+	hasCached, statsFile, err := kernel.CheckCachedBuild(version, arch, config.GlobalPaths)
 	if err != nil {
-		return errResult(fmt.Errorf("no build stats found — build kernel %s for %s first: %w", version, arch, err))
+		return errResult(err)
 	}
-
-	if stats.KernelVersion != version {
-		return errResult(fmt.Errorf("cached build is for %s, not %s", stats.KernelVersion, version))
+	if !hasCached {
+		return errResult(fmt.Errorf("no cached build found — build kernel %s for %s first", version, arch))
+	}
+	stats, err := kernel.ReadBuildStats(statsFile)
+	if err != nil {
+		return errResult(err)
 	}
 
 	archiveDir, err := getArchiveDir()
@@ -126,3 +133,26 @@ func handleArchiveGet(_ context.Context, req gomcp.CallToolRequest) (*gomcp.Call
 		"size":      detail.Size,
 	})
 }
+
+func handleArchivePrune(_ context.Context, req gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+	archiveDir, err := getArchiveDir()
+	if err != nil {
+		return errResult(err)
+	}
+
+	retainCount := req.GetInt("retain_count", config.GetKernelsArchiveRetainCount())
+	retainDays := req.GetInt("retain_days", config.GetKernelsArchiveRetainDays())
+
+	result, err := kernel.ArchivePrune(archiveDir, retainCount, retainDays)
+	if err != nil {
+		return errResult(err)
+	}
+
+	return jsonResult(map[string]any{
+		"removed":      result.Removed,
+		"count":        len(result.Removed),
+		"freed_bytes":  result.FreedBytes,
+		"retain_count": retainCount,
+		"retain_days":  retainDays,
+	})
+}
@@ -23,7 +23,7 @@ func registerConfigTools(s *server.MCPServer) {
 	), handleConfigSet)
 
 	s.AddTool(gomcp.NewTool("config_list",
-		gomcp.WithDescription("List all anvil config values with their sources. CLI: anvil config list"),
+		gomcp.WithDescription("List every known anvil config key with its effective value, default, source (env, repo, user, default) and forbidden scopes. CLI: anvil config list"),
 		gomcp.WithReadOnlyHintAnnotation(true),
 	), handleConfigList)
 
@@ -94,21 +94,12 @@ func handleConfigList(_ context.Context, _ gomcp.CallToolRequest) (*gomcp.CallTo
 		return errResult(err)
 	}
 
-	values, err := config.ListConfigValues()
+	keys, err := config.ListEffective()
 	if err != nil {
 		return errResult(err)
 	}
 
-	items := make([]map[string]any, len(values))
-	for i, v := range values {
-		items[i] = map[string]any{
-			"key":    v.Key,
-			"value":  v.Value,
-			"source": v.Source,
-		}
-	}
-
-	return jsonResult(items)
+	return jsonResult(keys)
 }
 
 func handleConfigGetPaths(_ context.Context, _ gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
@@ -3,6 +3,8 @@ package mcp
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"path/filepath"
 	"time"
 
@@ -53,7 +55,16 @@ func registerFirecrackerTools(s *server.MCPServer) {
 		gomcp.WithDescription("Create an Alpine Linux rootfs for Firecracker testing. CLI: anvil firecracker create-rootfs"),
 		gomcp.WithString("output", gomcp.Description("Output file path")),
 		gomcp.WithNumber("size_mb", gomcp.Description("Size in MB (default: 512)")),
+		gomcp.WithString("arch", gomcp.Description("Target architecture: x86_64 or aarch64 (default: host)")),
+		gomcp.WithString("alpine_mirror", gomcp.Description("Alpine mirror base URL, https (default: rootfs.alpine-mirror)")),
 		gomcp.WithBoolean("inject_binary", gomcp.Description("Inject anvil binary into rootfs")),
+		gomcp.WithBoolean("reproducible", gomcp.Description("Build a deterministic image: fixed UUID, label, file order and timestamps (default: false)")),
+		gomcp.WithString("seed", gomcp.Description("Seed for the reproducible filesystem UUID; implies reproducible")),
+		gomcp.WithArray("packages", gomcp.WithStringItems(), gomcp.Description("Extra Alpine packages to install with apk (target arch must match the host)")),
+		gomcp.WithNumber("vsock_port", gomcp.Description("Port the init script starts the vsock server on (default: 8000)")),
+		gomcp.WithString("format", gomcp.Description("Image format: ext4 or squashfs (default: ext4)")),
+		gomcp.WithArray("ssh_keys", gomcp.WithStringItems(), gomcp.Description("OpenSSH public keys or key files to authorize for root; installs openssh-server")),
+		gomcp.WithString("init_script", gomcp.Description("Custom /init script file to use instead of the built-in one (must start with a shebang)")),
 		gomcp.WithBoolean("force", gomcp.Description("Overwrite existing rootfs")),
 	), handleFirecrackerCreateRootfs)
 }
@@ -179,20 +190,46 @@ func handleFirecrackerVersions(_ context.Context, _ gomcp.CallToolRequest) (*gom
 }
 
 func handleFirecrackerCreateRootfs(_ context.Context, req gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+	format := req.GetString("format", rootfs.FormatExt4)
 	output := req.GetString("output", "")
 	if output == "" {
-		output = filepath.Join(config.GlobalPaths.DataDir, "alpine-rootfs.ext4")
+		output = filepath.Join(config.GlobalPaths.DataDir, "alpine-rootfs."+format)
 	}
 
 	sizeMB := req.GetInt("size_mb", 512)
 	inject := req.GetBool("inject_binary", false)
 	force := req.GetBool("force", false)
+	arch := req.GetString("arch", "")
+	mirror := req.GetString("alpine_mirror", "")
+	seed := req.GetString("seed", "")
+	packages := req.GetStringSlice("packages", nil)
+	initScript := req.GetString("init_script", "")
+	sshKeys := req.GetStringSlice("ssh_keys", nil)
+	reproducible := req.GetBool("reproducible", false) || seed != ""
+	vsockPort := req.GetInt("vsock_port", int(rootfs.DefaultVsockPort))
+	if vsockPort < 1 || vsockPort > math.MaxUint32 {
+		return errResult(fmt.Errorf("invalid vsock_port: %d", vsockPort))
+	}
 
 	opts := rootfs.CreateOptions{
 		OutputPath:     output,
 		SizeMB:         sizeMB,
+		Arch:           arch,
+		AlpineMirror:   mirror,
 		InjectBinary:   inject,
 		ForceOverwrite: force,
+		VsockPort:      uint32(vsockPort),
+		Reproducible:   reproducible,
+		Seed:           seed,
+		Packages:       packages,
+		InitScriptPath: initScript,
+		Format:         format,
+		AuthorizedKeys: sshKeys,
+	}
+
+	var checksum string
+	opts.StatsCallback = func(stats rootfs.CreateStats) {
+		checksum = stats.SHA256
 	}
 
 	if err := rootfs.Create(opts); err != nil {
@@ -203,6 +240,8 @@ func handleFirecrackerCreateRootfs(_ context.Context, req gomcp.CallToolRequest)
 		"output":        output,
 		"size_mb":       sizeMB,
 		"inject_binary": inject,
+		"format":        format,
+		"sha256":        checksum,
 		"status":        "created",
 	})
 }
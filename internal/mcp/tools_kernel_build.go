@@ -47,6 +47,18 @@ func registerKernelBuildTools(s *server.MCPServer, bm *BuildManager) {
 		gomcp.WithString("config_file", gomcp.Description("Custom kernel config file path (overrides anvil.yaml)")),
 		gomcp.WithString("verification_level", gomcp.Description("Source verification: high (default), medium, or disabled"),
 			gomcp.Enum("high", "medium", "disabled")),
+		gomcp.WithBoolean("allow_root", gomcp.Description("Allow building as root; build cache files will be root-owned (default: false)")),
+		gomcp.WithBoolean("ccache", gomcp.Description("Compile through ccache; true fails if ccache is missing (default: when ccache is on PATH)")),
+		gomcp.WithString("source_tarball", gomcp.Description("Local linux-<version>.tar.xz to build from instead of downloading")),
+		gomcp.WithString("mirror", gomcp.Description("Kernel source mirror base URL, http(s) (default: kernels.mirror)")),
+		gomcp.WithString("checksums_file", gomcp.Description("Local sha256sums.asc to verify source_tarball against (default: next to the tarball)")),
+		gomcp.WithNumber("jobs", gomcp.Description("Parallel make jobs for the compile (default: MAKEFLAGS -j, else one per CPU)")),
+		gomcp.WithString("compression", gomcp.Description("Compression of the packaged kernel: xz (default) or zstd"),
+			gomcp.Enum("xz", "zstd")),
+		gomcp.WithString("toolchain", gomcp.Description("Compiler toolchain: gcc (default) or llvm (clang and ld.lld, LLVM=1)"),
+			gomcp.Enum("gcc", "llvm")),
+		gomcp.WithBoolean("modules", gomcp.Description("Also build kernel modules and package them as modules-<version>-<arch>.tar.xz")),
+		gomcp.WithArray("patches", gomcp.WithStringItems(), gomcp.Description("Patch files applied in order with patch -p1 before configuring")),
 	), func(ctx context.Context, req gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
 		return handleKernelBuild(s, bm, ctx, req)
 	})
@@ -86,8 +98,8 @@ func registerKernelBuildTools(s *server.MCPServer, bm *BuildManager) {
 	s.AddTool(gomcp.NewTool("kernel_versions",
 		gomcp.WithDescription("List available kernel versions from kernel.org. CLI: anvil kernel versions"),
 		gomcp.WithReadOnlyHintAnnotation(true),
-	), func(_ context.Context, _ gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
-		latest, err := kernel.GetLatestKernelVersion()
+	), func(ctx context.Context, _ gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+		latest, err := kernel.GetLatestKernelVersion(ctx)
 		if err != nil {
 			return errResult(err)
 		}
@@ -98,12 +110,12 @@ func registerKernelBuildTools(s *server.MCPServer, bm *BuildManager) {
 		gomcp.WithDescription("Check if a kernel version exists on kernel.org. CLI: anvil kernel version-check"),
 		gomcp.WithString("version", gomcp.Required(), gomcp.Description("Kernel version to check")),
 		gomcp.WithReadOnlyHintAnnotation(true),
-	), func(_ context.Context, req gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+	), func(ctx context.Context, req gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
 		version, err := req.RequireString("version")
 		if err != nil {
 			return errResult(err)
 		}
-		if err := kernel.ValidateVersion(version); err != nil {
+		if err := kernel.ValidateVersion(ctx, version); err != nil {
 			return jsonResult(map[string]any{"version": version, "valid": false, "error": err.Error()})
 		}
 		return jsonResult(map[string]any{"version": version, "valid": true})
@@ -147,6 +159,15 @@ func handleKernelBuild(s *server.MCPServer, bm *BuildManager, ctx context.Contex
 
 	configFile := req.GetString("config_file", "")
 	verLevel := req.GetString("verification_level", "high")
+	var useCcache *bool
+	if _, ok := req.GetArguments()["ccache"]; ok {
+		value := req.GetBool("ccache", false)
+		useCcache = &value
+	}
+	jobs := req.GetInt("jobs", 0)
+	if jobs < 0 {
+		return errResult(fmt.Errorf("invalid jobs %d: must be 0 or more", jobs))
+	}
 
 	// Create job with cancellable context
 	buildCtx, cancel := context.WithCancel(context.Background())
@@ -161,6 +182,16 @@ func handleKernelBuild(s *server.MCPServer, bm *BuildManager, ctx context.Contex
 		Arch:              arch,
 		ConfigFile:        configFile,
 		VerificationLevel: verLevel,
+		Jobs:              jobs,
+		AllowRoot:         req.GetBool("allow_root", false),
+		UseCcache:         useCcache,
+		SourceTarball:     req.GetString("source_tarball", ""),
+		ChecksumsFile:     req.GetString("checksums_file", ""),
+		Mirror:            req.GetString("mirror", ""),
+		Patches:           req.GetStringSlice("patches", nil),
+		Compression:       req.GetString("compression", ""),
+		Toolchain:         req.GetString("toolchain", ""),
+		BuildModules:      req.GetBool("modules", false),
 		Writer:            logWriter,
 		Context:           buildCtx,
 		PhaseCallback: func(phase kernel.BuildPhase) {
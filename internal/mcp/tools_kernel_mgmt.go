@@ -4,7 +4,6 @@ package mcp
 import (
 	"context"
 	"fmt"
-	"path/filepath"
 	"strings"
 
 	"github.com/Work-Fort/Anvil/pkg/config"
@@ -29,6 +28,7 @@ func registerKernelMgmtTools(s *server.MCPServer) {
 	s.AddTool(gomcp.NewTool("kernel_set_default",
 		gomcp.WithDescription("Set the default kernel version. CLI: anvil kernel set"),
 		gomcp.WithString("version", gomcp.Required(), gomcp.Description("Version to set as default")),
+		gomcp.WithBoolean("force", gomcp.Description("Set the kernel even if it was built for another architecture (default: false)")),
 	), handleKernelSetDefault)
 
 	s.AddTool(gomcp.NewTool("kernel_remove",
@@ -42,7 +42,20 @@ func registerKernelMgmtTools(s *server.MCPServer) {
 		gomcp.WithString("version", gomcp.Required(), gomcp.Description("Version to install")),
 		gomcp.WithString("arch", gomcp.Required(), gomcp.Description("Architecture: x86_64 or aarch64")),
 		gomcp.WithBoolean("set_default", gomcp.Description("Set as default after install (default: true)")),
+		gomcp.WithBoolean("force", gomcp.Description("Set as default even if built for another architecture (default: false)")),
 	), handleKernelInstall)
+
+	s.AddTool(gomcp.NewTool("kernel_stats",
+		gomcp.WithDescription("Get stored build statistics for a kernel built with anvil. CLI: anvil kernel stats"),
+		gomcp.WithString("version", gomcp.Required(), gomcp.Description("Kernel version or installed version name")),
+		gomcp.WithString("arch", gomcp.Description("Architecture: x86_64 or aarch64 (default: host)")),
+		gomcp.WithReadOnlyHintAnnotation(true),
+	), handleKernelStats)
+
+	s.AddTool(gomcp.NewTool("kernel_audit",
+		gomcp.WithDescription("Check installed kernels for missing verification records, builds with verification disabled and files whose hash changed. CLI: anvil kernel audit"),
+		gomcp.WithReadOnlyHintAnnotation(true),
+	), handleKernelAudit)
 }
 
 func handleKernelList(_ context.Context, req gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
@@ -107,7 +120,7 @@ func handleKernelSetDefault(_ context.Context, req gomcp.CallToolRequest) (*gomc
 		return errResult(err)
 	}
 
-	if err := kernel.Set(version, config.GlobalPaths); err != nil {
+	if err := kernel.SetWithOptions(version, config.GlobalPaths, kernel.SetOptions{Force: req.GetBool("force", false)}); err != nil {
 		return errResult(err)
 	}
 
@@ -145,19 +158,23 @@ func handleKernelInstall(_ context.Context, req gomcp.CallToolRequest) (*gomcp.C
 
 	setDefault := req.GetBool("set_default", true)
 
-	// Read per-arch build stats from cache
-	artifactsDir := filepath.Join(config.GlobalPaths.KernelBuildDir, "artifacts")
-	statsFile := filepath.Join(artifactsDir, kernel.BuildStatsFile(arch))
-	stats, err := kernel.ReadBuildStats(statsFile)
+	// Read the version's build stats from the cache
+	hasCached, statsFile, err := kernel.CheckCachedBuild(version, arch, config.GlobalPaths)
 	if err != nil {
-		return errResult(fmt.Errorf("no build stats found — build kernel %s for %s first: %w", version, arch, err))
+		return errResult(err)
 	}
-
-	if stats.KernelVersion != version {
-		return errResult(fmt.Errorf("cached build is for %s, not %s", stats.KernelVersion, version))
+	if !hasCached {
+		return errResult(fmt.Errorf("no cached build found — build kernel %s for %s first", version, arch))
+	}
+	stats, err := kernel.ReadBuildStats(statsFile)
+	if err != nil {
+		return errResult(err)
 	}
 
-	installPath, err := kernel.InstallBuiltKernel(stats, setDefault, config.GlobalPaths)
+	installPath, err := kernel.InstallBuiltKernelWithOptions(stats, config.GlobalPaths, kernel.InstallOptions{
+		SetAsDefault: setDefault,
+		Force:        req.GetBool("force", false),
+	})
 	if err != nil {
 		return errResult(err)
 	}
@@ -170,3 +187,34 @@ func handleKernelInstall(_ context.Context, req gomcp.CallToolRequest) (*gomcp.C
 		"status":       "installed",
 	})
 }
+
+func handleKernelStats(_ context.Context, req gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+	version, err := req.RequireString("version")
+	if err != nil {
+		return errResult(err)
+	}
+	arch := req.GetString("arch", "")
+	if arch == "" {
+		arch, err = config.GetArch()
+		if err != nil {
+			return errResult(err)
+		}
+	}
+	if arch != "x86_64" && arch != "aarch64" {
+		return errResult(fmt.Errorf("invalid arch %q: must be x86_64 or aarch64", arch))
+	}
+
+	stored, err := kernel.FindBuildStats(version, arch, config.GetKernelsArchiveLocation(), config.GlobalPaths)
+	if err != nil {
+		return errResult(err)
+	}
+	return jsonResult(stored)
+}
+
+func handleKernelAudit(_ context.Context, _ gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+	report, err := kernel.Audit(config.GlobalPaths)
+	if err != nil {
+		return errResult(err)
+	}
+	return jsonResult(report)
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/Work-Fort/Anvil/pkg/config"
 	"github.com/Work-Fort/Anvil/pkg/signing"
 	gomcp "github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -33,6 +34,7 @@ func registerSigningTools(s *server.MCPServer) {
 		gomcp.WithString("name", gomcp.Required(), gomcp.Description("Key holder name")),
 		gomcp.WithString("email", gomcp.Required(), gomcp.Description("Key holder email")),
 		gomcp.WithString("expiry", gomcp.Description("Key expiry duration (e.g. 1y, 6m). Default: 1y")),
+		gomcp.WithString("algorithm", gomcp.Description("Key algorithm: rsa4096 or ed25519 (default: signing.key.algorithm)")),
 	), handleSigningGenerateKey)
 
 	s.AddTool(gomcp.NewTool("signing_rotate",
@@ -40,17 +42,19 @@ func registerSigningTools(s *server.MCPServer) {
 		gomcp.WithString("name", gomcp.Required(), gomcp.Description("Key holder name")),
 		gomcp.WithString("email", gomcp.Required(), gomcp.Description("Key holder email")),
 		gomcp.WithString("expiry", gomcp.Description("Key expiry duration (default: 1y)")),
+		gomcp.WithString("algorithm", gomcp.Description("Key algorithm: rsa4096 or ed25519 (default: signing.key.algorithm)")),
 		gomcp.WithDestructiveHintAnnotation(true),
 	), handleSigningRotateKey)
 
 	s.AddTool(gomcp.NewTool("signing_sign",
-		gomcp.WithDescription("Sign artifacts in a directory. CLI: anvil signing sign"),
-		gomcp.WithString("path", gomcp.Required(), gomcp.Description("Path to artifacts directory")),
+		gomcp.WithDescription("Sign artifacts in a directory, or a single file. CLI: anvil signing sign"),
+		gomcp.WithString("path", gomcp.Required(), gomcp.Description("Path to artifacts directory or file to sign")),
 	), handleSigningSign)
 
 	s.AddTool(gomcp.NewTool("signing_verify",
-		gomcp.WithDescription("Verify signatures of artifacts in a directory. CLI: anvil signing verify"),
-		gomcp.WithString("path", gomcp.Required(), gomcp.Description("Path to artifacts directory")),
+		gomcp.WithDescription("Verify signatures of artifacts in a directory, or of a single file. CLI: anvil signing verify"),
+		gomcp.WithString("path", gomcp.Required(), gomcp.Description("Path to artifacts directory or signed file")),
+		gomcp.WithArray("keys", gomcp.WithStringItems(), gomcp.Description("Trusted public key files; verification succeeds if any matches (default: current signing key)")),
 		gomcp.WithReadOnlyHintAnnotation(true),
 	), handleSigningVerify)
 
@@ -168,11 +172,13 @@ func handleSigningGenerateKey(_ context.Context, req gomcp.CallToolRequest) (*go
 		return errResult(err)
 	}
 	expiry := req.GetString("expiry", "1y")
+	algorithm := req.GetString("algorithm", config.GetSigningKeyAlgorithm())
 
 	opts := signing.GenerateKeyOptions{
-		Name:   name,
-		Email:  email,
-		Expiry: expiry,
+		Name:      name,
+		Email:     email,
+		Expiry:    expiry,
+		Algorithm: algorithm,
 	}
 
 	info, err := signing.GenerateKey(opts)
@@ -199,11 +205,13 @@ func handleSigningRotateKey(_ context.Context, req gomcp.CallToolRequest) (*gomc
 		return errResult(err)
 	}
 	expiry := req.GetString("expiry", "1y")
+	algorithm := req.GetString("algorithm", config.GetSigningKeyAlgorithm())
 
 	opts := signing.GenerateKeyOptions{
-		Name:   name,
-		Email:  email,
-		Expiry: expiry,
+		Name:      name,
+		Email:     email,
+		Expiry:    expiry,
+		Algorithm: algorithm,
 	}
 
 	info, err := signing.RotateKey(opts)
@@ -224,9 +232,19 @@ func handleSigningSign(_ context.Context, req gomcp.CallToolRequest) (*gomcp.Cal
 		return errResult(err)
 	}
 
+	info, err := os.Stat(path)
+	if err != nil {
+		return errResult(err)
+	}
+
 	password := os.Getenv("ANVIL_SIGNING_PASSWORD")
 
-	if err := signing.SignArtifacts(path, password); err != nil {
+	if info.IsDir() {
+		err = signing.SignArtifacts(path, password)
+	} else {
+		err = signing.SignFile(path, signing.KeyFormatArmored, password)
+	}
+	if err != nil {
 		return errResult(err)
 	}
 
@@ -242,7 +260,18 @@ func handleSigningVerify(_ context.Context, req gomcp.CallToolRequest) (*gomcp.C
 		return errResult(err)
 	}
 
-	if err := signing.VerifyArtifacts(path); err != nil {
+	info, err := os.Stat(path)
+	if err != nil {
+		return errResult(err)
+	}
+	keyPaths := req.GetStringSlice("keys", nil)
+
+	if info.IsDir() {
+		err = signing.VerifyArtifactsWithKeys(path, keyPaths)
+	} else {
+		err = signing.VerifyFileWithKeys(path, "", keyPaths)
+	}
+	if err != nil {
 		return jsonResult(map[string]any{
 			"path":     path,
 			"verified": false,
@@ -27,6 +27,11 @@ func registerUtilityTools(s *server.MCPServer) {
 		gomcp.WithDestructiveHintAnnotation(true),
 	), handleCleanBuildCache)
 
+	s.AddTool(gomcp.NewTool("clean_sources",
+		gomcp.WithDescription("Remove extracted kernel source trees and tarballs, keeping build artifacts. CLI: anvil kernel sources clean"),
+		gomcp.WithDestructiveHintAnnotation(true),
+	), handleCleanSources)
+
 	s.AddTool(gomcp.NewTool("clean_kernel",
 		gomcp.WithDescription("Remove installed kernel versions. CLI: anvil clean kernel"),
 		gomcp.WithBoolean("all", gomcp.Description("Remove ALL kernel data including default (default: false, removes only non-default)")),
@@ -99,6 +104,18 @@ func handleCleanBuildCache(_ context.Context, req gomcp.CallToolRequest) (*gomcp
 	return jsonResult(map[string]any{"status": status, "path": path})
 }
 
+func handleCleanSources(_ context.Context, _ gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+	result, err := kernel.CleanSources(config.GlobalPaths)
+	if err != nil {
+		return errResult(err)
+	}
+
+	return jsonResult(map[string]any{
+		"removed":     result.Removed,
+		"freed_bytes": result.FreedBytes,
+	})
+}
+
 func handleCleanKernel(_ context.Context, req gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
 	all := req.GetBool("all", false)
 
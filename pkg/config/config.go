@@ -24,6 +24,10 @@ const (
 	LocalConfigFile  = "anvil"  // Config file name for current directory (without extension)
 	ConfigType       = "yaml"   // Config file type
 	DefaultConfigExt = ".yaml"  // Default config file extension
+
+	// DataRootEnv roots every anvil directory under one path instead of the
+	// XDG directories, e.g. /var/lib/anvil in a container
+	DataRootEnv = "ANVIL_DATA_ROOT"
 )
 
 // Paths holds all XDG-compliant directory paths
@@ -55,8 +59,17 @@ func init() {
 	GlobalPaths = paths
 }
 
-// GetPaths returns XDG-compliant directory paths.
+// GetPaths returns XDG-compliant directory paths, or the layout of
+// PathsUnder when ANVIL_DATA_ROOT is set.
 func GetPaths() (*Paths, error) {
+	if root := os.Getenv(DataRootEnv); root != "" {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", DataRootEnv, err)
+		}
+		return PathsUnder(abs), nil
+	}
+
 	dataHome := os.Getenv("XDG_DATA_HOME")
 	if dataHome == "" {
 		home, err := os.UserHomeDir()
@@ -84,22 +97,34 @@ func GetPaths() (*Paths, error) {
 		configHome = filepath.Join(home, ".config")
 	}
 
-	dataDir := filepath.Join(dataHome, "anvil")
-	cacheDir := filepath.Join(cacheHome, "anvil")
-	configDir := filepath.Join(configHome, "anvil")
-	binDir := filepath.Join(dataDir, "bin")
+	return newPaths(filepath.Join(dataHome, "anvil"), filepath.Join(cacheHome, "anvil"), filepath.Join(configHome, "anvil")), nil
+}
 
+// PathsUnder returns the directory layout rooted at root: the data
+// directories directly in it, with cache/ and config/ next to them
+func PathsUnder(root string) *Paths {
+	return newPaths(root, filepath.Join(root, "cache"), filepath.Join(root, "config"))
+}
+
+// newPaths derives the subdirectories from the data, cache and config dirs
+func newPaths(dataDir, cacheDir, configDir string) *Paths {
 	return &Paths{
 		DataDir:        dataDir,
 		CacheDir:       cacheDir,
 		ConfigDir:      configDir,
-		BinDir:         binDir,
+		BinDir:         filepath.Join(dataDir, "bin"),
 		KernelsDir:     filepath.Join(dataDir, "kernels"),
 		FirecrackerDir: filepath.Join(dataDir, "firecracker"),
 		KernelBuildDir: filepath.Join(cacheDir, "build-kernel"),
 		KeysDir:        filepath.Join(dataDir, "keys"),
 		GnupgDir:       filepath.Join(dataDir, "gnupg"),
-	}, nil
+	}
+}
+
+// SetPaths points GlobalPaths at PathsUnder(root), so tests can run
+// against a temporary directory without touching the user's home
+func SetPaths(root string) {
+	GlobalPaths = PathsUnder(root)
 }
 
 // InitPaths initializes GlobalPaths explicitly. Call this instead of relying on init()
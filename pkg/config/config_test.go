@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGetPathsDataRoot(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(DataRootEnv, root)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(root, "xdg-data"))
+
+	paths, err := GetPaths()
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	want := map[string]string{
+		"DataDir":        root,
+		"KernelsDir":     filepath.Join(root, "kernels"),
+		"BinDir":         filepath.Join(root, "bin"),
+		"GnupgDir":       filepath.Join(root, "gnupg"),
+		"CacheDir":       filepath.Join(root, "cache"),
+		"KernelBuildDir": filepath.Join(root, "cache", "build-kernel"),
+		"ConfigDir":      filepath.Join(root, "config"),
+	}
+	got := map[string]string{
+		"DataDir":        paths.DataDir,
+		"KernelsDir":     paths.KernelsDir,
+		"BinDir":         paths.BinDir,
+		"GnupgDir":       paths.GnupgDir,
+		"CacheDir":       paths.CacheDir,
+		"KernelBuildDir": paths.KernelBuildDir,
+		"ConfigDir":      paths.ConfigDir,
+	}
+	for name, w := range want {
+		if got[name] != w {
+			t.Errorf("%s = %q, want %q", name, got[name], w)
+		}
+	}
+}
+
+func TestSetPaths(t *testing.T) {
+	saved := GlobalPaths
+	t.Cleanup(func() { GlobalPaths = saved })
+
+	root := t.TempDir()
+	SetPaths(root)
+	if GlobalPaths.KernelsDir != filepath.Join(root, "kernels") {
+		t.Errorf("KernelsDir = %q, want it under %q", GlobalPaths.KernelsDir, root)
+	}
+	if err := InitDirs(); err != nil {
+		t.Fatalf("InitDirs() error = %v", err)
+	}
+}
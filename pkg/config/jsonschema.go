@@ -23,6 +23,8 @@ type JSONSchemaProperty struct {
 	Default     interface{}            `json:"default,omitempty"`
 	Enum        []string               `json:"enum,omitempty"`
 	Pattern     string                 `json:"pattern,omitempty"`
+	Minimum     *int                   `json:"minimum,omitempty"`
+	Maximum     *int                   `json:"maximum,omitempty"`
 	Properties  map[string]interface{} `json:"properties,omitempty"`
 }
 
@@ -128,6 +130,10 @@ func buildProperty(def ConfigKeyDefinition) *JSONSchemaProperty {
 		if def.Pattern != "" {
 			prop.Pattern = def.Pattern
 		}
+	case "int":
+		prop.Type = "integer"
+		prop.Minimum = def.Min
+		prop.Maximum = def.Max
 	case "enum":
 		prop.Type = "string"
 		prop.Enum = def.EnumValues
@@ -0,0 +1,230 @@
+// SPDX-License-Identifier: Apache-2.0
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// AnnotationSkipConfigValidation marks a cobra command that must run even
+// when the loaded config files fail validation (e.g. config migrate)
+const AnnotationSkipConfigValidation = "anvil.skip-config-validation"
+
+// MigrationAction is the kind of change a migration rule makes
+type MigrationAction int
+
+const (
+	MigrationRenameKey  MigrationAction = iota // Move a value from Key to NewKey
+	MigrationRemapValue                        // Replace OldValue with NewValue for Key
+	MigrationDropKey                           // Remove Key entirely
+)
+
+// MigrationRule describes one schema change between config versions
+type MigrationRule struct {
+	Action   MigrationAction
+	Key      string
+	NewKey   string // MigrationRenameKey only
+	OldValue string // MigrationRemapValue only
+	NewValue string // MigrationRemapValue only
+}
+
+// migrationRules is applied in order. Append new rules when ConfigRegistry
+// changes in a way that would break existing config files; never reorder or
+// remove existing rules.
+var migrationRules = []MigrationRule{
+	// default-arch was removed; the host architecture is always the default
+	{Action: MigrationDropKey, Key: "default-arch"},
+}
+
+// MigrationResult describes the outcome of migrating one config file
+type MigrationResult struct {
+	Path       string   `json:"path"`
+	BackupPath string   `json:"backup_path,omitempty"`
+	Changes    []string `json:"changes"`
+}
+
+// HasMigration reports whether key is renamed or dropped by a migration rule
+func HasMigration(key string) bool {
+	for _, rule := range migrationRules {
+		if rule.Key == key && rule.Action != MigrationRemapValue {
+			return true
+		}
+	}
+	return false
+}
+
+// MigrateConfig migrates the config file for the given scope to the current
+// schema. See MigrateConfigFile.
+func MigrateConfig(scope ConfigScope, dryRun bool) (*MigrationResult, error) {
+	return MigrateConfigFile(getConfigPath(scope), dryRun)
+}
+
+// MigrateConfigFile applies migrationRules to the config file at path. When
+// anything changes, the original is copied to <path>.bak-<timestamp> and the
+// file is rewritten, keeping its comments and key order; with dryRun the file
+// is left untouched and only the changes are reported. A missing file is not
+// an error.
+func MigrateConfigFile(path string, dryRun bool) (*MigrationResult, error) {
+	result := &MigrationResult{Path: path, Changes: []string{}}
+
+	original, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(original, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return result, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config is not a YAML mapping")
+	}
+
+	result.Changes = applyMigrationRules(root)
+	if len(result.Changes) == 0 || dryRun {
+		return result, nil
+	}
+
+	result.BackupPath = fmt.Sprintf("%s.bak-%s", path, time.Now().UTC().Format("20060102-150405"))
+	if err := os.WriteFile(result.BackupPath, original, 0644); err != nil {
+		return nil, fmt.Errorf("failed to back up config: %w", err)
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.WriteFile(path, out.Bytes(), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write config: %w", err)
+	}
+
+	return result, nil
+}
+
+// applyMigrationRules rewrites the config's root mapping node in place and
+// returns a description of each change made.
+func applyMigrationRules(root *yaml.Node) []string {
+	changes := []string{}
+
+	for _, rule := range migrationRules {
+		parent, i := findYAMLKey(root, rule.Key)
+		if parent == nil {
+			continue
+		}
+		value := parent.Content[i+1]
+
+		switch rule.Action {
+		case MigrationRenameKey:
+			removeYAMLKey(root, rule.Key)
+			if existing, _ := findYAMLKey(root, rule.NewKey); existing != nil {
+				changes = append(changes, fmt.Sprintf("removed %s (superseded by existing %s)", rule.Key, rule.NewKey))
+				continue
+			}
+			setYAMLKey(root, rule.NewKey, value)
+			changes = append(changes, fmt.Sprintf("renamed %s to %s", rule.Key, rule.NewKey))
+
+		case MigrationRemapValue:
+			if value.Kind == yaml.ScalarNode && value.Value == rule.OldValue {
+				value.Value = rule.NewValue
+				changes = append(changes, fmt.Sprintf("changed %s from %q to %q", rule.Key, rule.OldValue, rule.NewValue))
+			}
+
+		case MigrationDropKey:
+			removeYAMLKey(root, rule.Key)
+			changes = append(changes, fmt.Sprintf("removed %s (no longer supported)", rule.Key))
+		}
+	}
+
+	return changes
+}
+
+// findYAMLKey returns the mapping node holding the dotted key and the index
+// of the key node in its Content, or nil if the key isn't set
+func findYAMLKey(root *yaml.Node, key string) (*yaml.Node, int) {
+	node := root
+	parts := strings.Split(key, ".")
+	for n, part := range parts {
+		if node.Kind != yaml.MappingNode {
+			return nil, 0
+		}
+		i := yamlKeyIndex(node, part)
+		if i < 0 {
+			return nil, 0
+		}
+		if n == len(parts)-1 {
+			return node, i
+		}
+		node = node.Content[i+1]
+	}
+	return nil, 0
+}
+
+// yamlKeyIndex returns the index of key in a mapping node's Content, or -1
+func yamlKeyIndex(m *yaml.Node, key string) int {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// removeYAMLKey removes the dotted key and its value, along with the
+// mappings above it that are left empty
+func removeYAMLKey(root *yaml.Node, key string) {
+	for key != "" {
+		parent, i := findYAMLKey(root, key)
+		if parent == nil {
+			return
+		}
+		parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+		if parent == root || len(parent.Content) > 0 {
+			return
+		}
+		key = key[:max(strings.LastIndex(key, "."), 0)]
+	}
+}
+
+// setYAMLKey sets the dotted key to value, creating the mappings on the way
+// at the end of their parents
+func setYAMLKey(root *yaml.Node, key string, value *yaml.Node) {
+	node := root
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		i := yamlKeyIndex(node, part)
+		if i < 0 || node.Content[i+1].Kind != yaml.MappingNode {
+			child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			if i < 0 {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: part}, child)
+			} else {
+				node.Content[i+1] = child
+			}
+			node = child
+			continue
+		}
+		node = node.Content[i+1]
+	}
+	last := parts[len(parts)-1]
+	if i := yamlKeyIndex(node, last); i >= 0 {
+		node.Content[i+1] = value
+		return
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: last}, value)
+}
@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrateConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "anvil.yaml")
+	content := `# Repo settings
+signing:
+  key:
+    name: Test # Shown in the key's user ID
+    email: test@example.com
+default-arch: x86_64
+log-level: info
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := MigrateConfigFile(path, false)
+	if err != nil {
+		t.Fatalf("MigrateConfigFile() error = %v", err)
+	}
+	if len(result.Changes) != 1 || !strings.Contains(result.Changes[0], "default-arch") {
+		t.Errorf("MigrateConfigFile() changes = %v, want default-arch removed", result.Changes)
+	}
+
+	backup, err := os.ReadFile(result.BackupPath)
+	if err != nil {
+		t.Fatalf("backup not written: %v", err)
+	}
+	if string(backup) != content {
+		t.Error("backup does not match original config")
+	}
+
+	// Comments and key order survive the rewrite
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Repo settings
+signing:
+  key:
+    name: Test # Shown in the key's user ID
+    email: test@example.com
+log-level: info
+`
+	if string(data) != want {
+		t.Errorf("migrated config =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestMigrateConfigFile_RenameAndRemap(t *testing.T) {
+	orig := migrationRules
+	migrationRules = []MigrationRule{
+		{Action: MigrationRenameKey, Key: "old.path", NewKey: "new.nested.path"},
+		{Action: MigrationRenameKey, Key: "stale", NewKey: "log-level"},
+		{Action: MigrationRemapValue, Key: "log-level", OldValue: "warning", NewValue: "warn"},
+	}
+	t.Cleanup(func() { migrationRules = orig })
+
+	path := filepath.Join(t.TempDir(), "anvil.yaml")
+	content := `log-level: warning
+old:
+  path: configs/x86.config # keep me
+stale: true
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := MigrateConfigFile(path, false)
+	if err != nil {
+		t.Fatalf("MigrateConfigFile() error = %v", err)
+	}
+	if len(result.Changes) != 3 {
+		t.Errorf("MigrateConfigFile() changes = %v, want 3", result.Changes)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `log-level: warn
+new:
+  nested:
+    path: configs/x86.config # keep me
+`
+	if string(data) != want {
+		t.Errorf("migrated config =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestMigrateConfigFile_DryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "anvil.yaml")
+	content := "default-arch: aarch64\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := MigrateConfigFile(path, true)
+	if err != nil {
+		t.Fatalf("MigrateConfigFile() error = %v", err)
+	}
+	if len(result.Changes) != 1 || !strings.Contains(result.Changes[0], "default-arch") {
+		t.Errorf("MigrateConfigFile() changes = %v, want default-arch removed", result.Changes)
+	}
+	if result.BackupPath != "" {
+		t.Errorf("dry run should not write a backup, got %s", result.BackupPath)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != content {
+		t.Error("dry run should not modify the config file")
+	}
+}
+
+func TestMigrateConfigFile_UpToDate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "anvil.yaml")
+	if err := os.WriteFile(path, []byte("log-level: info\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := MigrateConfigFile(path, false)
+	if err != nil {
+		t.Fatalf("MigrateConfigFile() error = %v", err)
+	}
+	if len(result.Changes) != 0 || result.BackupPath != "" {
+		t.Errorf("up-to-date config should not change: %+v", result)
+	}
+}
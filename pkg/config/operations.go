@@ -68,6 +68,11 @@ func SetConfigValue(key, valueStr string, scope ConfigScope) error {
 		return err
 	}
 
+	// Store whole-number floats such as "3.0" as ints
+	if def := GetKeyDefinition(key); def != nil && def.Type == "int" {
+		value, _ = intValue(value)
+	}
+
 	// Set the value
 	v.Set(key, value)
 
@@ -89,6 +94,12 @@ func SetConfigValue(key, valueStr string, scope ConfigScope) error {
 	return nil
 }
 
+// SetSessionValue sets a configuration value for the current process only,
+// without writing it to any config file
+func SetSessionValue(key string, value interface{}) {
+	viper.Set(key, value)
+}
+
 // GetConfigValue retrieves a configuration value and its source
 func GetConfigValue(key string) (*ConfigValue, error) {
 	// Check if key exists
@@ -191,6 +202,105 @@ func ListConfigValues() ([]ConfigValue, error) {
 	return values, nil
 }
 
+// Sources of an effective configuration value, in increasing precedence
+const (
+	SourceDefault = "default"
+	SourceUser    = "user"
+	SourceRepo    = "repo"
+	SourceEnv     = "env"
+)
+
+// EffectiveKey is a registered configuration key with its effective value
+// and the source that value comes from
+type EffectiveKey struct {
+	Key         string      `json:"key"`
+	Type        string      `json:"type"`
+	Value       interface{} `json:"value"`
+	Default     interface{} `json:"default"`
+	Source      string      `json:"source"`       // SourceDefault, SourceUser, SourceRepo or SourceEnv
+	EnvVar      string      `json:"env_var"`      // Environment variable that overrides the key
+	ForbiddenIn []string    `json:"forbidden_in"` // Scopes ("user", "repo") the key may not be set in
+	Description string      `json:"description"`
+}
+
+// ListEffective returns every key in ConfigRegistry, sorted by key, with
+// its effective value resolved through Viper and the source of that value
+func ListEffective() ([]EffectiveKey, error) {
+	userSettings, err := readScopeSettings(ScopeUser)
+	if err != nil {
+		return nil, err
+	}
+	repoSettings, err := readScopeSettings(ScopeRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(ConfigRegistry))
+	for key := range ConfigRegistry {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]EffectiveKey, 0, len(keys))
+	for _, key := range keys {
+		def := ConfigRegistry[key]
+
+		value := viper.Get(key)
+		if value == nil {
+			value = def.Default
+		}
+
+		envVar := keyToEnvVar(key)
+		source := SourceDefault
+		switch {
+		case os.Getenv(envVar) != "":
+			source = SourceEnv
+		case repoSettings != nil && repoSettings.IsSet(key):
+			source = SourceRepo
+		case userSettings != nil && userSettings.IsSet(key):
+			source = SourceUser
+		}
+
+		forbiddenIn := []string{}
+		if def.UserConstraints != nil && def.UserConstraints.Forbidden {
+			forbiddenIn = append(forbiddenIn, getScopeName(ScopeUser))
+		}
+		if def.RepoConstraints != nil && def.RepoConstraints.Forbidden {
+			forbiddenIn = append(forbiddenIn, getScopeName(ScopeRepo))
+		}
+
+		result = append(result, EffectiveKey{
+			Key:         key,
+			Type:        def.Type,
+			Value:       value,
+			Default:     def.Default,
+			Source:      source,
+			EnvVar:      envVar,
+			ForbiddenIn: forbiddenIn,
+			Description: def.Description,
+		})
+	}
+
+	return result, nil
+}
+
+// readScopeSettings reads the config file of a scope on its own, returning
+// nil when the file does not exist
+func readScopeSettings(scope ConfigScope) (*viper.Viper, error) {
+	configPath := getConfigPath(scope)
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(configPath)
+	v.SetConfigType(ConfigType)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read %s config: %w", getScopeName(scope), err)
+	}
+	return v, nil
+}
+
 // parseValue attempts to parse a string value into its appropriate type
 func parseValue(valueStr string) interface{} {
 	// Try boolean aliases
@@ -2,10 +2,13 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/spf13/viper"
 )
 
 func TestSetConfigValue_ValidatesScope(t *testing.T) {
@@ -144,3 +147,191 @@ func TestSetConfigValue_ForbiddenKeyInUserScope(t *testing.T) {
 		t.Error("github-token should be written to user config")
 	}
 }
+
+func TestListEffective(t *testing.T) {
+	tmpDir := t.TempDir()
+	GlobalPaths = &Paths{
+		ConfigDir: filepath.Join(tmpDir, "config"),
+	}
+	os.MkdirAll(GlobalPaths.ConfigDir, 0755)
+	t.Chdir(tmpDir)
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	InitViper()
+
+	if err := SetConfigValue("log-level", "warn", ScopeUser); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetConfigValue("log-level", "info", ScopeRepo); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetConfigValue("signing.key.name", "Test", ScopeUser); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ANVIL_USE_TUI", "false")
+
+	// Load both files the way LoadConfig layers them, without its repo
+	// config validation
+	viper.SetConfigFile(filepath.Join(GlobalPaths.ConfigDir, "config.yaml"))
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	viper.SetConfigFile("anvil.yaml")
+	if err := viper.MergeInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := ListEffective()
+	if err != nil {
+		t.Fatalf("ListEffective() error = %v", err)
+	}
+	if len(keys) != len(ConfigRegistry) {
+		t.Errorf("ListEffective() returned %d keys, want %d", len(keys), len(ConfigRegistry))
+	}
+
+	byKey := map[string]EffectiveKey{}
+	for i, k := range keys {
+		if i > 0 && keys[i-1].Key >= k.Key {
+			t.Errorf("keys not sorted: %s before %s", keys[i-1].Key, k.Key)
+		}
+		byKey[k.Key] = k
+	}
+
+	tests := []struct {
+		key    string
+		value  interface{}
+		source string
+	}{
+		{key: "log-level", value: "info", source: SourceRepo},
+		{key: "signing.key.name", value: "Test", source: SourceUser},
+		{key: "use-tui", value: "false", source: SourceEnv},
+		{key: "signing.key.format", value: "armored", source: SourceDefault},
+	}
+	for _, tt := range tests {
+		got := byKey[tt.key]
+		if got.Source != tt.source {
+			t.Errorf("%s source = %q, want %q", tt.key, got.Source, tt.source)
+		}
+		if fmt.Sprint(got.Value) != fmt.Sprint(tt.value) {
+			t.Errorf("%s value = %v, want %v", tt.key, got.Value, tt.value)
+		}
+	}
+
+	if forbidden := byKey["github-token"].ForbiddenIn; len(forbidden) != 1 || forbidden[0] != "repo" {
+		t.Errorf("github-token ForbiddenIn = %v, want [repo]", forbidden)
+	}
+}
+
+func TestValidateConfigFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	GlobalPaths = &Paths{
+		ConfigDir: filepath.Join(tmpDir, "config"),
+	}
+	os.MkdirAll(GlobalPaths.ConfigDir, 0755)
+	t.Chdir(tmpDir)
+
+	// No config files at all is valid
+	problems, err := ValidateConfigFiles()
+	if err != nil {
+		t.Fatalf("ValidateConfigFiles() error = %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("ValidateConfigFiles() with no files = %v, want none", problems)
+	}
+
+	userConfig := "log-level: loud\nsigning:\n  key:\n    name: Test\n"
+	if err := os.WriteFile(filepath.Join(GlobalPaths.ConfigDir, "config.yaml"), []byte(userConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repoConfig := "github-token: secret\nlog-levle: info\nkernels:\n  config:\n    x86_64: configs/missing.config\n"
+	if err := os.WriteFile("anvil.yaml", []byte(repoConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err = ValidateConfigFiles()
+	if err != nil {
+		t.Fatalf("ValidateConfigFiles() error = %v", err)
+	}
+
+	found := make(map[string]ValidationProblem)
+	for _, p := range problems {
+		found[p.Scope+":"+p.Key] = p
+		if p.Message == "" || p.Hint == "" {
+			t.Errorf("problem %+v missing message or hint", p)
+		}
+	}
+
+	want := []string{
+		"user:log-level",
+		"repo:github-token",
+		"repo:log-levle",
+		"repo:kernels.config.x86_64",
+	}
+	for _, key := range GetRequiredRepoKeys() {
+		// kernels.config.x86_64 is set, if invalidly
+		if key != "kernels.config.x86_64" {
+			want = append(want, "repo:"+key)
+		}
+	}
+	for _, key := range want {
+		if _, ok := found[key]; !ok {
+			t.Errorf("expected a problem for %s, got %v", key, problems)
+		}
+	}
+	if len(problems) != len(want) {
+		t.Errorf("ValidateConfigFiles() returned %d problems, want %d: %v", len(problems), len(want), problems)
+	}
+
+	if hint := found["repo:log-levle"].Hint; !strings.Contains(hint, "log-level") {
+		t.Errorf("hint for misspelled key = %q, want suggestion of log-level", hint)
+	}
+	if hint := found["user:log-level"].Hint; !strings.Contains(hint, "debug") {
+		t.Errorf("hint for bad enum = %q, want allowed values", hint)
+	}
+}
+
+func TestGetInt(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	// Falls back to the registry default before InitViper
+	if got := GetInt("kernels.download.retries"); got != 3 {
+		t.Errorf("GetInt() before InitViper = %d, want registry default 3", got)
+	}
+	if got := GetInt("no.such.key"); got != 0 {
+		t.Errorf("GetInt(unknown) = %d, want 0", got)
+	}
+
+	InitViper()
+	viper.Set("kernels.download.retries", float64(7))
+	if got := GetInt("kernels.download.retries"); got != 7 {
+		t.Errorf("GetInt() = %d, want 7", got)
+	}
+}
+
+func TestSetConfigValue_IntStoredAsInt(t *testing.T) {
+	tmpDir := t.TempDir()
+	GlobalPaths = &Paths{
+		ConfigDir: filepath.Join(tmpDir, "config"),
+	}
+	os.MkdirAll(GlobalPaths.ConfigDir, 0755)
+	t.Chdir(tmpDir)
+	t.Cleanup(viper.Reset)
+
+	if err := SetConfigValue("kernels.download.retries", "5.0", ScopeRepo); err != nil {
+		t.Fatalf("SetConfigValue(5.0) error = %v", err)
+	}
+	content, err := os.ReadFile("anvil.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "retries: 5\n") {
+		t.Errorf("anvil.yaml = %q, want retries stored as 5", content)
+	}
+
+	for _, bad := range []string{"-1", "2.5", "many"} {
+		if err := SetConfigValue("kernels.download.retries", bad, ScopeRepo); err == nil {
+			t.Errorf("SetConfigValue(%q) should be rejected", bad)
+		}
+	}
+}
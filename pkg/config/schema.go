@@ -3,6 +3,7 @@ package config
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -14,6 +15,9 @@ type ScopeConstraints struct {
 	Forbidden  bool     // If true, this key cannot be set in this scope
 	EnumValues []string // Valid enum values for this scope (overrides global EnumValues if set)
 	Pattern    string   // Regex pattern for this scope (overrides global Pattern if set)
+	FormatHint string   // Describes Pattern's format in errors (overrides global FormatHint if set)
+
+	compiled *regexp.Regexp // Pattern, compiled by compilePatterns
 }
 
 // ConfigKeyDefinition defines metadata for a configuration key
@@ -26,10 +30,15 @@ type ConfigKeyDefinition struct {
 	// Global constraints (apply unless overridden by scope-specific constraints)
 	EnumValues []string // Valid values for enum type (if Type="enum")
 	Pattern    string   // Regex pattern for validation (if Type="string")
+	FormatHint string   // Describes Pattern's format in errors, e.g. "expected an email address"
+	Min        *int     // Smallest allowed value (if Type="int", nil=no lower bound)
+	Max        *int     // Largest allowed value (if Type="int", nil=no upper bound)
 
 	// Per-scope constraints (optional - if nil, key is allowed in scope with global constraints)
 	UserConstraints *ScopeConstraints // Constraints when setting in user config
 	RepoConstraints *ScopeConstraints // Constraints when setting in repo config
+
+	compiled *regexp.Regexp // Pattern, compiled by compilePatterns
 }
 
 // ConfigRegistry holds all known configuration keys with per-scope constraints.
@@ -55,6 +64,14 @@ var ConfigRegistry = map[string]ConfigKeyDefinition{
 		EnumValues:  []string{"disabled", "debug", "info", "warn", "error"},
 	},
 
+	"ui.theme": {
+		Key:         "ui.theme",
+		Type:        "enum",
+		Default:     ThemeDefault,
+		Description: "Color theme (mono for terminals with poor color support, high-contrast for accessibility; NO_COLOR forces mono)",
+		EnumValues:  []string{ThemeDefault, ThemeMono, ThemeHighContrast},
+	},
+
 	"github-token": {
 		Key:         "github-token",
 		Type:        "string",
@@ -78,6 +95,7 @@ var ConfigRegistry = map[string]ConfigKeyDefinition{
 		Default:     "fake@example.com",
 		Description: "Default key owner email for project releases",
 		Pattern:     "^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\\.[a-zA-Z]{2,}$",
+		FormatHint:  "expected an email address such as releases@example.com",
 	},
 
 	"signing.key.expiry": {
@@ -86,6 +104,23 @@ var ConfigRegistry = map[string]ConfigKeyDefinition{
 		Default:     "1y",
 		Description: "Default key expiration (0=never, <n>d/w/m/y)",
 		Pattern:     "^(0|[0-9]+[dwmy])$",
+		FormatHint:  "expected 0 or a number followed by d/w/m/y, e.g. 1y",
+	},
+
+	"signing.key.max-expiry": {
+		Key:         "signing.key.max-expiry",
+		Type:        "string",
+		Default:     "2y",
+		Description: "Longest key expiration allowed without a warning when signing.require-expiry is set (<n>d/w/m/y)",
+		Pattern:     "^[0-9]+[dwmy]$",
+		FormatHint:  "expected a number followed by d/w/m/y, e.g. 2y",
+	},
+
+	"signing.require-expiry": {
+		Key:         "signing.require-expiry",
+		Type:        "bool",
+		Default:     false,
+		Description: "Reject generating or rotating signing keys that never expire",
 	},
 
 	"signing.key.format": {
@@ -96,6 +131,22 @@ var ConfigRegistry = map[string]ConfigKeyDefinition{
 		EnumValues:  []string{"armored", "binary"},
 	},
 
+	"signing.key.algorithm": {
+		Key:         "signing.key.algorithm",
+		Type:        "enum",
+		Default:     "rsa4096",
+		Description: "Key algorithm for new signing keys: rsa4096 or ed25519 (needs GnuPG 2.1+ to verify)",
+		EnumValues:  []string{"rsa4096", "ed25519"},
+	},
+
+	"signing.backend": {
+		Key:         "signing.backend",
+		Type:        "enum",
+		Default:     "file",
+		Description: "Signing backend: file (private key file) or gpg-agent (gpg --detach-sign, e.g. for keys on a smartcard)",
+		EnumValues:  []string{"file", "gpg-agent"},
+	},
+
 	"signing.history.location": {
 		Key:         "signing.history.location",
 		Type:        "string",
@@ -157,6 +208,118 @@ var ConfigRegistry = map[string]ConfigKeyDefinition{
 			Forbidden: true, // Archive location is repo-specific
 		},
 	},
+
+	"kernels.archive.retain-count": {
+		Key:         "kernels.archive.retain-count",
+		Type:        "int",
+		Default:     0,
+		Description: "Number of archived versions to keep per architecture (0=unlimited)",
+		Min:         intPtr(0),
+		UserConstraints: &ScopeConstraints{
+			Forbidden: true, // Archive retention is repo-specific
+		},
+	},
+
+	"kernels.archive.retain-days": {
+		Key:         "kernels.archive.retain-days",
+		Type:        "int",
+		Default:     0,
+		Description: "Remove archived versions older than this many days (0=unlimited)",
+		Min:         intPtr(0),
+		UserConstraints: &ScopeConstraints{
+			Forbidden: true, // Archive retention is repo-specific
+		},
+	},
+
+	"kernels.autosigner-key": {
+		Key:         "kernels.autosigner-key",
+		Type:        "string",
+		Default:     "",
+		Description: "Local kernel.org autosigner public key file to import for PGP verification instead of the embedded key and keyservers (fingerprint must match)",
+	},
+
+	"kernels.download.retries": {
+		Key:         "kernels.download.retries",
+		Type:        "int",
+		Default:     3,
+		Description: "Retries for kernel.org requests and source downloads after a network error or 5xx response, with exponential backoff from 500ms (0=no retries)",
+		Min:         intPtr(0),
+	},
+
+	"kernels.download.parallel": {
+		Key:         "kernels.download.parallel",
+		Type:        "int",
+		Default:     1,
+		Description: "Concurrent range requests for kernel source downloads (1=single stream, which can be resumed)",
+		Min:         intPtr(1),
+	},
+
+	"kernels.api-timeout": {
+		Key:         "kernels.api-timeout",
+		Type:        "string",
+		Default:     "15s",
+		Description: "How long kernel.org API requests (the release list for the version picker, latest and lts) may take, retries included",
+		Pattern:     "^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$",
+		FormatHint:  "expected a duration such as 15s, 500ms or 1m30s",
+	},
+
+	"kernels.min-free-space-gb": {
+		Key:         "kernels.min-free-space-gb",
+		Type:        "int",
+		Default:     15,
+		Description: "Free space (GiB) a kernel build needs in the build cache before it starts (0=no check)",
+		Min:         intPtr(0),
+	},
+
+	"kernels.mirror": {
+		Key:     "kernels.mirror",
+		Type:    "string",
+		Default: "https://cdn.kernel.org/pub/linux/kernel",
+		Description: "Base URL kernel sources and sha256sums.asc are downloaded from (http or https, kernel.org's v<major>.x/ layout below it), " +
+			"e.g. https://mirrors.edge.kernel.org/pub/linux/kernel or https://mirrors.kernel.org/pub/linux/kernel",
+		Pattern:    "^https?://[^/\\s?#]+(/[^\\s?#]*)?$",
+		FormatHint: "expected an http or https URL without a query or fragment",
+	},
+
+	"rootfs.alpine-mirror": {
+		Key:         "rootfs.alpine-mirror",
+		Type:        "string",
+		Default:     "https://dl-cdn.alpinelinux.org",
+		Description: "Base URL of the Alpine mirror used by create-rootfs (https, CDN path layout)",
+		Pattern:     "^https://[^/\\s?#]+(/[^\\s?#]*)?$",
+		FormatHint:  "expected an https URL without a query or fragment",
+	},
+}
+
+func init() {
+	compilePatterns()
+}
+
+// compilePatterns compiles every registry pattern once, so validating many
+// values (config validate) doesn't recompile them. A bad pattern is a
+// programming error and panics at startup.
+func compilePatterns() {
+	for key, def := range ConfigRegistry {
+		if def.Pattern != "" {
+			def.compiled = regexp.MustCompile(def.Pattern)
+		}
+		for _, constraints := range []*ScopeConstraints{def.UserConstraints, def.RepoConstraints} {
+			if constraints != nil && constraints.Pattern != "" {
+				constraints.compiled = regexp.MustCompile(constraints.Pattern)
+			}
+		}
+		ConfigRegistry[key] = def
+	}
+}
+
+// compiledPattern returns pattern compiled, reusing compiled when
+// compilePatterns already did it (entries added to the registry later are
+// compiled here)
+func compiledPattern(compiled *regexp.Regexp, pattern string) (*regexp.Regexp, error) {
+	if compiled != nil || pattern == "" {
+		return compiled, nil
+	}
+	return regexp.Compile(pattern)
 }
 
 // GetKeyDefinition returns the definition for a key, or nil if not found
@@ -194,7 +357,7 @@ func GetRequiredRepoKeys() []string {
 func ValidateKeyScope(key string, scope ConfigScope) error {
 	def := GetKeyDefinition(key)
 	if def == nil {
-		return fmt.Errorf("unknown configuration key: %s", key)
+		return unknownKeyError(key)
 	}
 
 	// Get constraints for the target scope
@@ -239,7 +402,7 @@ func ValidateKeyScope(key string, scope ConfigScope) error {
 func ValidateValue(key string, value interface{}, scope ConfigScope) error {
 	def := GetKeyDefinition(key)
 	if def == nil {
-		return fmt.Errorf("unknown configuration key: %s", key)
+		return unknownKeyError(key)
 	}
 
 	// Get scope-specific constraints
@@ -259,9 +422,16 @@ func ValidateValue(key string, value interface{}, scope ConfigScope) error {
 		}
 
 	case "int":
-		if _, ok := value.(int); !ok {
+		n, ok := intValue(value)
+		if !ok {
 			return fmt.Errorf("key '%s' must be an integer", key)
 		}
+		if def.Min != nil && n < *def.Min {
+			return fmt.Errorf("key '%s' must be at least %d (got %d)", key, *def.Min, n)
+		}
+		if def.Max != nil && n > *def.Max {
+			return fmt.Errorf("key '%s' must be at most %d (got %d)", key, *def.Max, n)
+		}
 
 	case "string":
 		str, ok := value.(string)
@@ -270,25 +440,28 @@ func ValidateValue(key string, value interface{}, scope ConfigScope) error {
 		}
 
 		// Pattern validation - use scope-specific pattern if available
-		pattern := def.Pattern
+		pattern, err := compiledPattern(def.compiled, def.Pattern)
+		hint := def.FormatHint
 		if constraints != nil && constraints.Pattern != "" {
-			pattern = constraints.Pattern
+			pattern, err = compiledPattern(constraints.compiled, constraints.Pattern)
+			hint = constraints.FormatHint
+		}
+		if err != nil {
+			return fmt.Errorf("pattern validation error: %w", err)
 		}
 
-		if pattern != "" {
-			matched, err := regexp.MatchString(pattern, str)
-			if err != nil {
-				return fmt.Errorf("pattern validation error: %w", err)
-			}
-			if !matched {
-				scopeName := getScopeName(scope)
-				return fmt.Errorf(
-					"key '%s' value '%s' does not match required format for %s scope",
-					key,
-					str,
-					scopeName,
-				)
+		if pattern != nil && !pattern.MatchString(str) {
+			if hint == "" {
+				hint = fmt.Sprintf("expected a value matching %s", pattern)
 			}
+			scopeName := getScopeName(scope)
+			return fmt.Errorf(
+				"key '%s' value '%s' does not match required format for %s scope: %s",
+				key,
+				str,
+				scopeName,
+				hint,
+			)
 		}
 
 	case "enum":
@@ -455,3 +628,36 @@ func validateRepoFilePath(path string) error {
 
 	return nil
 }
+
+// intPtr returns a pointer to n, for the Min and Max bounds of int keys
+func intPtr(n int) *int {
+	return &n
+}
+
+// intValue converts a number from the CLI or a config file to an int.
+// YAML, JSON and Viper may hand numbers over as int64 or float64; floats
+// are only accepted when they hold a whole number.
+func intValue(value interface{}) (int, bool) {
+	switch n := value.(type) {
+	case int:
+		return n, true
+	case int32:
+		return int(n), true
+	case int64:
+		if n < math.MinInt || n > math.MaxInt {
+			return 0, false
+		}
+		return int(n), true
+	case uint64:
+		if n > math.MaxInt {
+			return 0, false
+		}
+		return int(n), true
+	case float64:
+		if n != math.Trunc(n) || n < math.MinInt || n >= math.MaxInt {
+			return 0, false
+		}
+		return int(n), true
+	}
+	return 0, false
+}
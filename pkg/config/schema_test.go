@@ -78,6 +78,8 @@ func TestConfigRegistry_ContainsSigningKeys(t *testing.T) {
 		"signing.key.email",
 		"signing.key.expiry",
 		"signing.key.format",
+		"signing.key.algorithm",
+		"signing.backend",
 		"signing.history.location",
 		"signing.history.format",
 	}
@@ -548,3 +550,27 @@ func TestGetRequiredRepoKeys(t *testing.T) {
 		}
 	}
 }
+
+func TestConfigRegistry_PatternsCompiledWithFormatHints(t *testing.T) {
+	for key, def := range ConfigRegistry {
+		if def.Pattern == "" {
+			continue
+		}
+		if def.compiled == nil {
+			t.Errorf("%s: pattern was not compiled at init", key)
+		}
+		if def.FormatHint == "" {
+			t.Errorf("%s: has a pattern but no FormatHint", key)
+		}
+	}
+}
+
+func TestValidateValue_PatternErrorShowsFormatHint(t *testing.T) {
+	err := ValidateValue("signing.key.expiry", "13x", ScopeUser)
+	if err == nil {
+		t.Fatal("ValidateValue should reject '13x'")
+	}
+	if !strings.Contains(err.Error(), "expected 0 or a number followed by d/w/m/y") {
+		t.Errorf("error %q doesn't describe the expected format", err)
+	}
+}
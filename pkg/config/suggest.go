@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/viper"
+)
+
+// maxSuggestionDistance is the largest edit distance for a "did you mean" hint
+const maxSuggestionDistance = 3
+
+// strictConfig turns unknown-key warnings into errors when true.
+var strictConfig bool
+
+// SetStrictConfig makes LoadConfig fail on unknown keys in config files
+// instead of logging a warning.
+func SetStrictConfig(strict bool) {
+	strictConfig = strict
+}
+
+// SuggestKey returns the registered config key closest to key, or "" if
+// none is close enough to be a likely typo.
+func SuggestKey(key string) string {
+	known := make([]string, 0, len(ConfigRegistry))
+	for k := range ConfigRegistry {
+		known = append(known, k)
+	}
+	sort.Strings(known) // Deterministic choice between equal distances
+
+	best := ""
+	bestDistance := maxSuggestionDistance + 1
+	for _, k := range known {
+		if d := editDistance(key, k); d < bestDistance {
+			best = k
+			bestDistance = d
+		}
+	}
+	return best
+}
+
+// unknownKeyError formats an unknown key with a "did you mean" hint when a
+// close match exists
+func unknownKeyError(key string) error {
+	if suggestion := SuggestKey(key); suggestion != "" {
+		return fmt.Errorf("unknown configuration key: %s (did you mean %s?)", key, suggestion)
+	}
+	return fmt.Errorf("unknown configuration key: %s", key)
+}
+
+// checkUnknownKeys reports keys in the config file at configPath that are not
+// in ConfigRegistry. Each is logged as a warning, or returned as an error when
+// strict config is enabled.
+func checkUnknownKeys(configPath string) error {
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(configPath)
+	v.SetConfigType(ConfigType)
+	if err := v.ReadInConfig(); err != nil {
+		return nil // Read errors are reported by the main config load
+	}
+
+	keys := flattenKeys(v.AllSettings(), "")
+	sort.Strings(keys)
+
+	var unknown []string
+	for _, key := range keys {
+		if GetKeyDefinition(key) != nil {
+			continue
+		}
+		msg := unknownKeyError(key).Error()
+		if HasMigration(key) {
+			msg += " - run 'anvil config migrate' to update it"
+		}
+		unknown = append(unknown, msg)
+	}
+
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	if strictConfig {
+		return fmt.Errorf("invalid keys in config file %s:\n  - %s", configPath, strings.Join(unknown, "\n  - "))
+	}
+	for _, msg := range unknown {
+		log.Warnf("%s in %s (ignored)", msg, configPath)
+	}
+	return nil
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
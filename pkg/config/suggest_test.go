@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSuggestKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"signing.key.emial", "signing.key.email"},
+		{"signing.key.nme", "signing.key.name"},
+		{"use_tui", "use-tui"},
+		{"log-levle", "log-level"},
+		{"completely.unrelated.key", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := SuggestKey(tt.key); got != tt.want {
+				t.Errorf("SuggestKey(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "signing:\n  key:\n    emial: me@example.com\nuse-tui: false\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetStrictConfig(false) })
+
+	SetStrictConfig(false)
+	if err := checkUnknownKeys(path); err != nil {
+		t.Errorf("checkUnknownKeys() should only warn when not strict: %v", err)
+	}
+
+	SetStrictConfig(true)
+	err := checkUnknownKeys(path)
+	if err == nil {
+		t.Fatal("checkUnknownKeys() should fail on unknown keys when strict")
+	}
+	if !strings.Contains(err.Error(), "did you mean signing.key.email?") {
+		t.Errorf("error should suggest signing.key.email, got: %v", err)
+	}
+}
@@ -4,6 +4,7 @@ package config
 import (
 	"fmt"
 	"image/color"
+	"os"
 
 	"charm.land/lipgloss/v2"
 )
@@ -24,9 +25,16 @@ type Theme struct {
 	Error     color.Color // Error states, destructive actions
 }
 
-// CurrentTheme is the active theme used throughout the application.
-// Defaults to hackerman-inspired colors; overridden by Omarchy loader on Omarchy systems.
-var CurrentTheme = Theme{
+// Theme names accepted by the ui.theme config key
+const (
+	ThemeDefault      = "default"
+	ThemeMono         = "mono"
+	ThemeHighContrast = "high-contrast"
+)
+
+// DefaultTheme is the hackerman-inspired palette. On Omarchy systems the
+// Omarchy loader replaces it with the system colors.
+var DefaultTheme = Theme{
 	Primary:   lipgloss.Color("#82FB9C"), // Bright mint green
 	Secondary: lipgloss.Color("#7cf8f7"), // Bright cyan
 	Muted:     lipgloss.Color("#6a6e95"), // Purple-gray
@@ -40,6 +48,63 @@ var CurrentTheme = Theme{
 	Error:     lipgloss.Color("#FF6B6B"), // Soft red
 }
 
+// MonoTheme renders without colors, for terminals with poor color support.
+// Bold and the message symbols still set states apart.
+var MonoTheme = Theme{
+	Primary:   lipgloss.NoColor{},
+	Secondary: lipgloss.NoColor{},
+	Muted:     lipgloss.NoColor{},
+	Accent:    lipgloss.NoColor{},
+	Text:      lipgloss.NoColor{},
+	TextDim:   lipgloss.NoColor{},
+	BgDark:    lipgloss.NoColor{},
+	Success:   lipgloss.NoColor{},
+	Info:      lipgloss.NoColor{},
+	Warning:   lipgloss.NoColor{},
+	Error:     lipgloss.NoColor{},
+}
+
+// HighContrastTheme uses saturated colors and near-white dimmed text, so
+// every element stays legible on a dark background
+var HighContrastTheme = Theme{
+	Primary:   lipgloss.Color("#FFFF00"), // Yellow
+	Secondary: lipgloss.Color("#00FFFF"), // Cyan
+	Muted:     lipgloss.Color("#C0C0C0"), // Silver
+	Accent:    lipgloss.Color("#FFFF00"), // Same as primary
+	Text:      lipgloss.Color("#FFFFFF"), // White
+	TextDim:   lipgloss.Color("#E0E0E0"), // Near-white
+	BgDark:    lipgloss.Color("#000000"), // Black
+	Success:   lipgloss.Color("#00FF00"), // Green
+	Info:      lipgloss.Color("#00FFFF"), // Cyan
+	Warning:   lipgloss.Color("#FFFF00"), // Yellow
+	Error:     lipgloss.Color("#FF4040"), // Red
+}
+
+// CurrentTheme is the active theme used throughout the application.
+// ApplyTheme sets it from the ui.theme config key at startup.
+var CurrentTheme = DefaultTheme
+
+// ApplyTheme sets CurrentTheme from the ui.theme config key. A non-empty
+// NO_COLOR environment variable (https://no-color.org) forces the mono
+// theme. The default theme follows the Omarchy system theme if available.
+func ApplyTheme() {
+	name := GetUITheme()
+	if os.Getenv("NO_COLOR") != "" {
+		name = ThemeMono
+	}
+
+	switch name {
+	case ThemeMono:
+		CurrentTheme = MonoTheme
+	case ThemeHighContrast:
+		CurrentTheme = HighContrastTheme
+	default:
+		CurrentTheme = DefaultTheme
+		// Silently keeps the defaults on non-Omarchy systems
+		_ = LoadOmarchyTheme()
+	}
+}
+
 // --- Color getters (backward compat — thin wrappers over fields) ---
 
 func (t Theme) GetPrimaryColor() color.Color   { return t.Primary }
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestThemes_PrimaryColorsDiffer(t *testing.T) {
+	themes := map[string]Theme{
+		ThemeDefault:      DefaultTheme,
+		ThemeMono:         MonoTheme,
+		ThemeHighContrast: HighContrastTheme,
+	}
+	for a, ta := range themes {
+		for b, tb := range themes {
+			if a < b && ta.GetPrimaryColor() == tb.GetPrimaryColor() {
+				t.Errorf("themes %s and %s have the same primary color %v", a, b, ta.GetPrimaryColor())
+			}
+		}
+	}
+}
+
+func TestApplyTheme(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // keep a real Omarchy theme out of the default
+	saved := CurrentTheme
+	t.Cleanup(func() { CurrentTheme = saved })
+
+	tests := []struct {
+		name    string
+		theme   string
+		noColor string
+		want    Theme
+	}{
+		{name: "unset", want: DefaultTheme},
+		{name: "default", theme: ThemeDefault, want: DefaultTheme},
+		{name: "mono", theme: ThemeMono, want: MonoTheme},
+		{name: "high-contrast", theme: ThemeHighContrast, want: HighContrastTheme},
+		{name: "NO_COLOR overrides config", theme: ThemeHighContrast, noColor: "1", want: MonoTheme},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			if tt.theme != "" {
+				viper.Set("ui.theme", tt.theme)
+			}
+			t.Setenv("NO_COLOR", tt.noColor)
+
+			ApplyTheme()
+			if got := CurrentTheme.GetPrimaryColor(); got != tt.want.GetPrimaryColor() {
+				t.Errorf("primary color = %v, want %v", got, tt.want.GetPrimaryColor())
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ValidationProblem is one problem found in a config file by ValidateConfigFiles
+type ValidationProblem struct {
+	Scope   string // "user" or "repo"
+	File    string // Config file the problem is in
+	Key     string
+	Message string
+	Hint    string // How to fix it
+}
+
+// ValidateConfigFiles checks the user config and the repo config
+// (./anvil.yaml) as they are on disk. Every key set must be known, allowed
+// in its scope and hold a valid value (kernel config paths must point to
+// existing files), and a repo config must set every required repo key.
+// All problems are returned rather than stopping at the first.
+func ValidateConfigFiles() ([]ValidationProblem, error) {
+	var problems []ValidationProblem
+	for _, scope := range []ConfigScope{ScopeUser, ScopeRepo} {
+		scopeProblems, err := validateScopeFile(scope)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, scopeProblems...)
+	}
+	return problems, nil
+}
+
+// validateScopeFile validates the config file of one scope; a missing file
+// has no problems
+func validateScopeFile(scope ConfigScope) ([]ValidationProblem, error) {
+	configPath := getConfigPath(scope)
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(configPath)
+	v.SetConfigType(ConfigType)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", configPath, err)
+	}
+
+	scopeName := getScopeName(scope)
+	problem := func(key, message, hint string) ValidationProblem {
+		return ValidationProblem{Scope: scopeName, File: configPath, Key: key, Message: message, Hint: hint}
+	}
+	setCmd := "anvil config set"
+	if scope == ScopeUser {
+		setCmd += " --global"
+	}
+
+	keys := flattenKeys(v.AllSettings(), "")
+	sort.Strings(keys)
+
+	var problems []ValidationProblem
+	for _, key := range keys {
+		def := GetKeyDefinition(key)
+		if def == nil {
+			hint := "remove the key"
+			if HasMigration(key) {
+				hint = "run 'anvil config migrate' to update it"
+			} else if suggestion := SuggestKey(key); suggestion != "" {
+				hint = fmt.Sprintf("rename it to %s", suggestion)
+			}
+			problems = append(problems, problem(key, "unknown configuration key", hint))
+			continue
+		}
+
+		if err := ValidateKeyScope(key, scope); err != nil {
+			hint := fmt.Sprintf("move it to ./anvil.yaml: anvil config set %s <value>", key)
+			if scope == ScopeRepo {
+				hint = fmt.Sprintf("move it to user config: anvil config set --global %s <value>", key)
+			}
+			problems = append(problems, problem(key, firstLine(err), hint))
+			continue
+		}
+
+		if err := ValidateValue(key, v.Get(key), scope); err != nil {
+			problems = append(problems, problem(key, firstLine(err), valueHint(def, setCmd)))
+		}
+	}
+
+	if scope == ScopeRepo {
+		required := GetRequiredRepoKeys()
+		sort.Strings(required)
+		for _, key := range required {
+			if v.IsSet(key) {
+				continue
+			}
+			def := ConfigRegistry[key]
+			problems = append(problems, problem(key, "required key is missing",
+				fmt.Sprintf("%s: %s %s <value>", def.Description, setCmd, key)))
+		}
+	}
+
+	return problems, nil
+}
+
+// valueHint describes what a valid value for a key looks like
+func valueHint(def *ConfigKeyDefinition, setCmd string) string {
+	switch {
+	case def.Type == "enum":
+		return fmt.Sprintf("use one of: %s", strings.Join(def.EnumValues, ", "))
+	case def.Key == "kernels.config.x86_64" || def.Key == "kernels.config.aarch64":
+		return "point it at a kernel config file committed in the repository"
+	case def.Type == "bool" || def.Type == "int":
+		return fmt.Sprintf("set a %s: %s %s <value>", def.Type, setCmd, def.Key)
+	}
+	return fmt.Sprintf("%s: %s %s <value>", def.Description, setCmd, def.Key)
+}
+
+// firstLine returns the first line of an error message, dropping the
+// multi-line hints some validation errors carry
+func firstLine(err error) string {
+	msg, _, _ := strings.Cut(err.Error(), "\n")
+	return msg
+}
@@ -555,3 +555,67 @@ func TestValidateValue_KernelConfig_Directory(t *testing.T) {
 		t.Error("ValidateValue should reject directory for kernel config")
 	}
 }
+
+func TestValidateValue_Int_Accepted(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{"int", 3},
+		{"int64 from YAML", int64(3)},
+		{"whole float64 from JSON", float64(3)},
+		{"zero", 0},
+		{"large", 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateValue("kernels.download.retries", tt.value, ScopeRepo); err != nil {
+				t.Errorf("ValidateValue(%v) should accept: %v", tt.value, err)
+			}
+		})
+	}
+}
+
+func TestValidateValue_Int_Rejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantErr string
+	}{
+		{"negative int", -1, "must be at least 0"},
+		{"negative int64", int64(-5), "must be at least 0"},
+		{"negative float64", float64(-2), "must be at least 0"},
+		{"fractional float64", 1.5, "must be an integer"},
+		{"string", "three", "must be an integer"},
+		{"numeric string", "3", "must be an integer"},
+		{"bool", true, "must be an integer"},
+		{"nil", nil, "must be an integer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateValue("kernels.download.retries", tt.value, ScopeRepo)
+			if err == nil {
+				t.Fatalf("ValidateValue(%v) should reject", tt.value)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateValue(%v) error = %q, want it to contain %q", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateValue_Int_Max(t *testing.T) {
+	limit := 10
+	ConfigRegistry["test.int-max"] = ConfigKeyDefinition{Key: "test.int-max", Type: "int", Default: 1, Max: &limit}
+	t.Cleanup(func() { delete(ConfigRegistry, "test.int-max") })
+
+	if err := ValidateValue("test.int-max", 10, ScopeUser); err != nil {
+		t.Errorf("ValidateValue(10) should accept the maximum: %v", err)
+	}
+	err := ValidateValue("test.int-max", 11, ScopeUser)
+	if err == nil || !strings.Contains(err.Error(), "must be at most 10") {
+		t.Errorf("ValidateValue(11) error = %v, want 'must be at most 10'", err)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/spf13/pflag"
@@ -21,15 +23,29 @@ func InitViper() {
 	// Set defaults (lowest precedence)
 	viper.SetDefault("use-tui", true)
 	viper.SetDefault("log-level", "debug")
+	viper.SetDefault("ui.theme", ThemeDefault)
 	viper.SetDefault("github-token", "") // No default for sensitive keys
 	viper.SetDefault("signing.key.name", "ACME Kernels")
 	viper.SetDefault("signing.key.email", "fake@example.com")
 	viper.SetDefault("signing.key.expiry", "1y")
+	viper.SetDefault("signing.key.max-expiry", "2y")
+	viper.SetDefault("signing.require-expiry", false)
 	viper.SetDefault("signing.key.format", "armored")
+	viper.SetDefault("signing.key.algorithm", "rsa4096")
+	viper.SetDefault("signing.backend", "file")
 	viper.SetDefault("signing.key.location", GlobalPaths.KeysDir) // XDG: ~/.local/share/anvil/keys
 	viper.SetDefault("signing.history.location", "keys/history")
 	viper.SetDefault("signing.history.format", "armored")
 	viper.SetDefault("signing.encrypted-keys", true) // Encrypt private keys at rest by default
+	viper.SetDefault("kernels.archive.retain-count", 0)
+	viper.SetDefault("kernels.autosigner-key", "")
+	viper.SetDefault("kernels.archive.retain-days", 0)
+	viper.SetDefault("kernels.download.retries", 3)
+	viper.SetDefault("kernels.download.parallel", 1)
+	viper.SetDefault("kernels.api-timeout", "15s")
+	viper.SetDefault("kernels.min-free-space-gb", 15)
+	viper.SetDefault("kernels.mirror", "https://cdn.kernel.org/pub/linux/kernel")
+	viper.SetDefault("rootfs.alpine-mirror", "https://dl-cdn.alpinelinux.org")
 
 	// Enable environment variable support (highest precedence)
 	viper.SetEnvPrefix(EnvPrefix)
@@ -48,6 +64,9 @@ func ReloadConfig() error {
 // LoadConfig reads config files in precedence order
 // Precedence: ENV > ./anvil.yaml > ~/.config/anvil/config.yaml > defaults
 func LoadConfig() error {
+	// Apply the configured theme even if a config file fails to load
+	defer ApplyTheme()
+
 	// First, try to read user config from XDG config directory
 	viper.SetConfigName(ConfigFileName)
 	viper.AddConfigPath(GlobalPaths.ConfigDir)
@@ -58,6 +77,10 @@ func LoadConfig() error {
 		}
 		// Config file not found is OK
 	} else {
+		// Warn about unknown keys (typos) in user config
+		if err := checkUnknownKeys(filepath.Join(GlobalPaths.ConfigDir, ConfigFileName+DefaultConfigExt)); err != nil {
+			return err
+		}
 		// Warn about misplaced keys in user config
 		warnMisplacedKeys(GlobalPaths.ConfigDir, "user")
 	}
@@ -83,11 +106,39 @@ func LoadConfig() error {
 	return nil
 }
 
+// GetInt returns the value of an int-typed configuration key. When the key
+// is not set anywhere (not even as a Viper default), the registry default is
+// returned; unknown keys and values that are not whole numbers read as 0.
+func GetInt(key string) int {
+	if viper.IsSet(key) {
+		return viper.GetInt(key)
+	}
+	if def := GetKeyDefinition(key); def != nil {
+		if n, ok := intValue(def.Default); ok {
+			return n
+		}
+	}
+	return 0
+}
+
+// GetAssumeYes returns whether ANVIL_ASSUME_YES is set to a true value.
+// It is deliberately not a config file key, so auto-confirming has to be
+// asked for per run or per environment.
+func GetAssumeYes() bool {
+	value, err := strconv.ParseBool(os.Getenv(EnvPrefix + "_ASSUME_YES"))
+	return err == nil && value
+}
+
 // GetUseTUI returns the use-tui configuration value
 func GetUseTUI() bool {
 	return viper.GetBool("use-tui")
 }
 
+// GetUITheme returns the ui.theme configuration value
+func GetUITheme() string {
+	return viper.GetString("ui.theme")
+}
+
 // GetLogLevel returns the log-level configuration value
 func GetLogLevel() string {
 	return viper.GetString("log-level")
@@ -108,11 +159,31 @@ func GetSigningKeyExpiry() string {
 	return viper.GetString("signing.key.expiry")
 }
 
+// GetSigningKeyMaxExpiry returns the signing.key.max-expiry configuration value
+func GetSigningKeyMaxExpiry() string {
+	return viper.GetString("signing.key.max-expiry")
+}
+
+// GetSigningRequireExpiry returns the signing.require-expiry configuration value
+func GetSigningRequireExpiry() bool {
+	return viper.GetBool("signing.require-expiry")
+}
+
 // GetSigningKeyFormat returns the signing.key.format configuration value
 func GetSigningKeyFormat() string {
 	return viper.GetString("signing.key.format")
 }
 
+// GetSigningKeyAlgorithm returns the signing.key.algorithm configuration value
+func GetSigningKeyAlgorithm() string {
+	return viper.GetString("signing.key.algorithm")
+}
+
+// GetSigningBackend returns the signing.backend configuration value
+func GetSigningBackend() string {
+	return viper.GetString("signing.backend")
+}
+
 // GetSigningKeyLocation returns the signing.key.location configuration value
 // In a repo context (anvil.yaml exists), ENV variables are ignored
 // Precedence in repo context: repo config > user config > default
@@ -180,12 +251,87 @@ func GetKernelsConfigAarch64() string {
 	return viper.GetString("kernels.config.aarch64")
 }
 
+// GetKernelsConfigForArch returns the kernels.config.<arch> configuration value
+func GetKernelsConfigForArch(arch string) string {
+	switch arch {
+	case "x86_64":
+		return GetKernelsConfigX86_64()
+	case "aarch64":
+		return GetKernelsConfigAarch64()
+	default:
+		return ""
+	}
+}
+
 // GetKernelsArchiveLocation returns the kernels.archive.location configuration value.
 // Returns an empty string when not configured (no archiving).
 func GetKernelsArchiveLocation() string {
 	return viper.GetString("kernels.archive.location")
 }
 
+// GetKernelsArchiveRetainCount returns the kernels.archive.retain-count configuration value.
+// Returns 0 when not configured (keep all versions).
+func GetKernelsArchiveRetainCount() int {
+	return GetInt("kernels.archive.retain-count")
+}
+
+// GetKernelsArchiveRetainDays returns the kernels.archive.retain-days configuration value.
+// Returns 0 when not configured (no age limit).
+func GetKernelsArchiveRetainDays() int {
+	return GetInt("kernels.archive.retain-days")
+}
+
+// GetKernelsDownloadRetries returns the kernels.download.retries configuration
+// value: how often kernel.org requests are retried after a transient failure
+func GetKernelsDownloadRetries() int {
+	return max(GetInt("kernels.download.retries"), 0)
+}
+
+// DefaultKernelsAPITimeout is used when kernels.api-timeout is not a
+// positive duration
+const DefaultKernelsAPITimeout = 15 * time.Second
+
+// GetKernelsAPITimeout returns the kernels.api-timeout configuration value:
+// how long a kernel.org API request may take, retries included
+func GetKernelsAPITimeout() time.Duration {
+	timeout, err := time.ParseDuration(viper.GetString("kernels.api-timeout"))
+	if err != nil || timeout <= 0 {
+		return DefaultKernelsAPITimeout
+	}
+	return timeout
+}
+
+// GetKernelsDownloadParallel returns the kernels.download.parallel
+// configuration value: how many range requests a kernel source download
+// uses at once (1 = a single resumable stream)
+func GetKernelsDownloadParallel() int {
+	return max(GetInt("kernels.download.parallel"), 1)
+}
+
+// GetKernelsAutosignerKey returns the kernels.autosigner-key configuration
+// value: a local kernel.org autosigner public key file to import instead of
+// the embedded key and keyservers. Empty when not configured.
+func GetKernelsAutosignerKey() string {
+	return viper.GetString("kernels.autosigner-key")
+}
+
+// GetKernelsMinFreeSpaceGB returns the kernels.min-free-space-gb
+// configuration value: the free space (in GiB) a kernel build needs in the
+// build cache before it starts. 0 disables the check.
+func GetKernelsMinFreeSpaceGB() int {
+	return max(GetInt("kernels.min-free-space-gb"), 0)
+}
+
+// GetKernelsMirror returns the kernels.mirror configuration value
+func GetKernelsMirror() string {
+	return viper.GetString("kernels.mirror")
+}
+
+// GetRootfsAlpineMirror returns the rootfs.alpine-mirror configuration value
+func GetRootfsAlpineMirror() string {
+	return viper.GetString("rootfs.alpine-mirror")
+}
+
 // validateConfigFile validates that a config file doesn't contain forbidden keys for the given scope
 // For repo scope, also validates that all required keys are present
 func validateConfigFile(configDir string, scope ConfigScope) error {
@@ -245,6 +391,9 @@ func validateConfigFile(configDir string, scope ConfigScope) error {
 	for _, key := range keys {
 		// Validate scope
 		if err := ValidateKeyScope(key, scope); err != nil {
+			if HasMigration(key) {
+				return fmt.Errorf("invalid key in config file %s: %w\n\nRun 'anvil config migrate' to update it", configPath, err)
+			}
 			return fmt.Errorf("invalid key in config file %s: %w", configPath, err)
 		}
 
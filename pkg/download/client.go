@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+package download
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// CABundleEnv names a PEM file of extra CA certificates to trust, for
+// proxies and mirrors that re-sign TLS with a corporate CA
+const CABundleEnv = "ANVIL_CA_BUNDLE"
+
+const (
+	// RequestTimeout limits a whole request made with HTTPClient, response
+	// body included
+	RequestTimeout = 30 * time.Second
+
+	// Connecting and waiting for a response are limited separately so that
+	// a hung server fails a download instead of stalling it, while a slow
+	// but steady download of a large file is never cut off
+	dialTimeout           = 30 * time.Second
+	tlsHandshakeTimeout   = 15 * time.Second
+	responseHeaderTimeout = 60 * time.Second
+)
+
+var (
+	sharedTransport     *http.Transport
+	sharedTransportOnce sync.Once
+)
+
+// HTTPClient returns the client for API requests and other small responses
+// (kernel.org's releases.json, Alpine's release index). It uses the proxy
+// from HTTP_PROXY, HTTPS_PROXY and NO_PROXY, trusts the certificates in
+// ANVIL_CA_BUNDLE in addition to the system roots, and gives up after
+// RequestTimeout.
+func HTTPClient() *http.Client {
+	return &http.Client{Transport: transport(), Timeout: RequestTimeout}
+}
+
+// downloadClient is HTTPClient without the overall timeout, for file
+// downloads that may legitimately take longer than RequestTimeout
+func downloadClient() *http.Client {
+	return &http.Client{Transport: transport()}
+}
+
+// transport returns the transport shared by all clients, so connections
+// are reused across requests. ANVIL_CA_BUNDLE is read once.
+func transport() *http.Transport {
+	sharedTransportOnce.Do(func() {
+		t, err := newTransport(os.Getenv(CABundleEnv))
+		if err != nil {
+			log.Warnf("Ignoring %s: %v", CABundleEnv, err)
+			t, _ = newTransport("")
+		}
+		sharedTransport = t
+	})
+	return sharedTransport
+}
+
+// newTransport builds a transport that uses the environment's proxy
+// settings and, when caBundle is set, also trusts the CA certificates in it
+func newTransport(caBundle string) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyFromEnvironment
+	t.DialContext = (&net.Dialer{Timeout: dialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	t.TLSHandshakeTimeout = tlsHandshakeTimeout
+	t.ResponseHeaderTimeout = responseHeaderTimeout
+
+	if caBundle == "" {
+		return t, nil
+	}
+	pem, err := os.ReadFile(caBundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil || roots == nil {
+		roots = x509.NewCertPool()
+	}
+	if !roots.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", caBundle)
+	}
+	t.TLSClientConfig = &tls.Config{RootCAs: roots}
+	return t, nil
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+package download
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewTransportCABundle(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	// Without the server's CA the certificate is rejected
+	plain, err := newTransport("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := (&http.Client{Transport: plain}).Get(srv.URL); err == nil {
+		resp.Body.Close()
+		t.Fatal("request to a server with an untrusted certificate succeeded")
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(bundle, cert, 0644); err != nil {
+		t.Fatal(err)
+	}
+	trusted, err := newTransport(bundle)
+	if err != nil {
+		t.Fatalf("newTransport() error = %v", err)
+	}
+	resp, err := (&http.Client{Transport: trusted}).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("request with the CA bundle failed: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestNewTransportInvalidCABundle(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := newTransport(filepath.Join(dir, "missing.pem")); err == nil {
+		t.Error("newTransport() accepted a missing CA bundle")
+	}
+
+	notPEM := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newTransport(notPEM); err == nil {
+		t.Error("newTransport() accepted a CA bundle without certificates")
+	}
+}
+
+func TestNewTransportProxyFromEnvironment(t *testing.T) {
+	tr, err := newTransport("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Proxy == nil {
+		t.Error("transport should take its proxy from the environment")
+	}
+	if tr.ResponseHeaderTimeout == 0 {
+		t.Error("transport should time out waiting for a response")
+	}
+}
@@ -2,10 +2,13 @@
 package download
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/log"
 )
@@ -18,6 +21,8 @@ type ProgressCallback func(percent float64)
 type Options struct {
 	ProgressCallback ProgressCallback
 	Headers          map[string]string
+	Context          context.Context // Optional: cancels the request (e.g. on timeout)
+	Retries          int             // Retries of the initial request after a network error or 5xx (0 = none)
 }
 
 // File downloads a file from URL to destination with optional progress callback
@@ -35,22 +40,25 @@ func FileWithOptions(url, dest string, opts *Options) error {
 		opts = &Options{}
 	}
 
-	// Create HTTP client with default settings
-	client := &http.Client{}
+	client := downloadClient()
 
-	// Create the HTTP request
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Add custom headers
-	for key, value := range opts.Headers {
-		req.Header.Set(key, value)
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
-	// Make the request
-	resp, err := client.Do(req)
+	// Make the request, retrying transient failures to connect
+	resp, err := doWithRetry(ctx, client, opts.Retries, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		// Add custom headers
+		for key, value := range opts.Headers {
+			req.Header.Set(key, value)
+		}
+		return req, nil
+	})
 	if err != nil {
 		return fmt.Errorf("failed to download: %w", err)
 	}
@@ -68,40 +76,194 @@ func FileWithOptions(url, dest string, opts *Options) error {
 	}
 	defer out.Close()
 
-	// Get total size for progress tracking
-	totalSize := resp.ContentLength
-	downloaded := int64(0)
-
-	// Create a progress reader if callback provided
-	if opts.ProgressCallback != nil && totalSize > 0 {
-		// Wrap the response body with progress tracking
-		buf := make([]byte, 32*1024) // 32KB buffer
-		for {
-			n, err := resp.Body.Read(buf)
-			if n > 0 {
-				downloaded += int64(n)
-				if _, writeErr := out.Write(buf[:n]); writeErr != nil {
-					return fmt.Errorf("failed to write: %w", writeErr)
-				}
-
-				// Report progress
-				percent := float64(downloaded) / float64(totalSize)
-				opts.ProgressCallback(percent)
-			}
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				return fmt.Errorf("failed to read: %w", err)
-			}
-		}
+	if err := copyWithProgress(out, resp.Body, 0, resp.ContentLength, opts.ProgressCallback); err != nil {
+		return err
+	}
+
+	log.Debugf("Download complete: %s", dest)
+	return nil
+}
+
+// partSuffix is appended to dest while a resumable download is in progress
+const partSuffix = ".part"
+
+// FileResumable downloads a file from URL to destination, resuming from a
+// partial dest.part file left by an earlier interrupted download. Progress is
+// reported relative to the full file size.
+func FileResumable(url, dest string, progressCallback ProgressCallback) error {
+	return FileResumableWithOptions(url, dest, &Options{
+		ProgressCallback: progressCallback,
+	})
+}
+
+// FileResumableWithOptions downloads a file with custom options, resuming a
+// partial download with an HTTP range request. Data is written to dest.part
+// and renamed to dest once complete; on error the partial file is kept so the
+// next call can resume. If the server ignores the range and answers 200, the
+// download restarts from zero.
+func FileResumableWithOptions(url, dest string, opts *Options) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	partPath := dest + partSuffix
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil && info.Mode().IsRegular() {
+		offset = info.Size()
+	}
+
+	if offset > 0 {
+		log.Debugf("Resuming download of %s at byte %d", url, offset)
 	} else {
-		// No progress tracking, just copy
-		if _, err := io.Copy(out, resp.Body); err != nil {
-			return fmt.Errorf("failed to save: %w", err)
+		log.Debugf("Downloading %s to %s", url, dest)
+	}
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client := downloadClient()
+	resp, err := doWithRetry(ctx, client, opts.Retries, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range opts.Headers {
+			req.Header.Set(key, value)
+		}
+		if offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		}
+		return req, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to download: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var total int64
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		start, size, err := parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil {
+			return err
 		}
+		if start != offset {
+			return fmt.Errorf("server resumed at byte %d, expected %d", start, offset)
+		}
+		total = size
+		if total < 0 && resp.ContentLength >= 0 {
+			total = offset + resp.ContentLength
+		}
+		flags |= os.O_APPEND
+	case http.StatusOK:
+		if offset > 0 {
+			log.Debugf("Server does not support range requests, restarting download")
+		}
+		offset = 0
+		total = resp.ContentLength
+		flags |= os.O_TRUNC
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The partial file may already hold the whole body
+		if _, size, err := parseContentRange(resp.Header.Get("Content-Range")); err == nil && size == offset {
+			return finishPart(partPath, dest, opts.ProgressCallback)
+		}
+		os.Remove(partPath)
+		return fmt.Errorf("bad status: %s (partial download discarded, retry to start over)", resp.Status)
+	default:
+		return fmt.Errorf("bad status: %s", resp.Status)
+	}
+
+	out, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	if err := copyWithProgress(out, resp.Body, offset, total, opts.ProgressCallback); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to save: %w", err)
 	}
 
+	return finishPart(partPath, dest, opts.ProgressCallback)
+}
+
+// finishPart moves a completed partial download into place
+func finishPart(partPath, dest string, progressCallback ProgressCallback) error {
+	if err := os.Rename(partPath, dest); err != nil {
+		return fmt.Errorf("failed to save: %w", err)
+	}
+	if progressCallback != nil {
+		progressCallback(1.0)
+	}
 	log.Debugf("Download complete: %s", dest)
 	return nil
 }
+
+// parseContentRange parses a "bytes start-end/size" or "bytes */size"
+// Content-Range header. size is -1 when the server reports it as unknown.
+func parseContentRange(header string) (start, size int64, err error) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", header)
+	}
+	rangePart, sizePart, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", header)
+	}
+
+	size = -1
+	if sizePart != "*" {
+		if size, err = strconv.ParseInt(sizePart, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("invalid Content-Range: %q", header)
+		}
+	}
+
+	if rangePart == "*" {
+		return 0, size, nil
+	}
+	startPart, _, ok := strings.Cut(rangePart, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", header)
+	}
+	if start, err = strconv.ParseInt(startPart, 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", header)
+	}
+
+	return start, size, nil
+}
+
+// copyWithProgress copies body to out, reporting progress as a fraction of
+// total with done bytes already written. Progress is only reported when total
+// is known.
+func copyWithProgress(out io.Writer, body io.Reader, done, total int64, progressCallback ProgressCallback) error {
+	if progressCallback == nil || total <= 0 {
+		if _, err := io.Copy(out, body); err != nil {
+			return fmt.Errorf("failed to save: %w", err)
+		}
+		return nil
+	}
+
+	buf := make([]byte, 32*1024) // 32KB buffer
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			done += int64(n)
+			if _, writeErr := out.Write(buf[:n]); writeErr != nil {
+				return fmt.Errorf("failed to write: %w", writeErr)
+			}
+
+			// Report progress
+			progressCallback(float64(done) / float64(total))
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read: %w", err)
+		}
+	}
+}
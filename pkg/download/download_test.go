@@ -0,0 +1,301 @@
+// SPDX-License-Identifier: Apache-2.0
+package download
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileResumable(t *testing.T) {
+	content := bytes.Repeat([]byte("anvil kernel source "), 4096)
+
+	tests := []struct {
+		name         string
+		partial      []byte
+		ignoreRanges bool
+		wantRange    bool
+	}{
+		{name: "fresh download", wantRange: false},
+		{name: "resume from partial", partial: content[:1000], wantRange: true},
+		{name: "partial already complete", partial: content, wantRange: true},
+		{name: "server ignores range", partial: content[:1000], ignoreRanges: true, wantRange: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRange string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotRange = r.Header.Get("Range")
+				if tt.ignoreRanges {
+					w.Write(content)
+					return
+				}
+				http.ServeContent(w, r, "linux.tar.xz", time.Time{}, bytes.NewReader(content))
+			}))
+			defer srv.Close()
+
+			dest := filepath.Join(t.TempDir(), "linux.tar.xz")
+			if tt.partial != nil {
+				if err := os.WriteFile(dest+partSuffix, tt.partial, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var first, last float64 = -1, 0
+			err := FileResumable(srv.URL, dest, func(percent float64) {
+				if first < 0 {
+					first = percent
+				}
+				last = percent
+			})
+			if err != nil {
+				t.Fatalf("FileResumable() error = %v", err)
+			}
+
+			if (gotRange != "") != tt.wantRange {
+				t.Errorf("Range header = %q, want range request: %v", gotRange, tt.wantRange)
+			}
+			got, err := os.ReadFile(dest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Errorf("downloaded %d bytes, want %d matching bytes", len(got), len(content))
+			}
+			if _, err := os.Stat(dest + partSuffix); !os.IsNotExist(err) {
+				t.Error("partial file should be removed after a complete download")
+			}
+			if last != 1.0 {
+				t.Errorf("final progress = %v, want 1.0", last)
+			}
+			// Resumed progress starts from the bytes already on disk
+			if tt.partial != nil && !tt.ignoreRanges && first < float64(len(tt.partial))/float64(len(content)) {
+				t.Errorf("first progress = %v, want at least %v", first, float64(len(tt.partial))/float64(len(content)))
+			}
+		})
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		header    string
+		wantStart int64
+		wantSize  int64
+		wantErr   bool
+	}{
+		{header: "bytes 100-199/200", wantStart: 100, wantSize: 200},
+		{header: "bytes 0-99/*", wantStart: 0, wantSize: -1},
+		{header: "bytes */500", wantStart: 0, wantSize: 500},
+		{header: "", wantErr: true},
+		{header: "items 0-1/2", wantErr: true},
+		{header: "bytes x-1/2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			start, size, err := parseContentRange(tt.header)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseContentRange(%q) should fail", tt.header)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseContentRange(%q) error = %v", tt.header, err)
+			}
+			if start != tt.wantStart || size != tt.wantSize {
+				t.Errorf("parseContentRange(%q) = (%d, %d), want (%d, %d)", tt.header, start, size, tt.wantStart, tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestGetRetries(t *testing.T) {
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = 500 * time.Millisecond }()
+
+	tests := []struct {
+		name         string
+		statuses     []int // status per attempt; the last one repeats
+		retries      int
+		wantStatus   int
+		wantAttempts int
+	}{
+		{name: "success", statuses: []int{200}, retries: 3, wantStatus: 200, wantAttempts: 1},
+		{name: "recovers from 503", statuses: []int{503, 502, 200}, retries: 3, wantStatus: 200, wantAttempts: 3},
+		{name: "gives up after retries", statuses: []int{503}, retries: 2, wantStatus: 503, wantAttempts: 3},
+		{name: "404 is not retried", statuses: []int{404}, retries: 3, wantStatus: 404, wantAttempts: 1},
+		{name: "no retries", statuses: []int{500, 200}, retries: 0, wantStatus: 500, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[min(attempts, len(tt.statuses)-1)]
+				attempts++
+				w.WriteHeader(status)
+			}))
+			defer srv.Close()
+
+			resp, err := Get(t.Context(), srv.URL, tt.retries)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestGetRetriesNetworkErrors(t *testing.T) {
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = 500 * time.Millisecond }()
+
+	// A closed server refuses connections
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	_, err := Get(t.Context(), url, 2)
+	if err == nil {
+		t.Fatal("Get() should fail when the server is unreachable")
+	}
+	if !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Errorf("error = %v, want it to report 3 attempts", err)
+	}
+}
+
+func TestFileParallel(t *testing.T) {
+	// Large enough for several minChunkSize chunks
+	content := bytes.Repeat([]byte("0123456789abcdef"), 4*minChunkSize/16+123)
+
+	tests := []struct {
+		name         string
+		handler      func(w http.ResponseWriter, r *http.Request)
+		wantRanges   bool
+		wantFallback bool
+	}{
+		{
+			name: "server supports ranges",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.ServeContent(w, r, "linux.tar.xz", time.Time{}, bytes.NewReader(content))
+			},
+			wantRanges: true,
+		},
+		{
+			name: "server rejects ranges",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Accept-Ranges", "none")
+				w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+				if r.Method == "GET" {
+					w.Write(content)
+				}
+			},
+			wantFallback: true,
+		},
+		{
+			name: "server advertises but ignores ranges",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Accept-Ranges", "bytes")
+				w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+				if r.Method == "GET" {
+					w.Write(content)
+				}
+			},
+			wantRanges:   true,
+			wantFallback: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var ranges, plainGets int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				if r.Method == "GET" {
+					if r.Header.Get("Range") != "" {
+						ranges++
+					} else {
+						plainGets++
+					}
+				}
+				mu.Unlock()
+				tt.handler(w, r)
+			}))
+			defer srv.Close()
+
+			dest := filepath.Join(t.TempDir(), "linux.tar.xz")
+			var last float64
+			err := FileParallel(srv.URL, dest, 4, func(percent float64) {
+				if percent < last {
+					t.Errorf("progress went backwards: %v after %v", percent, last)
+				}
+				last = percent
+			})
+			if err != nil {
+				t.Fatalf("FileParallel() error = %v", err)
+			}
+
+			got, err := os.ReadFile(dest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Errorf("downloaded %d bytes, want %d matching bytes", len(got), len(content))
+			}
+			if _, err := os.Stat(dest + parallelSuffix); !os.IsNotExist(err) {
+				t.Error("temporary file should be removed after the download")
+			}
+			if last != 1.0 {
+				t.Errorf("final progress = %v, want 1.0", last)
+			}
+			if tt.wantRanges && ranges < 2 {
+				t.Errorf("got %d range requests, want several", ranges)
+			}
+			if !tt.wantRanges && ranges != 0 {
+				t.Errorf("got %d range requests, want none", ranges)
+			}
+			if (plainGets == 1) != tt.wantFallback {
+				t.Errorf("got %d single-stream requests, want fallback: %v", plainGets, tt.wantFallback)
+			}
+		})
+	}
+}
+
+func TestFileParallelError(t *testing.T) {
+	content := bytes.Repeat([]byte("x"), 2*minChunkSize)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.Header.Get("Range"), "bytes=0-") {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		http.ServeContent(w, r, "linux.tar.xz", time.Time{}, bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "linux.tar.xz")
+	if err := FileParallel(srv.URL, dest, 2, nil); err == nil {
+		t.Fatal("FileParallel() should fail when a chunk fails")
+	}
+	for _, path := range []string{dest, dest + parallelSuffix} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should not exist after a failed download", filepath.Base(path))
+		}
+	}
+}
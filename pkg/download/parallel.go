@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: Apache-2.0
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+)
+
+// parallelSuffix is appended to dest while a parallel download is in progress
+const parallelSuffix = ".parallel"
+
+// minChunkSize keeps small files from being split into tiny ranges
+const minChunkSize = 1 << 20
+
+// errRangesIgnored is returned by a chunk when the server answers a range
+// request with the whole file
+var errRangesIgnored = errors.New("server ignored the range request")
+
+// FileParallel downloads a file from URL to destination using up to chunks
+// concurrent range requests
+func FileParallel(url, dest string, chunks int, progressCallback ProgressCallback) error {
+	return FileParallelWithOptions(url, dest, chunks, &Options{
+		ProgressCallback: progressCallback,
+	})
+}
+
+// FileParallelWithOptions downloads a file with custom options, splitting it
+// into up to chunks disjoint byte ranges fetched at the same time. Progress
+// is reported across all chunks. A server that doesn't advertise
+// "Accept-Ranges: bytes" or a Content-Length, or that ignores the ranges,
+// gets a single-stream download instead. Data is written to dest.parallel
+// and renamed to dest once complete; it is removed on error, since a
+// partly filled file can't be resumed.
+func FileParallelWithOptions(url, dest string, chunks int, opts *Options) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if chunks <= 1 {
+		return FileWithOptions(url, dest, opts)
+	}
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client := downloadClient()
+
+	size, ok, err := probeRanges(ctx, client, url, opts)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		log.Debugf("Server does not support range requests, downloading %s as a single stream", url)
+		return FileWithOptions(url, dest, opts)
+	}
+	chunks = int(min(int64(chunks), max(size/minChunkSize, 1)))
+	if chunks == 1 {
+		return FileWithOptions(url, dest, opts)
+	}
+
+	log.Debugf("Downloading %s to %s in %d chunks", url, dest, chunks)
+
+	tmpPath := dest + parallelSuffix
+	out, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if err := out.Truncate(size); err != nil {
+		out.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to allocate file: %w", err)
+	}
+
+	err = fetchChunks(ctx, client, url, out, size, chunks, opts)
+	if closeErr := out.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to save: %w", closeErr)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		if errors.Is(err, errRangesIgnored) {
+			log.Debugf("%v, downloading %s as a single stream", err, url)
+			return FileWithOptions(url, dest, opts)
+		}
+		return err
+	}
+
+	if err := os.Rename(tmpPath, dest); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save: %w", err)
+	}
+	if opts.ProgressCallback != nil {
+		opts.ProgressCallback(1.0)
+	}
+	log.Debugf("Download complete: %s", dest)
+	return nil
+}
+
+// probeRanges sends a HEAD request and reports the file size and whether
+// the server accepts byte ranges for it
+func probeRanges(ctx context.Context, client *http.Client, url string, opts *Options) (int64, bool, error) {
+	resp, err := doWithRetry(ctx, client, opts.Retries, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range opts.Headers {
+			req.Header.Set(key, value)
+		}
+		return req, nil
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to download: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Some servers reject HEAD; let the single-stream GET report errors
+		return 0, false, nil
+	}
+	acceptsRanges := strings.Contains(strings.ToLower(resp.Header.Get("Accept-Ranges")), "bytes")
+	return resp.ContentLength, acceptsRanges && resp.ContentLength > 0, nil
+}
+
+// fetchChunks downloads size bytes in chunks concurrent range requests,
+// writing each at its offset in out. The first failure cancels the rest.
+func fetchChunks(ctx context.Context, client *http.Client, url string, out *os.File, size int64, chunks int, opts *Options) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		done     int64
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	// Chunks report through one counter so the callback sees overall
+	// progress, one call at a time
+	report := func(n int64) {
+		mu.Lock()
+		defer mu.Unlock()
+		done += n
+		if opts.ProgressCallback != nil {
+			opts.ProgressCallback(float64(done) / float64(size))
+		}
+	}
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	chunkSize := size / int64(chunks)
+	for i := range chunks {
+		start := int64(i) * chunkSize
+		end := start + chunkSize - 1
+		if i == chunks-1 {
+			end = size - 1
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fetchChunk(ctx, client, url, out, start, end, opts, report); err != nil {
+				fail(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// fetchChunk downloads bytes start through end (inclusive) into out
+func fetchChunk(ctx context.Context, client *http.Client, url string, out *os.File, start, end int64, opts *Options, report func(int64)) error {
+	resp, err := doWithRetry(ctx, client, opts.Retries, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range opts.Headers {
+			req.Header.Set(key, value)
+		}
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+		return req, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to download: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		return errRangesIgnored
+	default:
+		return fmt.Errorf("bad status: %s", resp.Status)
+	}
+	gotStart, _, err := parseContentRange(resp.Header.Get("Content-Range"))
+	if err != nil {
+		return err
+	}
+	if gotStart != start {
+		return fmt.Errorf("server sent range starting at byte %d, expected %d", gotStart, start)
+	}
+
+	want := end - start + 1
+	w := &countingWriter{w: io.NewOffsetWriter(out, start), report: report}
+	n, err := io.Copy(w, io.LimitReader(resp.Body, want))
+	if err != nil {
+		return fmt.Errorf("failed to read: %w", err)
+	}
+	if n != want {
+		return fmt.Errorf("range %d-%d ended after %d of %d bytes", start, end, n, want)
+	}
+	return nil
+}
+
+// countingWriter reports every write's size
+type countingWriter struct {
+	w      io.Writer
+	report func(int64)
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	if n > 0 {
+		c.report(int64(n))
+	}
+	return n, err
+}
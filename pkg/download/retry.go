@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+package download
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// DefaultRetries is the number of retries after a transient failure used
+// when no retry count is configured
+const DefaultRetries = 3
+
+// retryBaseDelay is the wait before the first retry; it doubles after each
+// attempt (500ms, 1s, 2s, ...)
+var retryBaseDelay = 500 * time.Millisecond
+
+// Get performs a GET request to url, retrying network errors and 5xx
+// responses up to retries times with exponential backoff. Other responses,
+// including 404, are returned as-is for the caller to check. The caller
+// closes the response body, which must arrive within RequestTimeout.
+func Get(ctx context.Context, url string, retries int) (*http.Response, error) {
+	return doWithRetry(ctx, HTTPClient(), retries, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", url, nil)
+	})
+}
+
+// doWithRetry sends the request built by newRequest, retrying transient
+// failures. A fresh request is built for every attempt.
+func doWithRetry(ctx context.Context, client *http.Client, retries int, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		resp, err := client.Do(req)
+		if err == nil && resp.StatusCode < 500 {
+			return resp, nil
+		}
+		if attempt >= retries || ctx.Err() != nil {
+			if err != nil && attempt > 0 {
+				return nil, fmt.Errorf("%w (after %d attempts)", err, attempt+1)
+			}
+			return resp, err
+		}
+
+		if err != nil {
+			log.Debugf("Request to %s failed, retrying in %s: %v", req.URL, delay, err)
+		} else {
+			log.Debugf("Request to %s returned %s, retrying in %s", req.URL, resp.Status, delay)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
@@ -2,17 +2,20 @@
 package embedded
 
 import (
-	_ "embed"
+	"embed"
 	"fmt"
 	"os"
 )
 
-// VsockServerBinary contains the embedded vsock-server-standalone binary
-// This is embedded at build time from vsock-server-standalone in this directory
-// The binary is copied here during the build process
+// vsockServerBinaries holds the host build plus any cross-compiled
+// vsock-server-standalone-<arch> binaries copied here during the build
 //
-//go:embed vsock-server-standalone
-var VsockServerBinary []byte
+//go:embed vsock-server-standalone*
+var vsockServerBinaries embed.FS
+
+// VsockServerBinary contains the embedded vsock-server-standalone binary
+// This is the host build, read from vsockServerBinaries so it is only embedded once
+var VsockServerBinary, _ = vsockServerBinaries.ReadFile("vsock-server-standalone")
 
 // ExtractVsockServer extracts the embedded vsock-server binary to a temporary file
 // Returns the path to the extracted binary and a cleanup function
@@ -20,7 +23,23 @@ func ExtractVsockServer() (path string, cleanup func(), err error) {
 	if len(VsockServerBinary) == 0 {
 		return "", nil, fmt.Errorf("vsock-server binary not embedded (build with: task go:build)")
 	}
+	return extractBinary(VsockServerBinary)
+}
+
+// ExtractVsockServerForArch extracts the embedded vsock-server binary built
+// for arch (x86_64 or aarch64) to a temporary file. Falls back to the host
+// build when no vsock-server-standalone-<arch> binary is embedded, so callers
+// should verify the result matches the target architecture.
+func ExtractVsockServerForArch(arch string) (path string, cleanup func(), err error) {
+	data, err := vsockServerBinaries.ReadFile("vsock-server-standalone-" + arch)
+	if err != nil || len(data) == 0 {
+		return ExtractVsockServer()
+	}
+	return extractBinary(data)
+}
 
+// extractBinary writes data to an executable temporary file
+func extractBinary(data []byte) (path string, cleanup func(), err error) {
 	// Create temp file
 	tmpFile, err := os.CreateTemp("", "vsock-server-*")
 	if err != nil {
@@ -30,7 +49,7 @@ func ExtractVsockServer() (path string, cleanup func(), err error) {
 	tmpPath := tmpFile.Name()
 
 	// Write embedded binary
-	if _, err := tmpFile.Write(VsockServerBinary); err != nil {
+	if _, err := tmpFile.Write(data); err != nil {
 		tmpFile.Close()
 		os.Remove(tmpPath)
 		return "", nil, fmt.Errorf("failed to write binary: %w", err)
@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: Apache-2.0
+package embedded
+
+import (
+	"bytes"
+	"debug/elf"
+	"testing"
+)
+
+func TestVsockServerBinariesForEveryArch(t *testing.T) {
+	machines := map[string]elf.Machine{
+		"x86_64":  elf.EM_X86_64,
+		"aarch64": elf.EM_AARCH64,
+	}
+	for arch, machine := range machines {
+		t.Run(arch, func(t *testing.T) {
+			data, err := vsockServerBinaries.ReadFile("vsock-server-standalone-" + arch)
+			if err != nil {
+				t.Fatalf("no vsock-server-standalone-%s embedded (build with: mise run build): %v", arch, err)
+			}
+			f, err := elf.NewFile(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("vsock-server-standalone-%s isn't an ELF binary: %v", arch, err)
+			}
+			if f.Machine != machine {
+				t.Errorf("vsock-server-standalone-%s is built for %v, want %v", arch, f.Machine, machine)
+			}
+		})
+	}
+}
@@ -61,16 +61,15 @@ func DownloadWithProgress(version string, client *github.Client, paths *config.P
 
 	log.Debugf("Downloading Firecracker %s for %s", version, arch)
 
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
-	}
-
 	// Firecracker uses the same arch naming for aarch64
 	fcArch := arch
 
 	releaseURL := fmt.Sprintf("https://github.com/%s/releases/download/v%s", config.FirecrackerRepo, version)
 	filename := fmt.Sprintf("firecracker-v%s-%s.tgz", version, fcArch)
 	tempFile := filepath.Join(paths.CacheDir, filename)
+	checksumsFile := tempFile + ".sha256.txt"
+	defer os.Remove(tempFile)
+	defer os.Remove(checksumsFile)
 
 	// Download with automatic GitHub token injection and progress tracking
 	if statusCallback != nil {
@@ -82,12 +81,43 @@ func DownloadWithProgress(version string, client *github.Client, paths *config.P
 		return fmt.Errorf("failed to download Firecracker: %w", err)
 	}
 
+	// Each release tarball has a sha256sum-style checksum file next to it
+	if err := client.DownloadFile(downloadURL+".sha256.txt", checksumsFile, nil); err != nil {
+		return fmt.Errorf("failed to download Firecracker checksum: %w", err)
+	}
+
+	if err := installRelease(tempFile, checksumsFile, version, fcArch, paths.CacheDir, outputDir, statusCallback); err != nil {
+		return err
+	}
+
+	// Done
+	if statusCallback != nil {
+		statusCallback("Installation complete!")
+	}
+
+	return nil
+}
+
+// installRelease verifies a downloaded release tarball against its checksum
+// file, extracts it into workDir and installs the firecracker binary into
+// outputDir. The binary is written under a temporary name and renamed, so an
+// interrupted install never leaves a partial binary that looks installed.
+func installRelease(tarball, checksumsFile, version, fcArch, workDir, outputDir string, statusCallback func(string)) error {
+	if statusCallback != nil {
+		statusCallback("Verifying checksum...")
+	}
+	if err := util.VerifySHA256File(tarball, checksumsFile); err != nil {
+		return fmt.Errorf("firecracker release failed verification: %w", err)
+	}
+
 	// Extract
 	if statusCallback != nil {
 		statusCallback("Extracting archive...")
 	}
 	log.Debug("Extracting Firecracker")
-	if err := util.ExtractTarGz(tempFile, paths.CacheDir); err != nil {
+	extractedDir := filepath.Join(workDir, fmt.Sprintf("release-v%s-%s", version, fcArch))
+	defer os.RemoveAll(extractedDir)
+	if err := util.ExtractTarGz(tarball, workDir); err != nil {
 		return fmt.Errorf("failed to extract Firecracker: %w", err)
 	}
 
@@ -95,7 +125,6 @@ func DownloadWithProgress(version string, client *github.Client, paths *config.P
 	if statusCallback != nil {
 		statusCallback("Installing binary...")
 	}
-	extractedDir := filepath.Join(paths.CacheDir, fmt.Sprintf("release-v%s-%s", version, fcArch))
 	extractedBinary := filepath.Join(extractedDir, fmt.Sprintf("firecracker-v%s-%s", version, fcArch))
 
 	data, err := os.ReadFile(extractedBinary)
@@ -103,20 +132,24 @@ func DownloadWithProgress(version string, client *github.Client, paths *config.P
 		return fmt.Errorf("failed to read extracted binary: %w", err)
 	}
 
-	if err := os.WriteFile(outputFile, data, 0755); err != nil {
-		return fmt.Errorf("failed to write binary: %w", err)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	// Clean up
-	if statusCallback != nil {
-		statusCallback("Cleaning up temporary files...")
+	outputFile := filepath.Join(outputDir, "firecracker")
+	tmpFile := outputFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0755); err != nil {
+		os.Remove(tmpFile)
+		return fmt.Errorf("failed to write binary: %w", err)
 	}
-	os.Remove(tempFile)
-	os.RemoveAll(extractedDir)
-
-	// Done
-	if statusCallback != nil {
-		statusCallback("Installation complete!")
+	// WriteFile's mode is subject to the umask
+	if err := os.Chmod(tmpFile, 0755); err != nil {
+		os.Remove(tmpFile)
+		return fmt.Errorf("failed to make binary executable: %w", err)
+	}
+	if err := os.Rename(tmpFile, outputFile); err != nil {
+		os.Remove(tmpFile)
+		return fmt.Errorf("failed to install binary: %w", err)
 	}
 
 	return nil
@@ -178,6 +211,10 @@ func Set(version string, paths *config.Paths) error {
 
 	log.Debugf("Setting Firecracker %s as default", version)
 
+	if err := os.MkdirAll(paths.BinDir, 0755); err != nil {
+		return fmt.Errorf("failed to create bin directory: %w", err)
+	}
+
 	// Remove existing symlink if present
 	os.Remove(symlinkPath)
 
@@ -0,0 +1,120 @@
+// anvil/pkg/firecracker/firecracker_internal_test.go
+package firecracker
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+// writeReleaseTarball creates a release tarball laid out like upstream's,
+// with the binary at release-v<version>-<arch>/firecracker-v<version>-<arch>
+func writeReleaseTarball(t *testing.T, dir, version, arch string, binary []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, fmt.Sprintf("firecracker-v%s-%s.tgz", version, arch))
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create tarball: %v", err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	releaseDir := fmt.Sprintf("release-v%s-%s", version, arch)
+	if err := tw.WriteHeader(&tar.Header{Name: releaseDir + "/", Typeflag: tar.TypeDir, Mode: 0755}); err != nil {
+		t.Fatalf("failed to write dir header: %v", err)
+	}
+	name := fmt.Sprintf("%s/firecracker-v%s-%s", releaseDir, version, arch)
+	if err := tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0755, Size: int64(len(binary))}); err != nil {
+		t.Fatalf("failed to write file header: %v", err)
+	}
+	if _, err := tw.Write(binary); err != nil {
+		t.Fatalf("failed to write binary: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar writer: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("failed to close gzip writer: %v", err)
+	}
+	return path
+}
+
+func writeChecksums(t *testing.T, tarball, hash string) string {
+	t.Helper()
+	path := tarball + ".sha256.txt"
+	content := fmt.Sprintf("%s  %s\n", hash, filepath.Base(tarball))
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write checksums: %v", err)
+	}
+	return path
+}
+
+// TestInstallRelease tests that a verified tarball installs an executable binary
+func TestInstallRelease(t *testing.T) {
+	workDir := t.TempDir()
+	outputDir := filepath.Join(t.TempDir(), "1.14.1")
+	binary := []byte("#!/bin/sh\necho firecracker")
+
+	tarball := writeReleaseTarball(t, workDir, "1.14.1", "x86_64", binary)
+	hash, err := util.CalculateSHA256(tarball)
+	if err != nil {
+		t.Fatalf("CalculateSHA256() failed: %v", err)
+	}
+	checksums := writeChecksums(t, tarball, hash)
+
+	var statuses []string
+	status := func(s string) { statuses = append(statuses, s) }
+	if err := installRelease(tarball, checksums, "1.14.1", "x86_64", workDir, outputDir, status); err != nil {
+		t.Fatalf("installRelease() failed: %v", err)
+	}
+
+	installed := filepath.Join(outputDir, "firecracker")
+	info, err := os.Stat(installed)
+	if err != nil {
+		t.Fatalf("binary not installed: %v", err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("binary mode = %v, want 0755", info.Mode().Perm())
+	}
+	data, err := os.ReadFile(installed)
+	if err != nil {
+		t.Fatalf("failed to read binary: %v", err)
+	}
+	if string(data) != string(binary) {
+		t.Errorf("binary content = %q, want %q", data, binary)
+	}
+
+	if _, err := os.Stat(filepath.Join(workDir, "release-v1.14.1-x86_64")); !os.IsNotExist(err) {
+		t.Errorf("extracted release directory was not removed")
+	}
+	if len(statuses) == 0 || statuses[0] != "Verifying checksum..." {
+		t.Errorf("statuses = %v, want verification first", statuses)
+	}
+}
+
+// TestInstallRelease_ChecksumMismatch tests that a tampered tarball is not installed
+func TestInstallRelease_ChecksumMismatch(t *testing.T) {
+	workDir := t.TempDir()
+	outputDir := filepath.Join(t.TempDir(), "1.14.1")
+
+	tarball := writeReleaseTarball(t, workDir, "1.14.1", "x86_64", []byte("binary"))
+	checksums := writeChecksums(t, tarball, strings.Repeat("0", 64))
+
+	err := installRelease(tarball, checksums, "1.14.1", "x86_64", workDir, outputDir, nil)
+	if err == nil {
+		t.Fatal("installRelease() should fail on a checksum mismatch")
+	}
+	if !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("error = %v, want a checksum mismatch", err)
+	}
+	if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+		t.Errorf("output directory should not exist after a failed install")
+	}
+}
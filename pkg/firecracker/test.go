@@ -14,6 +14,7 @@ import (
 	"github.com/Work-Fort/Anvil/pkg/config"
 	"github.com/Work-Fort/Anvil/pkg/firecracker/embedded"
 	"github.com/Work-Fort/Anvil/pkg/rootfs"
+	"github.com/Work-Fort/Anvil/pkg/util"
 	"github.com/Work-Fort/Anvil/pkg/vsock"
 )
 
@@ -87,8 +88,6 @@ func Test(opts TestOptions, paths *config.Paths) (*TestResult, error) {
 		createOpts := rootfs.CreateOptions{
 			OutputPath:     rootfsPath,
 			SizeMB:         512,
-			AlpineVersion:  "3.23",
-			AlpinePatch:    "3",
 			ForceOverwrite: false,
 			InjectBinary:   true,
 			BinaryPath:     vsockServerPath,
@@ -177,7 +176,7 @@ func Test(opts TestOptions, paths *config.Paths) (*TestResult, error) {
 	logger("Waiting for VM to boot...")
 
 	// Probe for vsock readiness instead of blind sleep
-	client := vsock.NewClient(vsockPath, 8000, nil)
+	client := vsock.NewClient(vsockPath, rootfs.DefaultVsockPort, nil)
 	probeInterval := 100 * time.Millisecond
 	maxProbeTime := opts.BootTimeout
 	probeDeadline := time.Now().Add(maxProbeTime)
@@ -311,7 +310,7 @@ func getKernelPath(version string, paths *config.Paths) (string, error) {
 
 	// Find vmlinux file (not compressed)
 	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) != ".xz" && filepath.Ext(entry.Name()) != ".sha256" {
+		if !entry.IsDir() && util.CompressionFromPath(entry.Name()) == "" && filepath.Ext(entry.Name()) != ".sha256" && filepath.Ext(entry.Name()) != ".sha512" {
 			if filepath.Ext(entry.Name()) == "" || entry.Name()[:7] == "vmlinux" {
 				return filepath.Join(kernelDir, entry.Name()), nil
 			}
@@ -7,10 +7,13 @@ import (
 	"io"
 	"net/http"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/Work-Fort/Anvil/pkg/config"
 	"github.com/Work-Fort/Anvil/pkg/download"
-	"github.com/hashicorp/go-version"
+	"github.com/Work-Fort/Anvil/pkg/util"
 )
 
 // Release represents a GitHub release
@@ -32,7 +35,11 @@ type Client struct {
 }
 
 // NewClient creates a GitHub API client with the given token and API URL.
+// An empty token falls back to the configured github-token.
 func NewClient(token, apiURL string) *Client {
+	if token == "" {
+		token = config.GetGitHubToken()
+	}
 	return &Client{
 		token:  token,
 		apiURL: apiURL,
@@ -66,9 +73,8 @@ func (c *Client) GetReleases(owner, repo string, perPage int) ([]Release, error)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("GitHub API returned %d: %s", resp.StatusCode, string(body))
+	if err := checkResponse(resp); err != nil {
+		return nil, err
 	}
 
 	var releases []Release
@@ -92,9 +98,8 @@ func (c *Client) getRelease(url string) (*Release, error) {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("GitHub API returned %d: %s", resp.StatusCode, string(body))
+	if err := checkResponse(resp); err != nil {
+		return nil, err
 	}
 
 	var release Release
@@ -105,15 +110,17 @@ func (c *Client) getRelease(url string) (*Release, error) {
 	return &release, nil
 }
 
-// DownloadFile downloads a file from a URL with automatic GitHub token injection
+// DownloadFile downloads a file from a URL. The token is only sent to the
+// API itself: release assets are public redirects to a CDN, and a stale
+// token would make GitHub reject downloads that work without one.
 func (c *Client) DownloadFile(url, dest string, progressCallback download.ProgressCallback) error {
 	opts := &download.Options{
 		ProgressCallback: progressCallback,
 	}
 
-	if c.token != "" {
+	if c.token != "" && strings.HasPrefix(url, c.apiURL) {
 		opts.Headers = map[string]string{
-			"Authorization": "token " + c.token,
+			"Authorization": "Bearer " + c.token,
 		}
 	}
 
@@ -123,10 +130,48 @@ func (c *Client) DownloadFile(url, dest string, progressCallback download.Progre
 // DoRequest executes an HTTP request with automatic GitHub token injection
 func (c *Client) DoRequest(req *http.Request) (*http.Response, error) {
 	if c.token != "" {
-		req.Header.Set("Authorization", "token "+c.token)
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	return download.HTTPClient().Do(req)
+}
+
+// RateLimitError is returned when GitHub refuses a request because the
+// API rate limit is used up
+type RateLimitError struct {
+	Reset         time.Time // when the limit resets; zero if unknown
+	Authenticated bool
+}
+
+func (e *RateLimitError) Error() string {
+	msg := "GitHub API rate limit exceeded"
+	if !e.Reset.IsZero() {
+		msg += fmt.Sprintf("; resets at %s (in %s)", e.Reset.Local().Format("15:04:05"), time.Until(e.Reset).Round(time.Second))
+	}
+	if !e.Authenticated {
+		msg += "; set github-token (anvil config set --global github-token <token>) for a higher limit"
+	}
+	return msg
+}
+
+// checkResponse turns a non-200 API response into an error, reporting an
+// exhausted rate limit as a RateLimitError
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
 	}
 
-	return http.DefaultClient.Do(req)
+	if (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) &&
+		resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		rateErr := &RateLimitError{Authenticated: resp.Request != nil && resp.Request.Header.Get("Authorization") != ""}
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			rateErr.Reset = time.Unix(reset, 0)
+		}
+		return rateErr
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	return fmt.Errorf("GitHub API returned %d: %s", resp.StatusCode, string(body))
 }
 
 // StripVersionPrefix removes 'v' prefix from version strings
@@ -139,18 +184,8 @@ func SortReleasesBySemver(releases []Release) []Release {
 	sorted := make([]Release, len(releases))
 	copy(sorted, releases)
 
-	sort.Slice(sorted, func(i, j int) bool {
-		// Strip 'v' prefix and parse versions
-		v1, err1 := version.NewVersion(StripVersionPrefix(sorted[i].TagName))
-		v2, err2 := version.NewVersion(StripVersionPrefix(sorted[j].TagName))
-
-		// If either version is invalid, fall back to string comparison
-		if err1 != nil || err2 != nil {
-			return sorted[i].TagName > sorted[j].TagName
-		}
-
-		// Compare semantic versions (descending order)
-		return v1.GreaterThan(v2)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return util.CompareKernelVersions(sorted[i].TagName, sorted[j].TagName) > 0
 	})
 
 	return sorted
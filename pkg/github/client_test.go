@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+package github
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientSendsBearerToken(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.Write([]byte(`{"tag_name":"v6.1.0"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("ghp_test", srv.URL)
+	release, err := client.GetLatestRelease("Work-Fort", "Anvil")
+	if err != nil {
+		t.Fatalf("GetLatestRelease() error = %v", err)
+	}
+	if release.TagName != "v6.1.0" {
+		t.Errorf("TagName = %q, want v6.1.0", release.TagName)
+	}
+	if gotAuth != "Bearer ghp_test" {
+		t.Errorf("Authorization = %q, want %q", gotAuth, "Bearer ghp_test")
+	}
+}
+
+func TestClientRateLimitError(t *testing.T) {
+	reset := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+
+	tests := []struct {
+		name      string
+		status    int
+		remaining string
+		wantRate  bool
+	}{
+		{name: "primary limit", status: http.StatusForbidden, remaining: "0", wantRate: true},
+		{name: "secondary limit", status: http.StatusTooManyRequests, remaining: "0", wantRate: true},
+		{name: "forbidden with quota left", status: http.StatusForbidden, remaining: "42", wantRate: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-RateLimit-Remaining", tt.remaining)
+				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+			}))
+			defer srv.Close()
+
+			_, err := NewClient("ghp_test", srv.URL).GetReleases("Work-Fort", "Anvil", 10)
+			if err == nil {
+				t.Fatal("GetReleases() succeeded, want error")
+			}
+
+			var rateErr *RateLimitError
+			if got := errors.As(err, &rateErr); got != tt.wantRate {
+				t.Fatalf("errors.As(RateLimitError) = %v, want %v (err: %v)", got, tt.wantRate, err)
+			}
+			if !tt.wantRate {
+				return
+			}
+			if !rateErr.Reset.Equal(reset) {
+				t.Errorf("Reset = %v, want %v", rateErr.Reset, reset)
+			}
+			if !rateErr.Authenticated {
+				t.Error("Authenticated = false, want true")
+			}
+			if !strings.Contains(err.Error(), reset.Local().Format("15:04:05")) {
+				t.Errorf("error %q does not include the reset time", err)
+			}
+		})
+	}
+}
+
+func TestDownloadFileOmitsTokenForAssets(t *testing.T) {
+	var gotAuth string
+	assets := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.Write([]byte("asset"))
+	}))
+	defer assets.Close()
+
+	client := NewClient("ghp_test", "https://api.github.invalid")
+	dest := filepath.Join(t.TempDir(), "asset")
+	if err := client.DownloadFile(assets.URL+"/releases/download/v1/asset", dest, nil); err != nil {
+		t.Fatalf("DownloadFile() error = %v", err)
+	}
+	if gotAuth != "" {
+		t.Errorf("Authorization = %q, want none for release assets", gotAuth)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "asset" {
+		t.Errorf("downloaded %q, want %q", data, "asset")
+	}
+}
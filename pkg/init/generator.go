@@ -9,10 +9,33 @@ import (
 	"text/template"
 )
 
+// GenerateResult describes what GenerateRepoFilesWithResult did with each file
+type GenerateResult struct {
+	Created    []string // Files written from scratch (or overwritten with Force)
+	Merged     []string // Existing files that had missing keys added
+	MergedKeys []string // Keys added to an existing anvil.yaml
+	Skipped    []string // Existing files left untouched
+}
+
 // GenerateRepoFiles creates all repository files atomically.
 // It returns a list of created files on success, or rolls back all changes on error.
 func GenerateRepoFiles(settings InitSettings) ([]string, error) {
-	var createdItems []string // Track files and directories for rollback
+	result, err := GenerateRepoFilesWithResult(settings)
+	if err != nil {
+		return nil, err
+	}
+	return result.Created, nil
+}
+
+// GenerateRepoFilesWithResult creates all repository files atomically and
+// reports what happened to each. Unless settings.Force is set, existing files
+// are not overwritten: keys missing from an existing anvil.yaml are merged in
+// (preserving user edits) and other existing files are skipped. On error all
+// changes are rolled back, including restoring merged files.
+func GenerateRepoFilesWithResult(settings InitSettings) (*GenerateResult, error) {
+	result := &GenerateResult{}
+	var createdItems []string            // Track files and directories for rollback
+	originals := make(map[string][]byte) // Original contents of modified files
 
 	// Helper function to track created items
 	trackCreated := func(path string) {
@@ -25,9 +48,33 @@ func GenerateRepoFiles(settings InitSettings) ([]string, error) {
 		for i := len(createdItems) - 1; i >= 0; i-- {
 			os.RemoveAll(createdItems[i])
 		}
+		for path, data := range originals {
+			os.WriteFile(path, data, 0644)
+		}
+	}
+
+	// writeFile writes a generated file; existing files are skipped unless
+	// Force is set
+	writeFile := func(path string, data []byte) error {
+		existing, err := os.ReadFile(path)
+		if err == nil {
+			if !settings.Force {
+				result.Skipped = append(result.Skipped, path)
+				return nil
+			}
+			originals[path] = existing
+		} else {
+			trackCreated(path)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+		result.Created = append(result.Created, path)
+		return nil
 	}
 
-	// Create directories
+	// Create directories. Only directories that did not exist before are
+	// removed on rollback.
 	dirs := []string{
 		"configs",
 		"keys",
@@ -35,11 +82,14 @@ func GenerateRepoFiles(settings InitSettings) ([]string, error) {
 	}
 
 	for _, dir := range dirs {
+		_, statErr := os.Stat(dir)
 		if err := os.MkdirAll(dir, 0755); err != nil {
 			rollback()
 			return nil, fmt.Errorf("failed to create directory %s (rolled back): %w", dir, err)
 		}
-		trackCreated(dir)
+		if os.IsNotExist(statErr) {
+			trackCreated(dir)
+		}
 	}
 
 	// Generate anvil.yaml from template
@@ -55,13 +105,29 @@ func GenerateRepoFiles(settings InitSettings) ([]string, error) {
 		return nil, fmt.Errorf("failed to execute repo config template (rolled back): %w", err)
 	}
 
-	// Write anvil.yaml
+	// Write anvil.yaml, or merge missing keys into an existing one
 	repoConfigPath := "anvil.yaml"
-	if err := os.WriteFile(repoConfigPath, buf.Bytes(), 0644); err != nil {
+	if existing, err := os.ReadFile(repoConfigPath); err == nil && !settings.Force {
+		merged, added, err := mergeYAMLKeys(existing, buf.Bytes())
+		if err != nil {
+			rollback()
+			return nil, fmt.Errorf("failed to merge %s (rolled back): %w", repoConfigPath, err)
+		}
+		if len(added) == 0 {
+			result.Skipped = append(result.Skipped, repoConfigPath)
+		} else {
+			originals[repoConfigPath] = existing
+			if err := os.WriteFile(repoConfigPath, merged, 0644); err != nil {
+				rollback()
+				return nil, fmt.Errorf("failed to write %s (rolled back): %w", repoConfigPath, err)
+			}
+			result.Merged = append(result.Merged, repoConfigPath)
+			result.MergedKeys = added
+		}
+	} else if err := writeFile(repoConfigPath, buf.Bytes()); err != nil {
 		rollback()
 		return nil, fmt.Errorf("failed to write %s (rolled back): %w", repoConfigPath, err)
 	}
-	trackCreated(repoConfigPath)
 
 	// Write .gitignore (templated so archive location is included)
 	gitignorePath := ".gitignore"
@@ -75,33 +141,24 @@ func GenerateRepoFiles(settings InitSettings) ([]string, error) {
 		rollback()
 		return nil, fmt.Errorf("failed to execute gitignore template (rolled back): %w", err)
 	}
-	if err := os.WriteFile(gitignorePath, buf.Bytes(), 0644); err != nil {
+	if err := writeFile(gitignorePath, buf.Bytes()); err != nil {
 		rollback()
 		return nil, fmt.Errorf("failed to write %s (rolled back): %w", gitignorePath, err)
 	}
-	trackCreated(gitignorePath)
-
-	// Write kernel config files
-	kernelConfigs := map[string]string{
-		filepath.Join("configs", "kernel-x86_64.config"):  X86ConfigTemplate,
-		filepath.Join("configs", "kernel-aarch64.config"): Aarch64ConfigTemplate,
-	}
 
-	for path, content := range kernelConfigs {
-		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+	// Write the bundled Firecracker kernel config for each architecture
+	for _, arch := range kernelConfigArches {
+		path := filepath.Join("configs", "kernel-"+arch+".config")
+		data, err := DefaultKernelConfig(arch, settings.KernelSeries)
+		if err != nil {
+			rollback()
+			return nil, fmt.Errorf("failed to generate %s (rolled back): %w", path, err)
+		}
+		if err := writeFile(path, data); err != nil {
 			rollback()
 			return nil, fmt.Errorf("failed to write %s (rolled back): %w", path, err)
 		}
-		trackCreated(path)
-	}
-
-	// Return only the files (not directories) that were created
-	files := []string{
-		repoConfigPath,
-		gitignorePath,
-		filepath.Join("configs", "kernel-x86_64.config"),
-		filepath.Join("configs", "kernel-aarch64.config"),
 	}
 
-	return files, nil
+	return result, nil
 }
@@ -3,6 +3,8 @@ package init
 
 import (
 	"os"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -267,3 +269,93 @@ func containsString(s, substr string) bool {
 		(len(s) > 0 && (s[:len(substr)] == substr ||
 			(len(s) > len(substr) && containsString(s[1:], substr)))))
 }
+
+func TestGenerateRepoFiles_MergesExistingConfig(t *testing.T) {
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current dir: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("Failed to change to temp dir: %v", err)
+	}
+
+	existing := `# My release repo
+kernels:
+  config:
+    x86_64: custom/my-x86.config # tuned
+signing:
+  key:
+    name: "Existing Name"
+`
+	if err := os.WriteFile("anvil.yaml", []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(".gitignore", []byte("node_modules/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	settings := InitSettings{
+		ArchiveLocation: "archive",
+		KeyName:         "New Name",
+		KeyEmail:        "test@example.com",
+		KeyExpiry:       "1y",
+		KeyFormat:       "armored",
+		HistoryFormat:   "armored",
+	}
+
+	result, err := GenerateRepoFilesWithResult(settings)
+	if err != nil {
+		t.Fatalf("GenerateRepoFilesWithResult failed: %v", err)
+	}
+
+	content, err := os.ReadFile("anvil.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	contentStr := string(content)
+
+	// User edits and comments are kept
+	for _, want := range []string{"# My release repo", "custom/my-x86.config", "# tuned", "Existing Name"} {
+		if !strings.Contains(contentStr, want) {
+			t.Errorf("merged anvil.yaml lost %q:\n%s", want, contentStr)
+		}
+	}
+	if strings.Contains(contentStr, "New Name") {
+		t.Errorf("merged anvil.yaml overwrote an existing value:\n%s", contentStr)
+	}
+
+	// Missing keys are added
+	for _, want := range []string{"kernels.config.aarch64", "kernels.archive.location", "signing.key.email"} {
+		if !slices.Contains(result.MergedKeys, want) {
+			t.Errorf("MergedKeys = %v, want it to contain %s", result.MergedKeys, want)
+		}
+	}
+	if !slices.Equal(result.Merged, []string{"anvil.yaml"}) {
+		t.Errorf("Merged = %v, want [anvil.yaml]", result.Merged)
+	}
+
+	// Other existing files are kept
+	if !slices.Contains(result.Skipped, ".gitignore") {
+		t.Errorf("Skipped = %v, want it to contain .gitignore", result.Skipped)
+	}
+	gitignore, _ := os.ReadFile(".gitignore")
+	if string(gitignore) != "node_modules/\n" {
+		t.Errorf(".gitignore was overwritten: %q", gitignore)
+	}
+
+	// With Force, existing files are overwritten
+	settings.Force = true
+	result, err = GenerateRepoFilesWithResult(settings)
+	if err != nil {
+		t.Fatalf("GenerateRepoFilesWithResult with Force failed: %v", err)
+	}
+	if len(result.Skipped) != 0 || len(result.Merged) != 0 {
+		t.Errorf("Force: Skipped = %v, Merged = %v, want none", result.Skipped, result.Merged)
+	}
+	content, _ = os.ReadFile("anvil.yaml")
+	if !strings.Contains(string(content), "New Name") {
+		t.Errorf("Force did not overwrite anvil.yaml:\n%s", content)
+	}
+}
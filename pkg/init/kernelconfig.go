@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+package init
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+
+	"github.com/Work-Fort/Anvil/pkg/util"
+	"github.com/charmbracelet/log"
+)
+
+// kernelConfigs holds known-good Firecracker microvm kernel configs, one
+// directory per kernel series (kernelconfigs/6.1/microvm-kernel-x86_64.config).
+// The 6.1 configs are copies of the repo's configs/, refreshed by go generate.
+//
+//go:embed kernelconfigs
+var kernelConfigs embed.FS
+
+//go:generate cp ../../configs/microvm-kernel-x86_64.config ../../configs/microvm-kernel-aarch64.config kernelconfigs/6.1/
+
+// kernelConfigArches are the architectures init writes a kernel config for
+var kernelConfigArches = []string{"x86_64", "aarch64"}
+
+// KernelConfigSeries returns the kernel series a bundled config exists
+// for, newest first
+func KernelConfigSeries() []string {
+	entries, err := fs.ReadDir(kernelConfigs, "kernelconfigs")
+	if err != nil {
+		return nil
+	}
+	var series []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			series = append(series, entry.Name())
+		}
+	}
+	util.SortKernelVersions(series)
+	return series
+}
+
+// DefaultKernelConfig returns the bundled Firecracker microvm kernel config
+// for arch and a kernel series ("6.1", or a version such as "6.1.102").
+// When no config is bundled for the series, the one for the nearest older
+// series is used (the oldest if series predates all of them) and a
+// warning is logged; make olddefconfig fills in the options it lacks. An
+// empty series selects the newest bundled config.
+func DefaultKernelConfig(arch, series string) ([]byte, error) {
+	if !slices.Contains(kernelConfigArches, arch) {
+		return nil, fmt.Errorf("unsupported architecture: %s (supported: x86_64, aarch64)", arch)
+	}
+	available := KernelConfigSeries()
+	if len(available) == 0 {
+		return nil, fmt.Errorf("no kernel configs are bundled")
+	}
+
+	chosen := nearestKernelConfigSeries(available, series)
+	if series != "" && chosen != util.KernelSeries(series) {
+		log.Warnf("No bundled kernel config for %s, using the %s config", util.KernelSeries(series), chosen)
+	}
+
+	data, err := kernelConfigs.ReadFile(path.Join("kernelconfigs", chosen, "microvm-kernel-"+arch+".config"))
+	if err != nil {
+		return nil, fmt.Errorf("no bundled %s kernel config for %s: %w", arch, chosen, err)
+	}
+	return data, nil
+}
+
+// nearestKernelConfigSeries picks the series to use from available
+// (newest first) for series: an exact match, else the newest older
+// series, else the oldest. An empty series picks the newest.
+func nearestKernelConfigSeries(available []string, series string) string {
+	if series == "" {
+		return available[0]
+	}
+	series = util.KernelSeries(series)
+	for _, s := range available {
+		if util.CompareKernelVersions(s, series) <= 0 {
+			return s
+		}
+	}
+	return available[len(available)-1]
+}
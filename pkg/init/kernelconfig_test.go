@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+package init
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNearestKernelConfigSeries(t *testing.T) {
+	available := []string{"6.12", "6.6", "6.1"}
+	tests := []struct {
+		series string
+		want   string
+	}{
+		{series: "", want: "6.12"},
+		{series: "6.6", want: "6.6"},
+		{series: "6.6.70", want: "6.6"},
+		{series: "6.10", want: "6.6"},
+		{series: "6.19-rc3", want: "6.12"},
+		{series: "5.15", want: "6.1"},
+	}
+	for _, tt := range tests {
+		if got := nearestKernelConfigSeries(available, tt.series); got != tt.want {
+			t.Errorf("nearestKernelConfigSeries(%q) = %q, want %q", tt.series, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultKernelConfig(t *testing.T) {
+	if len(KernelConfigSeries()) == 0 {
+		t.Fatal("no kernel configs are bundled")
+	}
+
+	exact, err := DefaultKernelConfig("x86_64", KernelConfigSeries()[0])
+	if err != nil {
+		t.Fatalf("DefaultKernelConfig() error = %v", err)
+	}
+	// A series without a bundled config falls back rather than failing
+	fallback, err := DefaultKernelConfig("x86_64", "99.0")
+	if err != nil {
+		t.Fatalf("DefaultKernelConfig(99.0) error = %v", err)
+	}
+	if !bytes.Equal(exact, fallback) {
+		t.Error("DefaultKernelConfig(99.0) should fall back to the newest bundled config")
+	}
+
+	if _, err := DefaultKernelConfig("riscv64", ""); err == nil {
+		t.Error("DefaultKernelConfig(riscv64) should fail")
+	}
+}
+
+func TestBundledKernelConfigsMatchRepo(t *testing.T) {
+	for _, arch := range kernelConfigArches {
+		name := "microvm-kernel-" + arch + ".config"
+		repo, err := os.ReadFile(filepath.Join("..", "..", "configs", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		bundled, err := kernelConfigs.ReadFile("kernelconfigs/6.1/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bundled, repo) {
+			t.Errorf("kernelconfigs/6.1/%s differs from configs/%s; run go generate ./pkg/init", name, name)
+		}
+	}
+}
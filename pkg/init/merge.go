@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+package init
+
+import (
+	"bytes"
+	"fmt"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// mergeYAMLKeys adds every key from generated that is missing in existing,
+// keeping existing values, comments and key order. Returns the merged
+// document and the dotted paths of the keys that were added.
+func mergeYAMLKeys(existing, generated []byte) ([]byte, []string, error) {
+	var existingDoc, generatedDoc yaml.Node
+	if err := yaml.Unmarshal(existing, &existingDoc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse existing config: %w", err)
+	}
+	if err := yaml.Unmarshal(generated, &generatedDoc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse generated config: %w", err)
+	}
+
+	genRoot := documentRoot(&generatedDoc)
+	if genRoot == nil {
+		return existing, nil, nil
+	}
+
+	// An empty existing file is treated as an empty mapping
+	root := documentRoot(&existingDoc)
+	if root == nil {
+		root = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		existingDoc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("existing config is not a YAML mapping")
+	}
+
+	added := mergeMappingNodes(root, genRoot, "")
+	if len(added) == 0 {
+		return existing, nil, nil
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&existingDoc); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode merged config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode merged config: %w", err)
+	}
+
+	return out.Bytes(), added, nil
+}
+
+// documentRoot returns the top-level node of a parsed document, or nil for
+// an empty document
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+	return doc.Content[0]
+}
+
+// mergeMappingNodes copies keys from src missing in dst, recursing into
+// mappings present in both. Existing non-mapping values are never replaced.
+func mergeMappingNodes(dst, src *yaml.Node, prefix string) []string {
+	var added []string
+
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		path := key.Value
+		if prefix != "" {
+			path = prefix + "." + key.Value
+		}
+
+		existing := mappingValue(dst, key.Value)
+		if existing == nil {
+			dst.Content = append(dst.Content, key, value)
+			added = append(added, leafPaths(value, path)...)
+			continue
+		}
+		if existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode {
+			added = append(added, mergeMappingNodes(existing, value, path)...)
+		}
+	}
+
+	return added
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// leafPaths returns the dotted paths of the scalar values under node
+func leafPaths(node *yaml.Node, prefix string) []string {
+	if node.Kind != yaml.MappingNode {
+		return []string{prefix}
+	}
+	var paths []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		paths = append(paths, leafPaths(node.Content[i+1], prefix+"."+node.Content[i].Value)...)
+	}
+	return paths
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+package init
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// PlanAction is what anvil init would do with a file
+type PlanAction int
+
+const (
+	PlanCreate    PlanAction = iota // File does not exist and will be created
+	PlanOverwrite                   // File exists and will be replaced (--force)
+	PlanMerge                       // anvil.yaml exists; missing keys will be added
+	PlanKeep                        // File exists and will be left untouched
+)
+
+// PlannedFile is a file anvil init would write
+type PlannedFile struct {
+	Path   string
+	Exists bool // Already present in the directory
+	Action PlanAction
+}
+
+// repoFilePaths returns the files written by GenerateRepoFiles, in order
+func repoFilePaths() []string {
+	return []string{
+		"anvil.yaml",
+		".gitignore",
+		filepath.Join("configs", "kernel-x86_64.config"),
+		filepath.Join("configs", "kernel-aarch64.config"),
+	}
+}
+
+// keyFilePaths returns the signing key files written into keys/ by init
+func keyFilePaths() []string {
+	return []string{
+		filepath.Join("keys", "signing-key-private.asc"),
+		filepath.Join("keys", "signing-key.asc"),
+	}
+}
+
+// KeysExist reports whether the repo already has a signing key pair in keys/
+func KeysExist() bool {
+	for _, path := range keyFilePaths() {
+		if _, err := os.Stat(path); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// PlanRepoFiles returns every file anvil init would write in the current
+// directory (repo files, then signing keys) without writing anything, and
+// what would happen to each. With force, existing files are overwritten;
+// otherwise anvil.yaml is merged and other existing files are kept.
+func PlanRepoFiles(force bool) []PlannedFile {
+	paths := append(repoFilePaths(), keyFilePaths()...)
+	keysExist := KeysExist()
+
+	plan := make([]PlannedFile, 0, len(paths))
+	for _, path := range paths {
+		_, err := os.Stat(path)
+		file := PlannedFile{Path: path, Exists: err == nil}
+		switch {
+		case !file.Exists:
+			file.Action = PlanCreate
+		case force:
+			file.Action = PlanOverwrite
+		case path == "anvil.yaml":
+			file.Action = PlanMerge
+		case isKeyFile(path) && !keysExist:
+			// An incomplete key pair is regenerated
+			file.Action = PlanOverwrite
+		default:
+			file.Action = PlanKeep
+		}
+		plan = append(plan, file)
+	}
+	return plan
+}
+
+// isKeyFile reports whether path is one of the signing key files
+func isKeyFile(path string) bool {
+	for _, key := range keyFilePaths() {
+		if key == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+package init
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlanRepoFiles(t *testing.T) {
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Empty directory: everything is created
+	for _, file := range PlanRepoFiles(false) {
+		if file.Exists || file.Action != PlanCreate {
+			t.Errorf("PlanRepoFiles() in empty dir: %s Exists=%v Action=%v, want new file", file.Path, file.Exists, file.Action)
+		}
+	}
+
+	if err := os.WriteFile("anvil.yaml", []byte("kernels: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(".gitignore", []byte("node_modules/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll("keys", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("keys", "signing-key.asc"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		force bool
+		want  map[string]PlanAction
+	}{
+		{
+			name: "merge",
+			want: map[string]PlanAction{
+				"anvil.yaml": PlanMerge,
+				".gitignore": PlanKeep,
+				// Only half of the key pair exists, so it is regenerated
+				filepath.Join("keys", "signing-key.asc"): PlanOverwrite,
+			},
+		},
+		{
+			name:  "force",
+			force: true,
+			want: map[string]PlanAction{
+				"anvil.yaml":                             PlanOverwrite,
+				".gitignore":                             PlanOverwrite,
+				filepath.Join("keys", "signing-key.asc"): PlanOverwrite,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := PlanRepoFiles(tt.force)
+			if len(plan) != len(repoFilePaths())+len(keyFilePaths()) {
+				t.Fatalf("PlanRepoFiles() returned %d files, want %d", len(plan), len(repoFilePaths())+len(keyFilePaths()))
+			}
+			for _, file := range plan {
+				want, ok := tt.want[file.Path]
+				if !ok {
+					want = PlanCreate
+				}
+				if file.Action != want {
+					t.Errorf("PlanRepoFiles(%v): %s Action = %v, want %v", tt.force, file.Path, file.Action, want)
+				}
+			}
+		})
+	}
+
+	// A complete key pair is kept
+	if err := os.WriteFile(filepath.Join("keys", "signing-key-private.asc"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range PlanRepoFiles(false) {
+		if isKeyFile(file.Path) && file.Action != PlanKeep {
+			t.Errorf("PlanRepoFiles(false): %s Action = %v, want PlanKeep", file.Path, file.Action)
+		}
+	}
+}
@@ -5,12 +5,15 @@ package init
 type InitSettings struct {
 	// Repo layout
 	ArchiveLocation string // Local archive directory (default: "archive")
+	Force           bool   // Overwrite existing files instead of merging/skipping
+	KernelSeries    string // Series the kernel configs are for ("6.1"); empty for the newest bundled
 
 	// Tab 1: Signing Settings
 	KeyName       string
 	KeyEmail      string
 	KeyExpiry     string
 	KeyFormat     string // "armored" or "binary"
+	KeyAlgorithm  string // "rsa4096" or "ed25519"
 	HistoryFormat string // "armored" or "binary"
 	KeyPassword   string // Used to encrypt private key
 
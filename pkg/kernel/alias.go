@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/github"
+	"github.com/Work-Fort/Anvil/pkg/util"
+	"github.com/charmbracelet/log"
+)
+
+// Version aliases accepted in place of a kernel version
+const (
+	VersionLatest = "latest" // Newest stable release
+	VersionLTS    = "lts"    // Newest release of a longterm series
+)
+
+// IsVersionAlias reports whether version is "latest" or "lts"
+func IsVersionAlias(version string) bool {
+	return version == VersionLatest || version == VersionLTS
+}
+
+// kernelOrgEntry is one release in kernel.org's releases.json
+type kernelOrgEntry struct {
+	Version string `json:"version"`
+	Moniker string `json:"moniker"`
+}
+
+// ResolveSourceVersion resolves a version alias for a source build:
+// "latest" is kernel.org's latest stable release and "lts" the newest
+// longterm release. Other versions are returned unchanged. Cancelling ctx
+// aborts the kernel.org request.
+func ResolveSourceVersion(ctx context.Context, version string) (string, error) {
+	var resolved string
+	var err error
+	switch version {
+	case VersionLatest:
+		resolved, err = GetLatestKernelVersion(ctx)
+	case VersionLTS:
+		resolved, err = GetLatestLTSKernelVersion(ctx)
+	default:
+		return version, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %q: %w", version, err)
+	}
+	log.Debugf("Resolved kernel %s to %s", version, resolved)
+	return resolved, nil
+}
+
+// ResolveReleaseVersion resolves a version alias to a GitHub release:
+// "latest" is the latest release and "lts" the newest release whose series
+// kernel.org lists as longterm
+func ResolveReleaseVersion(version string, client *github.Client) (*github.Release, error) {
+	parts := strings.Split(config.GitHubRepo, "/")
+
+	var release *github.Release
+	switch version {
+	case VersionLatest:
+		r, err := client.GetLatestRelease(parts[0], parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch latest kernel version: %w", err)
+		}
+		release = r
+	case VersionLTS:
+		series, err := longtermSeries(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %q: %w", version, err)
+		}
+		releases, err := client.GetReleases(parts[0], parts[1], 100)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch kernel versions: %w", err)
+		}
+		if release = newestReleaseInSeries(releases, series); release == nil {
+			names := slices.Collect(maps.Keys(series))
+			util.SortKernelVersions(names)
+			return nil, fmt.Errorf("no kernel release of a longterm series (%s)", strings.Join(names, ", "))
+		}
+	default:
+		return nil, fmt.Errorf("not a version alias: %s", version)
+	}
+
+	log.Debugf("Resolved kernel %s to %s", version, github.StripVersionPrefix(release.TagName))
+	return release, nil
+}
+
+// GetLatestLTSKernelVersion fetches the newest longterm kernel version
+// from kernel.org
+func GetLatestLTSKernelVersion(ctx context.Context) (string, error) {
+	releases, err := fetchKernelOrgReleases(ctx)
+	if err != nil {
+		return "", err
+	}
+	if latest := latestLongterm(releases); latest != "" {
+		return latest, nil
+	}
+	return "", fmt.Errorf("no longterm version found")
+}
+
+// fetchKernelOrgReleases returns the releases listed in kernel.org's
+// releases.json
+func fetchKernelOrgReleases(ctx context.Context) ([]kernelOrgEntry, error) {
+	var data struct {
+		Releases []kernelOrgEntry `json:"releases"`
+	}
+	if err := FetchReleasesJSON(ctx, &data); err != nil {
+		return nil, err
+	}
+	return data.Releases, nil
+}
+
+// longtermSeries returns the series ("6.12") kernel.org lists as longterm
+func longtermSeries(ctx context.Context) (map[string]bool, error) {
+	releases, err := fetchKernelOrgReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	series := make(map[string]bool)
+	for _, r := range releases {
+		if r.Moniker == "longterm" {
+			series[util.KernelSeries(r.Version)] = true
+		}
+	}
+	if len(series) == 0 {
+		return nil, fmt.Errorf("no longterm version found")
+	}
+	return series, nil
+}
+
+// latestLongterm returns the newest longterm version in releases
+func latestLongterm(releases []kernelOrgEntry) string {
+	latest := ""
+	for _, r := range releases {
+		if r.Moniker == "longterm" && (latest == "" || util.CompareKernelVersions(r.Version, latest) > 0) {
+			latest = r.Version
+		}
+	}
+	return latest
+}
+
+// newestReleaseInSeries returns the newest release belonging to one of
+// series, or nil
+func newestReleaseInSeries(releases []github.Release, series map[string]bool) *github.Release {
+	for _, r := range github.SortReleasesBySemver(releases) {
+		if series[util.KernelSeries(github.StripVersionPrefix(r.TagName))] {
+			return &r
+		}
+	}
+	return nil
+}
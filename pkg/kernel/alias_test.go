@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/github"
+)
+
+func TestIsVersionAlias(t *testing.T) {
+	for version, want := range map[string]bool{
+		"latest": true,
+		"lts":    true,
+		"LTS":    false,
+		"6.12.9": false,
+		"":       false,
+	} {
+		if got := IsVersionAlias(version); got != want {
+			t.Errorf("IsVersionAlias(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestValidateVersionAcceptsAliases(t *testing.T) {
+	for _, alias := range []string{VersionLatest, VersionLTS} {
+		if err := ValidateVersion(context.Background(), alias); err != nil {
+			t.Errorf("ValidateVersion(%q) error = %v", alias, err)
+		}
+	}
+}
+
+func TestLatestLongterm(t *testing.T) {
+	releases := []kernelOrgEntry{
+		{Version: "6.19-rc3", Moniker: "mainline"},
+		{Version: "6.18.2", Moniker: "stable"},
+		{Version: "6.6.119", Moniker: "longterm"},
+		{Version: "6.12.62", Moniker: "longterm"},
+		{Version: "5.15.197", Moniker: "longterm"},
+	}
+	if got := latestLongterm(releases); got != "6.12.62" {
+		t.Errorf("latestLongterm() = %q, want 6.12.62", got)
+	}
+	if got := latestLongterm(releases[:2]); got != "" {
+		t.Errorf("latestLongterm() without longterm releases = %q, want empty", got)
+	}
+}
+
+func TestNewestReleaseInSeries(t *testing.T) {
+	releases := []github.Release{
+		{TagName: "v6.6.119"},
+		{TagName: "v6.18.2"},
+		{TagName: "v6.12.9"},
+		{TagName: "v6.12.62"},
+	}
+
+	got := newestReleaseInSeries(releases, map[string]bool{"6.12": true, "6.6": true})
+	if got == nil || got.TagName != "v6.12.62" {
+		t.Errorf("newestReleaseInSeries() = %v, want v6.12.62", got)
+	}
+	if got := newestReleaseInSeries(releases, map[string]bool{"5.10": true}); got != nil {
+		t.Errorf("newestReleaseInSeries() with no matching series = %v, want nil", got.TagName)
+	}
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+// ArchMismatchError reports a kernel that targets a different architecture
+// than the host it is being set as default on
+type ArchMismatchError struct {
+	Path       string
+	KernelArch string
+	HostArch   string
+}
+
+func (e *ArchMismatchError) Error() string {
+	return fmt.Sprintf("%s is built for %s, but this host is %s: Firecracker here cannot boot it",
+		filepath.Base(e.Path), e.KernelArch, e.HostArch)
+}
+
+// kernelFileArch returns the architecture suffix of a kernel file name such
+// as vmlinux-6.19.6-x86_64 or Image-6.19.6-20260101T000000-aarch64, or ""
+// when the name doesn't end in a known arch
+func kernelFileArch(path string) string {
+	base := util.TrimCompressionExt(filepath.Base(path))
+	for _, arch := range []string{"x86_64", "aarch64"} {
+		if strings.HasSuffix(base, "-"+arch) {
+			return arch
+		}
+	}
+	return ""
+}
+
+// checkKernelArch returns an *ArchMismatchError when the kernel at path is
+// named for an architecture other than hostArch
+func checkKernelArch(path, hostArch string) error {
+	kernelArch := kernelFileArch(path)
+	if kernelArch == "" || kernelArch == hostArch {
+		return nil
+	}
+	return &ArchMismatchError{Path: path, KernelArch: kernelArch, HostArch: hostArch}
+}
+
+// findOtherArchKernel returns the kernel image in versionDir built for an
+// architecture other than hostArch, or "" when there is none
+func findOtherArchKernel(versionDir, version, hostArch string) string {
+	for _, arch := range []string{"x86_64", "aarch64"} {
+		if arch == hostArch {
+			continue
+		}
+		kernelName, err := config.GetKernelNameForArch(arch)
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(versionDir, fmt.Sprintf("%s-%s-%s", kernelName, version, arch))
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
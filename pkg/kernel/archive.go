@@ -0,0 +1,254 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/util"
+	"github.com/charmbracelet/log"
+	goversion "github.com/hashicorp/go-version"
+)
+
+// ArchivePruneResult describes what an archive prune removed.
+type ArchivePruneResult struct {
+	Removed    []ArchiveEntry `json:"removed"`
+	FreedBytes int64          `json:"freed_bytes"`
+}
+
+// ArchivePrune removes archived kernel versions beyond the retention policy and
+// updates index.json. retainCount keeps the newest N versions per arch and
+// retainDays keeps versions archived within the last N days; zero disables a
+// limit. The newest version of each arch is never removed.
+func ArchivePrune(archiveDir string, retainCount, retainDays int) (*ArchivePruneResult, error) {
+	if retainCount < 0 || retainDays < 0 {
+		return nil, fmt.Errorf("retention values must not be negative")
+	}
+
+	result := &ArchivePruneResult{Removed: []ArchiveEntry{}}
+	if retainCount == 0 && retainDays == 0 {
+		return result, nil
+	}
+
+	indexPath := filepath.Join(archiveDir, "index.json")
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, fmt.Errorf("failed to read archive index: %w", err)
+	}
+
+	var index map[string]map[string]string
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse archive index: %w", err)
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -retainDays)
+
+	for arch, versions := range index {
+		sorted := sortVersionsNewestFirst(versions)
+
+		for i, version := range sorted {
+			// Never remove the newest version for an arch
+			if i == 0 {
+				continue
+			}
+
+			versionDir := filepath.Join(archiveDir, arch, version)
+
+			expired := retainCount > 0 && i >= retainCount
+			if !expired && retainDays > 0 {
+				if info, err := os.Stat(versionDir); err == nil && info.ModTime().Before(cutoff) {
+					expired = true
+				}
+			}
+			if !expired {
+				continue
+			}
+
+			size, _ := util.DirSize(versionDir)
+			if err := os.RemoveAll(versionDir); err != nil {
+				return nil, fmt.Errorf("failed to remove %s/%s: %w", arch, version, err)
+			}
+
+			result.Removed = append(result.Removed, ArchiveEntry{
+				Arch:    arch,
+				Version: version,
+				Path:    versions[version],
+			})
+			result.FreedBytes += size
+			delete(versions, version)
+		}
+
+		// Remove the arch directory once it has no versions left on disk
+		archDir := filepath.Join(archiveDir, arch)
+		if entries, err := os.ReadDir(archDir); err == nil && len(entries) == 0 {
+			os.Remove(archDir)
+		}
+	}
+
+	if len(result.Removed) == 0 {
+		return result, nil
+	}
+
+	data, err = json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal archive index: %w", err)
+	}
+	if err := os.WriteFile(indexPath, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write archive index: %w", err)
+	}
+
+	return result, nil
+}
+
+// sortVersionsNewestFirst returns the versions in the index map ordered from
+// newest to oldest. Unparseable versions sort after valid ones.
+func sortVersionsNewestFirst(versions map[string]string) []string {
+	sorted := make([]string, 0, len(versions))
+	for v := range versions {
+		sorted = append(sorted, v)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		vi, errI := goversion.NewVersion(sorted[i])
+		vj, errJ := goversion.NewVersion(sorted[j])
+		switch {
+		case errI != nil && errJ != nil:
+			return sorted[i] > sorted[j]
+		case errI != nil:
+			return false
+		case errJ != nil:
+			return true
+		}
+		return vi.GreaterThan(vj)
+	})
+
+	return sorted
+}
+
+// ErrNotInArchive is returned (wrapped) by DownloadFromArchive when the
+// archive index has no kernel for the requested version and arch
+var ErrNotInArchive = errors.New("kernel not found in archive")
+
+// DownloadFromArchive installs a kernel from a repo-local archive written by
+// ArchiveInstalledKernel: it resolves the compressed kernel through
+// index.json, copies it into the kernels directory, verifies it against the
+// archived .sha256 and decompresses it. An empty version installs the
+// newest archived version for arch.
+func DownloadFromArchive(archiveDir, version, arch string) error {
+	return downloadFromArchive(archiveDir, version, arch, config.GlobalPaths)
+}
+
+func downloadFromArchive(archiveDir, version, arch string, paths *config.Paths) error {
+	data, err := os.ReadFile(filepath.Join(archiveDir, "index.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w %s: no index.json", ErrNotInArchive, archiveDir)
+		}
+		return fmt.Errorf("failed to read archive index: %w", err)
+	}
+	var index map[string]map[string]string
+	if err := json.Unmarshal(data, &index); err != nil {
+		return fmt.Errorf("failed to parse archive index: %w", err)
+	}
+
+	versions := sortVersionsNewestFirst(index[arch])
+	if version == "" && len(versions) > 0 {
+		version = versions[0]
+	}
+	relPath, ok := index[arch][version]
+	if !ok {
+		if len(versions) == 0 {
+			return fmt.Errorf("%w %s: no %s kernels archived", ErrNotInArchive, archiveDir, arch)
+		}
+		return fmt.Errorf("%w %s: no %s for %s (available: %s)", ErrNotInArchive, archiveDir, version, arch, strings.Join(versions, ", "))
+	}
+	// The index is read from a shared location; don't follow it outside
+	if !filepath.IsLocal(relPath) {
+		return fmt.Errorf("archive index has an unsafe path for %s (%s): %s", version, arch, relPath)
+	}
+
+	kernelName, err := config.GetKernelNameForArch(arch)
+	if err != nil {
+		return err
+	}
+	outputDir := filepath.Join(paths.KernelsDir, version)
+	outputFile := filepath.Join(outputDir, fmt.Sprintf("%s-%s-%s", kernelName, version, arch))
+	if _, err := os.Stat(outputFile); err == nil {
+		log.Infof("Kernel already exists: %s", outputFile)
+		return nil
+	}
+
+	archived := filepath.Join(archiveDir, relPath)
+	archivedChecksum := archived + "." + util.ChecksumSHA256
+	if _, err := os.Stat(archivedChecksum); err != nil {
+		return fmt.Errorf("archived kernel %s has no checksum file: %w", relPath, err)
+	}
+
+	// Only remove the version directory on failure if this call created it
+	_, statErr := os.Stat(outputDir)
+	created := os.IsNotExist(statErr)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := installArchivedKernel(archived, outputDir, outputFile); err != nil {
+		if created {
+			os.RemoveAll(outputDir)
+		}
+		return err
+	}
+
+	log.Infof("Installed kernel %s (%s) from archive %s", version, arch, archiveDir)
+	return nil
+}
+
+// installArchivedKernel copies an archived compressed kernel and its
+// checksums into outputDir, verifies it and decompresses it to outputFile
+func installArchivedKernel(archived, outputDir, outputFile string) error {
+	compressed := filepath.Join(outputDir, filepath.Base(archived))
+	if err := linkOrCopyFile(archived, compressed); err != nil {
+		return fmt.Errorf("failed to copy archived kernel: %w", err)
+	}
+
+	// The archive keeps per-file checksums next to each artifact, and
+	// build-stats.json for anvil kernel audit and stats
+	archiveVersionDir := filepath.Dir(archived)
+	uncompressedName := util.TrimCompressionExt(filepath.Base(archived))
+	extras := []string{BuildStatsSidecar}
+	for _, algo := range util.ChecksumAlgorithms {
+		extras = append(extras, filepath.Base(archived)+"."+algo, uncompressedName+"."+algo)
+	}
+	for _, name := range extras {
+		src := filepath.Join(archiveVersionDir, name)
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		if err := linkOrCopyFile(src, filepath.Join(outputDir, name)); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", name, err)
+		}
+	}
+
+	if err := util.VerifySHA256File(compressed, compressed+"."+util.ChecksumSHA256); err != nil {
+		return fmt.Errorf("archived kernel verification failed: %w", err)
+	}
+
+	if err := util.DecompressWithProgress(compressed, outputFile, nil); err != nil {
+		return fmt.Errorf("failed to decompress kernel: %w", err)
+	}
+	checksum := outputFile + "." + util.ChecksumSHA256
+	if _, err := os.Stat(checksum); err == nil {
+		if err := util.VerifySHA256File(outputFile, checksum); err != nil {
+			return fmt.Errorf("decompressed kernel verification failed: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+// archiveFixture archives an xz-compressed x86_64 kernel for each version,
+// as ArchiveInstalledKernel lays them out, and returns the archive directory
+func archiveFixture(t *testing.T, versions ...string) string {
+	t.Helper()
+	archiveDir := t.TempDir()
+	for _, version := range versions {
+		versionDir := filepath.Join(archiveDir, "x86_64", version)
+		if err := os.MkdirAll(versionDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		kernel := filepath.Join(versionDir, "vmlinux-"+version+"-x86_64")
+		if err := os.WriteFile(kernel, []byte("kernel "+version), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := util.CompressWithProgress(util.CompressionXZ, kernel, kernel+".xz", nil); err != nil {
+			t.Fatal(err)
+		}
+		for _, path := range []string{kernel, kernel + ".xz"} {
+			hash, err := util.CalculateSHA256(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			line := fmt.Sprintf("%s  %s\n", hash, filepath.Base(path))
+			if err := os.WriteFile(path+".sha256", []byte(line), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := updateArchiveIndex(archiveDir, "x86_64", version, filepath.Join("x86_64", version, filepath.Base(kernel)+".xz")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return archiveDir
+}
+
+func TestDownloadFromArchive(t *testing.T) {
+	archiveDir := archiveFixture(t, "6.12.9", "6.18.9")
+	paths := config.PathsUnder(t.TempDir())
+
+	if err := downloadFromArchive(archiveDir, "6.12.9", "x86_64", paths); err != nil {
+		t.Fatalf("downloadFromArchive() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(paths.KernelsDir, "6.12.9", "vmlinux-6.12.9-x86_64"))
+	if err != nil || string(data) != "kernel 6.12.9" {
+		t.Errorf("installed kernel = %q, %v", data, err)
+	}
+
+	// Without a version the newest archived kernel is installed
+	if err := downloadFromArchive(archiveDir, "", "x86_64", paths); err != nil {
+		t.Fatalf("downloadFromArchive() without a version error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(paths.KernelsDir, "6.18.9", "vmlinux-6.18.9-x86_64")); err != nil {
+		t.Errorf("newest kernel wasn't installed: %v", err)
+	}
+}
+
+func TestDownloadFromArchiveNotFound(t *testing.T) {
+	archiveDir := archiveFixture(t, "6.1.0", "6.12.9", "6.18.9")
+	paths := config.PathsUnder(t.TempDir())
+
+	tests := []struct {
+		name       string
+		archiveDir string
+		version    string
+		arch       string
+		want       string
+	}{
+		{"no index", t.TempDir(), "6.18.9", "x86_64", "no index.json"},
+		{"no arch", archiveDir, "6.18.9", "aarch64", "no aarch64 kernels archived"},
+		{"no version", archiveDir, "6.19.1", "x86_64", "no 6.19.1 for x86_64 (available: 6.18.9, 6.12.9, 6.1.0)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := downloadFromArchive(tt.archiveDir, tt.version, tt.arch, paths)
+			if !errors.Is(err, ErrNotInArchive) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("downloadFromArchive() error = %v, want ErrNotInArchive with %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownloadFromArchiveUnsafePath(t *testing.T) {
+	paths := config.PathsUnder(t.TempDir())
+	for _, relPath := range []string{"../outside/vmlinux-6.18.9-x86_64.xz", "/etc/passwd", ""} {
+		t.Run(relPath, func(t *testing.T) {
+			archiveDir := t.TempDir()
+			data, err := json.Marshal(map[string]map[string]string{"x86_64": {"6.18.9": relPath}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(archiveDir, "index.json"), data, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			err = downloadFromArchive(archiveDir, "6.18.9", "x86_64", paths)
+			if err == nil || !strings.Contains(err.Error(), "unsafe path") {
+				t.Errorf("downloadFromArchive() error = %v, want an unsafe path error", err)
+			}
+			if _, err := os.Stat(filepath.Join(paths.KernelsDir, "6.18.9")); !os.IsNotExist(err) {
+				t.Error("nothing should be installed from an unsafe path")
+			}
+		})
+	}
+}
+
+func TestDownloadFromArchiveChecksumFailure(t *testing.T) {
+	archiveDir := archiveFixture(t, "6.18.9")
+	archived := filepath.Join(archiveDir, "x86_64", "6.18.9", "vmlinux-6.18.9-x86_64.xz")
+	if err := os.WriteFile(archived, []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The version directory this call created is removed
+	paths := config.PathsUnder(t.TempDir())
+	err := downloadFromArchive(archiveDir, "6.18.9", "x86_64", paths)
+	if err == nil || !strings.Contains(err.Error(), "archived kernel verification failed") {
+		t.Fatalf("downloadFromArchive() error = %v, want a verification failure", err)
+	}
+	if _, err := os.Stat(filepath.Join(paths.KernelsDir, "6.18.9")); !os.IsNotExist(err) {
+		t.Error("version directory wasn't cleaned up after the failure")
+	}
+
+	// but one that already existed is kept
+	existing := filepath.Join(paths.KernelsDir, "6.18.9")
+	if err := os.MkdirAll(existing, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := downloadFromArchive(archiveDir, "6.18.9", "x86_64", paths); err == nil {
+		t.Fatal("downloadFromArchive() of a tampered kernel should fail")
+	}
+	if _, err := os.Stat(existing); err != nil {
+		t.Errorf("existing version directory was removed: %v", err)
+	}
+
+	// A kernel without its checksum file isn't installed at all
+	if err := os.Remove(archived + ".sha256"); err != nil {
+		t.Fatal(err)
+	}
+	err = downloadFromArchive(archiveDir, "6.18.9", "x86_64", config.PathsUnder(t.TempDir()))
+	if err == nil || !strings.Contains(err.Error(), "has no checksum file") {
+		t.Errorf("downloadFromArchive() error = %v, want a missing checksum error", err)
+	}
+}
+
+func TestArchivePrune(t *testing.T) {
+	versions := []string{"6.1.0", "6.6.70", "6.12.9", "6.18.9"}
+	old := time.Now().AddDate(0, 0, -30)
+
+	tests := []struct {
+		name        string
+		retainCount int
+		retainDays  int
+		want        []string // versions left in the index
+	}{
+		{"disabled", 0, 0, versions},
+		{"retain count", 2, 0, []string{"6.12.9", "6.18.9"}},
+		{"retain days", 0, 7, []string{"6.12.9", "6.18.9"}},
+		{"either limit", 3, 7, []string{"6.12.9", "6.18.9"}},
+		{"retain one", 1, 0, []string{"6.18.9"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archiveDir := archiveFixture(t, versions...)
+			// The two oldest were archived a month ago
+			for _, version := range versions[:2] {
+				if err := os.Chtimes(filepath.Join(archiveDir, "x86_64", version), old, old); err != nil {
+					t.Fatal(err)
+				}
+			}
+			sizes := map[string]int64{}
+			for _, version := range versions {
+				size, err := util.DirSize(filepath.Join(archiveDir, "x86_64", version))
+				if err != nil {
+					t.Fatal(err)
+				}
+				sizes[version] = size
+			}
+
+			result, err := ArchivePrune(archiveDir, tt.retainCount, tt.retainDays)
+			if err != nil {
+				t.Fatalf("ArchivePrune() error = %v", err)
+			}
+
+			var freed int64
+			removed := map[string]bool{}
+			for _, entry := range result.Removed {
+				removed[entry.Version] = true
+				freed += sizes[entry.Version]
+				if entry.Arch != "x86_64" || entry.Path != filepath.Join("x86_64", entry.Version, "vmlinux-"+entry.Version+"-x86_64.xz") {
+					t.Errorf("removed entry = %+v", entry)
+				}
+			}
+			if result.FreedBytes != freed {
+				t.Errorf("FreedBytes = %d, want %d", result.FreedBytes, freed)
+			}
+
+			data, err := os.ReadFile(filepath.Join(archiveDir, "index.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var index map[string]map[string]string
+			if err := json.Unmarshal(data, &index); err != nil {
+				t.Fatal(err)
+			}
+			kept := slices.Sorted(maps.Keys(index["x86_64"]))
+			want := slices.Clone(tt.want)
+			slices.Sort(want)
+			if !slices.Equal(kept, want) {
+				t.Errorf("index keeps %v, want %v", kept, want)
+			}
+			for _, version := range versions {
+				keep := slices.Contains(tt.want, version)
+				_, statErr := os.Stat(filepath.Join(archiveDir, "x86_64", version))
+				if keep != (statErr == nil) || keep == removed[version] {
+					t.Errorf("%s: on disk %v, reported removed %v, want kept %v", version, statErr == nil, removed[version], keep)
+				}
+			}
+		})
+	}
+
+	// The newest version of an arch survives however old it is
+	archiveDir := archiveFixture(t, "6.12.9", "6.18.9")
+	for _, version := range []string{"6.12.9", "6.18.9"} {
+		if err := os.Chtimes(filepath.Join(archiveDir, "x86_64", version), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	result, err := ArchivePrune(archiveDir, 0, 7)
+	if err != nil || len(result.Removed) != 1 || result.Removed[0].Version != "6.12.9" {
+		t.Errorf("ArchivePrune() of an aged archive = %+v, %v", result, err)
+	}
+	if _, err := os.Stat(filepath.Join(archiveDir, "x86_64", "6.18.9")); err != nil {
+		t.Errorf("newest version was removed: %v", err)
+	}
+
+	if _, err := ArchivePrune(t.TempDir(), -1, 0); err == nil {
+		t.Error("ArchivePrune() with a negative retention should fail")
+	}
+	// An archive that was never written is empty, not an error
+	result, err = ArchivePrune(filepath.Join(t.TempDir(), "archive"), 1, 0)
+	if err != nil || len(result.Removed) != 0 {
+		t.Errorf("ArchivePrune() of a missing archive = %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+// ReleaseSidecar records how a kernel downloaded from a GitHub release was
+// verified. Built kernels carry BuildStatsSidecar instead.
+const ReleaseSidecar = "release.json"
+
+// ReleaseRecord is the verification record of a downloaded kernel
+type ReleaseRecord struct {
+	Version           string    `json:"version"`
+	Arch              string    `json:"arch"`
+	Asset             string    `json:"asset"`
+	VerificationLevel string    `json:"verification_level"` // Releases are always PGP and checksum verified
+	SHA256            string    `json:"sha256"`             // Of the decompressed kernel
+	DownloadedAt      time.Time `json:"downloaded_at"`
+}
+
+// writeReleaseRecord writes the release sidecar for a verified download
+func writeReleaseRecord(dir, kernelPath, version, arch, asset string) error {
+	hash, err := util.CalculateSHA256(kernelPath)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(ReleaseRecord{
+		Version:           version,
+		Arch:              arch,
+		Asset:             asset,
+		VerificationLevel: "high",
+		SHA256:            hash,
+		DownloadedAt:      time.Now(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ReleaseSidecar), append(data, '\n'), 0644)
+}
+
+// Audit sources: how an installed kernel was obtained
+const (
+	AuditSourceBuilt      = "built"
+	AuditSourceDownloaded = "downloaded"
+	AuditSourceUnknown    = "unknown"
+)
+
+// AuditEntry is the verification posture of one installed kernel
+type AuditEntry struct {
+	Version           string   `json:"version"`
+	Arch              string   `json:"arch,omitempty"`
+	Source            string   `json:"source"`                       // built, downloaded or unknown
+	VerificationLevel string   `json:"verification_level,omitempty"` // high, medium or disabled; empty when not recorded
+	IsDefault         bool     `json:"is_default"`
+	Path              string   `json:"path"`
+	Problems          []string `json:"problems,omitempty"`
+}
+
+// AuditReport is the result of auditing all installed kernels
+type AuditReport struct {
+	Kernels  []AuditEntry `json:"kernels"`
+	Problems int          `json:"problems"` // Kernels with at least one problem
+}
+
+// Audit checks every installed kernel for a missing verification record,
+// a build with source verification disabled, and kernel files whose hash
+// no longer matches the recorded one
+func Audit(paths *config.Paths) (*AuditReport, error) {
+	report := &AuditReport{Kernels: []AuditEntry{}}
+
+	entries, err := os.ReadDir(paths.KernelsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return report, nil
+		}
+		return nil, fmt.Errorf("failed to read kernels directory: %w", err)
+	}
+
+	defaultKernel := defaultKernelTarget(paths)
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(paths.KernelsDir, entry.Name())
+		result := auditKernelDir(dir)
+		result.IsDefault = defaultKernel != "" && filepath.Dir(defaultKernel) == dir
+		if len(result.Problems) > 0 {
+			report.Problems++
+		}
+		report.Kernels = append(report.Kernels, result)
+	}
+	sort.Slice(report.Kernels, func(i, j int) bool { return report.Kernels[i].Version < report.Kernels[j].Version })
+
+	return report, nil
+}
+
+// defaultKernelTarget returns the kernel the host arch's default symlink
+// points at, or ""
+func defaultKernelTarget(paths *config.Paths) string {
+	kernelName, err := config.GetKernelName()
+	if err != nil {
+		return ""
+	}
+	target, err := os.Readlink(filepath.Join(paths.DataDir, kernelName))
+	if err != nil {
+		return ""
+	}
+	return target
+}
+
+// auditKernelDir audits one installed version directory
+func auditKernelDir(dir string) AuditEntry {
+	result := AuditEntry{Version: filepath.Base(dir), Source: AuditSourceUnknown, Path: dir}
+
+	var kernelPath string
+	for _, arch := range []string{"x86_64", "aarch64"} {
+		if path := installedKernelFile(dir, arch); path != "" {
+			kernelPath = path
+			result.Arch = arch
+			break
+		}
+	}
+	if kernelPath == "" {
+		result.Problems = append(result.Problems, "no kernel image found")
+	}
+
+	if stats, err := ReadBuildStats(filepath.Join(dir, BuildStatsSidecar)); err == nil {
+		result.Source = AuditSourceBuilt
+		result.VerificationLevel = stats.VerificationLevel
+		switch stats.VerificationLevel {
+		case "":
+			result.Problems = append(result.Problems, "source verification level not recorded")
+		case "disabled":
+			result.Problems = append(result.Problems, "built with source verification disabled")
+		}
+		if kernelPath != "" {
+			result.Problems = append(result.Problems, checkRecordedHash(kernelPath, stats.UncompressedHash)...)
+			if compressed := existingCompressedKernel(kernelPath); compressed != "" {
+				result.Problems = append(result.Problems, checkRecordedHash(compressed, stats.CompressedHash)...)
+			}
+		}
+		return result
+	}
+
+	if record, err := readReleaseRecord(filepath.Join(dir, ReleaseSidecar)); err == nil {
+		result.Source = AuditSourceDownloaded
+		result.VerificationLevel = record.VerificationLevel
+		if kernelPath != "" {
+			result.Problems = append(result.Problems, checkRecordedHash(kernelPath, record.SHA256)...)
+		}
+		return result
+	}
+
+	result.Problems = append(result.Problems, "verification metadata missing")
+	return result
+}
+
+// checkRecordedHash compares a file's SHA256 with the recorded one
+func checkRecordedHash(path, recorded string) []string {
+	name := filepath.Base(path)
+	if recorded == "" {
+		return []string{fmt.Sprintf("%s: no recorded hash", name)}
+	}
+	hash, err := util.CalculateSHA256(path)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", name, err)}
+	}
+	if hash != recorded {
+		return []string{fmt.Sprintf("%s: hash mismatch (recorded %s, on disk %s)", name, shortHash(recorded), shortHash(hash))}
+	}
+	return nil
+}
+
+// shortHash abbreviates a hex hash for messages
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+// readReleaseRecord reads a release sidecar
+func readReleaseRecord(path string) (ReleaseRecord, error) {
+	var record ReleaseRecord
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return record, err
+	}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return record, fmt.Errorf("invalid release record %s: %w", path, err)
+	}
+	return record, nil
+}
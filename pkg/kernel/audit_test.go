@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+// installKernel writes an installed x86_64 kernel for version, returning
+// its version directory and kernel path
+func installKernel(t *testing.T, paths *config.Paths, version string) (string, string) {
+	t.Helper()
+	dir := filepath.Join(paths.KernelsDir, version)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	kernelPath := filepath.Join(dir, "vmlinux-"+version+"-x86_64")
+	if err := os.WriteFile(kernelPath, []byte("kernel "+version), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir, kernelPath
+}
+
+func TestAudit(t *testing.T) {
+	paths := config.PathsUnder(t.TempDir())
+
+	// Built with high verification and unchanged since
+	dir, kernelPath := installKernel(t, paths, "6.18.9")
+	hash, err := util.CalculateSHA256(kernelPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeBuildStats(filepath.Join(dir, BuildStatsSidecar), BuildStats{KernelVersion: "6.18.9", VerificationLevel: "high", UncompressedHash: hash}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Built without verifying the source
+	dir, kernelPath = installKernel(t, paths, "6.12.9")
+	hash, err = util.CalculateSHA256(kernelPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeBuildStats(filepath.Join(dir, BuildStatsSidecar), BuildStats{KernelVersion: "6.12.9", VerificationLevel: "disabled", UncompressedHash: hash}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changed after it was downloaded
+	dir, kernelPath = installKernel(t, paths, "6.6.70")
+	if err := writeReleaseRecord(dir, kernelPath, "6.6.70", "x86_64", "vmlinux-6.6.70-x86_64.xz"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(kernelPath, []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Installed by hand, with no record at all
+	installKernel(t, paths, "6.1.0")
+
+	report, err := Audit(paths)
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+
+	want := []struct {
+		version  string
+		source   string
+		level    string
+		problems []string
+	}{
+		{"6.1.0", AuditSourceUnknown, "", []string{"verification metadata missing"}},
+		{"6.12.9", AuditSourceBuilt, "disabled", []string{"built with source verification disabled"}},
+		{"6.18.9", AuditSourceBuilt, "high", nil},
+		{"6.6.70", AuditSourceDownloaded, "high", []string{"vmlinux-6.6.70-x86_64: hash mismatch"}},
+	}
+	if len(report.Kernels) != len(want) {
+		t.Fatalf("Audit() audited %d kernels, want %d: %+v", len(report.Kernels), len(want), report.Kernels)
+	}
+	for i, w := range want {
+		got := report.Kernels[i]
+		if got.Version != w.version || got.Source != w.source || got.VerificationLevel != w.level || got.Arch != "x86_64" {
+			t.Errorf("kernel %d = %+v, want %s %s %q", i, got, w.version, w.source, w.level)
+		}
+		if len(got.Problems) != len(w.problems) {
+			t.Errorf("%s problems = %q, want %q", got.Version, got.Problems, w.problems)
+			continue
+		}
+		for j, problem := range w.problems {
+			if !strings.HasPrefix(got.Problems[j], problem) {
+				t.Errorf("%s problem = %q, want %q", got.Version, got.Problems[j], problem)
+			}
+		}
+	}
+	if report.Problems != 3 {
+		t.Errorf("Audit() counted %d kernels with problems, want 3", report.Problems)
+	}
+}
+
+func TestAuditBuiltKernelProblems(t *testing.T) {
+	paths := config.PathsUnder(t.TempDir())
+	dir, kernelPath := installKernel(t, paths, "6.18.9")
+	if err := os.WriteFile(kernelPath+".xz", []byte("XZ"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// An old build: no verification level or compressed hash recorded, and
+	// a kernel hash that no longer matches
+	if err := writeBuildStats(filepath.Join(dir, BuildStatsSidecar), BuildStats{KernelVersion: "6.18.9", UncompressedHash: strings.Repeat("0", 64)}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Audit(paths)
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+	if len(report.Kernels) != 1 {
+		t.Fatalf("Audit() = %+v", report.Kernels)
+	}
+	problems := report.Kernels[0].Problems
+	for _, want := range []string{
+		"source verification level not recorded",
+		"vmlinux-6.18.9-x86_64: hash mismatch (recorded 000000000000",
+		"vmlinux-6.18.9-x86_64.xz: no recorded hash",
+	} {
+		if !slices.ContainsFunc(problems, func(p string) bool { return strings.HasPrefix(p, want) }) {
+			t.Errorf("problems %q don't include %q", problems, want)
+		}
+	}
+}
+
+func TestAuditNoKernels(t *testing.T) {
+	report, err := Audit(config.PathsUnder(t.TempDir()))
+	if err != nil || len(report.Kernels) != 0 || report.Problems != 0 {
+		t.Errorf("Audit() without a kernels directory = %+v, %v", report, err)
+	}
+
+	// A version directory without a kernel image is a problem of its own
+	paths := config.PathsUnder(t.TempDir())
+	if err := os.MkdirAll(filepath.Join(paths.KernelsDir, "6.18.9"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	report, err = Audit(paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Kernels) != 1 || !slices.Contains(report.Kernels[0].Problems, "no kernel image found") {
+		t.Errorf("Audit() of an empty version directory = %+v", report.Kernels)
+	}
+}
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+)
+
+// embeddedKeys holds the kernel.org keys shipped in the binary
+//
+//go:embed keys
+var embeddedKeys embed.FS
+
+// embeddedAutosignerKeyFile is the autosigner key's path in embeddedKeys
+const embeddedAutosignerKeyFile = "keys/autosigner.asc"
+
+// importAutosignerKey imports the kernel.org autosigner GPG key. A key file
+// set with kernels.autosigner-key is the only source when configured;
+// otherwise the embedded key is tried first and keyservers after it. Keys
+// from a file are only imported if their fingerprint matches.
+func importAutosignerKey(logger *buildLogger) error {
+	// Check if gpg is available
+	if _, err := exec.LookPath("gpg"); err != nil {
+		return fmt.Errorf("gpg not found")
+	}
+
+	// Check if key is already imported
+	cmd := exec.Command("gpg", "--list-keys", autosignerKeyID)
+	if err := cmd.Run(); err == nil {
+		// Key already imported
+		return nil
+	}
+
+	logger.Info("Importing kernel.org autosigner GPG key...")
+	logger.Info(fmt.Sprintf("  Key ID: %s", autosignerKeyID))
+	logger.Info(fmt.Sprintf("  Fingerprint: %s", autosignerKeyFingerprint))
+
+	// A pinned key file replaces the other sources
+	if path := config.GetKernelsAutosignerKey(); path != "" {
+		logger.Info(fmt.Sprintf("  Using key file: %s", path))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read kernels.autosigner-key: %w", err)
+		}
+		if err := importAutosignerKeyData(data); err != nil {
+			return fmt.Errorf("failed to import %s: %w", path, err)
+		}
+		logger.Info("✓ Autosigner key imported successfully")
+		return nil
+	}
+
+	// The embedded key works without network access
+	if data, err := embeddedKeys.ReadFile(embeddedAutosignerKeyFile); err != nil {
+		logger.Debug("No autosigner key embedded in this build")
+	} else if err := importAutosignerKeyData(data); err != nil {
+		logger.Warn(fmt.Sprintf("Could not import the embedded autosigner key: %v", err))
+	} else {
+		logger.Info("✓ Autosigner key imported from the embedded copy")
+		return nil
+	}
+
+	// Try multiple keyservers
+	keyservers := []string{
+		"hkps://keyserver.ubuntu.com",
+		"hkps://keys.openpgp.org",
+		"hkps://pgp.mit.edu",
+	}
+
+	for _, keyserver := range keyservers {
+		logger.Info(fmt.Sprintf("  Trying keyserver: %s", keyserver))
+		cmd := exec.Command("gpg", "--keyserver", keyserver, "--recv-keys", autosignerKeyID)
+		if err := cmd.Run(); err == nil {
+			logger.Info("✓ Autosigner key imported successfully")
+			return verifyKeyFingerprint(autosignerKeyFingerprint)
+		}
+	}
+
+	return fmt.Errorf("failed to import autosigner key from any keyserver")
+}
+
+// importAutosignerKeyData imports an armored or binary public key after
+// checking it is the autosigner key, so a wrong file never reaches the
+// keyring
+func importAutosignerKeyData(data []byte) error {
+	return importPublicKeyData(data, autosignerKeyFingerprint)
+}
+
+// importPublicKeyData imports an armored or binary public key if its
+// primary key has the given fingerprint
+func importPublicKeyData(data []byte, fingerprint string) error {
+	cmd := exec.Command("gpg", "--show-keys", "--with-colons")
+	cmd.Stdin = bytes.NewReader(data)
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("not a readable PGP public key: %w", err)
+	}
+	if !primaryFingerprintsContain(string(output), fingerprint) {
+		return fmt.Errorf("fingerprint mismatch - the key is not %s", fingerprint)
+	}
+
+	cmd = exec.Command("gpg", "--import")
+	cmd.Stdin = bytes.NewReader(data)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("gpg --import failed: %w\n%s", err, output)
+	}
+
+	return verifyKeyFingerprint(fingerprint)
+}
+
+// primaryFingerprintsContain reports whether gpg --with-colons output lists
+// a primary key with fingerprint fpr
+func primaryFingerprintsContain(colons, fpr string) bool {
+	for _, primary := range primaryFingerprints(colons) {
+		if strings.EqualFold(primary, fpr) {
+			return true
+		}
+	}
+	return false
+}
+
+// primaryFingerprints returns the primary key fingerprints listed in gpg
+// --with-colons output. Only the fpr record right after a pub record
+// belongs to the primary key; later ones are subkeys.
+func primaryFingerprints(colons string) []string {
+	var fprs []string
+	afterPub := false
+	for _, line := range strings.Split(colons, "\n") {
+		fields := strings.Split(line, ":")
+		switch fields[0] {
+		case "pub":
+			afterPub = true
+		case "fpr":
+			if afterPub && len(fields) > 9 {
+				fprs = append(fprs, fields[9])
+			}
+			afterPub = false
+		}
+	}
+	return fprs
+}
+
+// verifyKeyFingerprint checks that the keyring holds the key with the
+// given fingerprint
+func verifyKeyFingerprint(fingerprint string) error {
+	cmd := exec.Command("gpg", "--fingerprint", fingerprint)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to verify fingerprint: %w", err)
+	}
+
+	// gpg prints the fingerprint in groups of four
+	if !strings.Contains(strings.ReplaceAll(string(output), " ", ""), fingerprint) {
+		return fmt.Errorf("fingerprint mismatch - possible key substitution attack")
+	}
+
+	return nil
+}
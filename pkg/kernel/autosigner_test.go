@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// testGPGKey creates a throwaway signing key in a temporary GNUPGHOME and
+// returns its armored public key and fingerprint. The key is then deleted
+// from the keyring so imports start from a clean slate.
+func testGPGKey(t *testing.T) ([]byte, string) {
+	t.Helper()
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg not installed")
+	}
+	home, err := os.MkdirTemp("", "gpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		exec.Command("gpgconf", "--homedir", home, "--kill", "gpg-agent").Run()
+		os.RemoveAll(home)
+	})
+	t.Setenv("GNUPGHOME", home)
+
+	if out, err := exec.Command("gpg", "--batch", "--passphrase", "", "--quick-gen-key", "Anvil Test <test@example.com>", "ed25519", "sign", "never").CombinedOutput(); err != nil {
+		t.Fatalf("gpg --quick-gen-key: %v\n%s", err, out)
+	}
+	colons, err := exec.Command("gpg", "--with-colons", "--list-keys", "test@example.com").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fprs := primaryFingerprints(string(colons))
+	if len(fprs) != 1 {
+		t.Fatalf("expected one test key, got %v", fprs)
+	}
+	armored, err := exec.Command("gpg", "--armor", "--export", fprs[0]).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("gpg", "--batch", "--yes", "--delete-secret-and-public-key", fprs[0]).CombinedOutput(); err != nil {
+		t.Fatalf("gpg --delete-secret-and-public-key: %v\n%s", err, out)
+	}
+	return armored, fprs[0]
+}
+
+func TestImportAutosignerKeyDataRejectsOtherKey(t *testing.T) {
+	armored, fpr := testGPGKey(t)
+
+	err := importAutosignerKeyData(armored)
+	if err == nil || !strings.Contains(err.Error(), "fingerprint mismatch") {
+		t.Fatalf("importAutosignerKeyData() error = %v, want a fingerprint mismatch", err)
+	}
+	// The wrong key never reaches the keyring
+	if err := exec.Command("gpg", "--list-keys", fpr).Run(); err == nil {
+		t.Error("rejected key was imported")
+	}
+}
+
+func TestImportPublicKeyDataMatchingFingerprint(t *testing.T) {
+	armored, fpr := testGPGKey(t)
+
+	if err := importPublicKeyData(armored, fpr); err != nil {
+		t.Fatalf("importPublicKeyData() error = %v", err)
+	}
+	if err := exec.Command("gpg", "--list-keys", fpr).Run(); err != nil {
+		t.Error("key with the expected fingerprint wasn't imported")
+	}
+}
+
+func TestImportPublicKeyDataNotAKey(t *testing.T) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg not installed")
+	}
+	t.Setenv("GNUPGHOME", t.TempDir())
+	if err := importPublicKeyData([]byte("not a key"), autosignerKeyFingerprint); err == nil {
+		t.Error("importPublicKeyData() accepted data that isn't a key")
+	}
+}
+
+func TestEmbeddedAutosignerKeyFingerprint(t *testing.T) {
+	data, err := embeddedKeys.ReadFile(embeddedAutosignerKeyFile)
+	if err != nil {
+		t.Skip("no autosigner key embedded")
+	}
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg not installed")
+	}
+	t.Setenv("GNUPGHOME", t.TempDir())
+	cmd := exec.Command("gpg", "--show-keys", "--with-colons")
+	cmd.Stdin = bytes.NewReader(data)
+	colons, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("embedded %s isn't a readable key: %v", embeddedAutosignerKeyFile, err)
+	}
+	if !primaryFingerprintsContain(string(colons), autosignerKeyFingerprint) {
+		t.Errorf("embedded %s has fingerprints %v, want %s", embeddedAutosignerKeyFile, primaryFingerprints(string(colons)), autosignerKeyFingerprint)
+	}
+}
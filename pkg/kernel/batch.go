@@ -0,0 +1,291 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+)
+
+// BatchEntry is one version/arch pair from a batch file
+type BatchEntry struct {
+	Version string `json:"version"`
+	Arch    string `json:"arch"`
+	Line    int    `json:"line"` // Line in the batch file, for error messages
+}
+
+// BatchOptions configures BuildBatch
+type BatchOptions struct {
+	// Parallel is the number of builds run at once (0 or 1 = sequential).
+	// Entries of the same kernel version never build at the same time,
+	// since they share the kept source tarball.
+	Parallel int
+
+	// FailFast stops starting new builds after the first failure. Builds
+	// already running are finished.
+	FailFast bool
+
+	// EntryCallback is called as each entry starts and finishes
+	EntryCallback func(entry BatchEntry, result *BatchResult)
+}
+
+// BatchResult is the outcome of one batch entry
+type BatchResult struct {
+	Version        string        `json:"version"`
+	Arch           string        `json:"arch"`
+	Status         string        `json:"status"` // succeeded, failed or skipped
+	Error          string        `json:"error,omitempty"`
+	Duration       time.Duration `json:"duration_ns"`
+	OutputPath     string        `json:"output_path,omitempty"`
+	CompressedPath string        `json:"compressed_path,omitempty"`
+	Stats          *BuildStats   `json:"stats,omitempty"`
+}
+
+// Batch result statuses
+const (
+	BatchSucceeded = "succeeded"
+	BatchFailed    = "failed"
+	BatchSkipped   = "skipped" // Not started after a failure with FailFast, or after cancellation
+)
+
+// BatchReport summarises a batch build
+type BatchReport struct {
+	Started   time.Time     `json:"started"`
+	Finished  time.Time     `json:"finished"`
+	Duration  time.Duration `json:"duration_ns"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Skipped   int           `json:"skipped"`
+	Entries   []BatchResult `json:"entries"`
+}
+
+// ParseBatchFile reads a batch file of "version[,arch]" lines. Blank lines
+// and lines starting with # are ignored. Entries without an arch use
+// defaultArch; "all" expands to one entry per architecture.
+func ParseBatchFile(path, defaultArch string) ([]BatchEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch file: %w", err)
+	}
+
+	if defaultArch == "" {
+		defaultArch, err = config.GetArch()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var entries []BatchEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, ",")
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("%s:%d: expected version[,arch], got %q", path, lineNum, line)
+		}
+		version := strings.TrimSpace(fields[0])
+		if version == "" {
+			return nil, fmt.Errorf("%s:%d: missing version", path, lineNum)
+		}
+		arch := defaultArch
+		if len(fields) == 2 && strings.TrimSpace(fields[1]) != "" {
+			arch = strings.TrimSpace(fields[1])
+		}
+
+		switch arch {
+		case "x86_64", "aarch64":
+			entries = append(entries, BatchEntry{Version: version, Arch: arch, Line: lineNum})
+		case "all":
+			for _, a := range []string{"x86_64", "aarch64"} {
+				entries = append(entries, BatchEntry{Version: version, Arch: a, Line: lineNum})
+			}
+		default:
+			return nil, fmt.Errorf("%s:%d: unsupported architecture: %s (supported: x86_64, aarch64, all)", path, lineNum, arch)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read batch file: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("batch file %s has no versions", path)
+	}
+
+	return entries, nil
+}
+
+// BuildBatch builds every entry with the settings in base (its Version,
+// Arch and StatsCallback are set per entry) and reports the outcome of
+// each. Failed entries don't stop the batch unless opts.FailFast is set.
+// With more than one parallel build, each output line is prefixed with the
+// entry's version and arch.
+func BuildBatch(entries []BatchEntry, base BuildOptions, paths *config.Paths, opts BatchOptions) *BatchReport {
+	parallel := max(opts.Parallel, 1)
+
+	writer := base.Writer
+	if writer == nil {
+		writer = os.Stdout
+	}
+	ctx := base.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	report := &BatchReport{Started: time.Now(), Entries: make([]BatchResult, len(entries))}
+
+	var (
+		mu           sync.Mutex // guards failed and versionLocks
+		failed       bool
+		versionLocks = map[string]*sync.Mutex{}
+		writeMu      sync.Mutex // serialises prefixed output lines
+		wg           sync.WaitGroup
+	)
+	slots := make(chan struct{}, parallel)
+
+	for i, entry := range entries {
+		slots <- struct{}{}
+
+		mu.Lock()
+		stop := (opts.FailFast && failed) || ctx.Err() != nil
+		lock := versionLocks[entry.Version]
+		if lock == nil {
+			lock = &sync.Mutex{}
+			versionLocks[entry.Version] = lock
+		}
+		mu.Unlock()
+
+		if stop {
+			<-slots
+			report.Entries[i] = BatchResult{Version: entry.Version, Arch: entry.Arch, Status: BatchSkipped}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, entry BatchEntry) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			if opts.EntryCallback != nil {
+				opts.EntryCallback(entry, nil)
+			}
+
+			entryOpts := base
+			entryOpts.Version = entry.Version
+			entryOpts.Arch = entry.Arch
+			entryOpts.Context = ctx
+			entryOpts.Writer = writer
+			if parallel > 1 {
+				entryOpts.Writer = &prefixWriter{w: writer, mu: &writeMu, prefix: fmt.Sprintf("[%s %s] ", entry.Version, entry.Arch)}
+			}
+			var stats *BuildStats
+			entryOpts.StatsCallback = func(s BuildStats) {
+				stats = &s
+			}
+
+			start := time.Now()
+			err := Build(entryOpts, paths)
+			if pw, ok := entryOpts.Writer.(*prefixWriter); ok {
+				pw.Flush()
+			}
+
+			result := BatchResult{
+				Version:  entry.Version,
+				Arch:     entry.Arch,
+				Status:   BatchSucceeded,
+				Duration: time.Since(start),
+				Stats:    stats,
+			}
+			if stats != nil {
+				result.OutputPath = stats.OutputPath
+				result.CompressedPath = stats.CompressedPath
+			}
+			if err != nil {
+				result.Status = BatchFailed
+				result.Error = err.Error()
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+			}
+			report.Entries[i] = result
+
+			if opts.EntryCallback != nil {
+				opts.EntryCallback(entry, &result)
+			}
+		}(i, entry)
+	}
+	wg.Wait()
+
+	report.Finished = time.Now()
+	report.Duration = report.Finished.Sub(report.Started)
+	for _, result := range report.Entries {
+		switch result.Status {
+		case BatchSucceeded:
+			report.Succeeded++
+		case BatchFailed:
+			report.Failed++
+		case BatchSkipped:
+			report.Skipped++
+		}
+	}
+
+	return report
+}
+
+// prefixWriter writes complete lines to w with a prefix, so the output of
+// parallel builds doesn't interleave mid-line
+type prefixWriter struct {
+	w      io.Writer
+	mu     *sync.Mutex // shared by all writers to w
+	prefix string
+
+	bufMu sync.Mutex
+	buf   []byte
+}
+
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.bufMu.Lock()
+	defer p.bufMu.Unlock()
+
+	p.buf = append(p.buf, data...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			break
+		}
+		p.writeLine(p.buf[:i+1])
+		p.buf = p.buf[i+1:]
+	}
+	return len(data), nil
+}
+
+// Flush writes any trailing partial line
+func (p *prefixWriter) Flush() {
+	p.bufMu.Lock()
+	defer p.bufMu.Unlock()
+
+	if len(p.buf) > 0 {
+		p.writeLine(append(p.buf, '\n'))
+		p.buf = nil
+	}
+}
+
+func (p *prefixWriter) writeLine(line []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	io.WriteString(p.w, p.prefix)
+	p.w.Write(line)
+}
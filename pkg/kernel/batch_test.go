@@ -0,0 +1,259 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+)
+
+func TestParseBatchFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []BatchEntry
+		wantErr string
+	}{
+		{
+			name:    "default arch",
+			content: "6.18.9\n",
+			want:    []BatchEntry{{Version: "6.18.9", Arch: "x86_64", Line: 1}},
+		},
+		{
+			name:    "comments and blank lines",
+			content: "# kernels to build\n\n6.12.9,aarch64\n  6.18.9 , x86_64  \n",
+			want: []BatchEntry{
+				{Version: "6.12.9", Arch: "aarch64", Line: 3},
+				{Version: "6.18.9", Arch: "x86_64", Line: 4},
+			},
+		},
+		{
+			name:    "empty arch uses the default",
+			content: "6.18.9,\n",
+			want:    []BatchEntry{{Version: "6.18.9", Arch: "x86_64", Line: 1}},
+		},
+		{
+			name:    "all",
+			content: "6.18.9,all\n",
+			want: []BatchEntry{
+				{Version: "6.18.9", Arch: "x86_64", Line: 1},
+				{Version: "6.18.9", Arch: "aarch64", Line: 1},
+			},
+		},
+		{name: "too many fields", content: "6.18.9,x86_64,extra\n", wantErr: ":1: expected version[,arch]"},
+		{name: "missing version", content: "6.18.9\n,aarch64\n", wantErr: ":2: missing version"},
+		{name: "bad arch", content: "6.18.9,riscv64\n", wantErr: ":1: unsupported architecture: riscv64"},
+		{name: "no versions", content: "# nothing yet\n", wantErr: "has no versions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "kernels.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ParseBatchFile(path, "x86_64")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseBatchFile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBatchFile() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseBatchFile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseBatchFile(filepath.Join(t.TempDir(), "missing.txt"), "x86_64"); err == nil {
+		t.Error("ParseBatchFile() of a missing file should fail")
+	}
+}
+
+// cachedBuild leaves a built kernel and its stats in the artifacts
+// directory, so building it again returns without compiling
+func cachedBuild(t *testing.T, paths *config.Paths, version, arch string) {
+	t.Helper()
+	artifactsDir := filepath.Join(paths.KernelBuildDir, "artifacts")
+	if err := os.MkdirAll(artifactsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	kernelFilename, _ := kernelArtifactNames(version, arch)
+	kernelPath := filepath.Join(artifactsDir, kernelFilename)
+	for _, path := range []string{kernelPath, kernelPath + ".xz"} {
+		if err := os.WriteFile(path, []byte("ELF"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stats := BuildStats{KernelVersion: version, Arch: arch, OutputPath: kernelPath, CompressedPath: kernelPath + ".xz"}
+	if err := writeBuildStats(filepath.Join(artifactsDir, BuildStatsFile(version, arch)), stats); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildBatch(t *testing.T) {
+	paths := config.PathsUnder(t.TempDir())
+	cachedBuild(t, paths, "6.12.9", "x86_64")
+	cachedBuild(t, paths, "6.18.9", "x86_64")
+	cachedBuild(t, paths, "6.18.9", "aarch64")
+
+	// An unsupported arch fails before any work
+	entries := []BatchEntry{
+		{Version: "6.12.9", Arch: "x86_64"},
+		{Version: "6.12.9", Arch: "riscv64"},
+		{Version: "6.18.9", Arch: "x86_64"},
+		{Version: "6.18.9", Arch: "aarch64"},
+	}
+	tests := []struct {
+		name     string
+		opts     BatchOptions
+		statuses []string
+	}{
+		{
+			name:     "sequential",
+			statuses: []string{BatchSucceeded, BatchFailed, BatchSucceeded, BatchSucceeded},
+		},
+		{
+			name:     "fail fast",
+			opts:     BatchOptions{FailFast: true},
+			statuses: []string{BatchSucceeded, BatchFailed, BatchSkipped, BatchSkipped},
+		},
+		{
+			name:     "parallel",
+			opts:     BatchOptions{Parallel: 3},
+			statuses: []string{BatchSucceeded, BatchFailed, BatchSucceeded, BatchSucceeded},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu       sync.Mutex
+				started  int
+				finished int
+			)
+			tt.opts.EntryCallback = func(entry BatchEntry, result *BatchResult) {
+				mu.Lock()
+				defer mu.Unlock()
+				if result == nil {
+					started++
+				} else {
+					finished++
+				}
+			}
+			base := BuildOptions{Writer: &bytes.Buffer{}, AllowRoot: true, VerificationLevel: "disabled"}
+
+			report := BuildBatch(entries, base, paths, tt.opts)
+
+			var statuses []string
+			for _, result := range report.Entries {
+				statuses = append(statuses, result.Status)
+			}
+			if !reflect.DeepEqual(statuses, tt.statuses) {
+				t.Errorf("BuildBatch() statuses = %v, want %v", statuses, tt.statuses)
+			}
+
+			var succeeded, failed, skipped int
+			for _, status := range tt.statuses {
+				switch status {
+				case BatchSucceeded:
+					succeeded++
+				case BatchFailed:
+					failed++
+				case BatchSkipped:
+					skipped++
+				}
+			}
+			if report.Succeeded != succeeded || report.Failed != failed || report.Skipped != skipped {
+				t.Errorf("BuildBatch() counted %d/%d/%d succeeded/failed/skipped, want %d/%d/%d",
+					report.Succeeded, report.Failed, report.Skipped, succeeded, failed, skipped)
+			}
+			if started != len(entries)-skipped || finished != started {
+				t.Errorf("EntryCallback started %d and finished %d entries, want %d", started, finished, len(entries)-skipped)
+			}
+
+			for i, result := range report.Entries {
+				switch result.Status {
+				case BatchSucceeded:
+					if result.Stats == nil || result.Stats.KernelVersion != entries[i].Version {
+						t.Errorf("entry %d: stats = %+v, want the cached %s build's", i, result.Stats, entries[i].Version)
+					}
+				case BatchFailed:
+					if !strings.Contains(result.Error, "unsupported architecture") {
+						t.Errorf("entry %d: error = %q", i, result.Error)
+					}
+				}
+			}
+		})
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func TestPrefixWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{"whole lines", []string{"one\ntwo\n"}, "[x] one\n[x] two\n"},
+		{"split line", []string{"o", "ne\nt", "wo\n"}, "[x] one\n[x] two\n"},
+		{"partial line flushed", []string{"one\ntw", "o"}, "[x] one\n[x] two\n"},
+		{"empty line", []string{"\n"}, "[x] \n"},
+		{"nothing", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			pw := &prefixWriter{w: &out, mu: &sync.Mutex{}, prefix: "[x] "}
+			for _, w := range tt.writes {
+				if n, err := pw.Write([]byte(w)); n != len(w) || err != nil {
+					t.Fatalf("Write(%q) = %d, %v", w, n, err)
+				}
+			}
+			pw.Flush()
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+
+	// Writers sharing an output never interleave within a line
+	var out syncBuffer
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, prefix := range []string{"[a] ", "[b] "} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pw := &prefixWriter{w: &out, mu: &mu, prefix: prefix}
+			for range 100 {
+				pw.Write([]byte("hello "))
+				pw.Write([]byte("world\n"))
+			}
+		}()
+	}
+	wg.Wait()
+	for _, line := range strings.Split(strings.TrimSuffix(out.buf.String(), "\n"), "\n") {
+		if line != "[a] hello world" && line != "[b] hello world" {
+			t.Fatalf("interleaved line %q", line)
+		}
+	}
+}
@@ -4,14 +4,16 @@ package kernel
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -35,19 +37,123 @@ const (
 
 // BuildOptions contains options for building a kernel
 type BuildOptions struct {
-	Version           string
-	Arch              string
-	VerificationLevel string
-	ConfigFile        string
-	Writer            io.Writer        // Optional: custom writer for build output (for TUI streaming)
-	ProgressCallback  func(float64)    // Optional: callback for download progress (0.0 to 1.0)
-	PhaseCallback     func(BuildPhase) // Optional: callback for phase transitions
-	StatsCallback     func(BuildStats) // Optional: callback for final build statistics
-	Context           context.Context  // Optional: context for cancellation
+	Version              string
+	Arch                 string
+	VerificationLevel    string
+	ConfigFile           string
+	Writer               io.Writer                // Optional: custom writer for build output (for TUI streaming)
+	ProgressCallback     func(float64)            // Optional: callback for download and extract progress (0.0 to 1.0)
+	PhaseCallback        func(BuildPhase)         // Optional: callback for phase transitions
+	ArchPhaseCallback    func(string, BuildPhase) // Optional: like PhaseCallback, with the architecture being built
+	ArchProgressCallback func(string, float64)    // Optional: like ProgressCallback, with the architecture being built
+	Progress             util.ProgressReporter    // Optional: receives phases, download progress and log messages
+	StatsCallback        func(BuildStats)         // Optional: callback for final build statistics
+	Context              context.Context          // Optional: context for cancellation
+
+	// AutoFix repairs the kernel config (defconfig merge) and retries once when
+	// the compile fails on missing symbols. Without it, ConfirmConfigRepair
+	// (if set) is asked whether to repair.
+	AutoFix             bool
+	ConfirmConfigRepair func(symbols []string) bool
+
+	// KeepTarball keeps the verified source tarball in a cache keyed by
+	// version and hash, and reuses it (after re-checking the hash) instead
+	// of downloading again
+	KeepTarball bool
+
+	// ChecksumAlgorithms selects the per-file checksums written next to the
+	// artifacts (and the SHA256SUMS/SHA512SUMS generated when archiving).
+	// Defaults to sha256 only.
+	ChecksumAlgorithms []string
+
+	// Jobs is the number of parallel make jobs for the compile (0 = one per
+	// CPU, or whatever -j MAKEFLAGS sets). Values above the core count are
+	// passed through unchanged.
+	Jobs int
+
+	// AllowRoot lets the build run as root. Without it, Build refuses to run
+	// with euid 0 so root-owned files don't end up in the user's cache.
+	AllowRoot bool
+
+	// UseCcache compiles through ccache. nil uses ccache when it is on PATH;
+	// true fails the build if ccache is missing; false never uses it.
+	UseCcache *bool
+
+	// SourceTarball builds from a local linux-<version>.tar.xz instead of
+	// downloading it; the version defaults to the one in its name.
+	// ChecksumsFile is the sha256sums.asc to verify it against (default:
+	// next to the tarball). Nothing is fetched from kernel.org.
+	SourceTarball string
+	ChecksumsFile string
+
+	// GitSource shallow-clones a tag or branch into the source directory
+	// instead of downloading and extracting a release tarball. The version
+	// defaults to the one the ref names, else the cloned Makefile's. The
+	// clone can't be verified, so the build records verification disabled.
+	GitSource *GitSource
+
+	// Mirror is the base URL sources and checksums are downloaded from
+	// (default: kernels.mirror, else DefaultSourceMirror). It must serve
+	// kernel.org's v<major>.x/ layout.
+	Mirror string
+
+	// Patches are applied in order with patch -p1 right after the source is
+	// extracted, before the kernel is configured. A source tree patched
+	// differently from a previous build is re-extracted first.
+	Patches []string
+
+	// Compression is the format of the packaged kernel: "xz" (default) or
+	// "zstd". It sets the compressed artifact's extension (.xz or .zst).
+	Compression string
+
+	// Toolchain is the compiler toolchain: "gcc" (default) or "llvm", which
+	// builds with clang and ld.lld (LLVM=1)
+	Toolchain string
+
+	// BuildModules also runs make modules and modules_install after the
+	// kernel image, and packages lib/modules/<release> as
+	// modules-<version>-<arch>.tar.xz in the artifacts
+	BuildModules bool
+
+	// Optional per-phase timeouts (0 = no limit). A phase that runs over
+	// fails with a *PhaseTimeoutError naming it.
+	DownloadTimeout  time.Duration
+	ConfigureTimeout time.Duration
+	CompileTimeout   time.Duration
+	PackageTimeout   time.Duration
+
+	// ParallelArch builds x86_64 and aarch64 at the same time when Arch is
+	// "all", each in its own build/<arch> directory, instead of one after
+	// the other. Output lines are prefixed with the architecture, and the
+	// callbacks and Progress are called from both builds concurrently. A
+	// failed architecture doesn't stop the other.
+	ParallelArch bool
+
+	// ForceRebuild builds from source even when the artifacts directory
+	// already holds a kernel for the version and arch
+	ForceRebuild bool
+
+	// ResumeFrom skips the phases before it, reusing what an earlier build of
+	// the same version left in the build directory (the zero value,
+	// PhaseDownload, builds from the start). The build fails if an input of
+	// the resumed phase is missing. Sources are kept even with verification
+	// enabled, and an existing kernel artifact is packaged again.
+	ResumeFrom BuildPhase
+
+	// Timeout limits the whole build (0 = no limit). Running commands are
+	// killed at the deadline and the build fails with a *BuildTimeoutError
+	// naming the phase it was in. With Arch "all" it covers both builds; in
+	// watch mode it limits each rebuild.
+	Timeout time.Duration
 }
 
+// BuildStatsSchemaVersion is the build-stats file format written by this
+// version of anvil. Bump it when BuildStats changes incompatibly.
+const BuildStatsSchemaVersion = 1
+
 // BuildStats contains statistics about a completed build
 type BuildStats struct {
+	SchemaVersion     int // 0 for files written before versioning was added
 	TotalDuration     time.Duration
 	DownloadDuration  time.Duration
 	ExtractDuration   time.Duration
@@ -59,9 +165,15 @@ type BuildStats struct {
 	UncompressedHash  string
 	CompressedHash    string
 	KernelVersion     string
+	Arch              string // x86_64 or aarch64 ("" in older files)
 	OutputPath        string
 	CompressedPath    string
 	BuildTimestamp    time.Time // Timestamp when build completed
+	VerificationLevel string    // Source verification used: high, medium or disabled ("" in older files)
+	ModulesPath       string    // modules-<version>-<arch>.tar.xz, empty when modules weren't built
+	ModulesSize       int64
+	ModulesHash       string   // SHA256 of the modules tarball
+	Patches           []string // "<sha256>  <path>" of each applied patch, in order
 }
 
 // Kernel.org autosigner key (signs sha256sums.asc)
@@ -77,25 +189,34 @@ type kernelOrgRelease struct {
 	} `json:"latest_stable"`
 }
 
-// buildLogger wraps a writer to emit structured log messages for TUI
+// buildLogger wraps a writer to emit structured log messages for TUI, and
+// forwards them to reporter when one is set
 type buildLogger struct {
-	writer io.Writer
+	writer   io.Writer
+	reporter util.ProgressReporter
+}
+
+func (bl *buildLogger) log(level, prefix, msg string) {
+	bl.writer.Write([]byte(fmt.Sprintf("[%s] %s\n", prefix, msg)))
+	if bl.reporter != nil {
+		bl.reporter.Log(level, msg)
+	}
 }
 
 func (bl *buildLogger) Info(msg string) {
-	bl.writer.Write([]byte(fmt.Sprintf("[INFO] %s\n", msg)))
+	bl.log(util.LevelInfo, "INFO", msg)
 }
 
 func (bl *buildLogger) Warn(msg string) {
-	bl.writer.Write([]byte(fmt.Sprintf("[WARN] %s\n", msg)))
+	bl.log(util.LevelWarn, "WARN", msg)
 }
 
 func (bl *buildLogger) Error(msg string) {
-	bl.writer.Write([]byte(fmt.Sprintf("[ERROR] %s\n", msg)))
+	bl.log(util.LevelError, "ERROR", msg)
 }
 
 func (bl *buildLogger) Debug(msg string) {
-	bl.writer.Write([]byte(fmt.Sprintf("[DEBUG] %s\n", msg)))
+	bl.log(util.LevelDebug, "DEBUG", msg)
 }
 
 // Build builds a kernel from source
@@ -126,27 +247,91 @@ func Build(opts BuildOptions, paths *config.Paths) error {
 		return fmt.Errorf("invalid verification level: %s (must be: high, medium, disabled)", opts.VerificationLevel)
 	}
 
+	// Validate the phase to resume from
+	if opts.ResumeFrom < PhaseDownload || opts.ResumeFrom > PhasePackage {
+		return fmt.Errorf("invalid phase to resume from: %s", opts.ResumeFrom)
+	}
+
+	// Validate the source mirror
+	if opts.Mirror == "" {
+		opts.Mirror = config.GetKernelsMirror()
+	}
+	if opts.Mirror == "" {
+		opts.Mirror = DefaultSourceMirror
+	}
+	mirror, err := normalizeSourceMirror(opts.Mirror)
+	if err != nil {
+		return err
+	}
+	opts.Mirror = mirror
+
+	// Check local source files before doing any work
+	if err := resolveGitSource(&opts); err != nil {
+		return err
+	}
+	if err := resolveLocalSource(&opts); err != nil {
+		return err
+	}
+	if err := resolvePatches(&opts); err != nil {
+		return err
+	}
+
+	// Validate compression format
+	if err := resolveCompression(&opts); err != nil {
+		return err
+	}
+
+	// Validate toolchain
+	if err := resolveToolchain(&opts); err != nil {
+		return err
+	}
+
+	// Validate checksum algorithms
+	for _, algo := range opts.ChecksumAlgorithms {
+		if err := util.ValidateChecksumAlgorithm(algo); err != nil {
+			return err
+		}
+	}
+
 	// Determine output writer (custom writer for TUI, or stdout for CLI)
 	writer := opts.Writer
 	if writer == nil {
 		writer = os.Stdout
 	}
 
+	if err := checkRunningAsRoot(&buildLogger{writer: writer, reporter: opts.Progress}, opts.AllowRoot, paths.KernelBuildDir); err != nil {
+		return err
+	}
+
 	// Use context or background
 	ctx := opts.Context
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
+	// Bound the whole build; cancelling buildCtx kills running commands
+	buildCtx := ctx
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		buildCtx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
 	// Handle "all" architecture - build for both x86_64 and aarch64
 	if opts.Arch == "all" {
 		architectures := []string{"x86_64", "aarch64"}
+		if opts.ParallelArch {
+			return buildArchitecturesParallel(opts, paths, architectures, writer, ctx, buildCtx)
+		}
 		for _, arch := range architectures {
 			archOpts := opts
 			archOpts.Arch = arch
 
-			logger := &buildLogger{writer: writer}
-			if err := runBuild(archOpts, paths, logger, opts.ProgressCallback, opts.PhaseCallback, ctx); err != nil {
+			var currentPhase string
+			progressCallback, phaseCallback := archOpts.progressCallbacks()
+			logger := &buildLogger{writer: writer, reporter: opts.Progress}
+			if err := runBuild(archOpts, paths, logger, progressCallback, trackPhase(phaseCallback, &currentPhase), buildCtx); err != nil {
+				err = buildTimeoutError(ctx, buildCtx, opts.Timeout, currentPhase, err)
 				return fmt.Errorf("failed to build for %s: %w", arch, err)
 			}
 		}
@@ -154,20 +339,109 @@ func Build(opts BuildOptions, paths *config.Paths) error {
 	}
 
 	// Single architecture build
-	logger := &buildLogger{writer: writer}
-	if err := runBuild(opts, paths, logger, opts.ProgressCallback, opts.PhaseCallback, ctx); err != nil {
-		return err
+	var currentPhase string
+	progressCallback, phaseCallback := opts.progressCallbacks()
+	logger := &buildLogger{writer: writer, reporter: opts.Progress}
+	if err := runBuild(opts, paths, logger, progressCallback, trackPhase(phaseCallback, &currentPhase), buildCtx); err != nil {
+		return buildTimeoutError(ctx, buildCtx, opts.Timeout, currentPhase, err)
 	}
 
 	return nil
 }
 
+// buildArchitecturesParallel runs one build per architecture at the same
+// time, with each output line prefixed with the arch. The builds use their
+// own build/<version>-<arch> directories, so the source trees and their
+// .config don't collide. Every build runs to completion; the failures are
+// joined.
+func buildArchitecturesParallel(opts BuildOptions, paths *config.Paths, architectures []string, writer io.Writer, ctx, buildCtx context.Context) error {
+	var (
+		wg      sync.WaitGroup
+		writeMu sync.Mutex // serialises prefixed output lines
+		errs    = make([]error, len(architectures))
+	)
+	for i, arch := range architectures {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			archOpts := opts
+			archOpts.Arch = arch
+
+			pw := &prefixWriter{w: writer, mu: &writeMu, prefix: fmt.Sprintf("[%s] ", arch)}
+			defer pw.Flush()
+
+			var currentPhase string
+			progressCallback, phaseCallback := archOpts.progressCallbacks()
+			logger := &buildLogger{writer: pw, reporter: opts.Progress}
+			if err := runBuild(archOpts, paths, logger, progressCallback, trackPhase(phaseCallback, &currentPhase), buildCtx); err != nil {
+				err = buildTimeoutError(ctx, buildCtx, opts.Timeout, currentPhase, err)
+				errs[i] = fmt.Errorf("failed to build for %s: %w", arch, err)
+				logger.Error(errs[i].Error())
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// trackPhase wraps phaseCallback to record the current phase, so a timeout
+// can say where the build stalled
+func trackPhase(phaseCallback func(BuildPhase), currentPhase *string) func(BuildPhase) {
+	return func(p BuildPhase) {
+		*currentPhase = p.String()
+		if phaseCallback != nil {
+			phaseCallback(p)
+		}
+	}
+}
+
+// progressCallbacks returns the progress and phase callbacks to
+// report through: ProgressCallback and PhaseCallback, plus Progress,
+// ArchProgressCallback and ArchPhaseCallback (tagged with opts.Arch) when set
+func (opts BuildOptions) progressCallbacks() (func(float64), func(BuildPhase)) {
+	if opts.Progress == nil && opts.ArchPhaseCallback == nil && opts.ArchProgressCallback == nil {
+		return opts.ProgressCallback, opts.PhaseCallback
+	}
+	progress := opts.ProgressCallback
+	if opts.Progress != nil || opts.ArchProgressCallback != nil {
+		var report func(float64)
+		if opts.Progress != nil {
+			report = util.ReportFraction(opts.Progress)
+		}
+		progress = func(fraction float64) {
+			if opts.ProgressCallback != nil {
+				opts.ProgressCallback(fraction)
+			}
+			if opts.ArchProgressCallback != nil {
+				opts.ArchProgressCallback(opts.Arch, fraction)
+			}
+			if report != nil {
+				report(fraction)
+			}
+		}
+	}
+	phase := func(p BuildPhase) {
+		if opts.PhaseCallback != nil {
+			opts.PhaseCallback(p)
+		}
+		if opts.ArchPhaseCallback != nil {
+			opts.ArchPhaseCallback(opts.Arch, p)
+		}
+		if opts.Progress != nil {
+			opts.Progress.SetPhase(p.String())
+		}
+	}
+	return progress, phase
+}
+
 // runBuild executes the actual build process
 func runBuild(opts BuildOptions, paths *config.Paths, logger *buildLogger, progressCallback func(float64), phaseCallback func(BuildPhase), ctx context.Context) error {
 	// Track build timing
 	buildStartTime := time.Now()
-	var downloadStart, extractStart, configureStart, compileStart, packageStart time.Time
-	var downloadDuration, extractDuration, configureDuration, compileDuration, packageDuration time.Duration
+	var downloadStart, extractStart, configureStart, compileStart time.Time
+	var downloadDuration, extractDuration, configureDuration, compileDuration time.Duration
 
 	// Check context at start
 	if ctx != nil {
@@ -177,79 +451,101 @@ func runBuild(opts BuildOptions, paths *config.Paths, logger *buildLogger, progr
 		default:
 		}
 	}
-	buildDir := filepath.Join(paths.KernelBuildDir, "build")
 	artifactsDir := filepath.Join(paths.KernelBuildDir, "artifacts")
 
 	// Create directories
-	if err := os.MkdirAll(buildDir, 0755); err != nil {
-		return fmt.Errorf("failed to create build directory: %w", err)
-	}
 	if err := os.MkdirAll(artifactsDir, 0755); err != nil {
 		return fmt.Errorf("failed to create artifacts directory: %w", err)
 	}
 
+	// Fail before a long compile rather than while packaging
+	if err := checkFreeSpace(logger, paths.KernelBuildDir, config.GetKernelsMinFreeSpaceGB()); err != nil {
+		return err
+	}
+
 	// Determine kernel version
 	version := opts.Version
-	if version == "" {
+	var gitClone string // Staging clone of a git source whose ref names no version
+	if version == "" && opts.GitSource != nil {
+		// Only the Makefile of a branch tells its version, so clone first
+		if err := checkBuildTools(opts.Arch, opts.Toolchain, true); err != nil {
+			return err
+		}
+		if phaseCallback != nil {
+			phaseCallback(PhaseDownload)
+		}
+		downloadStart = time.Now()
+		gitClone = gitStagingDir(paths, opts.Arch)
+		if err := cloneGitSource(logger, opts, gitClone, ctx); err != nil {
+			return err
+		}
+		// Gone once moved into the build directory
+		defer os.RemoveAll(gitClone)
+		downloadDuration = time.Since(downloadStart)
+		var err error
+		version, err = makefileRelease(gitClone)
+		if err != nil {
+			return fmt.Errorf("failed to read the kernel version of %s: %w", opts.GitSource.Ref, err)
+		}
+		logger.Info(fmt.Sprintf("Kernel version from the Makefile: %s", version))
+	} else if version == "" {
 		logger.Info("Fetching latest stable kernel version from kernel.org...")
 		var err error
-		version, err = GetLatestKernelVersion()
+		version, err = GetLatestKernelVersion(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to fetch latest kernel version: %w", err)
 		}
 		logger.Info(fmt.Sprintf("Latest stable kernel version: %s", version))
+	} else if IsVersionAlias(version) {
+		resolved, err := ResolveSourceVersion(ctx, version)
+		if err != nil {
+			return err
+		}
+		logger.Info(fmt.Sprintf("Resolved kernel %s to %s", version, resolved))
+		version = resolved
 	} else {
 		logger.Info(fmt.Sprintf("Using provided kernel version: %s", version))
 	}
 
 	// Determine output paths
-	var kernelFilename, kernelImage string
-	if opts.Arch == "x86_64" {
-		kernelFilename = fmt.Sprintf("vmlinux-%s-%s", version, opts.Arch)
-		kernelImage = "vmlinux"
-	} else {
-		kernelFilename = fmt.Sprintf("Image-%s-%s", version, opts.Arch)
-		kernelImage = "arch/arm64/boot/Image"
-	}
+	kernelFilename, kernelImage := kernelArtifactNames(version, opts.Arch)
 	kernelPath := filepath.Join(artifactsDir, kernelFilename)
 
-	// Check if kernel already exists
-	if _, err := os.Stat(kernelPath); err == nil {
-		logger.Info(fmt.Sprintf("Kernel already exists: %s", kernelPath))
-
-		// Load build stats from cached build and send to callback
-		statsFile := filepath.Join(artifactsDir, BuildStatsFile(opts.Arch))
-		if stats, err := ReadBuildStats(statsFile); err == nil {
-			if opts.StatsCallback != nil {
-				opts.StatsCallback(stats)
-			}
-		} else {
-			logger.Warn(fmt.Sprintf("Failed to load cached build stats: %v", err))
+	// Check if kernel already exists in the requested compression format. A
+	// resumed build packages it again, since an interrupted package phase can
+	// leave a partial kernel behind, and a kernel built with other patches or
+	// compressed in the other format is rebuilt (packaging then replaces the
+	// other format's kernel), as is any kernel when ForceRebuild is set.
+	resuming := opts.ResumeFrom > PhaseDownload
+	reuse := !resuming && !opts.ForceRebuild
+	samePatches := cachedBuildMatchesPatches(version, opts.Arch, opts.Patches, paths)
+	compressedPath := kernelPath + util.CompressionExt(opts.Compression)
+	if _, err := os.Stat(compressedPath); err == nil && reuse {
+		if samePatches {
+			logger.Info(fmt.Sprintf("Kernel already exists: %s", compressedPath))
+			reportCachedStats(logger, opts, version, paths)
+			return nil
 		}
-
-		return nil
+		logger.Info(fmt.Sprintf("Kernel %s was built with different patches, rebuilding", compressedPath))
+	} else if otherPath := existingCompressedKernel(kernelPath); otherPath != "" && reuse {
+		logger.Info(fmt.Sprintf("Kernel %s is compressed with another format, rebuilding with %s", otherPath, opts.Compression))
 	}
-	if _, err := os.Stat(kernelPath + ".xz"); err == nil {
-		logger.Info(fmt.Sprintf("Compressed kernel already exists: %s.xz", kernelPath))
-
-		// Load build stats from cached build and send to callback
-		statsFile := filepath.Join(artifactsDir, BuildStatsFile(opts.Arch))
-		if stats, err := ReadBuildStats(statsFile); err == nil {
-			if opts.StatsCallback != nil {
-				opts.StatsCallback(stats)
-			}
-		} else {
-			logger.Warn(fmt.Sprintf("Failed to load cached build stats: %v", err))
-		}
 
-		return nil
+	// Each version and arch keeps its own sources, so other versions' trees
+	// are left in place for later builds
+	buildDir := versionBuildDir(paths, version, opts.Arch)
+	if err := os.MkdirAll(buildDir, 0755); err != nil {
+		return fmt.Errorf("failed to create build directory: %w", err)
 	}
 
 	logger.Info(fmt.Sprintf("Building kernel from source for architecture: %s", opts.Arch))
 
 	// Check for required build tools
 	logger.Info("Checking for required build tools...")
-	if err := checkBuildTools(opts.Arch); err != nil {
+	if err := checkBuildTools(opts.Arch, opts.Toolchain, opts.GitSource != nil); err != nil {
+		return err
+	}
+	if err := probeCcache(logger, opts.UseCcache); err != nil {
 		return err
 	}
 
@@ -257,12 +553,29 @@ func runBuild(opts BuildOptions, paths *config.Paths, logger *buildLogger, progr
 	majorVersion := strings.Split(version, ".")[0]
 
 	// Download and verify kernel source
-	kernelURL := fmt.Sprintf("https://cdn.kernel.org/pub/linux/kernel/v%s.x/linux-%s.tar.xz", majorVersion, version)
+	kernelURL := sourceTarballURL(opts.Mirror, majorVersion, version)
+	if opts.SourceTarball == "" && opts.GitSource == nil && opts.Mirror != DefaultSourceMirror {
+		logger.Info(fmt.Sprintf("Using kernel mirror %s", opts.Mirror))
+	}
 	kernelTarball := filepath.Join(buildDir, fmt.Sprintf("linux-%s.tar.xz", version))
 	kernelSrcDir := filepath.Join(buildDir, fmt.Sprintf("linux-%s", version))
 
-	// Delete cached source when verification is enabled (security: always use fresh sources)
-	if opts.VerificationLevel != "disabled" {
+	// A resumed build needs what the skipped phases would have produced
+	modulesDir := ""
+	if opts.BuildModules {
+		modulesDir = modulesStagingDir(kernelSrcDir, opts.Arch)
+	}
+	if err := checkResumeInputs(opts.ResumeFrom, kernelTarball, kernelSrcDir, kernelImage, modulesDir); err != nil {
+		return err
+	}
+	if resuming {
+		logger.Info(fmt.Sprintf("Resuming build from the %s phase", opts.ResumeFrom))
+	}
+
+	// Delete cached source when verification is enabled (security: always
+	// use fresh sources). A resumed build reuses the sources the earlier
+	// build verified.
+	if opts.VerificationLevel != "disabled" && !resuming {
 		if _, err := os.Stat(kernelTarball); err == nil {
 			logger.Info("Deleting cached source (verification enabled - using fresh sources)")
 			os.Remove(kernelTarball)
@@ -272,106 +585,322 @@ func runBuild(opts BuildOptions, paths *config.Paths, logger *buildLogger, progr
 		}
 	}
 
-	// Download kernel source if not already present
-	if _, err := os.Stat(kernelTarball); os.IsNotExist(err) {
+	// Patches must go onto a pristine tree
+	if _, err := os.Stat(kernelSrcDir); err == nil && !sourceTreeMatchesPatches(kernelSrcDir, opts.Patches) {
+		if opts.ResumeFrom > PhaseExtract {
+			return fmt.Errorf("cannot resume from %s: the source tree was patched differently; resume from %s or earlier", opts.ResumeFrom, PhaseExtract)
+		}
+		logger.Info("Source tree was patched differently, re-extracting")
+		if err := os.RemoveAll(kernelSrcDir); err != nil {
+			return fmt.Errorf("failed to remove patched source tree: %w", err)
+		}
+	}
+
+	// Offline build: use the local tarball instead of downloading
+	if opts.SourceTarball != "" && !resuming {
+		logger.Info(fmt.Sprintf("Using local source tarball: %s", opts.SourceTarball))
+		if err := linkOrCopyFile(opts.SourceTarball, kernelTarball); err != nil {
+			return fmt.Errorf("failed to copy source tarball: %w", err)
+		}
+	}
+
+	// Reuse a kept source tarball instead of downloading, if one matches
+	if opts.KeepTarball && !resuming {
+		if _, err := os.Stat(kernelTarball); os.IsNotExist(err) {
+			if cached := findCachedTarball(logger, paths, version); cached != "" {
+				logger.Info(fmt.Sprintf("Reusing kept source tarball: %s", cached))
+				if err := linkOrCopyFile(cached, kernelTarball); err != nil {
+					return fmt.Errorf("failed to reuse cached source tarball: %w", err)
+				}
+			}
+		}
+	}
+
+	// Clone a git source, or download the kernel source if not already present
+	if opts.GitSource != nil {
+		if reason := phaseSkipReason(PhaseDownload, opts.ResumeFrom, ""); reason != "" {
+			logger.Info(fmt.Sprintf("Skipping download: %s", reason))
+		} else {
+			if gitClone == "" {
+				if phaseCallback != nil {
+					phaseCallback(PhaseDownload)
+				}
+				downloadStart = time.Now()
+				if err := cloneGitSource(logger, opts, kernelSrcDir, ctx); err != nil {
+					return err
+				}
+				downloadDuration = time.Since(downloadStart)
+			} else {
+				if err := os.RemoveAll(kernelSrcDir); err != nil {
+					return fmt.Errorf("failed to remove previous source tree: %w", err)
+				}
+				if err := os.Rename(gitClone, kernelSrcDir); err != nil {
+					return fmt.Errorf("failed to move cloned source: %w", err)
+				}
+			}
+			logger.Info("Kernel source cloned successfully")
+
+			if err := applyPatches(logger, opts.Patches, kernelSrcDir, ctx); err != nil {
+				os.RemoveAll(kernelSrcDir)
+				return err
+			}
+		}
+	} else if reason := phaseSkipReason(PhaseDownload, opts.ResumeFrom, kernelTarball); reason == "" {
 		if phaseCallback != nil {
 			phaseCallback(PhaseDownload)
 		}
 		downloadStart = time.Now()
-		logger.Info(fmt.Sprintf("Downloading kernel source from %s...", kernelURL))
-		if err := download.File(kernelURL, kernelTarball, progressCallback); err != nil {
-			return fmt.Errorf("failed to download kernel source: %w", err)
+		// A .part file left by an interrupted single-stream download is
+		// resumed rather than fetched again in parallel
+		parallel := config.GetKernelsDownloadParallel()
+		_, statErr := os.Stat(kernelTarball + ".part")
+		resuming := statErr == nil
+		switch {
+		case resuming:
+			logger.Info(fmt.Sprintf("Resuming kernel source download from %s...", kernelURL))
+		case parallel > 1:
+			logger.Info(fmt.Sprintf("Downloading kernel source from %s (%d parallel requests)...", kernelURL, parallel))
+		default:
+			logger.Info(fmt.Sprintf("Downloading kernel source from %s...", kernelURL))
+		}
+		if err := withPhaseTimeout(ctx, PhaseDownload, opts.DownloadTimeout, func(ctx context.Context) error {
+			downloadOpts := &download.Options{
+				ProgressCallback: progressCallback,
+				Context:          ctx,
+				Retries:          config.GetKernelsDownloadRetries(),
+			}
+			var err error
+			if parallel > 1 && !resuming {
+				err = download.FileParallelWithOptions(kernelURL, kernelTarball, parallel, downloadOpts)
+			} else {
+				// An interrupted download leaves a .part file that the next build resumes
+				err = download.FileResumableWithOptions(kernelURL, kernelTarball, downloadOpts)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to download kernel source: %w", err)
+			}
+			return nil
+		}); err != nil {
+			os.Remove(kernelTarball)
+			return err
 		}
 		downloadDuration = time.Since(downloadStart)
 		logger.Info("Kernel source downloaded successfully")
 	} else {
-		logger.Info("Kernel source already downloaded")
+		logger.Info(fmt.Sprintf("Skipping download: %s", reason))
 	}
 
 	// Verify kernel source
-	if phaseCallback != nil {
-		phaseCallback(PhaseVerify)
-	}
-	if err := verifyKernelSource(logger, opts.VerificationLevel, majorVersion, version, kernelTarball, buildDir); err != nil {
-		return err
+	if opts.GitSource != nil {
+		logger.Warn(fmt.Sprintf("Skipping verify: sources cloned from %s are not checked against kernel.org checksums or signatures", opts.GitSource.URL))
+	} else if reason := phaseSkipReason(PhaseVerify, opts.ResumeFrom, ""); reason == "" {
+		if phaseCallback != nil {
+			phaseCallback(PhaseVerify)
+		}
+		if err := verifyKernelSource(logger, opts.VerificationLevel, opts.Mirror, majorVersion, version, kernelTarball, buildDir, opts.ChecksumsFile); err != nil {
+			return err
+		}
+
+		// Only tarballs that passed verification are kept for reuse
+		if opts.KeepTarball && opts.VerificationLevel != "disabled" {
+			if err := cacheVerifiedTarball(logger, paths, version, kernelTarball); err != nil {
+				logger.Warn(err.Error())
+			}
+		}
+	} else {
+		logger.Info(fmt.Sprintf("Skipping verify: %s", reason))
 	}
 
 	// Extract kernel source
-	if _, err := os.Stat(kernelSrcDir); os.IsNotExist(err) {
+	if opts.GitSource != nil {
+		logger.Info("Skipping extract: source cloned from git")
+	} else if reason := phaseSkipReason(PhaseExtract, opts.ResumeFrom, kernelSrcDir); reason == "" {
 		if phaseCallback != nil {
 			phaseCallback(PhaseExtract)
 		}
 		extractStart = time.Now()
+		// A resumed extract replaces whatever the interrupted one left
+		if err := os.RemoveAll(kernelSrcDir); err != nil {
+			return fmt.Errorf("failed to remove partial source tree: %w", err)
+		}
 		logger.Info("Extracting kernel source...")
 		if err := util.ExtractTarXzWithProgress(kernelTarball, buildDir, progressCallback); err != nil {
 			return fmt.Errorf("failed to extract kernel source: %w", err)
 		}
 		extractDuration = time.Since(extractStart)
 		logger.Info("Kernel source extracted successfully")
+
+		if err := applyPatches(logger, opts.Patches, kernelSrcDir, ctx); err != nil {
+			// Don't leave a partly patched tree for the next build to reuse
+			os.RemoveAll(kernelSrcDir)
+			return err
+		}
 	} else {
-		logger.Info("Kernel source already extracted, skipping...")
+		logger.Info(fmt.Sprintf("Skipping extract: %s", reason))
 	}
 
-	// Apply kernel configuration
-	if phaseCallback != nil {
-		phaseCallback(PhaseConfigure)
+	// Catch a truncated or mismatched tree here rather than halfway through make
+	if err := checkSourceTree(kernelSrcDir, version); err != nil {
+		if removeErr := os.RemoveAll(kernelSrcDir); removeErr != nil {
+			logger.Warn(fmt.Sprintf("Failed to remove source tree: %v", removeErr))
+		}
+		return fmt.Errorf("extracted kernel source in %s looks corrupt (%w); the tree was removed, build again to re-extract it", kernelSrcDir, err)
 	}
-	configureStart = time.Now()
-	if err := applyKernelConfig(logger, opts, kernelSrcDir, ctx); err != nil {
-		return err
+
+	// Apply kernel configuration
+	if reason := phaseSkipReason(PhaseConfigure, opts.ResumeFrom, ""); reason == "" {
+		if phaseCallback != nil {
+			phaseCallback(PhaseConfigure)
+		}
+		configureStart = time.Now()
+		if err := withPhaseTimeout(ctx, PhaseConfigure, opts.ConfigureTimeout, func(ctx context.Context) error {
+			return applyKernelConfig(logger, opts, kernelSrcDir, ctx)
+		}); err != nil {
+			return err
+		}
+		configureDuration = time.Since(configureStart)
+	} else {
+		logger.Info(fmt.Sprintf("Skipping configure: %s", reason))
 	}
-	configureDuration = time.Since(configureStart)
 
 	// Build the kernel
-	if phaseCallback != nil {
-		phaseCallback(PhaseCompile)
-	}
-	compileStart = time.Now()
-	if err := buildKernelImage(logger, opts, kernelSrcDir, kernelImage, ctx); err != nil {
-		return err
+	if reason := phaseSkipReason(PhaseCompile, opts.ResumeFrom, ""); reason == "" {
+		if phaseCallback != nil {
+			phaseCallback(PhaseCompile)
+		}
+		compileStart = time.Now()
+		if err := withPhaseTimeout(ctx, PhaseCompile, opts.CompileTimeout, func(ctx context.Context) error {
+			if err := compileKernel(logger, opts, kernelSrcDir, kernelImage, ctx); err != nil {
+				return err
+			}
+			if opts.BuildModules {
+				return buildModules(logger, opts, kernelSrcDir, ctx)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		compileDuration = time.Since(compileStart)
+	} else {
+		logger.Info(fmt.Sprintf("Skipping compile: %s", reason))
 	}
-	compileDuration = time.Since(compileStart)
 
 	// Package artifacts
 	if phaseCallback != nil {
 		phaseCallback(PhasePackage)
 	}
-	packageStart = time.Now()
-	if err := packageArtifacts(logger, opts, version, kernelSrcDir, kernelImage, artifactsDir, kernelFilename, ctx); err != nil {
+	stats, err := packageBuild(logger, opts, version, kernelSrcDir, kernelImage, artifactsDir, kernelFilename, ctx, func(packageDuration time.Duration) BuildStats {
+		return collectBuildStats(
+			version,
+			kernelPath,
+			kernelPath+util.CompressionExt(opts.Compression),
+			time.Since(buildStartTime),
+			downloadDuration,
+			extractDuration,
+			configureDuration,
+			compileDuration,
+			packageDuration,
+		)
+	})
+	if err != nil {
 		return err
 	}
-	packageDuration = time.Since(packageStart)
 
 	logger.Info("Build completed successfully!")
 
-	// Collect build stats
-	stats := collectBuildStats(
-		version,
-		kernelPath,
-		time.Since(buildStartTime),
-		downloadDuration,
-		extractDuration,
-		configureDuration,
-		compileDuration,
-		packageDuration,
-	)
+	// Call stats callback if provided
+	if opts.StatsCallback != nil {
+		opts.StatsCallback(stats)
+	}
 
-	// Write build stats to per-arch JSON file in artifacts directory
-	statsFile := filepath.Join(artifactsDir, BuildStatsFile(opts.Arch))
+	return nil
+}
+
+// artifactsMu serialises packaging: builds running in parallel (both
+// architectures, or a parallel batch) share the artifacts directory's sums
+// files and its build-stats.json and manifest.json links
+var artifactsMu sync.Mutex
+
+// packageBuild packages the built kernel into artifactsDir and writes the
+// build's stats and manifest there, one build at a time. collectStats is
+// given the packaging duration and returns the build's stats.
+func packageBuild(logger *buildLogger, opts BuildOptions, version, kernelSrcDir, kernelImage, artifactsDir, kernelFilename string, ctx context.Context, collectStats func(time.Duration) BuildStats) (BuildStats, error) {
+	artifactsMu.Lock()
+	defer artifactsMu.Unlock()
+
+	packageStart := time.Now()
+	if err := withPhaseTimeout(ctx, PhasePackage, opts.PackageTimeout, func(ctx context.Context) error {
+		return packageArtifacts(logger, opts, version, kernelSrcDir, kernelImage, artifactsDir, kernelFilename, ctx)
+	}); err != nil {
+		return BuildStats{}, err
+	}
+
+	stats := collectStats(time.Since(packageStart))
+	stats.Arch = opts.Arch
+	stats.VerificationLevel = opts.VerificationLevel
+	if opts.BuildModules {
+		addModulesStats(&stats, stats.OutputPath, version, opts.Arch)
+	}
+	if patches, err := patchHashes(opts.Patches); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to record applied patches: %v", err))
+	} else {
+		stats.Patches = patches
+	}
+
+	// Write build stats to the version's JSON file in the artifacts
+	// directory, and point build-stats.json at it
+	statsFile := filepath.Join(artifactsDir, BuildStatsFile(version, opts.Arch))
 	if err := writeBuildStats(statsFile, stats); err != nil {
 		logger.Warn(fmt.Sprintf("Failed to write build stats: %v", err))
+	} else if err := linkLatestBuildStats(statsFile); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to link %s: %v", BuildStatsSidecar, err))
+	}
+	if err := WriteManifest(artifactsDir, stats); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to write artifact manifest: %v", err))
 	}
 
-	// Call stats callback if provided
+	return stats, nil
+}
+
+// reportCachedStats loads the stats of the already built version and sends
+// them to the stats callback
+func reportCachedStats(logger *buildLogger, opts BuildOptions, version string, paths *config.Paths) {
+	statsFile := cachedBuildStatsFile(version, opts.Arch, paths)
+	if statsFile == "" {
+		logger.Warn(fmt.Sprintf("Failed to load cached build stats: none found for %s (%s)", version, opts.Arch))
+		return
+	}
+	stats, err := ReadBuildStats(statsFile)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Failed to load cached build stats: %v", err))
+		return
+	}
+	if stats.Arch == "" {
+		stats.Arch = opts.Arch
+	}
 	if opts.StatsCallback != nil {
 		opts.StatsCallback(stats)
 	}
+}
 
-	return nil
+// kernelArtifactNames returns the artifact filename and the in-tree image path
+// for a kernel version and architecture.
+func kernelArtifactNames(version, arch string) (kernelFilename, kernelImage string) {
+	if arch == "x86_64" {
+		return fmt.Sprintf("vmlinux-%s-%s", version, arch), "vmlinux"
+	}
+	return fmt.Sprintf("Image-%s-%s", version, arch), "arch/arm64/boot/Image"
+}
+
+// kernelConfigArtifactName returns the filename of the kernel config saved
+// alongside the built kernel
+func kernelConfigArtifactName(version, arch string) string {
+	return fmt.Sprintf("config-%s-%s", version, arch)
 }
 
 // writeBuildStats writes build statistics to a JSON file
 func writeBuildStats(path string, stats BuildStats) error {
+	stats.SchemaVersion = BuildStatsSchemaVersion
 	data, err := json.MarshalIndent(stats, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal build stats: %w", err)
@@ -384,11 +913,6 @@ func writeBuildStats(path string, stats BuildStats) error {
 	return nil
 }
 
-// BuildStatsFile returns the per-architecture stats filename for a given arch.
-func BuildStatsFile(arch string) string {
-	return fmt.Sprintf("build-stats-%s.json", arch)
-}
-
 // ReadBuildStats reads build statistics from a JSON file
 func ReadBuildStats(path string) (BuildStats, error) {
 	var stats BuildStats
@@ -398,10 +922,16 @@ func ReadBuildStats(path string) (BuildStats, error) {
 		return stats, fmt.Errorf("failed to read build stats file: %w", err)
 	}
 
+	// Unknown fields are ignored and missing ones left zero, so files written
+	// by older or newer versions still load
 	if err := json.Unmarshal(data, &stats); err != nil {
 		return stats, fmt.Errorf("failed to unmarshal build stats: %w", err)
 	}
 
+	if stats.SchemaVersion > BuildStatsSchemaVersion {
+		log.Warnf("Build stats %s use schema version %d (this anvil supports up to %d); some fields may be ignored", path, stats.SchemaVersion, BuildStatsSchemaVersion)
+	}
+
 	return stats, nil
 }
 
@@ -424,56 +954,33 @@ func CheckKernelInstalled(stats BuildStats, paths *config.Paths) (bool, string,
 	return true, versionWithTimestamp, nil
 }
 
-// CheckCachedBuild checks if a completed build exists for the given version and arch.
-// If arch is empty, it defaults to the host architecture.
-func CheckCachedBuild(version, arch string, paths *config.Paths) (bool, string, error) {
-	if arch == "" {
-		var err error
-		arch, err = config.GetArch()
-		if err != nil {
-			return false, "", err
-		}
-	}
-	artifactsDir := filepath.Join(paths.KernelBuildDir, "artifacts")
-	statsFile := filepath.Join(artifactsDir, BuildStatsFile(arch))
-
-	// Check if build-stats.json exists
-	if _, err := os.Stat(statsFile); os.IsNotExist(err) {
-		return false, "", nil
-	}
-
-	// Try to read the stats to verify it's valid
-	stats, err := ReadBuildStats(statsFile)
-	if err != nil {
-		return false, "", err
-	}
-
-	// Check if the version matches (empty version means "any cached build")
-	if version != "" && stats.KernelVersion != version {
-		log.Debugf("Cached build version mismatch: cached=%s, requested=%s", stats.KernelVersion, version)
-		return false, "", nil
-	}
-
-	// Check if output files exist
-	if _, err := os.Stat(stats.OutputPath); os.IsNotExist(err) {
-		log.Debugf("Cached build output missing: %s", stats.OutputPath)
-		return false, "", nil
+// resolveCompression defaults the packaged kernel's compression to xz and
+// rejects unsupported formats
+func resolveCompression(opts *BuildOptions) error {
+	if opts.Compression == "" {
+		opts.Compression = util.CompressionXZ
 	}
+	return util.ValidateCompression(opts.Compression)
+}
 
-	if _, err := os.Stat(stats.CompressedPath); os.IsNotExist(err) {
-		log.Debugf("Cached build compressed output missing: %s", stats.CompressedPath)
-		return false, "", nil
+// existingCompressedKernel returns the compressed kernel next to kernelPath
+// in any supported format, or "" when there is none
+func existingCompressedKernel(kernelPath string) string {
+	for _, format := range util.CompressionFormats {
+		path := kernelPath + util.CompressionExt(format)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
 	}
-
-	return true, statsFile, nil
+	return ""
 }
 
 // collectBuildStats collects statistics about the completed build
-func collectBuildStats(version, kernelPath string, totalDuration, downloadDuration, extractDuration, configureDuration, compileDuration, packageDuration time.Duration) BuildStats {
+func collectBuildStats(version, kernelPath, compressedPath string, totalDuration, downloadDuration, extractDuration, configureDuration, compileDuration, packageDuration time.Duration) BuildStats {
 	stats := BuildStats{
 		KernelVersion:     version,
 		OutputPath:        kernelPath,
-		CompressedPath:    kernelPath + ".xz",
+		CompressedPath:    compressedPath,
 		TotalDuration:     totalDuration,
 		DownloadDuration:  downloadDuration,
 		ExtractDuration:   extractDuration,
@@ -492,18 +999,39 @@ func collectBuildStats(version, kernelPath string, totalDuration, downloadDurati
 	}
 
 	// Get compressed kernel size and hash
-	if info, err := os.Stat(kernelPath + ".xz"); err == nil {
+	if info, err := os.Stat(compressedPath); err == nil {
 		stats.CompressedSize = info.Size()
 	}
-	if hash, err := util.CalculateSHA256(kernelPath + ".xz"); err == nil {
+	if hash, err := util.CalculateSHA256(compressedPath); err == nil {
 		stats.CompressedHash = hash
 	}
 
 	return stats
 }
 
+// InstallOptions configures InstallBuiltKernelWithOptions
+type InstallOptions struct {
+	SetAsDefault     bool
+	Force            bool          // Set as default even if built for another architecture
+	ProgressCallback func(float64) // Optional: copy progress (0.0 to 1.0)
+}
+
 // InstallBuiltKernel installs a built kernel to the kernels directory with a timestamped name
 func InstallBuiltKernel(stats BuildStats, setAsDefault bool, paths *config.Paths) (string, error) {
+	return InstallBuiltKernelWithOptions(stats, paths, InstallOptions{SetAsDefault: setAsDefault})
+}
+
+// InstallBuiltKernelWithProgress installs a built kernel, reporting copy progress (0.0 to 1.0)
+func InstallBuiltKernelWithProgress(stats BuildStats, setAsDefault bool, paths *config.Paths, progressCallback func(float64)) (string, error) {
+	return InstallBuiltKernelWithOptions(stats, paths, InstallOptions{SetAsDefault: setAsDefault, ProgressCallback: progressCallback})
+}
+
+// InstallBuiltKernelWithOptions installs a built kernel. Setting a kernel
+// built for another architecture as default fails with an
+// *ArchMismatchError before anything is copied, unless opts.Force is set.
+func InstallBuiltKernelWithOptions(stats BuildStats, paths *config.Paths, opts InstallOptions) (string, error) {
+	setAsDefault := opts.SetAsDefault
+
 	// Derive arch from build output path (e.g. vmlinux-6.19.6-x86_64 → x86_64)
 	base := filepath.Base(stats.OutputPath)
 	parts := strings.Split(base, "-")
@@ -514,6 +1042,19 @@ func InstallBuiltKernel(stats BuildStats, setAsDefault bool, paths *config.Paths
 		return "", err
 	}
 
+	if setAsDefault {
+		hostArch, err := config.GetArch()
+		if err != nil {
+			return "", err
+		}
+		if mismatch := checkKernelArch(stats.OutputPath, hostArch); mismatch != nil {
+			if !opts.Force {
+				return "", mismatch
+			}
+			log.Warnf("%v", mismatch)
+		}
+	}
+
 	// Create timestamped version name: version-YYYYMMDDTHHmmss
 	// Use the build timestamp from stats to ensure consistency
 	timestamp := stats.BuildTimestamp.Format("20060102T150405")
@@ -527,42 +1068,44 @@ func InstallBuiltKernel(stats BuildStats, setAsDefault bool, paths *config.Paths
 
 	// Destination file names with timestamp
 	destKernel := filepath.Join(destDir, fmt.Sprintf("%s-%s-%s", kernelName, versionWithTimestamp, arch))
-	destKernelXz := destKernel + ".xz"
+	destCompressed := destKernel + filepath.Ext(stats.CompressedPath)
+
+	progress := newCopyProgress(opts.ProgressCallback, stats.OutputPath, stats.CompressedPath)
 
 	// Copy uncompressed kernel
-	if err := copyFile(stats.OutputPath, destKernel); err != nil {
+	if err := linkOrCopyFileWithProgress(stats.OutputPath, destKernel, progress.add); err != nil {
 		return "", fmt.Errorf("failed to copy kernel: %w", err)
 	}
 
 	// Copy compressed kernel
-	if err := copyFile(stats.CompressedPath, destKernelXz); err != nil {
+	if err := linkOrCopyFileWithProgress(stats.CompressedPath, destCompressed, progress.add); err != nil {
 		return "", fmt.Errorf("failed to copy compressed kernel: %w", err)
 	}
 
 	// Copy checksums if they exist
-	if _, err := os.Stat(stats.OutputPath + ".sha256"); err == nil {
-		destChecksum := destKernel + ".sha256"
-		if err := copyFile(stats.OutputPath+".sha256", destChecksum); err != nil {
-			return "", fmt.Errorf("failed to copy checksum: %w", err)
+	for _, algo := range util.ChecksumAlgorithms {
+		for _, c := range [][2]string{{stats.OutputPath, destKernel}, {stats.CompressedPath, destCompressed}} {
+			src := c[0] + "." + algo
+			if _, err := os.Stat(src); err != nil {
+				continue
+			}
+			if err := linkOrCopyFile(src, c[1]+"."+algo); err != nil {
+				return "", fmt.Errorf("failed to copy checksum: %w", err)
+			}
 		}
 	}
 
-	if _, err := os.Stat(stats.CompressedPath + ".sha256"); err == nil {
-		destChecksumXz := destKernelXz + ".sha256"
-		if err := copyFile(stats.CompressedPath+".sha256", destChecksumXz); err != nil {
-			return "", fmt.Errorf("failed to copy compressed checksum: %w", err)
-		}
+	// Keep the build stats with the kernel for anvil kernel stats
+	if err := writeBuildStats(filepath.Join(destDir, BuildStatsSidecar), stats); err != nil {
+		return "", err
 	}
 
 	// Set as default if requested
 	if setAsDefault {
 		symlinkPath := filepath.Join(paths.DataDir, kernelName)
 
-		// Remove existing symlink
-		os.Remove(symlinkPath)
-
-		// Create new symlink
-		if err := os.Symlink(destKernel, symlinkPath); err != nil {
+		// Swap the link in one rename so there is always a default kernel
+		if err := util.ReplaceSymlink(destKernel, symlinkPath); err != nil {
 			return "", fmt.Errorf("failed to create symlink: %w", err)
 		}
 	}
@@ -579,14 +1122,24 @@ func InstallBuiltKernel(stats BuildStats, setAsDefault bool, paths *config.Paths
 //	├── x86_64/
 //	│   └── {version}/
 //	│       ├── vmlinux-{version}-x86_64
-//	│       ├── vmlinux-{version}-x86_64.xz
+//	│       ├── vmlinux-{version}-x86_64.xz    (.zst with zstd compression)
 //	│       ├── vmlinux-{version}-x86_64.sha256
 //	│       ├── vmlinux-{version}-x86_64.xz.sha256
+//	│       ├── config-{version}-x86_64
+//	│       ├── config-{version}-x86_64.sha256
+//	│       ├── build-stats.json
+//	│       ├── manifest.json                  (builds that wrote a manifest)
+//	│       ├── SHA256SUMS
 //	│       └── signing-key.asc
 //	└── index.json  {"x86_64": {"6.18.9": "x86_64/6.18.9/vmlinux-6.18.9-x86_64.xz"}}
 func ArchiveInstalledKernel(stats BuildStats, archiveDir string) error {
+	return ArchiveInstalledKernelWithProgress(stats, archiveDir, nil)
+}
+
+// ArchiveInstalledKernelWithProgress archives a built kernel, reporting copy progress (0.0 to 1.0)
+func ArchiveInstalledKernelWithProgress(stats BuildStats, archiveDir string, progressCallback func(float64)) error {
 	// Derive arch from compressed filename: vmlinux-6.18.9-x86_64.xz → x86_64
-	base := strings.TrimSuffix(filepath.Base(stats.CompressedPath), ".xz")
+	base := util.TrimCompressionExt(filepath.Base(stats.CompressedPath))
 	parts := strings.Split(base, "-")
 	arch := parts[len(parts)-1]
 
@@ -598,25 +1151,71 @@ func ArchiveInstalledKernel(stats BuildStats, archiveDir string) error {
 
 	// Copy artifacts into the arch/version directory
 	type srcDst struct{ src, dst string }
-	copies := []srcDst{
-		{stats.OutputPath, filepath.Join(versionDir, filepath.Base(stats.OutputPath))},
-		{stats.CompressedPath, filepath.Join(versionDir, filepath.Base(stats.CompressedPath))},
-	}
-	for _, extra := range []string{stats.OutputPath + ".sha256", stats.CompressedPath + ".sha256"} {
-		if _, err := os.Stat(extra); err == nil {
-			copies = append(copies, srcDst{extra, filepath.Join(versionDir, filepath.Base(extra))})
+	var copies []srcDst
+	artifactsDir := filepath.Dir(stats.OutputPath)
+	manifest, hasManifest := buildManifest(stats, arch)
+	if hasManifest {
+		// The build's manifest lists every file it produced
+		for _, file := range manifest.Files {
+			copies = append(copies, srcDst{filepath.Join(artifactsDir, file.Name), filepath.Join(versionDir, file.Name)})
+		}
+	} else {
+		// Builds from before manifests were written: derive the names
+		copies = []srcDst{
+			{stats.OutputPath, filepath.Join(versionDir, filepath.Base(stats.OutputPath))},
+			{stats.CompressedPath, filepath.Join(versionDir, filepath.Base(stats.CompressedPath))},
+		}
+		// The kernel config and its checksums go along so SHA256SUMS (and its
+		// signature) cover the config that produced the kernel
+		configPath := filepath.Join(artifactsDir, kernelConfigArtifactName(stats.KernelVersion, arch))
+		extras := []string{configPath}
+		for _, algo := range util.ChecksumAlgorithms {
+			extras = append(extras, stats.OutputPath+"."+algo, stats.CompressedPath+"."+algo, configPath+"."+algo)
+		}
+		if stats.ModulesPath != "" {
+			extras = append(extras, stats.ModulesPath)
+			for _, algo := range util.ChecksumAlgorithms {
+				extras = append(extras, stats.ModulesPath+"."+algo)
+			}
+		}
+		for _, extra := range extras {
+			if _, err := os.Stat(extra); err == nil {
+				copies = append(copies, srcDst{extra, filepath.Join(versionDir, filepath.Base(extra))})
+			}
 		}
 	}
+	srcs := make([]string, len(copies))
+	for i, c := range copies {
+		srcs[i] = c.src
+	}
+	progress := newCopyProgress(progressCallback, srcs...)
 	for _, c := range copies {
-		if err := copyFile(c.src, c.dst); err != nil {
+		if err := linkOrCopyFileWithProgress(c.src, c.dst, progress.add); err != nil {
 			return fmt.Errorf("failed to archive %s: %w", filepath.Base(c.src), err)
 		}
 	}
 
-	// Generate SHA256SUMS by concatenating all individual .sha256 files.
-	// SignArtifacts expects this file when signing the directory.
-	if err := generateSHA256SUMS(versionDir); err != nil {
-		return fmt.Errorf("failed to generate SHA256SUMS: %w", err)
+	if err := writeBuildStats(filepath.Join(versionDir, BuildStatsSidecar), stats); err != nil {
+		return err
+	}
+	if hasManifest {
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal manifest: %w", err)
+		}
+		if err := writeArtifactFile(filepath.Join(versionDir, ManifestSidecar), data, 0644); err != nil {
+			return fmt.Errorf("failed to write manifest: %w", err)
+		}
+	}
+
+	// Generate SHA256SUMS (and SHA512SUMS if .sha512 files were written) by
+	// concatenating the individual checksum files. SignArtifacts signs these.
+	// Builds running in parallel archive one at a time, since they share
+	// index.json.
+	archiveMu.Lock()
+	defer archiveMu.Unlock()
+	if err := generateChecksumSums(versionDir); err != nil {
+		return fmt.Errorf("failed to generate checksums: %w", err)
 	}
 
 	// Update archive/index.json: path is relative to archiveDir
@@ -624,30 +1223,57 @@ func ArchiveInstalledKernel(stats BuildStats, archiveDir string) error {
 	return updateArchiveIndex(archiveDir, arch, stats.KernelVersion, kernelPath)
 }
 
-// generateSHA256SUMS concatenates all *.sha256 files in dir into a single
-// SHA256SUMS file in the standard sha256sum format, as expected by SignArtifacts.
-func generateSHA256SUMS(dir string) error {
+// archiveMu serialises writes to the archive's sums files and index.json
+var archiveMu sync.Mutex
+
+// generateChecksumSums writes SHA256SUMS for dir, plus a sums file for every
+// other algorithm that has per-file checksums in dir (e.g. SHA512SUMS).
+// SHA256SUMS is always written so existing consumers keep working.
+func generateChecksumSums(dir string) error {
+	for _, algo := range util.ChecksumAlgorithms {
+		written, err := generateSumsFile(dir, algo, algo == util.ChecksumSHA256)
+		if err != nil {
+			return err
+		}
+		if !written && algo != util.ChecksumSHA256 {
+			// Drop a stale sums file left by a build that used this algorithm
+			os.Remove(filepath.Join(dir, util.SumsFileName(algo)))
+		}
+	}
+	return nil
+}
+
+// generateSumsFile concatenates all *.<algo> files in dir into a single sums
+// file (SHA256SUMS, SHA512SUMS) in the standard sha256sum format, as expected
+// by SignArtifacts. Unless always is set, nothing is written when dir has no
+// *.<algo> files. Returns whether the file was written.
+func generateSumsFile(dir, algo string, always bool) (bool, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	var combined []byte
+	found := false
 	for _, e := range entries {
-		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sha256") {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), "."+algo) {
 			continue
 		}
 		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
 		if err != nil {
-			return err
+			return false, err
 		}
+		found = true
 		combined = append(combined, data...)
 		if len(data) > 0 && data[len(data)-1] != '\n' {
 			combined = append(combined, '\n')
 		}
 	}
 
-	return os.WriteFile(filepath.Join(dir, "SHA256SUMS"), combined, 0644)
+	if !found && !always {
+		return false, nil
+	}
+	return true, os.WriteFile(filepath.Join(dir, util.SumsFileName(algo)), combined, 0644)
 }
 
 // updateArchiveIndex reads (or initialises) archive/index.json and records
@@ -769,21 +1395,12 @@ func ArchiveGet(version, arch, archiveDir string) (*ArchiveDetail, error) {
 	}, nil
 }
 
-// GetLatestKernelVersion fetches the latest stable kernel version from kernel.org
-func GetLatestKernelVersion() (string, error) {
-	resp, err := http.Get("https://www.kernel.org/releases.json")
-	if err != nil {
-		return "", fmt.Errorf("failed to fetch kernel.org API: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("kernel.org API returned status: %s", resp.Status)
-	}
-
+// GetLatestKernelVersion fetches the latest stable kernel version from
+// kernel.org. Cancelling ctx aborts the request.
+func GetLatestKernelVersion(ctx context.Context) (string, error) {
 	var release kernelOrgRelease
-	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
-		return "", fmt.Errorf("failed to parse kernel.org API response: %w", err)
+	if err := FetchReleasesJSON(ctx, &release); err != nil {
+		return "", err
 	}
 
 	if release.LatestStable.Version == "" {
@@ -793,25 +1410,26 @@ func GetLatestKernelVersion() (string, error) {
 	return release.LatestStable.Version, nil
 }
 
-// ValidateVersion checks if a kernel version exists in kernel.org releases
-func ValidateVersion(version string) error {
-	// Fetch releases from kernel.org
-	resp, err := http.Get("https://www.kernel.org/releases.json")
-	if err != nil {
-		// If we can't reach kernel.org, allow the version (might be offline build)
+// ValidateVersion checks if a kernel version exists in kernel.org releases.
+// The aliases "latest" and "lts" are always valid. When kernel.org can't be
+// reached the version is allowed (it might be an offline build), unless
+// ctx was cancelled.
+func ValidateVersion(ctx context.Context, version string) error {
+	if IsVersionAlias(version) {
 		return nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		// If API fails, allow the version
-		return nil
-	}
-
-	// Parse the full releases structure to get all available versions
+	// Fetch releases from kernel.org
 	var data map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		// If we can't parse, allow the version
+	if err := FetchReleasesJSON(ctx, &data); err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+		var timeoutErr *APITimeoutError
+		if errors.As(err, &timeoutErr) {
+			log.Warnf("Not checking that kernel %s exists: %v", version, err)
+		}
+		// If the API fails or can't be parsed, allow the version
 		return nil
 	}
 
@@ -847,13 +1465,43 @@ func ValidateVersion(version string) error {
 		strings.Join(availableVersions, "\n  "))
 }
 
+// ErrRunningAsRoot is returned when a build is started as root without
+// BuildOptions.AllowRoot
+var ErrRunningAsRoot = errors.New("refusing to build the kernel as root")
+
+// checkRunningAsRoot refuses to build as root unless allowRoot is set, and
+// warns when it is. Kernel builds never need root, and files a root build
+// writes into the build cache (buildDir) and data directories are
+// root-owned, so the user can't clean them up afterwards.
+func checkRunningAsRoot(logger *buildLogger, allowRoot bool, buildDir string) error {
+	if os.Geteuid() != 0 {
+		return nil
+	}
+	if !allowRoot {
+		return fmt.Errorf("%w: kernel builds don't need root, and the files written to %s would be owned by root so you couldn't clean them later", ErrRunningAsRoot, buildDir)
+	}
+	logger.Warn("Building as root: build cache files will be owned by root")
+	return nil
+}
+
 // checkBuildTools verifies that required build tools are installed
-func checkBuildTools(arch string) error {
+func checkBuildTools(arch, toolchain string, needGit bool) error {
 	// Check make
 	if _, err := exec.LookPath("make"); err != nil {
 		return fmt.Errorf("make not found. Please install build-essential")
 	}
 
+	// Check git for builds from a git source
+	if needGit {
+		if _, err := exec.LookPath("git"); err != nil {
+			return fmt.Errorf("git not found. Please install git to build from a git source")
+		}
+	}
+
+	if toolchain == ToolchainLLVM {
+		return checkLLVMTools(arch)
+	}
+
 	// Check gcc
 	if _, err := exec.LookPath("gcc"); err != nil {
 		return fmt.Errorf("gcc not found. Please install build-essential")
@@ -869,8 +1517,29 @@ func checkBuildTools(arch string) error {
 	return nil
 }
 
-// verifyKernelSource verifies the downloaded kernel source based on verification level
-func verifyKernelSource(logger *buildLogger, verificationLevel, majorVersion, version, kernelTarball, buildDir string) error {
+// checkFreeSpace fails when the filesystem holding dir has less than minGB
+// GiB available. minGB 0 skips the check.
+func checkFreeSpace(logger *buildLogger, dir string, minGB int) error {
+	if minGB <= 0 {
+		return nil
+	}
+
+	free, err := util.FreeSpace(dir)
+	if err != nil {
+		return fmt.Errorf("failed to check free space in %s: %w", dir, err)
+	}
+
+	required := int64(minGB) << 30
+	if free < required {
+		logger.Error(fmt.Sprintf("Not enough free space in %s: %s available, %s required", dir, util.FormatSize(free), util.FormatSize(required)))
+		return fmt.Errorf("not enough free space in %s: %s available, %s required (free up space or lower kernels.min-free-space-gb)", dir, util.FormatSize(free), util.FormatSize(required))
+	}
+	return nil
+}
+
+// verifyKernelSource verifies the downloaded kernel source based on verification level.
+// sha256sums.asc is downloaded from mirror unless localChecksums is set.
+func verifyKernelSource(logger *buildLogger, verificationLevel, mirror, majorVersion, version, kernelTarball, buildDir, localChecksums string) error {
 	if verificationLevel == "disabled" {
 		logger.Warn("Verification disabled - proceeding without any security checks")
 		logger.Warn("  The kernel source tarball has NOT been verified")
@@ -878,15 +1547,20 @@ func verifyKernelSource(logger *buildLogger, verificationLevel, majorVersion, ve
 		return nil
 	}
 
-	// Download checksums file
-	logger.Info("Downloading checksums file for verification...")
-	checksumsURL := fmt.Sprintf("https://cdn.kernel.org/pub/linux/kernel/v%s.x/sha256sums.asc", majorVersion)
-	checksumsFile := filepath.Join(buildDir, "sha256sums.asc")
+	// Download checksums file, unless a local one was given
+	checksumsFile := localChecksums
+	if checksumsFile != "" {
+		logger.Info(fmt.Sprintf("Using local checksums file: %s", checksumsFile))
+	} else {
+		logger.Info("Downloading checksums file for verification...")
+		checksumsURL := sourceChecksumsURL(mirror, majorVersion)
+		checksumsFile = filepath.Join(buildDir, "sha256sums.asc")
 
-	if err := download.File(checksumsURL, checksumsFile, nil); err != nil {
-		return fmt.Errorf("could not download checksums file from kernel.org: %w\nUse --verification-level disabled to proceed anyway (not recommended)", err)
+		if err := download.FileWithOptions(checksumsURL, checksumsFile, &download.Options{Retries: config.GetKernelsDownloadRetries()}); err != nil {
+			return fmt.Errorf("could not download checksums file from %s: %w\nUse --verification-level disabled to proceed anyway (not recommended)", checksumsURL, err)
+		}
+		defer os.Remove(checksumsFile)
 	}
-	defer os.Remove(checksumsFile)
 
 	// PGP verification (only for 'high' level)
 	if verificationLevel == "high" {
@@ -896,11 +1570,9 @@ func verifyKernelSource(logger *buildLogger, verificationLevel, majorVersion, ve
 		if err := importAutosignerKey(logger); err != nil {
 			logger.Warn("Could not import autosigner key, skipping PGP verification")
 		} else {
-			// Verify the signature
-			cmd := exec.Command("gpg", "--verify", checksumsFile)
-			output, err := cmd.CombinedOutput()
-			if err != nil || !strings.Contains(string(output), "Good signature") {
-				return fmt.Errorf("PGP signature verification failed\nThe checksums file may have been tampered with\n%s", string(output))
+			// Verify the signature was made by the autosigner key itself
+			if err := verifySignedBy([]string{autosignerKeyFingerprint}, checksumsFile); err != nil {
+				return fmt.Errorf("PGP signature verification failed\nThe checksums file may have been tampered with\n%w", err)
 			}
 			logger.Info("✓ PGP signature verification passed")
 			logger.Info("  Signed by: Kernel.org checksum autosigner <autosigner@kernel.org>")
@@ -953,56 +1625,6 @@ func verifyKernelSource(logger *buildLogger, verificationLevel, majorVersion, ve
 	return nil
 }
 
-// importAutosignerKey imports the kernel.org autosigner GPG key
-func importAutosignerKey(logger *buildLogger) error {
-	// Check if gpg is available
-	if _, err := exec.LookPath("gpg"); err != nil {
-		return fmt.Errorf("gpg not found")
-	}
-
-	// Check if key is already imported
-	cmd := exec.Command("gpg", "--list-keys", autosignerKeyID)
-	if err := cmd.Run(); err == nil {
-		// Key already imported
-		return nil
-	}
-
-	logger.Info("Importing kernel.org autosigner GPG key...")
-	logger.Info(fmt.Sprintf("  Key ID: %s", autosignerKeyID))
-	logger.Info(fmt.Sprintf("  Fingerprint: %s", autosignerKeyFingerprint))
-
-	// Try multiple keyservers
-	keyservers := []string{
-		"hkps://keyserver.ubuntu.com",
-		"hkps://keys.openpgp.org",
-		"hkps://pgp.mit.edu",
-	}
-
-	for _, keyserver := range keyservers {
-		logger.Info(fmt.Sprintf("  Trying keyserver: %s", keyserver))
-		cmd := exec.Command("gpg", "--keyserver", keyserver, "--recv-keys", autosignerKeyID)
-		if err := cmd.Run(); err == nil {
-			logger.Info("✓ Autosigner key imported successfully")
-
-			// Verify the fingerprint matches
-			cmd = exec.Command("gpg", "--fingerprint", autosignerKeyID)
-			output, err := cmd.CombinedOutput()
-			if err != nil {
-				return fmt.Errorf("failed to verify fingerprint: %w", err)
-			}
-
-			// Simple check: output should contain the fingerprint
-			if !strings.Contains(string(output), autosignerKeyFingerprint) {
-				return fmt.Errorf("fingerprint mismatch - possible key substitution attack")
-			}
-
-			return nil
-		}
-	}
-
-	return fmt.Errorf("failed to import autosigner key from any keyserver")
-}
-
 // applyKernelConfig applies the Firecracker kernel configuration
 func applyKernelConfig(logger *buildLogger, opts BuildOptions, kernelSrcDir string, ctx context.Context) error {
 	logger.Info(fmt.Sprintf("Applying Firecracker kernel configuration for %s...", opts.Arch))
@@ -1017,43 +1639,9 @@ func applyKernelConfig(logger *buildLogger, opts BuildOptions, kernelSrcDir stri
 	}
 
 	// Determine config file
-	configFile := opts.ConfigFile
-	if configFile == "" {
-		// Check if we're in repo mode (anvil.yaml exists)
-		repoConfigPath := filepath.Join(".", config.LocalConfigFile+config.DefaultConfigExt)
-		if _, err := os.Stat(repoConfigPath); err == nil {
-			// Repo mode: get kernel config from repo config
-			if opts.Arch == "x86_64" {
-				configFile = config.GetKernelsConfigX86_64()
-			} else if opts.Arch == "aarch64" {
-				configFile = config.GetKernelsConfigAarch64()
-			}
-
-			if configFile == "" {
-				return fmt.Errorf(
-					"kernel config not found in repo config for %s\n\n"+
-						"Add to anvil.yaml:\n"+
-						"kernels:\n"+
-						"  config:\n"+
-						"    %s: path/to/kernel.config",
-					opts.Arch,
-					opts.Arch,
-				)
-			}
-			logger.Info(fmt.Sprintf("Using kernel config from repo: %s", configFile))
-		} else {
-			// Not in repo mode: require --config flag
-			return fmt.Errorf(
-				"kernel config file required (not in repo mode)\n\n" +
-					"Either:\n" +
-					"  1. Use --config flag: anvil kernel build --config path/to/kernel.config\n" +
-					"  2. Create anvil.yaml in repo root with:\n" +
-					"     kernels:\n" +
-					"       config:\n" +
-					"         x86_64: configs/kernel-x86_64.config\n" +
-					"         aarch64: configs/kernel-aarch64.config",
-			)
-		}
+	configFile, err := resolveKernelConfigFile(logger, opts)
+	if err != nil {
+		return err
 	}
 
 	// Check if config file exists
@@ -1067,25 +1655,80 @@ func applyKernelConfig(logger *buildLogger, opts BuildOptions, kernelSrcDir stri
 		return fmt.Errorf("failed to copy kernel config: %w", err)
 	}
 
-	// Update config for new kernel version
-	logger.Info("Running make olddefconfig to update config...")
+	return runOldDefconfig(logger, opts.Arch, opts.Toolchain, kernelSrcDir, ctx)
+}
 
-	var cmd *exec.Cmd
-	if opts.Arch == "aarch64" {
-		cmd = exec.Command("make", "olddefconfig", "ARCH=arm64")
-	} else {
-		cmd = exec.Command("make", "olddefconfig")
+// resolveKernelConfigFile returns the kernel config file to use for a build:
+// the --config flag if set, otherwise the per-arch path from the repo config.
+func resolveKernelConfigFile(logger *buildLogger, opts BuildOptions) (string, error) {
+	if opts.ConfigFile != "" {
+		return opts.ConfigFile, nil
+	}
+
+	// Check if we're in repo mode (anvil.yaml exists)
+	repoConfigPath := filepath.Join(".", config.LocalConfigFile+config.DefaultConfigExt)
+	if _, err := os.Stat(repoConfigPath); err != nil {
+		// Not in repo mode: require --config flag
+		return "", fmt.Errorf(
+			"kernel config file required (not in repo mode)\n\n" +
+				"Either:\n" +
+				"  1. Use --config flag: anvil kernel build --config path/to/kernel.config\n" +
+				"  2. Create anvil.yaml in repo root with:\n" +
+				"     kernels:\n" +
+				"       config:\n" +
+				"         x86_64: configs/kernel-x86_64.config\n" +
+				"         aarch64: configs/kernel-aarch64.config",
+		)
+	}
+
+	// Repo mode: get kernel config from repo config
+	var configFile string
+	if opts.Arch == "x86_64" {
+		configFile = config.GetKernelsConfigX86_64()
+	} else if opts.Arch == "aarch64" {
+		configFile = config.GetKernelsConfigAarch64()
 	}
+
+	if configFile == "" {
+		return "", fmt.Errorf(
+			"kernel config not found in repo config for %s\n\n"+
+				"Add to anvil.yaml:\n"+
+				"kernels:\n"+
+				"  config:\n"+
+				"    %s: path/to/kernel.config",
+			opts.Arch,
+			opts.Arch,
+		)
+	}
+	logger.Info(fmt.Sprintf("Using kernel config from repo: %s", configFile))
+
+	return configFile, nil
+}
+
+// runOldDefconfig updates the .config in kernelSrcDir for the kernel version
+func runOldDefconfig(logger *buildLogger, arch, toolchain, kernelSrcDir string, ctx context.Context) error {
+	logger.Info("Running make olddefconfig to update config...")
+
+	cmd := exec.Command("make", append([]string{"olddefconfig"}, configMakeArgs(arch, toolchain)...)...)
 	cmd.Dir = kernelSrcDir
-	// Route output through logger's writer (pipes to TUI properly)
-	cmd.Stdout = logger.writer
-	cmd.Stderr = logger.writer
+	// Route output through logger's writer (pipes to TUI properly), keeping a
+	// copy to report kconfig warnings
+	output := &tailBuffer{limit: compileOutputTail}
+	cmd.Stdout = io.MultiWriter(logger.writer, output)
+	cmd.Stderr = io.MultiWriter(logger.writer, output)
 
 	// Run with proper process group handling for cancellation
 	if err := runCommandWithProcessGroup(ctx, cmd); err != nil {
 		return fmt.Errorf("failed to update kernel config: %w", err)
 	}
 
+	if warnings := configWarnings(output.String()); len(warnings) > 0 {
+		logger.Warn(fmt.Sprintf("make olddefconfig reported %d warning(s):", len(warnings)))
+		for _, w := range warnings {
+			logger.Warn("  " + w)
+		}
+	}
+
 	return nil
 }
 
@@ -1136,7 +1779,7 @@ func buildKernelImage(logger *buildLogger, opts BuildOptions, kernelSrcDir, kern
 
 	// ARM64 kernels >= 6.11 need make prepare to generate syscall headers (unistd_64.h)
 	if opts.Arch == "aarch64" {
-		prepCmd := exec.Command("make", "prepare", "ARCH=arm64", "CROSS_COMPILE=aarch64-linux-gnu-")
+		prepCmd := exec.Command("make", append([]string{"prepare"}, compileMakeArgs(opts.Arch, opts.Toolchain)...)...)
 		prepCmd.Dir = kernelSrcDir
 		prepCmd.Stdout = logger.writer
 		prepCmd.Stderr = logger.writer
@@ -1148,14 +1791,19 @@ func buildKernelImage(logger *buildLogger, opts BuildOptions, kernelSrcDir, kern
 		}
 	}
 
-	var cmd *exec.Cmd
-	numCPU := runtime.NumCPU()
+	if opts.Jobs <= 0 && makeflagsSetJobs(os.Getenv("MAKEFLAGS")) {
+		logger.Info("Using parallelism from MAKEFLAGS")
+	}
 
+	target := "Image"
 	if opts.Arch == "x86_64" {
-		cmd = exec.Command("make", fmt.Sprintf("-j%d", numCPU), "vmlinux")
-	} else {
-		cmd = exec.Command("make", fmt.Sprintf("-j%d", numCPU), "Image", "ARCH=arm64", "CROSS_COMPILE=aarch64-linux-gnu-")
+		target = "vmlinux"
+	}
+	args, err := compileTargetArgs(opts, target)
+	if err != nil {
+		return err
 	}
+	cmd := exec.Command("make", args...)
 	cmd.Dir = kernelSrcDir
 	// Route output through logger's writer (pipes to TUI properly)
 	cmd.Stdout = logger.writer
@@ -1174,6 +1822,48 @@ func buildKernelImage(logger *buildLogger, opts BuildOptions, kernelSrcDir, kern
 	return nil
 }
 
+// compileTargetArgs returns the make arguments for building target: the job
+// count, arch and toolchain variables and the ccache compiler override
+func compileTargetArgs(opts BuildOptions, target string) ([]string, error) {
+	var args []string
+	switch {
+	case opts.Jobs > 0:
+		args = append(args, fmt.Sprintf("-j%d", opts.Jobs))
+	case makeflagsSetJobs(os.Getenv("MAKEFLAGS")):
+		// make reads MAKEFLAGS from the inherited environment
+	default:
+		args = append(args, fmt.Sprintf("-j%d", runtime.NumCPU()))
+	}
+
+	args = append(args, target)
+	args = append(args, compileMakeArgs(opts.Arch, opts.Toolchain)...)
+
+	ccache, err := resolveCcache(opts.UseCcache)
+	if err != nil {
+		return nil, err
+	}
+	if ccache != "" {
+		args = append(args, ccacheCompilerArg(ccache, opts.Arch, opts.Toolchain))
+	}
+	return args, nil
+}
+
+// makeflagsSetJobs reports whether a MAKEFLAGS value sets the job count,
+// either as -j/-jN/--jobs options or as a j in the leading single-letter
+// flag word (the form make itself exports, e.g. "j8" or "kj")
+func makeflagsSetJobs(makeflags string) bool {
+	fields := strings.Fields(makeflags)
+	for i, field := range fields {
+		if strings.HasPrefix(field, "-j") || field == "--jobs" || strings.HasPrefix(field, "--jobs=") {
+			return true
+		}
+		if i == 0 && !strings.HasPrefix(field, "-") && !strings.Contains(field, "=") && strings.Contains(field, "j") {
+			return true
+		}
+	}
+	return false
+}
+
 // packageArtifacts packages the built kernel and generates checksums
 func packageArtifacts(logger *buildLogger, opts BuildOptions, version, kernelSrcDir, kernelImage, artifactsDir, outputName string, ctx context.Context) error {
 	logger.Info("Preparing release artifacts...")
@@ -1196,43 +1886,65 @@ func packageArtifacts(logger *buildLogger, opts BuildOptions, version, kernelSrc
 		return fmt.Errorf("failed to copy kernel binary: %w", err)
 	}
 
-	// Generate SHA256 checksum of decompressed kernel
-	logger.Info("Generating SHA256 checksum of decompressed kernel...")
-	hash, err := util.CalculateSHA256(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to calculate checksum: %w", err)
-	}
-	checksumFile := outputPath + ".sha256"
-	if err := os.WriteFile(checksumFile, []byte(fmt.Sprintf("%s  %s\n", hash, outputName)), 0644); err != nil {
-		return fmt.Errorf("failed to write checksum file: %w", err)
+	// Generate checksums of decompressed kernel
+	if err := writeArtifactChecksums(logger, opts, outputPath, "decompressed kernel"); err != nil {
+		return err
 	}
 
-	// Compress kernel with xz (keep decompressed copy for signing)
-	logger.Info("Compressing kernel with xz (this may take a while)...")
-	compressedPath := outputPath + ".xz"
-	if err := util.CompressXZ(outputPath, compressedPath); err != nil {
+	// Compress kernel (keep decompressed copy for signing)
+	logger.Info(fmt.Sprintf("Compressing kernel with %s (this may take a while)...", opts.Compression))
+	compressedPath := outputPath + util.CompressionExt(opts.Compression)
+	// Remove any previous output first, in both formats so SHA256SUMS
+	// doesn't list a stale kernel of the other format: installed/archived
+	// copies may be hard links to it, and compressing truncates in place.
+	for _, format := range util.CompressionFormats {
+		previous := outputPath + util.CompressionExt(format)
+		paths := []string{previous}
+		for _, algo := range util.ChecksumAlgorithms {
+			paths = append(paths, previous+"."+algo)
+		}
+		for _, path := range paths {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove previous compressed kernel: %w", err)
+			}
+		}
+	}
+	if err := util.CompressWithProgress(opts.Compression, outputPath, compressedPath, nil); err != nil {
 		return fmt.Errorf("failed to compress kernel: %w", err)
 	}
 	logger.Info("Kernel compressed successfully")
 
-	// Generate SHA256 checksum of compressed kernel
-	logger.Info("Generating SHA256 checksum of compressed kernel...")
-	hashCompressed, err := util.CalculateSHA256(compressedPath)
-	if err != nil {
-		return fmt.Errorf("failed to calculate compressed checksum: %w", err)
-	}
-	checksumFileCompressed := compressedPath + ".sha256"
-	if err := os.WriteFile(checksumFileCompressed, []byte(fmt.Sprintf("%s  %s.xz\n", hashCompressed, outputName)), 0644); err != nil {
-		return fmt.Errorf("failed to write compressed checksum file: %w", err)
+	// Generate checksums of compressed kernel
+	if err := writeArtifactChecksums(logger, opts, compressedPath, "compressed kernel"); err != nil {
+		return err
 	}
 
 	// Copy kernel config
 	configSrc := filepath.Join(kernelSrcDir, ".config")
-	configDst := filepath.Join(artifactsDir, fmt.Sprintf("config-%s-%s", version, opts.Arch))
+	configDst := filepath.Join(artifactsDir, kernelConfigArtifactName(version, opts.Arch))
 	if err := copyFile(configSrc, configDst); err != nil {
 		return fmt.Errorf("failed to copy kernel config: %w", err)
 	}
 
+	// Checksum the config too so the signed manifest covers the exact
+	// config that produced the kernel
+	if err := writeArtifactChecksums(logger, opts, configDst, "kernel config"); err != nil {
+		return err
+	}
+
+	// Package the modules, or drop a modules tarball left by an earlier
+	// build so SHA256SUMS only lists this build's artifacts
+	if opts.BuildModules {
+		if err := packageModules(logger, opts, version, kernelSrcDir, artifactsDir); err != nil {
+			return err
+		}
+	} else if err := removeArtifactWithChecksums(filepath.Join(artifactsDir, modulesArtifactName(version, opts.Arch))); err != nil {
+		return fmt.Errorf("failed to remove previous modules tarball: %w", err)
+	}
+	if err := generateChecksumSums(artifactsDir); err != nil {
+		return fmt.Errorf("failed to generate checksums: %w", err)
+	}
+
 	// List artifacts
 	logger.Info("Artifacts created:")
 	entries, err := os.ReadDir(artifactsDir)
@@ -1247,3 +1959,42 @@ func packageArtifacts(logger *buildLogger, opts BuildOptions, version, kernelSrc
 
 	return nil
 }
+
+// writeArtifactChecksums writes <path>.<algo> in sha256sum/sha512sum format
+// for each of opts.ChecksumAlgorithms (default: sha256)
+func writeArtifactChecksums(logger *buildLogger, opts BuildOptions, path, description string) error {
+	for _, algo := range artifactChecksumAlgorithms(opts) {
+		logger.Info(fmt.Sprintf("Generating %s checksum of %s...", strings.ToUpper(algo), description))
+		hash, err := util.CalculateChecksum(path, algo)
+		if err != nil {
+			return fmt.Errorf("failed to calculate %s checksum: %w", description, err)
+		}
+		line := fmt.Sprintf("%s  %s\n", hash, filepath.Base(path))
+		if err := writeArtifactFile(path+"."+algo, []byte(line), 0644); err != nil {
+			return fmt.Errorf("failed to write %s checksum file: %w", description, err)
+		}
+	}
+	return nil
+}
+
+// artifactChecksumAlgorithms returns the configured checksum algorithms.
+// sha256 is always included, since SHA256SUMS is always written and
+// installing a kernel verifies its .sha256 file.
+func artifactChecksumAlgorithms(opts BuildOptions) []string {
+	algos := []string{util.ChecksumSHA256}
+	for _, algo := range opts.ChecksumAlgorithms {
+		if algo = strings.ToLower(algo); !slices.Contains(algos, algo) {
+			algos = append(algos, algo)
+		}
+	}
+	return algos
+}
+
+// writeArtifactFile replaces path with data. The old file is removed rather
+// than truncated so hard-linked installed or archived copies are untouched.
+func writeArtifactFile(path string, data []byte, perm os.FileMode) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.WriteFile(path, data, perm)
+}
@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/util"
+	"github.com/charmbracelet/log"
+)
+
+// versionBuildDir is the build directory for one kernel version and arch.
+// Each build keeps its own source tarball and extracted tree there, so
+// switching between versions doesn't re-download or re-extract the other.
+func versionBuildDir(paths *config.Paths, version, arch string) string {
+	return filepath.Join(paths.KernelBuildDir, "build", fmt.Sprintf("%s-%s", version, arch))
+}
+
+// BuildStatsFile returns the stats filename of a build in the artifacts
+// directory
+func BuildStatsFile(version, arch string) string {
+	return fmt.Sprintf("build-stats-%s-%s.json", version, arch)
+}
+
+// legacyBuildStatsFile is the per-arch stats file of the arch's last build,
+// written before stats were kept per version
+func legacyBuildStatsFile(arch string) string {
+	return fmt.Sprintf("build-stats-%s.json", arch)
+}
+
+// cachedBuildStatsFile returns the artifacts stats file for version and
+// arch, falling back to a legacy per-arch file that belongs to version. It
+// returns "" when neither exists.
+func cachedBuildStatsFile(version, arch string, paths *config.Paths) string {
+	artifactsDir := filepath.Join(paths.KernelBuildDir, "artifacts")
+	statsFile := filepath.Join(artifactsDir, BuildStatsFile(version, arch))
+	if _, err := os.Stat(statsFile); err == nil {
+		return statsFile
+	}
+
+	legacy := filepath.Join(artifactsDir, legacyBuildStatsFile(arch))
+	if stats, err := ReadBuildStats(legacy); err == nil && stats.KernelVersion == version {
+		return legacy
+	}
+	return ""
+}
+
+// artifactStats is a build's stats and the artifacts file they were read from
+type artifactStats struct {
+	file  string
+	stats BuildStats
+}
+
+// readArtifactStats reads the stats of every build in the artifacts
+// directory, newest first, once per version and arch. Unreadable files are
+// skipped with a warning.
+func readArtifactStats(paths *config.Paths) ([]artifactStats, error) {
+	files, err := filepath.Glob(filepath.Join(paths.KernelBuildDir, "artifacts", "build-stats-*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var builds []artifactStats
+	seen := map[string]bool{}
+	for _, file := range files {
+		stats, err := ReadBuildStats(file)
+		if err != nil {
+			log.Warnf("Ignoring cached build: %v", err)
+			continue
+		}
+		if stats.Arch == "" {
+			stats.Arch = kernelFileArch(stats.OutputPath)
+		}
+		// A legacy per-arch file may describe a build that also has its
+		// per-version file, which sorts first
+		key := stats.KernelVersion + "|" + stats.Arch
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		builds = append(builds, artifactStats{file: file, stats: stats})
+	}
+
+	sort.SliceStable(builds, func(i, j int) bool {
+		return builds[i].stats.BuildTimestamp.After(builds[j].stats.BuildTimestamp)
+	})
+	return builds, nil
+}
+
+// AllBuildStats returns the stats of every build in the artifacts
+// directory, newest first, including builds whose kernel files have since
+// been removed
+func AllBuildStats(paths *config.Paths) ([]BuildStats, error) {
+	builds, err := readArtifactStats(paths)
+	if err != nil {
+		return nil, err
+	}
+	all := make([]BuildStats, 0, len(builds))
+	for _, build := range builds {
+		all = append(all, build.stats)
+	}
+	return all, nil
+}
+
+// ListCachedBuilds returns the stats of every completed build in the
+// artifacts directory whose kernel files are still there, newest first
+func ListCachedBuilds(paths *config.Paths) ([]BuildStats, error) {
+	builds, err := readArtifactStats(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	var cached []BuildStats
+	for _, build := range builds {
+		if cachedBuildUsable(build.file, build.stats) {
+			cached = append(cached, build.stats)
+		}
+	}
+	return cached, nil
+}
+
+// linkLatestBuildStats points build-stats.json in the artifacts directory
+// at statsFile, the stats of the build that just finished, for tools that
+// read a single stats file there
+func linkLatestBuildStats(statsFile string) error {
+	return util.ReplaceSymlink(filepath.Base(statsFile), filepath.Join(filepath.Dir(statsFile), BuildStatsSidecar))
+}
+
+// CheckCachedBuild checks if a completed build exists for the given version and arch.
+// An empty version matches the newest cached build for the arch. If arch is
+// empty, it defaults to the host architecture.
+func CheckCachedBuild(version, arch string, paths *config.Paths) (bool, string, error) {
+	if arch == "" {
+		var err error
+		arch, err = config.GetArch()
+		if err != nil {
+			return false, "", err
+		}
+	}
+
+	if version == "" {
+		builds, err := ListCachedBuilds(paths)
+		if err != nil {
+			return false, "", err
+		}
+		for _, stats := range builds {
+			if stats.Arch == arch {
+				version = stats.KernelVersion
+				break
+			}
+		}
+		if version == "" {
+			return false, "", nil
+		}
+	}
+
+	statsFile := cachedBuildStatsFile(version, arch, paths)
+	if statsFile == "" {
+		return false, "", nil
+	}
+
+	// An unreadable or incompatible stats file just means there is no usable
+	// cache; the next build overwrites it
+	stats, err := ReadBuildStats(statsFile)
+	if err != nil {
+		log.Warnf("Ignoring cached build: %v", err)
+		return false, "", nil
+	}
+	if stats.KernelVersion != version {
+		log.Debugf("Cached build version mismatch: cached=%s, requested=%s", stats.KernelVersion, version)
+		return false, "", nil
+	}
+	if !cachedBuildUsable(statsFile, stats) {
+		return false, "", nil
+	}
+
+	return true, statsFile, nil
+}
+
+// cachedBuildUsable reports whether the build described by stats (read from
+// statsFile) still has its kernel files
+func cachedBuildUsable(statsFile string, stats BuildStats) bool {
+	if stats.KernelVersion == "" || stats.OutputPath == "" || stats.CompressedPath == "" {
+		log.Warnf("Ignoring cached build: %s is missing required fields", statsFile)
+		return false
+	}
+
+	// Check if output files exist
+	if _, err := os.Stat(stats.OutputPath); os.IsNotExist(err) {
+		log.Debugf("Cached build output missing: %s", stats.OutputPath)
+		return false
+	}
+
+	if _, err := os.Stat(stats.CompressedPath); os.IsNotExist(err) {
+		log.Debugf("Cached build compressed output missing: %s", stats.CompressedPath)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+)
+
+func TestAllBuildStats(t *testing.T) {
+	paths := config.PathsUnder(t.TempDir())
+	artifactsDir := filepath.Join(paths.KernelBuildDir, "artifacts")
+	if err := os.MkdirAll(artifactsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	built := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, version := range []string{"6.12.9", "6.18.2"} {
+		kernelPath := filepath.Join(artifactsDir, "vmlinux-"+version+"-x86_64")
+		stats := BuildStats{
+			KernelVersion:  version,
+			Arch:           "x86_64",
+			BuildTimestamp: built.Add(time.Duration(i) * time.Hour),
+			OutputPath:     kernelPath,
+			CompressedPath: kernelPath + ".xz",
+		}
+		statsFile := filepath.Join(artifactsDir, BuildStatsFile(version, "x86_64"))
+		if err := writeBuildStats(statsFile, stats); err != nil {
+			t.Fatal(err)
+		}
+		if err := linkLatestBuildStats(statsFile); err != nil {
+			t.Fatalf("linkLatestBuildStats() error = %v", err)
+		}
+	}
+	// Only 6.12.9 still has its kernel files
+	for _, name := range []string{"vmlinux-6.12.9-x86_64", "vmlinux-6.12.9-x86_64.xz"} {
+		if err := os.WriteFile(filepath.Join(artifactsDir, name), []byte("ELF"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := AllBuildStats(paths)
+	if err != nil {
+		t.Fatalf("AllBuildStats() error = %v", err)
+	}
+	if len(all) != 2 || all[0].KernelVersion != "6.18.2" || all[1].KernelVersion != "6.12.9" {
+		t.Errorf("AllBuildStats() = %+v, want 6.18.2 then 6.12.9", all)
+	}
+
+	cached, err := ListCachedBuilds(paths)
+	if err != nil {
+		t.Fatalf("ListCachedBuilds() error = %v", err)
+	}
+	if len(cached) != 1 || cached[0].KernelVersion != "6.12.9" {
+		t.Errorf("ListCachedBuilds() = %+v, want only 6.12.9", cached)
+	}
+
+	// build-stats.json follows the most recent build
+	latest, err := ReadBuildStats(filepath.Join(artifactsDir, BuildStatsSidecar))
+	if err != nil {
+		t.Fatalf("ReadBuildStats(%s) error = %v", BuildStatsSidecar, err)
+	}
+	if latest.KernelVersion != "6.18.2" {
+		t.Errorf("%s is for %s, want 6.18.2", BuildStatsSidecar, latest.KernelVersion)
+	}
+}
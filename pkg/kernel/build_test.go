@@ -0,0 +1,268 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+func TestArtifactChecksumAlgorithms(t *testing.T) {
+	tests := []struct {
+		name  string
+		algos []string
+		want  []string
+	}{
+		{"default", nil, []string{"sha256"}},
+		{"sha256", []string{"sha256"}, []string{"sha256"}},
+		{"sha512 only", []string{"sha512"}, []string{"sha256", "sha512"}},
+		{"both", []string{"SHA512", "sha256"}, []string{"sha256", "sha512"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := artifactChecksumAlgorithms(BuildOptions{ChecksumAlgorithms: tt.algos})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("artifactChecksumAlgorithms(%v) = %v, want %v", tt.algos, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateChecksumSumsSHA512(t *testing.T) {
+	dir := t.TempDir()
+	kernel := filepath.Join(dir, "vmlinux-6.18.9-x86_64")
+	if err := os.WriteFile(kernel, []byte("ELF"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := &buildLogger{writer: io.Discard}
+	opts := BuildOptions{ChecksumAlgorithms: []string{"sha512"}}
+	if err := writeArtifactChecksums(logger, opts, kernel, "kernel"); err != nil {
+		t.Fatalf("writeArtifactChecksums() error = %v", err)
+	}
+	if err := generateChecksumSums(dir); err != nil {
+		t.Fatalf("generateChecksumSums() error = %v", err)
+	}
+
+	// SHA256SUMS, which gets signed, must never be empty
+	for _, sums := range []string{"SHA256SUMS", "SHA512SUMS"} {
+		data, err := os.ReadFile(filepath.Join(dir, sums))
+		if err != nil {
+			t.Fatalf("%s not written: %v", sums, err)
+		}
+		if !strings.HasSuffix(strings.TrimSpace(string(data)), "  vmlinux-6.18.9-x86_64") {
+			t.Errorf("%s = %q, want an entry for the kernel", sums, data)
+		}
+	}
+}
+
+func TestPackageBuildParallel(t *testing.T) {
+	artifactsDir := t.TempDir()
+	archiveDir := t.TempDir()
+	logger := &buildLogger{writer: io.Discard}
+
+	type build struct{ version, arch string }
+	var builds []build
+	for _, version := range []string{"6.12.9", "6.18.9", "6.19.1"} {
+		for _, arch := range []string{"x86_64", "aarch64"} {
+			builds = append(builds, build{version, arch})
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(builds))
+	for i, b := range builds {
+		// Each build has its own source tree, as in build/<version>-<arch>
+		kernelFilename, kernelImage := kernelArtifactNames(b.version, b.arch)
+		srcDir := t.TempDir()
+		for name, content := range map[string]string{kernelImage: "kernel " + b.version + b.arch, ".config": "CONFIG_VIRTIO=y\n"} {
+			if err := os.MkdirAll(filepath.Dir(filepath.Join(srcDir, name)), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(srcDir, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		opts := BuildOptions{Arch: b.arch, Compression: util.CompressionXZ}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			kernelPath := filepath.Join(artifactsDir, kernelFilename)
+			stats, err := packageBuild(logger, opts, b.version, srcDir, kernelImage, artifactsDir, kernelFilename, context.Background(), func(time.Duration) BuildStats {
+				return BuildStats{KernelVersion: b.version, OutputPath: kernelPath, CompressedPath: kernelPath + ".xz"}
+			})
+			if err == nil {
+				err = ArchiveInstalledKernel(stats, archiveDir)
+			}
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		t.Fatalf("parallel packaging failed: %v", err)
+	}
+
+	// SHA256SUMS lists every build's kernel, compressed kernel and config
+	sums, err := os.ReadFile(filepath.Join(artifactsDir, "SHA256SUMS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range builds {
+		kernelFilename, _ := kernelArtifactNames(b.version, b.arch)
+		for _, name := range []string{kernelFilename, kernelFilename + ".xz", kernelConfigArtifactName(b.version, b.arch)} {
+			if !strings.Contains(string(sums), "  "+name+"\n") {
+				t.Errorf("SHA256SUMS has no entry for %s", name)
+			}
+		}
+	}
+
+	// The links point at one of the builds' files
+	for _, link := range []string{BuildStatsSidecar, ManifestSidecar} {
+		if _, err := os.Stat(filepath.Join(artifactsDir, link)); err != nil {
+			t.Errorf("%s is broken: %v", link, err)
+		}
+	}
+
+	// No archived build was lost from index.json
+	archives, err := ArchiveList("", archiveDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archives) != len(builds) {
+		t.Errorf("archive index lists %d kernels, want %d: %+v", len(archives), len(builds), archives)
+	}
+}
+
+func TestBuildCachedCompression(t *testing.T) {
+	paths := config.PathsUnder(t.TempDir())
+	cachedBuild(t, paths, "6.18.9", "x86_64")
+	// Block the build directory, so a build that gets past the cache check
+	// fails right away
+	if err := os.WriteFile(filepath.Join(paths.KernelBuildDir, "build"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	build := func(compression string) (string, *BuildStats, error) {
+		var out bytes.Buffer
+		var stats *BuildStats
+		err := Build(BuildOptions{
+			Version:           "6.18.9",
+			Arch:              "x86_64",
+			Compression:       compression,
+			VerificationLevel: "disabled",
+			AllowRoot:         true,
+			Writer:            &out,
+			StatsCallback:     func(s BuildStats) { stats = &s },
+		}, paths)
+		return out.String(), stats, err
+	}
+
+	// The cached xz kernel is reported
+	out, stats, err := build(util.CompressionXZ)
+	if err != nil {
+		t.Fatalf("Build() of the cached kernel error = %v\n%s", err, out)
+	}
+	if stats == nil || stats.KernelVersion != "6.18.9" {
+		t.Errorf("Build() reported stats %+v, want the cached build's", stats)
+	}
+
+	// but a zstd build doesn't take it for its own
+	out, stats, err = build(util.CompressionZstd)
+	if err == nil || !strings.Contains(err.Error(), "failed to create build directory") {
+		t.Errorf("Build() with zstd error = %v, want it to rebuild", err)
+	}
+	if !strings.Contains(out, "is compressed with another format, rebuilding with zstd") {
+		t.Errorf("Build() with zstd output:\n%s", out)
+	}
+	if stats != nil {
+		t.Errorf("Build() with zstd reported the cached xz build: %+v", stats)
+	}
+}
+
+func TestCheckRunningAsRoot(t *testing.T) {
+	buildDir := filepath.Join(t.TempDir(), "build-kernel")
+	var out bytes.Buffer
+	logger := &buildLogger{writer: &out}
+
+	err := checkRunningAsRoot(logger, false, buildDir)
+	if os.Geteuid() != 0 {
+		if err != nil {
+			t.Errorf("checkRunningAsRoot() as a normal user error = %v", err)
+		}
+		return
+	}
+	if !errors.Is(err, ErrRunningAsRoot) || !strings.Contains(err.Error(), buildDir) {
+		t.Errorf("checkRunningAsRoot() as root error = %v, want ErrRunningAsRoot naming %s", err, buildDir)
+	}
+	if strings.Contains(err.Error(), "--allow-root") {
+		t.Errorf("checkRunningAsRoot() error names a CLI flag: %v", err)
+	}
+
+	if err := checkRunningAsRoot(logger, true, buildDir); err != nil {
+		t.Errorf("checkRunningAsRoot() with allowRoot error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Building as root") {
+		t.Errorf("checkRunningAsRoot() with allowRoot didn't warn: %q", out.String())
+	}
+}
+
+func TestMakeflagsSetJobs(t *testing.T) {
+	tests := []struct {
+		makeflags string
+		want      bool
+	}{
+		{"-j4", true},
+		{"-j 4", true},
+		{"--jobs=4", true},
+		{"--jobs 4", true},
+		{"-j", true},
+		{"-k -j8 --no-print-directory", true},
+		// The flag word make exports to sub-makes
+		{"j8", true},
+		{"kj -- V=1", true},
+		{"", false},
+		{"-k", false},
+		{"--no-print-directory -s", false},
+		{"V=1 KCFLAGS=-j", false},
+		{"k -- KBUILD_BUILD_USER=jenkins", false},
+		{"--jobserver-auth=3,4", false},
+	}
+	for _, tt := range tests {
+		if got := makeflagsSetJobs(tt.makeflags); got != tt.want {
+			t.Errorf("makeflagsSetJobs(%q) = %v, want %v", tt.makeflags, got, tt.want)
+		}
+	}
+}
+
+func TestProgressCallbacksTagArch(t *testing.T) {
+	var got []string
+	opts := BuildOptions{
+		Arch:              "aarch64",
+		ProgressCallback:  func(fraction float64) { got = append(got, fmt.Sprintf("progress %.1f", fraction)) },
+		ArchPhaseCallback: func(arch string, p BuildPhase) { got = append(got, arch+" "+p.String()) },
+		ArchProgressCallback: func(arch string, fraction float64) {
+			got = append(got, fmt.Sprintf("%s progress %.1f", arch, fraction))
+		},
+	}
+	progress, phase := opts.progressCallbacks()
+	phase(PhaseExtract)
+	progress(0.5)
+
+	want := []string{"aarch64 extract", "progress 0.5", "aarch64 progress 0.5"}
+	if !slices.Equal(got, want) {
+		t.Errorf("callbacks = %q, want %q", got, want)
+	}
+}
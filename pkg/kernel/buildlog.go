@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+)
+
+// buildLogPhasePrefix starts the marker line written before each phase's
+// output in a build log ("### anvil phase: compile")
+const buildLogPhasePrefix = "### anvil phase: "
+
+// BuildLogPath returns where the full output of the last build of version
+// is saved (artifacts/build-<version>.log)
+func BuildLogPath(version string) string {
+	return filepath.Join(config.GlobalPaths.KernelBuildDir, "artifacts", fmt.Sprintf("build-%s.log", version))
+}
+
+// BuildLog saves a build's output to BuildLogPath, with a marker line at
+// the start of each phase so the output can be split by phase again
+type BuildLog struct {
+	f *os.File
+}
+
+// CreateBuildLog creates (or truncates) the build log of version
+func CreateBuildLog(version string) (*BuildLog, error) {
+	path := BuildLogPath(version)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create artifacts directory: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create build log: %w", err)
+	}
+	return &BuildLog{f: f}, nil
+}
+
+// Write appends build output. It never fails, so a full disk can't break
+// the build being logged; the log is only a record.
+func (l *BuildLog) Write(p []byte) (int, error) {
+	_, _ = l.f.Write(p)
+	return len(p), nil
+}
+
+// StartPhase writes the marker for phase. Call it from the goroutine that
+// writes the build output, so the marker lands between the right lines.
+func (l *BuildLog) StartPhase(phase BuildPhase) {
+	_, _ = fmt.Fprintf(l.f, "%s%s\n", buildLogPhasePrefix, phase)
+}
+
+// Close closes the log file
+func (l *BuildLog) Close() error {
+	return l.f.Close()
+}
+
+// ReadBuildLog reads a build log, returning every output line and the lines
+// of each phase. Marker lines are left out; output before the first marker
+// belongs to the download phase, where every build starts.
+func ReadBuildLog(path string) ([]string, map[BuildPhase][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	byPhase := make(map[BuildPhase][]string)
+	phase := PhaseDownload
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, buildLogPhasePrefix); ok {
+			if p, ok := ParseBuildPhase(name); ok {
+				phase = p
+				continue
+			}
+		}
+		lines = append(lines, line)
+		byPhase[phase] = append(byPhase[phase], line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read build log: %w", err)
+	}
+	return lines, byPhase, nil
+}
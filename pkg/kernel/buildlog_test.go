@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+)
+
+func TestBuildLogRoundTrip(t *testing.T) {
+	saved := config.GlobalPaths
+	config.SetPaths(t.TempDir())
+	t.Cleanup(func() { config.GlobalPaths = saved })
+
+	if got, want := BuildLogPath("6.12.9"), filepath.Join(config.GlobalPaths.KernelBuildDir, "artifacts", "build-6.12.9.log"); got != want {
+		t.Errorf("BuildLogPath() = %q, want %q", got, want)
+	}
+
+	buildLog, err := CreateBuildLog("6.12.9")
+	if err != nil {
+		t.Fatalf("CreateBuildLog() error = %v", err)
+	}
+	fmt.Fprintln(buildLog, "Fetching linux-6.12.9.tar.xz")
+	buildLog.StartPhase(PhaseExtract)
+	fmt.Fprintln(buildLog, "Extracting")
+	buildLog.StartPhase(PhaseCompile)
+	fmt.Fprint(buildLog, "  CC init/main.o\n  LD vmlinux\n")
+	if err := buildLog.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines, byPhase, err := ReadBuildLog(BuildLogPath("6.12.9"))
+	if err != nil {
+		t.Fatalf("ReadBuildLog() error = %v", err)
+	}
+
+	wantLines := []string{"Fetching linux-6.12.9.tar.xz", "Extracting", "  CC init/main.o", "  LD vmlinux"}
+	if !slices.Equal(lines, wantLines) {
+		t.Errorf("lines = %q, want %q", lines, wantLines)
+	}
+	wantPhases := map[BuildPhase][]string{
+		PhaseDownload: {"Fetching linux-6.12.9.tar.xz"},
+		PhaseExtract:  {"Extracting"},
+		PhaseCompile:  {"  CC init/main.o", "  LD vmlinux"},
+	}
+	if len(byPhase) != len(wantPhases) {
+		t.Errorf("got output for %d phases, want %d", len(byPhase), len(wantPhases))
+	}
+	for phase, want := range wantPhases {
+		if !slices.Equal(byPhase[phase], want) {
+			t.Errorf("%s output = %q, want %q", phase, byPhase[phase], want)
+		}
+	}
+}
+
+func TestReadBuildLogMissing(t *testing.T) {
+	if _, _, err := ReadBuildLog(filepath.Join(t.TempDir(), "build-6.1.log")); err == nil {
+		t.Error("ReadBuildLog() of a missing file succeeded")
+	}
+}
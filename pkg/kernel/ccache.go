@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// resolveCcache returns the path to ccache when the compile should use it,
+// or "" when it shouldn't. useCcache nil means use ccache if it is on PATH;
+// explicitly true fails when it isn't installed.
+func resolveCcache(useCcache *bool) (string, error) {
+	if useCcache != nil && !*useCcache {
+		return "", nil
+	}
+
+	path, err := exec.LookPath("ccache")
+	if err != nil {
+		if useCcache != nil {
+			return "", fmt.Errorf("ccache requested but not found on PATH. Install ccache or build without --ccache")
+		}
+		return "", nil
+	}
+	return path, nil
+}
+
+// probeCcache logs whether the compile will go through ccache and where
+// its cache lives
+func probeCcache(logger *buildLogger, useCcache *bool) error {
+	path, err := resolveCcache(useCcache)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		if useCcache == nil {
+			logger.Debug("ccache not found, compiling without it")
+		}
+		return nil
+	}
+
+	logger.Info(fmt.Sprintf("Using ccache: %s (cache: %s)", path, ccacheDir(path)))
+	return nil
+}
+
+// ccacheDir returns the cache directory ccache reports, falling back to
+// CCACHE_DIR or "default" when it can't be queried
+func ccacheDir(path string) string {
+	out, err := exec.Command(path, "--get-config", "cache_dir").Output()
+	if dir := strings.TrimSpace(string(out)); err == nil && dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("CCACHE_DIR"); dir != "" {
+		return dir
+	}
+	return "default"
+}
+
+// ccacheCompilerArg returns the make CC= override that wraps the arch's
+// compiler in ccache
+func ccacheCompilerArg(ccache, arch, toolchain string) string {
+	compiler := "gcc"
+	if toolchain == ToolchainLLVM {
+		compiler = "clang"
+	} else if arch == "aarch64" {
+		compiler = "aarch64-linux-gnu-gcc"
+	}
+	return fmt.Sprintf("CC=%s %s", ccache, compiler)
+}
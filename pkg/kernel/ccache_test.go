@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestResolveCcache(t *testing.T) {
+	on, off := true, false
+	tests := []struct {
+		name      string
+		useCcache *bool
+		installed bool
+		want      bool // ccache is used
+		wantErr   bool
+	}{
+		{"auto with ccache", nil, true, true, false},
+		{"auto without ccache", nil, false, false, false},
+		{"forced with ccache", &on, true, true, false},
+		{"forced without ccache", &on, false, false, true},
+		{"disabled with ccache", &off, true, false, false},
+		{"disabled without ccache", &off, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tools := map[string]string{}
+			if tt.installed {
+				tools["ccache"] = `echo /var/cache/ccache`
+			}
+			dir := fakeTools(t, tools)
+
+			path, err := resolveCcache(tt.useCcache)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveCcache() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if want := filepath.Join(dir, "ccache"); tt.want && path != want || !tt.want && path != "" {
+				t.Errorf("resolveCcache() = %q, want ccache used: %v", path, tt.want)
+			}
+
+			// The compile wraps the compiler in ccache only when it's used
+			args, err := compileTargetArgs(BuildOptions{Arch: "aarch64", Toolchain: ToolchainGCC, Jobs: 2, UseCcache: tt.useCcache}, "Image")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("compileTargetArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			wrapped := slices.Contains(args, "CC="+filepath.Join(dir, "ccache")+" aarch64-linux-gnu-gcc")
+			if wrapped != tt.want {
+				t.Errorf("compileTargetArgs() = %v, want ccache used: %v", args, tt.want)
+			}
+		})
+	}
+}
+
+func TestProbeCcache(t *testing.T) {
+	on := true
+	fakeTools(t, map[string]string{"ccache": `echo /var/cache/ccache`})
+
+	var out bytes.Buffer
+	if err := probeCcache(&buildLogger{writer: &out}, &on); err != nil {
+		t.Fatalf("probeCcache() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "(cache: /var/cache/ccache)") {
+		t.Errorf("probeCcache() output = %q, want the cache directory", out.String())
+	}
+
+	// Without a cache directory from ccache, CCACHE_DIR is reported
+	fakeTools(t, map[string]string{"ccache": `exit 1`})
+	t.Setenv("CCACHE_DIR", "/tmp/ccache")
+	out.Reset()
+	if err := probeCcache(&buildLogger{writer: &out}, nil); err != nil {
+		t.Fatalf("probeCcache() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "(cache: /tmp/ccache)") {
+		t.Errorf("probeCcache() output = %q, want CCACHE_DIR", out.String())
+	}
+}
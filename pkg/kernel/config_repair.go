@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Work-Fort/Anvil/pkg/kconfig"
+)
+
+// compileOutputTail is how much compiler output is kept for failure analysis
+const compileOutputTail = 256 * 1024
+
+// missingSymbolPatterns match compile and link errors caused by a config
+// that leaves out a symbol the new kernel version depends on
+var missingSymbolPatterns = []*regexp.Regexp{
+	regexp.MustCompile("undefined reference to [`']([A-Za-z0-9_]+)'"),
+	regexp.MustCompile(`ERROR: modpost: "([A-Za-z0-9_]+)" \[[^\]]*\] undefined!`),
+	regexp.MustCompile(`implicit declaration of function '([A-Za-z0-9_]+)'`),
+	regexp.MustCompile(`error: '([A-Za-z0-9_]+)' undeclared`),
+}
+
+// compileKernel runs the compile phase. When it fails with errors that point
+// at missing config symbols, it offers (via opts.ConfirmConfigRepair) or
+// performs (with opts.AutoFix) a single defconfig-merge repair and retries.
+func compileKernel(logger *buildLogger, opts BuildOptions, kernelSrcDir, kernelImage string, ctx context.Context) error {
+	tail := &tailBuffer{limit: compileOutputTail}
+	compileLogger := &buildLogger{writer: io.MultiWriter(logger.writer, tail), reporter: logger.reporter}
+
+	err := buildKernelImage(compileLogger, opts, kernelSrcDir, kernelImage, ctx)
+	if err == nil || (ctx != nil && ctx.Err() != nil) {
+		return err
+	}
+
+	symbols := detectMissingSymbols(tail.String())
+	if len(symbols) == 0 {
+		return err
+	}
+
+	logger.Warn(fmt.Sprintf("Compile failed with missing symbols: %s", strings.Join(symbols, ", ")))
+	logger.Warn("The kernel config may be missing options required by this kernel version")
+
+	repair := opts.AutoFix
+	if !repair && opts.ConfirmConfigRepair != nil {
+		repair = opts.ConfirmConfigRepair(symbols)
+	}
+	if !repair {
+		return fmt.Errorf("%w\n\nThe config may need options that are new in this kernel version.\nRe-run with --auto-fix to merge in defconfig values and retry", err)
+	}
+
+	if repairErr := repairKernelConfig(logger, opts, kernelSrcDir, ctx); repairErr != nil {
+		return fmt.Errorf("%w (config repair failed: %v)", err, repairErr)
+	}
+
+	logger.Info("Retrying kernel build with repaired config...")
+	return buildKernelImage(logger, opts, kernelSrcDir, kernelImage, ctx)
+}
+
+// repairKernelConfig rebuilds .config from the arch defconfig with the
+// repo's kernel config merged on top, so options the repo config doesn't
+// mention get defconfig values instead of olddefconfig defaults. Every
+// option that changes is logged.
+func repairKernelConfig(logger *buildLogger, opts BuildOptions, kernelSrcDir string, ctx context.Context) error {
+	logger.Info("Repairing kernel config by merging it onto defconfig...")
+
+	configFile, err := resolveKernelConfigFile(logger, opts)
+	if err != nil {
+		return err
+	}
+	overlay, err := kconfig.ParseFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to read kernel config: %w", err)
+	}
+
+	srcConfig := filepath.Join(kernelSrcDir, ".config")
+	before, err := kconfig.ParseFile(srcConfig)
+	if err != nil {
+		return fmt.Errorf("failed to read current .config: %w", err)
+	}
+
+	cmd := exec.Command("make", append([]string{"defconfig"}, configMakeArgs(opts.Arch, opts.Toolchain)...)...)
+	cmd.Dir = kernelSrcDir
+	cmd.Stdout = logger.writer
+	cmd.Stderr = logger.writer
+	if err := runCommandWithProcessGroup(ctx, cmd); err != nil {
+		return fmt.Errorf("make defconfig failed: %w", err)
+	}
+
+	merged, err := kconfig.ParseFile(srcConfig)
+	if err != nil {
+		return fmt.Errorf("failed to read defconfig: %w", err)
+	}
+	for _, opt := range overlay.List("") {
+		if err := merged.Set(opt.Name, opt.Value); err != nil {
+			logger.Warn(fmt.Sprintf("Skipping CONFIG_%s: %v", opt.Name, err))
+		}
+	}
+	if err := merged.WriteFile(srcConfig); err != nil {
+		return err
+	}
+
+	if err := runOldDefconfig(logger, opts.Arch, opts.Toolchain, kernelSrcDir, ctx); err != nil {
+		return err
+	}
+
+	after, err := kconfig.ParseFile(srcConfig)
+	if err != nil {
+		return fmt.Errorf("failed to read repaired .config: %w", err)
+	}
+
+	diffs := kconfig.Diff(before, after)
+	logger.Info(fmt.Sprintf("Config repair changed %d option(s):", len(diffs)))
+	for _, d := range diffs {
+		switch d.Type {
+		case kconfig.DiffAdded:
+			logger.Info(fmt.Sprintf("  + CONFIG_%s=%s", d.Name, d.ValueB))
+		case kconfig.DiffRemoved:
+			logger.Info(fmt.Sprintf("  - CONFIG_%s=%s", d.Name, d.ValueA))
+		case kconfig.DiffChanged:
+			logger.Info(fmt.Sprintf("  ~ CONFIG_%s: %s -> %s", d.Name, d.ValueA, d.ValueB))
+		}
+	}
+
+	return nil
+}
+
+// detectMissingSymbols returns the unique symbols named by missing-symbol
+// errors in compiler output, in order of first appearance
+func detectMissingSymbols(output string) []string {
+	seen := make(map[string]bool)
+	var symbols []string
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		for _, re := range missingSymbolPatterns {
+			m := re.FindStringSubmatch(line)
+			if m == nil || seen[m[1]] {
+				continue
+			}
+			seen[m[1]] = true
+			symbols = append(symbols, m[1])
+		}
+	}
+
+	return symbols
+}
+
+// configWarnings returns the warning lines from kconfig tool output
+func configWarnings(output string) []string {
+	var warnings []string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(strings.ToLower(line), "warning:") {
+			warnings = append(warnings, strings.TrimSpace(line))
+		}
+	}
+	return warnings
+}
+
+// tailBuffer is an io.Writer that keeps only the last limit bytes written
+type tailBuffer struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	limit int
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.buf.Write(p)
+	if over := t.buf.Len() - t.limit; over > 0 {
+		t.buf.Next(over)
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.buf.String()
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDetectMissingSymbols(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{
+			name: "linker",
+			output: `  LD      .tmp_vmlinux1
+ld: vmlinux.o: in function ` + "`" + `virtblk_probe':
+drivers/block/virtio_blk.c:1473: undefined reference to ` + "`" + `blk_mq_alloc_disk_for_queue'
+ld: drivers/block/virtio_blk.c:1490: undefined reference to ` + "`" + `blk_mq_alloc_disk_for_queue'
+ld: net/vmw_vsock/af_vsock.o: in function ` + "`" + `vsock_poll':
+af_vsock.c:(.text+0x1a2c): undefined reference to ` + "`" + `sk_busy_loop_end'
+make[2]: *** [scripts/Makefile.vmlinux:34: vmlinux] Error 1`,
+			want: []string{"blk_mq_alloc_disk_for_queue", "sk_busy_loop_end"},
+		},
+		{
+			name: "modpost",
+			output: `  MODPOST Module.symvers
+ERROR: modpost: "vsock_core_register" [net/vmw_vsock/vmw_vsock_virtio_transport.ko] undefined!
+ERROR: modpost: "virtio_transport_do_socket_init" [net/vmw_vsock/vmw_vsock_virtio_transport.ko] undefined!
+make[3]: *** [scripts/Makefile.modpost:145: Module.symvers] Error 1`,
+			want: []string{"vsock_core_register", "virtio_transport_do_socket_init"},
+		},
+		{
+			name: "compiler",
+			output: `  CC      fs/fuse/virtio_fs.o
+fs/fuse/virtio_fs.c:1123:9: error: implicit declaration of function 'dax_iomap_rw' [-Werror=implicit-function-declaration]
+fs/fuse/virtio_fs.c:1130:21: error: 'FUSE_DAX_MODE' undeclared (first use in this function)
+cc1: some warnings being treated as errors`,
+			want: []string{"dax_iomap_rw", "FUSE_DAX_MODE"},
+		},
+		{
+			name: "unrelated failure",
+			output: `  CC      kernel/bpf/core.o
+gcc: fatal error: Killed signal terminated program cc1
+compilation terminated.
+make[3]: *** [scripts/Makefile.build:243: kernel/bpf/core.o] Error 1`,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectMissingSymbols(tt.output); !slices.Equal(got, tt.want) {
+				t.Errorf("detectMissingSymbols() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Lines longer than bufio's default token size are still scanned
+	long := strings.Repeat("x", 100*1024) + "\nld: undefined reference to `late_symbol'\n"
+	if got := detectMissingSymbols(long); !slices.Equal(got, []string{"late_symbol"}) {
+		t.Errorf("detectMissingSymbols() after a long line = %q", got)
+	}
+}
+
+func TestConfigWarnings(t *testing.T) {
+	output := `#
+# configuration written to .config
+#
+.config:1234:warning: symbol value 'm' invalid for VIRTIO_BLK
+  WARNING: unmet direct dependencies detected for VIRTIO_MMIO
+scripts/kconfig/conf  --olddefconfig Kconfig`
+	want := []string{
+		".config:1234:warning: symbol value 'm' invalid for VIRTIO_BLK",
+		"WARNING: unmet direct dependencies detected for VIRTIO_MMIO",
+	}
+	if got := configWarnings(output); !slices.Equal(got, want) {
+		t.Errorf("configWarnings() = %q, want %q", got, want)
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	tail := &tailBuffer{limit: 16}
+	for i := range 10 {
+		n, err := fmt.Fprintf(tail, "line %d\n", i)
+		if n != 7 || err != nil {
+			t.Fatalf("Write() = %d, %v", n, err)
+		}
+	}
+	if got := tail.String(); got != "7\nline 8\nline 9\n" {
+		t.Errorf("tail = %q, want the last 16 bytes", got)
+	}
+
+	// A single write larger than the limit keeps its end
+	tail = &tailBuffer{limit: 4}
+	tail.Write([]byte("0123456789"))
+	if got := tail.String(); got != "6789" {
+		t.Errorf("tail = %q, want %q", got, "6789")
+	}
+
+	// Concurrent writers (make's stdout and stderr) are safe
+	tail = &tailBuffer{limit: 64}
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				tail.Write([]byte("abcdefgh"))
+			}
+		}()
+	}
+	wg.Wait()
+	if got := tail.String(); got != strings.Repeat("abcdefgh", 8) {
+		t.Errorf("tail after concurrent writes = %q", got)
+	}
+}
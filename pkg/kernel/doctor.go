@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+// kernelArches are the architectures kernels are built and installed for
+var kernelArches = []string{"x86_64", "aarch64"}
+
+// Issue is an inconsistency in the kernel directories found by Doctor
+type Issue struct {
+	Path    string `json:"path"`
+	Problem string `json:"problem"`
+	Fix     string `json:"fix"` // Command that fixes it
+}
+
+// Doctor checks the installed kernels and build artifacts for state that
+// the other commands silently trip over: a default kernel symlink whose
+// target is gone, kernel version directories without a kernel binary, and
+// built kernels in the artifacts directory without their build stats
+func Doctor(paths *config.Paths) ([]Issue, error) {
+	issues := []Issue{}
+
+	installed, err := installedKernelBinaries(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, arch := range kernelArches {
+		kernelName, err := config.GetKernelNameForArch(arch)
+		if err != nil {
+			return nil, err
+		}
+		symlinkPath := filepath.Join(paths.DataDir, kernelName)
+		target, dangling := danglingSymlink(symlinkPath)
+		if !dangling {
+			continue
+		}
+		fix := "anvil kernel get <version>"
+		if versions := installed[arch]; len(versions) > 0 {
+			fix = fmt.Sprintf("anvil kernel set %s", versions[0])
+		}
+		issues = append(issues, Issue{
+			Path:    symlinkPath,
+			Problem: fmt.Sprintf("default %s kernel points at %s, which no longer exists", arch, target),
+			Fix:     fix,
+		})
+	}
+
+	entries, err := os.ReadDir(paths.KernelsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read kernels directory: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == "default" {
+			continue
+		}
+		version := entry.Name()
+		if hasInstalledBinary(installed, version) {
+			continue
+		}
+		issues = append(issues, Issue{
+			Path:    filepath.Join(paths.KernelsDir, version),
+			Problem: fmt.Sprintf("kernel %s has no kernel binary", version),
+			Fix:     fmt.Sprintf("anvil kernel remove %s && anvil kernel get %s", version, version),
+		})
+	}
+
+	artifactsDir := filepath.Join(paths.KernelBuildDir, "artifacts")
+	artifacts, err := os.ReadDir(artifactsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read artifacts directory: %w", err)
+	}
+	seen := map[string]bool{}
+	for _, entry := range artifacts {
+		version, arch, ok := parseKernelArtifactName(entry.Name())
+		if !ok || seen[version+"|"+arch] {
+			continue
+		}
+		seen[version+"|"+arch] = true
+		if cachedBuildStatsFile(version, arch, paths) != "" {
+			continue
+		}
+		issues = append(issues, Issue{
+			Path:    filepath.Join(artifactsDir, entry.Name()),
+			Problem: fmt.Sprintf("built kernel %s (%s) has no %s; the build was interrupted", version, arch, BuildStatsFile(version, arch)),
+			Fix:     fmt.Sprintf("anvil build-kernel %s --arch %s --resume package", version, arch),
+		})
+	}
+
+	return issues, nil
+}
+
+// DanglingDefault returns the target of the host architecture's default
+// kernel symlink when the kernel it points at no longer exists (its
+// version directory was deleted by hand, say), or "" otherwise
+func DanglingDefault(paths *config.Paths) (string, error) {
+	kernelName, err := config.GetKernelName()
+	if err != nil {
+		return "", fmt.Errorf("failed to get kernel name: %w", err)
+	}
+	if target, dangling := danglingSymlink(filepath.Join(paths.DataDir, kernelName)); dangling {
+		return target, nil
+	}
+	return "", nil
+}
+
+// danglingSymlink reports whether path is a symlink whose target is
+// missing, and the target it points at
+func danglingSymlink(path string) (string, bool) {
+	target, err := os.Readlink(path)
+	if err != nil {
+		return "", false
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return target, true
+	}
+	return target, false
+}
+
+// installedKernelBinaries returns, for each architecture, the installed
+// versions that have a kernel binary for it, newest first
+func installedKernelBinaries(paths *config.Paths) (map[string][]string, error) {
+	installed := map[string][]string{}
+	entries, err := os.ReadDir(paths.KernelsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return installed, nil
+		}
+		return nil, fmt.Errorf("failed to read kernels directory: %w", err)
+	}
+	for _, arch := range kernelArches {
+		kernelName, err := config.GetKernelNameForArch(arch)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			version := entry.Name()
+			binary := filepath.Join(paths.KernelsDir, version, fmt.Sprintf("%s-%s-%s", kernelName, version, arch))
+			if _, err := os.Stat(binary); err == nil {
+				installed[arch] = append(installed[arch], version)
+			}
+		}
+		util.SortKernelVersions(installed[arch])
+	}
+	return installed, nil
+}
+
+// hasInstalledBinary reports whether version has a kernel binary for any
+// architecture
+func hasInstalledBinary(installed map[string][]string, version string) bool {
+	for _, versions := range installed {
+		if slices.Contains(versions, version) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseKernelArtifactName returns the version and architecture of a built
+// kernel in the artifacts directory (vmlinux-<version>-x86_64 or
+// Image-<version>-aarch64, possibly compressed). Checksums, configs and
+// other artifacts don't match.
+func parseKernelArtifactName(name string) (version, arch string, ok bool) {
+	for _, format := range util.CompressionFormats {
+		name = strings.TrimSuffix(name, util.CompressionExt(format))
+	}
+	for _, a := range kernelArches {
+		kernelName, err := config.GetKernelNameForArch(a)
+		if err != nil {
+			continue
+		}
+		if v, found := strings.CutPrefix(name, kernelName+"-"); found {
+			if v, found := strings.CutSuffix(v, "-"+a); found && v != "" {
+				return v, a, true
+			}
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+)
+
+func TestDoctor(t *testing.T) {
+	dir := t.TempDir()
+	paths := &config.Paths{
+		DataDir:        dir,
+		KernelsDir:     filepath.Join(dir, "kernels"),
+		KernelBuildDir: filepath.Join(dir, "build-kernel"),
+	}
+	artifactsDir := filepath.Join(paths.KernelBuildDir, "artifacts")
+	writeFile := func(path string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A healthy kernel, one whose binary is gone, and a default pointing at
+	// a kernel whose directory was deleted
+	writeFile(filepath.Join(paths.KernelsDir, "6.12.9", "vmlinux-6.12.9-x86_64"))
+	if err := os.MkdirAll(filepath.Join(paths.KernelsDir, "6.1.0"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(paths.KernelsDir, "6.6.0", "vmlinux-6.6.0-x86_64"), filepath.Join(dir, "vmlinux")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A complete build and one interrupted before its stats were written
+	writeFile(filepath.Join(artifactsDir, "vmlinux-6.12.9-x86_64.xz"))
+	writeFile(filepath.Join(artifactsDir, "vmlinux-6.12.9-x86_64.xz.sha256"))
+	writeFile(filepath.Join(artifactsDir, BuildStatsFile("6.12.9", "x86_64")))
+	writeFile(filepath.Join(artifactsDir, "Image-6.13-aarch64"))
+	writeFile(filepath.Join(artifactsDir, "Image-6.13-aarch64.sha256"))
+	writeFile(filepath.Join(artifactsDir, "config-6.13-aarch64"))
+
+	issues, err := Doctor(paths)
+	if err != nil {
+		t.Fatalf("Doctor() error = %v", err)
+	}
+
+	want := []Issue{
+		{Path: filepath.Join(dir, "vmlinux"), Fix: "anvil kernel set 6.12.9"},
+		{Path: filepath.Join(paths.KernelsDir, "6.1.0"), Fix: "anvil kernel remove 6.1.0 && anvil kernel get 6.1.0"},
+		{Path: filepath.Join(artifactsDir, "Image-6.13-aarch64"), Fix: "anvil build-kernel 6.13 --arch aarch64 --resume package"},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("Doctor() found %d issues, want %d: %+v", len(issues), len(want), issues)
+	}
+	for i, issue := range issues {
+		if issue.Path != want[i].Path || issue.Fix != want[i].Fix {
+			t.Errorf("issue %d = %s (fix %q), want %s (fix %q)", i, issue.Path, issue.Fix, want[i].Path, want[i].Fix)
+		}
+		if issue.Problem == "" {
+			t.Errorf("issue %d has no problem description", i)
+		}
+	}
+}
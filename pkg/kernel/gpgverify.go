@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// verifySignedBy runs gpg --verify on args (a clearsigned file, or a
+// detached signature and the signed file) and checks that a valid
+// signature was made by one of fingerprints. "Good signature" alone is not
+// enough: it is printed for any key in the keyring.
+func verifySignedBy(fingerprints []string, args ...string) error {
+	var status, stderr bytes.Buffer
+	cmd := exec.Command("gpg", append([]string{"--status-fd", "1", "--verify"}, args...)...)
+	cmd.Stdout = &status
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("gpg --verify failed: %w\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	signers := validSignatureFingerprints(status.String())
+	for _, signer := range signers {
+		for _, fpr := range fingerprints {
+			if strings.EqualFold(signer, fpr) {
+				return nil
+			}
+		}
+	}
+	if len(signers) == 0 {
+		return fmt.Errorf("gpg reported no valid signature")
+	}
+	return fmt.Errorf("signed by %s, not by the expected key %s", strings.Join(signers, ", "), strings.Join(fingerprints, ", "))
+}
+
+// validSignatureFingerprints returns the fingerprints named by the VALIDSIG
+// lines of gpg --status-fd output: the signing (sub)key's and, when
+// present, its primary key's
+func validSignatureFingerprints(status string) []string {
+	var fprs []string
+	for _, line := range strings.Split(status, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != "[GNUPG:]" || fields[1] != "VALIDSIG" {
+			continue
+		}
+		// VALIDSIG <fpr> <date> <timestamp> <expire> <version> <reserved>
+		// <pubkey-algo> <hash-algo> <sig-class> [<primary-key-fpr>]
+		fprs = append(fprs, fields[2])
+		if len(fields) >= 12 && !strings.EqualFold(fields[11], fields[2]) {
+			fprs = append(fprs, fields[11])
+		}
+	}
+	return fprs
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValidSignatureFingerprints(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		want   []string
+	}{
+		{
+			name: "primary key signature",
+			status: `[GNUPG:] NEWSIG
+[GNUPG:] GOODSIG 632D3A06589DA6B1 Kernel.org checksum autosigner <autosigner@kernel.org>
+[GNUPG:] VALIDSIG B8868C80BA62A1FFFAF5FDA9632D3A06589DA6B1 2026-01-05 1767600000 0 4 0 1 10 01 B8868C80BA62A1FFFAF5FDA9632D3A06589DA6B1
+`,
+			want: []string{"B8868C80BA62A1FFFAF5FDA9632D3A06589DA6B1"},
+		},
+		{
+			name:   "subkey signature",
+			status: "[GNUPG:] VALIDSIG 1111111111111111111111111111111111111111 2026-01-05 1767600000 0 4 0 22 10 00 2222222222222222222222222222222222222222\n",
+			want:   []string{"1111111111111111111111111111111111111111", "2222222222222222222222222222222222222222"},
+		},
+		{
+			name:   "bad signature",
+			status: "[GNUPG:] BADSIG 632D3A06589DA6B1 Kernel.org checksum autosigner\n",
+			want:   nil,
+		},
+		{
+			name:   "no status",
+			status: "",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validSignatureFingerprints(tt.status)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("validSignatureFingerprints() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrimaryFingerprints(t *testing.T) {
+	colons := `pub:-:4096:1:632D3A06589DA6B1:1324512000:::-:::scSC::::::23::0:
+fpr:::::::::B8868C80BA62A1FFFAF5FDA9632D3A06589DA6B1:
+uid:-::::1324512000::A1B2::Kernel.org checksum autosigner <autosigner@kernel.org>::::::::::0:
+sub:-:4096:1:AAAAAAAAAAAAAAAA:1324512000::::::e::::::23:
+fpr:::::::::CCCCCCCCCCCCCCCCCCCCCCCCAAAAAAAAAAAAAAAA:
+`
+	got := primaryFingerprints(colons)
+	want := []string{"B8868C80BA62A1FFFAF5FDA9632D3A06589DA6B1"}
+	if !slices.Equal(got, want) {
+		t.Errorf("primaryFingerprints() = %v, want %v", got, want)
+	}
+	if !primaryFingerprintsContain(colons, "b8868c80ba62a1fffaf5fda9632d3a06589da6b1") {
+		t.Error("primaryFingerprintsContain() should match case-insensitively")
+	}
+	if primaryFingerprintsContain(colons, "CCCCCCCCCCCCCCCCCCCCCCCCAAAAAAAAAAAAAAAA") {
+		t.Error("primaryFingerprintsContain() should not match subkeys")
+	}
+}
@@ -2,15 +2,18 @@
 package kernel
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+	"syscall"
 
 	"github.com/Work-Fort/Anvil/pkg/config"
 	"github.com/Work-Fort/Anvil/pkg/github"
 	"github.com/Work-Fort/Anvil/pkg/util"
+	"github.com/Work-Fort/Anvil/pkg/verify"
 	"github.com/charmbracelet/log"
 )
 
@@ -29,9 +32,29 @@ type AvailableVersion struct {
 	IsDefault   bool   `json:"is_default"`
 }
 
-// Get gets a kernel by trying to download pre-built version first, then building from source if needed
+// Get gets a kernel by trying the repo-local archive (when
+// kernels.archive.location is set and a version is given), then a pre-built
+// release, then building from source. Without a version the archive is
+// skipped, so the latest release is fetched rather than whatever the archive
+// holds.
 func Get(version string, client *github.Client, paths *config.Paths, buildOpts *BuildOptions) error {
-	// Try to download pre-built kernel first
+	if archiveDir := config.GetKernelsArchiveLocation(); archiveDir != "" && version != "" {
+		arch, err := config.GetArch()
+		if err != nil {
+			return err
+		}
+		err = downloadFromArchive(archiveDir, version, arch, paths)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, ErrNotInArchive) {
+			log.Infof("%v, trying GitHub releases", err)
+		} else {
+			log.Warnf("Failed to install kernel from archive: %v", err)
+		}
+	}
+
+	// Try to download pre-built kernel next
 	if err := Download(version, client, paths); err == nil {
 		// Download successful
 		return nil
@@ -58,6 +81,17 @@ func Download(version string, client *github.Client, paths *config.Paths) error
 
 // DownloadWithProgress downloads and verifies a kernel version with progress and status tracking
 func DownloadWithProgress(version string, client *github.Client, paths *config.Paths, progressCallback func(float64), statusCallback func(string)) error {
+	return DownloadWithReporter(version, client, paths, util.CallbackProgressReporter{Progress: progressCallback, Status: statusCallback})
+}
+
+// DownloadWithReporter downloads and verifies a kernel version, reporting
+// each step as a phase and the download and decompression as progress
+func DownloadWithReporter(version string, client *github.Client, paths *config.Paths, reporter util.ProgressReporter) error {
+	if reporter == nil {
+		reporter = util.NopProgressReporter{}
+	}
+	progressCallback := util.ReportFraction(reporter)
+
 	arch, err := config.GetArch()
 	if err != nil {
 		return err
@@ -69,34 +103,126 @@ func DownloadWithProgress(version string, client *github.Client, paths *config.P
 	}
 
 	// If no version specified, get latest
+	parts := strings.Split(config.GitHubRepo, "/")
+	var release *github.Release
 	if version == "" {
-		parts := strings.Split(config.GitHubRepo, "/")
-		release, err := client.GetLatestRelease(parts[0], parts[1])
+		release, err = client.GetLatestRelease(parts[0], parts[1])
 		if err != nil {
 			return fmt.Errorf("failed to fetch latest kernel version: %w", err)
 		}
 		version = github.StripVersionPrefix(release.TagName)
 		log.Debugf("Using latest kernel version: %s", version)
+	} else if IsVersionAlias(version) {
+		alias := version
+		if release, err = ResolveReleaseVersion(alias, client); err != nil {
+			return err
+		}
+		version = github.StripVersionPrefix(release.TagName)
+		log.Infof("Resolved kernel %s to %s", alias, version)
+		reporter.Log(util.LevelInfo, fmt.Sprintf("Resolved kernel %s to %s", alias, version))
 	}
 
-	filename := fmt.Sprintf("%s-%s-%s.xz", kernelName, version, arch)
 	outputDir := filepath.Join(paths.KernelsDir, version)
 	outputFile := filepath.Join(outputDir, fmt.Sprintf("%s-%s-%s", kernelName, version, arch))
 
 	// Check if already downloaded
 	if _, err := os.Stat(outputFile); err == nil {
 		log.Infof("Kernel already exists: %s", outputFile)
+		reporter.Log(util.LevelInfo, fmt.Sprintf("Kernel already exists: %s", outputFile))
 		return nil
 	}
 
 	log.Debugf("Downloading kernel %s for %s", version, arch)
 
+	// Releases carry an xz or a zstd compressed kernel; without the release
+	// listing, fall back to xz
+	filename := fmt.Sprintf("%s-%s-%s%s", kernelName, version, arch, util.CompressionExt(util.CompressionXZ))
+	if release == nil {
+		if r, err := client.GetReleaseByTag(parts[0], parts[1], "v"+version); err == nil {
+			release = r
+		} else {
+			log.Debugf("Failed to fetch release v%s, assuming xz kernel: %v", version, err)
+		}
+	}
+	if release != nil {
+		if asset := releaseKernelAsset(*release, kernelName, version, arch); asset != "" {
+			filename = asset
+		}
+	}
+
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	releaseURL := fmt.Sprintf("https://github.com/%s/releases/download/v%s", config.GitHubRepo, version)
-	tempFile := filepath.Join(paths.CacheDir, filename)
+	files, err := fetchVerifiedRelease(client, version, filename, paths.CacheDir, progressCallback, reporter.SetPhase)
+	if err != nil {
+		return err
+	}
+
+	// Decompress - this is the slowest operation
+	reporter.SetPhase("Decompressing kernel...")
+	reporter.SetProgress(0, 1) // Reset to 0 for this step
+	log.Debug("Decompressing kernel")
+	// Note: DecompressWithProgress will report progress from 0-100% as it reads the compressed file
+	if err := util.DecompressWithProgress(files.asset, outputFile, progressCallback); err != nil {
+		return fmt.Errorf("failed to decompress kernel: %w", err)
+	}
+
+	// Verify decompressed kernel checksum
+	reporter.SetPhase("Verifying decompressed checksum...")
+	reporter.SetProgress(0, 1)
+	log.Debug("Verifying decompressed kernel checksum")
+	if err := util.VerifySHA256File(outputFile, files.checksums); err != nil {
+		os.Remove(outputFile)
+		return fmt.Errorf("decompressed kernel checksum verification failed: %w", err)
+	}
+	reporter.SetProgress(1, 1)
+
+	// Record how the kernel was verified for anvil kernel audit
+	if err := writeReleaseRecord(outputDir, outputFile, version, arch, filename); err != nil {
+		log.Warnf("Failed to record kernel verification: %v", err)
+		reporter.Log(util.LevelWarn, fmt.Sprintf("Failed to record kernel verification: %v", err))
+	}
+
+	// Clean up
+	reporter.SetPhase("Cleaning up...")
+	reporter.SetProgress(0, 1)
+	os.Remove(files.asset)
+	os.Remove(files.checksums)
+	os.Remove(files.signature)
+	os.Remove(files.key)
+	reporter.SetProgress(1, 1)
+
+	// Done
+	reporter.SetPhase("Installation complete!")
+
+	return nil
+}
+
+// releaseDownloadURL is where release assets are downloaded from (a
+// variable so tests can point it at a local server)
+var releaseDownloadURL = "https://github.com"
+
+// releaseFiles holds the local paths of a downloaded release asset and its
+// verification material.
+type releaseFiles struct {
+	asset     string
+	checksums string
+	signature string
+	key       string
+}
+
+// fetchVerifiedRelease downloads a compressed kernel asset from a GitHub release
+// into workDir along with SHA256SUMS, SHA256SUMS.asc and signing-key.asc, then
+// verifies the asset checksum and that the signature was made by that key.
+func fetchVerifiedRelease(client *github.Client, version, filename, workDir string, progressCallback func(float64), statusCallback func(string)) (*releaseFiles, error) {
+	releaseURL := fmt.Sprintf("%s/%s/releases/download/v%s", releaseDownloadURL, config.GitHubRepo, version)
+	files := &releaseFiles{
+		asset:     filepath.Join(workDir, filename),
+		checksums: filepath.Join(workDir, "SHA256SUMS"),
+		signature: filepath.Join(workDir, "SHA256SUMS.asc"),
+		key:       filepath.Join(workDir, "signing-key.asc"),
+	}
 
 	// Download compressed kernel
 	if statusCallback != nil {
@@ -106,8 +232,8 @@ func DownloadWithProgress(version string, client *github.Client, paths *config.P
 		progressCallback(0) // Reset to 0 for this step
 	}
 	log.Debugf("Downloading from: %s/%s", releaseURL, filename)
-	if err := client.DownloadFile(fmt.Sprintf("%s/%s", releaseURL, filename), tempFile, progressCallback); err != nil {
-		return fmt.Errorf("failed to download kernel: %w", err)
+	if err := client.DownloadFile(fmt.Sprintf("%s/%s", releaseURL, filename), files.asset, progressCallback); err != nil {
+		return nil, fmt.Errorf("failed to download kernel: %w", err)
 	}
 
 	// Download checksums
@@ -118,9 +244,8 @@ func DownloadWithProgress(version string, client *github.Client, paths *config.P
 		progressCallback(0) // Reset to 0 for this step
 	}
 	log.Debug("Downloading checksums")
-	checksumFile := filepath.Join(paths.CacheDir, "SHA256SUMS")
-	if err := client.DownloadFile(fmt.Sprintf("%s/SHA256SUMS", releaseURL), checksumFile, progressCallback); err != nil {
-		return fmt.Errorf("failed to download checksums: %w", err)
+	if err := client.DownloadFile(fmt.Sprintf("%s/SHA256SUMS", releaseURL), files.checksums, progressCallback); err != nil {
+		return nil, fmt.Errorf("failed to download checksums: %w", err)
 	}
 
 	// Download signature
@@ -131,9 +256,8 @@ func DownloadWithProgress(version string, client *github.Client, paths *config.P
 		progressCallback(0) // Reset to 0 for this step
 	}
 	log.Debug("Downloading PGP signature")
-	sigFile := filepath.Join(paths.CacheDir, "SHA256SUMS.asc")
-	if err := client.DownloadFile(fmt.Sprintf("%s/SHA256SUMS.asc", releaseURL), sigFile, progressCallback); err != nil {
-		return fmt.Errorf("failed to download PGP signature: %w", err)
+	if err := client.DownloadFile(fmt.Sprintf("%s/SHA256SUMS.asc", releaseURL), files.signature, progressCallback); err != nil {
+		return nil, fmt.Errorf("failed to download PGP signature: %w", err)
 	}
 
 	// Download signing key
@@ -143,118 +267,159 @@ func DownloadWithProgress(version string, client *github.Client, paths *config.P
 	if progressCallback != nil {
 		progressCallback(0) // Reset to 0 for this step
 	}
-	log.Debug("Importing Anvil signing key")
-	keyFile := filepath.Join(paths.CacheDir, "signing-key.asc")
-	if err := client.DownloadFile(fmt.Sprintf("%s/signing-key.asc", releaseURL), keyFile, progressCallback); err != nil {
-		return fmt.Errorf("failed to download signing key: %w", err)
+	log.Debug("Downloading Anvil signing key")
+	if err := client.DownloadFile(fmt.Sprintf("%s/signing-key.asc", releaseURL), files.key, progressCallback); err != nil {
+		return nil, fmt.Errorf("failed to download signing key: %w", err)
 	}
 
-	// Import GPG key
+	// Verify the checksum and that SHA256SUMS is signed by the release's key
 	if statusCallback != nil {
-		statusCallback("Importing GPG key...")
+		statusCallback("Verifying signature and checksum...")
 	}
 	if progressCallback != nil {
 		progressCallback(0)
 	}
-	cmd := exec.Command("gpg", "--import", "--quiet", keyFile)
-	if err := cmd.Run(); err != nil {
-		// Ignore errors - key might already be imported
+	log.Debug("Verifying compressed kernel checksum and PGP signature")
+	if err := verify.SignedArtifact(files.asset, files.checksums, files.signature, files.key); err != nil {
+		return nil, fmt.Errorf("failed to verify %s: %w", filename, err)
 	}
 	if progressCallback != nil {
 		progressCallback(1.0)
 	}
 
-	// Verify PGP signature
-	if statusCallback != nil {
-		statusCallback("Verifying PGP signature...")
-	}
-	if progressCallback != nil {
-		progressCallback(0)
-	}
-	log.Debug("Verifying PGP signature")
-	cmd = exec.Command("gpg", "--verify", sigFile, checksumFile)
-	output, err := cmd.CombinedOutput()
-	if err != nil || !strings.Contains(string(output), "Good signature") {
-		return fmt.Errorf("PGP signature verification failed")
-	}
-	if progressCallback != nil {
-		progressCallback(1.0)
-	}
+	return files, nil
+}
 
-	// Verify compressed file checksum
-	if statusCallback != nil {
-		statusCallback("Verifying compressed checksum...")
-	}
-	if progressCallback != nil {
-		progressCallback(0)
-	}
-	log.Debug("Verifying compressed kernel checksum")
-	if err := util.VerifySHA256File(tempFile, checksumFile); err != nil {
-		return fmt.Errorf("compressed kernel checksum verification failed: %w", err)
-	}
-	if progressCallback != nil {
-		progressCallback(1.0)
-	}
+// copyBufferSize is the buffer size used for streaming file copies
+const copyBufferSize = 1024 * 1024
 
-	// Decompress - this is the slowest operation
-	if statusCallback != nil {
-		statusCallback("Decompressing kernel...")
-	}
-	if progressCallback != nil {
-		progressCallback(0) // Reset to 0 for this step
-	}
-	log.Debug("Decompressing kernel")
-	// Note: DecompressXZWithProgress will report progress from 0-100% as it reads the compressed file
-	if err := util.DecompressXZWithProgress(tempFile, outputFile, progressCallback); err != nil {
-		return fmt.Errorf("failed to decompress kernel: %w", err)
+// copyFile streams src to dst, preserving the source file's permissions.
+// An existing dst is removed first so that a hard link created by
+// linkOrCopyFile is replaced rather than written through.
+func copyFile(src, dst string) error {
+	return copyFileWithProgress(src, dst, nil)
+}
+
+// copyFileWithProgress is copyFile with an optional callback that receives the
+// number of bytes written by each chunk.
+func copyFileWithProgress(src, dst string, onBytes func(int64)) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
 	}
+	defer in.Close()
 
-	// Verify decompressed kernel checksum
-	if statusCallback != nil {
-		statusCallback("Verifying decompressed checksum...")
+	info, err := in.Stat()
+	if err != nil {
+		return err
 	}
-	if progressCallback != nil {
-		progressCallback(0)
+
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return err
 	}
-	log.Debug("Verifying decompressed kernel checksum")
-	if err := util.VerifySHA256File(outputFile, checksumFile); err != nil {
-		os.Remove(outputFile)
-		return fmt.Errorf("decompressed kernel checksum verification failed: %w", err)
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
 	}
-	if progressCallback != nil {
-		progressCallback(1.0)
+
+	var w io.Writer = out
+	if onBytes != nil {
+		w = &countingWriter{w: out, onBytes: onBytes}
 	}
 
-	// Clean up
-	if statusCallback != nil {
-		statusCallback("Cleaning up...")
+	if _, err := io.CopyBuffer(w, in, make([]byte, copyBufferSize)); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
 	}
-	if progressCallback != nil {
-		progressCallback(0)
+
+	return out.Close()
+}
+
+// linkOrCopyFile hard links src to dst when both are on the same filesystem,
+// falling back to a streaming copy across devices (or wherever linking is not
+// supported). Callers must not modify src in place afterwards.
+func linkOrCopyFile(src, dst string) error {
+	return linkOrCopyFileWithProgress(src, dst, nil)
+}
+
+// hardLink creates dst as a hard link to src (a variable so tests can simulate
+// cross-device and unsupported links)
+var hardLink = os.Link
+
+// linkOrCopyFileWithProgress is linkOrCopyFile with byte-based progress. A
+// successful hard link reports the whole file size at once.
+func linkOrCopyFileWithProgress(src, dst string, onBytes func(int64)) error {
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return err
 	}
-	os.Remove(tempFile)
-	os.Remove(checksumFile)
-	os.Remove(sigFile)
-	os.Remove(keyFile)
-	if progressCallback != nil {
-		progressCallback(1.0)
+
+	if err := hardLink(src, dst); err == nil {
+		if onBytes != nil {
+			if info, err := os.Stat(dst); err == nil {
+				onBytes(info.Size())
+			}
+		}
+		return nil
+	} else if !errors.Is(err, syscall.EXDEV) {
+		log.Debugf("Hard link %s -> %s failed, copying instead: %v", src, dst, err)
 	}
 
-	// Done
-	if statusCallback != nil {
-		statusCallback("Installation complete!")
+	return copyFileWithProgress(src, dst, onBytes)
+}
+
+// countingWriter reports the number of bytes written through it
+type countingWriter struct {
+	w       io.Writer
+	onBytes func(int64)
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	if n > 0 {
+		cw.onBytes(int64(n))
 	}
+	return n, err
+}
 
-	return nil
+// copyProgress turns per-chunk byte counts across a set of files into an
+// overall 0.0-1.0 progress callback.
+type copyProgress struct {
+	total    int64
+	done     int64
+	callback func(float64)
 }
 
-// copyFile copies a file from src to dst
-func copyFile(src, dst string) error {
-	data, err := os.ReadFile(src)
-	if err != nil {
-		return err
+// newCopyProgress sizes a progress tracker from the given source files.
+// Missing files are ignored. Returns nil if callback is nil.
+func newCopyProgress(callback func(float64), srcs ...string) *copyProgress {
+	if callback == nil {
+		return nil
+	}
+	cp := &copyProgress{callback: callback}
+	for _, src := range srcs {
+		if info, err := os.Stat(src); err == nil {
+			cp.total += info.Size()
+		}
+	}
+	callback(0)
+	return cp
+}
+
+// add records n copied bytes. Safe to call on a nil tracker.
+func (cp *copyProgress) add(n int64) {
+	if cp == nil {
+		return
+	}
+	cp.done += n
+	if cp.total > 0 {
+		percent := float64(cp.done) / float64(cp.total)
+		if percent > 1.0 {
+			percent = 1.0
+		}
+		cp.callback(percent)
 	}
-	return os.WriteFile(dst, data, 0644)
 }
 
 // List returns installed kernel versions with their metadata.
@@ -269,10 +434,11 @@ func List(paths *config.Paths) ([]KernelInfo, string, error) {
 		return nil, "", fmt.Errorf("failed to get kernel name: %w", err)
 	}
 
-	// Determine default version from symlink
+	// Determine default version from symlink. A dangling symlink names no
+	// default; DanglingDefault reports it.
 	defaultVersion := ""
 	kernelSymlink := filepath.Join(paths.DataDir, kernelName)
-	if target, err := os.Readlink(kernelSymlink); err == nil {
+	if target, dangling := danglingSymlink(kernelSymlink); target != "" && !dangling {
 		parts := strings.Split(target, "/")
 		for i, part := range parts {
 			if part == "kernels" && i+1 < len(parts) {
@@ -316,8 +482,30 @@ func List(paths *config.Paths) ([]KernelInfo, string, error) {
 	return kernels, arch, nil
 }
 
+// SetOptions configures SetWithOptions
+type SetOptions struct {
+	Force bool // Set a kernel built for another architecture as its arch's default
+}
+
 // Set sets a kernel version as default by creating a symlink
 func Set(version string, paths *config.Paths) error {
+	return SetWithOptions(version, paths, SetOptions{})
+}
+
+// SetWithOptions sets a kernel version as default. If the version has no
+// kernel for the host architecture but one for another architecture, it
+// returns an *ArchMismatchError unless opts.Force is set. "latest" and "lts"
+// resolve to the matching GitHub release, which must be installed.
+func SetWithOptions(version string, paths *config.Paths, opts SetOptions) error {
+	if IsVersionAlias(version) {
+		release, err := ResolveReleaseVersion(version, github.NewClient("", config.GitHubAPI))
+		if err != nil {
+			return err
+		}
+		log.Infof("Resolved kernel %s to %s", version, github.StripVersionPrefix(release.TagName))
+		version = github.StripVersionPrefix(release.TagName)
+	}
+
 	arch, err := config.GetArch()
 	if err != nil {
 		return fmt.Errorf("failed to get architecture: %w", err)
@@ -328,21 +516,33 @@ func Set(version string, paths *config.Paths) error {
 		return fmt.Errorf("failed to get kernel name: %w", err)
 	}
 
-	sourceFile := filepath.Join(paths.KernelsDir, version, fmt.Sprintf("%s-%s-%s", kernelName, version, arch))
-	symlinkPath := filepath.Join(paths.DataDir, kernelName)
+	versionDir := filepath.Join(paths.KernelsDir, version)
+	sourceFile := filepath.Join(versionDir, fmt.Sprintf("%s-%s-%s", kernelName, version, arch))
 
 	// Check if version exists
 	if _, err := os.Stat(sourceFile); err != nil {
-		return fmt.Errorf("kernel %s not found for %s", version, arch)
+		other := findOtherArchKernel(versionDir, version, arch)
+		if other == "" {
+			return fmt.Errorf("kernel %s not found for %s", version, arch)
+		}
+		mismatch := checkKernelArch(other, arch)
+		if !opts.Force {
+			return mismatch
+		}
+		log.Warnf("%v", mismatch)
+
+		// The default symlink is named for the kernel's own arch, as install does
+		sourceFile = other
+		if kernelName, err = config.GetKernelNameForArch(kernelFileArch(other)); err != nil {
+			return err
+		}
 	}
+	symlinkPath := filepath.Join(paths.DataDir, kernelName)
 
 	log.Debugf("Setting kernel %s as default", version)
 
-	// Remove existing symlink if present
-	os.Remove(symlinkPath)
-
-	// Create symlink
-	if err := os.Symlink(sourceFile, symlinkPath); err != nil {
+	// Swap the link in one rename so there is always a default kernel
+	if err := util.ReplaceSymlink(sourceFile, symlinkPath); err != nil {
 		return fmt.Errorf("failed to set default: %w", err)
 	}
 
@@ -465,6 +665,91 @@ func CleanBuildCache(all bool, paths *config.Paths) (status string, cleanedPath
 	return "cleaned", buildSubdir, nil
 }
 
+// SourcesCleanResult describes what CleanSources removed
+type SourcesCleanResult struct {
+	Removed    []string `json:"removed"`
+	FreedBytes int64    `json:"freed_bytes"`
+}
+
+// KernelSources returns the extracted kernel source trees (linux-*/),
+// source tarballs (linux-*.tar.xz) and partial downloads
+// (linux-*.tar.xz.part) in the build directory and its per-version
+// build/<version>-<arch> directories, relative to the build directory
+func KernelSources(paths *config.Paths) ([]string, error) {
+	buildDir := filepath.Join(paths.KernelBuildDir, "build")
+	sources, err := kernelSourcesIn(buildDir, "")
+	if err != nil {
+		return nil, err
+	}
+	entries, _ := os.ReadDir(buildDir)
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), "linux-") {
+			continue
+		}
+		found, err := kernelSourcesIn(filepath.Join(buildDir, entry.Name()), entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, found...)
+	}
+	return sources, nil
+}
+
+// kernelSourcesIn returns the kernel sources in dir, relative to the build
+// directory
+func kernelSourcesIn(dir, subdir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read build directory: %w", err)
+	}
+
+	var sources []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, "linux-") {
+			continue
+		}
+		if !entry.IsDir() && !strings.HasSuffix(name, ".tar.xz") && !strings.HasSuffix(name, ".tar.xz.part") {
+			continue
+		}
+		sources = append(sources, filepath.Join(subdir, name))
+	}
+	return sources, nil
+}
+
+// CleanSources removes the kernel sources found by KernelSources. Build
+// artifacts and build stats are left intact.
+func CleanSources(paths *config.Paths) (*SourcesCleanResult, error) {
+	result := &SourcesCleanResult{Removed: []string{}}
+
+	sources, err := KernelSources(paths)
+	if err != nil {
+		return result, err
+	}
+	buildDir := filepath.Join(paths.KernelBuildDir, "build")
+	for _, source := range sources {
+		path := filepath.Join(buildDir, source)
+		var size int64
+		if info, err := os.Lstat(path); err == nil && info.IsDir() {
+			size, _ = util.DirSize(path)
+		} else if err == nil {
+			size = info.Size()
+		}
+
+		log.Debugf("Removing kernel source: %s", path)
+		if err := os.RemoveAll(path); err != nil {
+			return result, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		result.Removed = append(result.Removed, source)
+		result.FreedBytes += size
+	}
+
+	return result, nil
+}
+
 // ShowVersions returns available kernel versions from GitHub with install status.
 func ShowVersions(client *github.Client, paths *config.Paths) ([]AvailableVersion, error) {
 	log.Debug("Fetching available kernel versions from GitHub")
@@ -0,0 +1,313 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+)
+
+// sourcesFixture lays out kernel sources at the top of the build directory
+// and in a per-version build directory, next to a build that must be kept
+func sourcesFixture(t *testing.T) *config.Paths {
+	t.Helper()
+	paths := config.PathsUnder(t.TempDir())
+	buildDir := filepath.Join(paths.KernelBuildDir, "build")
+	files := map[string]string{
+		"linux-6.12.9.tar.xz":                      "0123456789",
+		"linux-6.12.9/Makefile":                    "VERSION = 6\n",
+		"6.18.9-x86_64/linux-6.18.9.tar.xz":        "01234",
+		"6.18.9-x86_64/linux-6.18.9.tar.xz.part":   "012",
+		"6.18.9-x86_64/linux-6.18.9/Makefile":      "VERSION = 6\n",
+		"6.18.9-x86_64/linux-6.18.9/.config":       "CONFIG_VIRTIO=y\n",
+		"6.18.9-x86_64/build-stats.json":           "{}",
+		"6.18.9-aarch64/vmlinux-6.18.9-aarch64.xz": "XZ",
+	}
+	for name, content := range files {
+		path := filepath.Join(buildDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return paths
+}
+
+func TestKernelSources(t *testing.T) {
+	paths := sourcesFixture(t)
+
+	sources, err := KernelSources(paths)
+	if err != nil {
+		t.Fatalf("KernelSources() error = %v", err)
+	}
+	want := []string{
+		"linux-6.12.9",
+		"linux-6.12.9.tar.xz",
+		filepath.Join("6.18.9-x86_64", "linux-6.18.9"),
+		filepath.Join("6.18.9-x86_64", "linux-6.18.9.tar.xz"),
+		filepath.Join("6.18.9-x86_64", "linux-6.18.9.tar.xz.part"),
+	}
+	slices.Sort(sources)
+	slices.Sort(want)
+	if !slices.Equal(sources, want) {
+		t.Errorf("KernelSources() = %v, want %v", sources, want)
+	}
+
+	// No build directory yet is not an error
+	if sources, err := KernelSources(config.PathsUnder(t.TempDir())); err != nil || len(sources) != 0 {
+		t.Errorf("KernelSources() without a build directory = %v, %v", sources, err)
+	}
+}
+
+func TestCleanSources(t *testing.T) {
+	paths := sourcesFixture(t)
+	buildDir := filepath.Join(paths.KernelBuildDir, "build")
+	cached := cacheTarball(t, &buildLogger{writer: io.Discard}, paths, "6.18.9", "linux 6.18.9")
+
+	result, err := CleanSources(paths)
+	if err != nil {
+		t.Fatalf("CleanSources() error = %v", err)
+	}
+	if len(result.Removed) != 5 {
+		t.Errorf("CleanSources() removed %v, want 5 entries", result.Removed)
+	}
+	// Both tarballs, the partial download and both trees' files
+	if want := int64(10 + 5 + 3 + 12 + 12 + 16); result.FreedBytes != want {
+		t.Errorf("CleanSources() freed %d bytes, want %d", result.FreedBytes, want)
+	}
+
+	for _, source := range result.Removed {
+		if _, err := os.Lstat(filepath.Join(buildDir, source)); !os.IsNotExist(err) {
+			t.Errorf("%s wasn't removed", source)
+		}
+	}
+	for _, kept := range []string{"6.18.9-x86_64/build-stats.json", "6.18.9-aarch64/vmlinux-6.18.9-aarch64.xz"} {
+		if _, err := os.Stat(filepath.Join(buildDir, kept)); err != nil {
+			t.Errorf("%s should be kept: %v", kept, err)
+		}
+	}
+	// Tarballs kept with --keep-tarball live outside build/
+	if _, err := os.Stat(cached); err != nil {
+		t.Errorf("cached source tarball should be kept: %v", err)
+	}
+
+	// Cleaning again finds nothing
+	result, err = CleanSources(paths)
+	if err != nil || len(result.Removed) != 0 || result.FreedBytes != 0 {
+		t.Errorf("CleanSources() of a clean tree = %+v, %v", result, err)
+	}
+	// and neither does cleaning before anything was built
+	result, err = CleanSources(config.PathsUnder(t.TempDir()))
+	if err != nil || len(result.Removed) != 0 || result.FreedBytes != 0 {
+		t.Errorf("CleanSources() without a build directory = %+v, %v", result, err)
+	}
+}
+
+func TestLinkOrCopyFile(t *testing.T) {
+	crossDevice := func(oldname, newname string) error {
+		return &os.LinkError{Op: "link", Old: oldname, New: newname, Err: syscall.EXDEV}
+	}
+	unsupported := func(oldname, newname string) error {
+		return &os.LinkError{Op: "link", Old: oldname, New: newname, Err: syscall.EPERM}
+	}
+
+	tests := []struct {
+		name       string
+		link       func(string, string) error
+		size       int
+		mode       os.FileMode
+		existing   bool
+		wantLinked bool
+	}{
+		{name: "hard link", link: os.Link, size: 4096, mode: 0o644, wantLinked: true},
+		{name: "hard link replaces existing dst", link: os.Link, size: 4096, mode: 0o644, existing: true, wantLinked: true},
+		{name: "cross-device copy", link: crossDevice, size: 2*copyBufferSize + 512, mode: 0o644},
+		{name: "cross-device copy preserves permissions", link: crossDevice, size: 100, mode: 0o750},
+		{name: "cross-device copy replaces existing dst", link: crossDevice, size: 100, mode: 0o600, existing: true},
+		{name: "unsupported link copies", link: unsupported, size: 100, mode: 0o640},
+		{name: "empty file", link: crossDevice, size: 0, mode: 0o644},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := hardLink
+			hardLink = tt.link
+			t.Cleanup(func() { hardLink = orig })
+
+			dir := t.TempDir()
+			src := filepath.Join(dir, "src")
+			dst := filepath.Join(dir, "dst")
+			data := make([]byte, tt.size)
+			for i := range data {
+				data[i] = byte(i % 251)
+			}
+			if err := os.WriteFile(src, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chmod(src, tt.mode); err != nil {
+				t.Fatal(err)
+			}
+
+			// dst shares an inode with an unrelated file; replacing dst must
+			// not write through to it
+			shared := filepath.Join(dir, "shared")
+			if tt.existing {
+				if err := os.WriteFile(shared, []byte("keep me"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Link(shared, dst); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var reported int64
+			var calls int
+			if err := linkOrCopyFileWithProgress(src, dst, func(n int64) {
+				reported += n
+				calls++
+			}); err != nil {
+				t.Fatalf("linkOrCopyFileWithProgress: %v", err)
+			}
+
+			got, err := os.ReadFile(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("dst content differs from src (%d vs %d bytes)", len(got), len(data))
+			}
+			if reported != int64(tt.size) {
+				t.Errorf("reported %d bytes, want %d", reported, tt.size)
+			}
+			if tt.wantLinked && calls != 1 {
+				t.Errorf("hard link reported progress %d times, want once", calls)
+			}
+
+			srcInfo, err := os.Stat(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dstInfo, err := os.Stat(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if linked := os.SameFile(srcInfo, dstInfo); linked != tt.wantLinked {
+				t.Errorf("dst linked to src = %v, want %v", linked, tt.wantLinked)
+			}
+			if perm := dstInfo.Mode().Perm(); perm != tt.mode {
+				t.Errorf("dst mode = %v, want %v", perm, tt.mode)
+			}
+
+			if tt.existing {
+				kept, err := os.ReadFile(shared)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(kept) != "keep me" {
+					t.Errorf("file sharing the old dst inode was overwritten: %q", kept)
+				}
+			}
+		})
+	}
+}
+
+func TestCopyFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     os.FileMode
+		existing []byte
+	}{
+		{name: "new dst", mode: 0o644},
+		{name: "preserves executable bit", mode: 0o755},
+		{name: "truncates longer existing dst", mode: 0o644, existing: bytes.Repeat([]byte("x"), 1024)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := filepath.Join(dir, "src")
+			dst := filepath.Join(dir, "dst")
+			if err := os.WriteFile(src, []byte("kernel"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chmod(src, tt.mode); err != nil {
+				t.Fatal(err)
+			}
+			if tt.existing != nil {
+				if err := os.WriteFile(dst, tt.existing, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := copyFile(src, dst); err != nil {
+				t.Fatalf("copyFile: %v", err)
+			}
+
+			got, err := os.ReadFile(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != "kernel" {
+				t.Errorf("dst = %q, want %q", got, "kernel")
+			}
+			info, err := os.Stat(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != tt.mode {
+				t.Errorf("dst mode = %v, want %v", info.Mode().Perm(), tt.mode)
+			}
+		})
+	}
+
+	t.Run("missing src leaves no dst", func(t *testing.T) {
+		dir := t.TempDir()
+		dst := filepath.Join(dir, "dst")
+		if err := copyFile(filepath.Join(dir, "missing"), dst); err == nil {
+			t.Fatal("copyFile succeeded with a missing src")
+		}
+		if _, err := os.Stat(dst); !os.IsNotExist(err) {
+			t.Errorf("dst exists after failed copy: %v", err)
+		}
+	})
+}
+
+func TestCountingWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   []int64
+	}{
+		{name: "no writes"},
+		{name: "single write", writes: []string{"vmlinux"}, want: []int64{7}},
+		{name: "empty write is not reported", writes: []string{"ab", "", "cde"}, want: []int64{2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			var got []int64
+			cw := &countingWriter{w: &buf, onBytes: func(n int64) { got = append(got, n) }}
+			for _, w := range tt.writes {
+				if _, err := cw.Write([]byte(w)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("reported %v, want %v", got, tt.want)
+			}
+			if buf.String() != strings.Join(tt.writes, "") {
+				t.Errorf("wrote %q, want %q", buf.String(), strings.Join(tt.writes, ""))
+			}
+		})
+	}
+}
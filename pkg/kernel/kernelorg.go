@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/download"
+)
+
+// kernelOrgReleasesURL lists kernel.org's current releases (a variable so
+// tests can point it at a local server)
+var kernelOrgReleasesURL = "https://www.kernel.org/releases.json"
+
+// APITimeoutError is returned when kernel.org doesn't answer an API request
+// within kernels.api-timeout
+type APITimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *APITimeoutError) Error() string {
+	return fmt.Sprintf("kernel.org didn't answer within %s; check your network or proxy, or raise kernels.api-timeout", e.Timeout)
+}
+
+// FetchReleasesJSON decodes kernel.org's releases.json into v. The request,
+// retries included, is limited to kernels.api-timeout, after which an
+// *APITimeoutError is returned; cancelling ctx aborts it with ctx's error.
+func FetchReleasesJSON(ctx context.Context, v any) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timeout := config.GetKernelsAPITimeout()
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fetchReleasesJSON(reqCtx, v)
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if reqCtx.Err() != nil || isTimeout(err) {
+		return &APITimeoutError{Timeout: timeout}
+	}
+	return err
+}
+
+// fetchReleasesJSON performs the request for FetchReleasesJSON
+func fetchReleasesJSON(ctx context.Context, v any) error {
+	resp, err := download.Get(ctx, kernelOrgReleasesURL, config.GetKernelsDownloadRetries())
+	if err != nil {
+		return fmt.Errorf("failed to fetch kernel.org API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("kernel.org API returned status: %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse kernel.org API response: %w", err)
+	}
+	return nil
+}
+
+// isTimeout reports whether err is a network timeout
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
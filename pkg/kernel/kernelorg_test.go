@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// stallingKernelOrg points kernelOrgReleasesURL at a server that doesn't
+// answer until the test ends
+func stallingKernelOrg(t *testing.T) {
+	t.Helper()
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(done) })
+
+	orig := kernelOrgReleasesURL
+	kernelOrgReleasesURL = srv.URL
+	t.Cleanup(func() { kernelOrgReleasesURL = orig })
+}
+
+func TestFetchReleasesJSONTimeout(t *testing.T) {
+	stallingKernelOrg(t)
+	viper.Set("kernels.api-timeout", "50ms")
+	viper.Set("kernels.download.retries", 0)
+	t.Cleanup(viper.Reset)
+
+	var data map[string]any
+	err := FetchReleasesJSON(context.Background(), &data)
+	var timeoutErr *APITimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("FetchReleasesJSON() error = %v, want an APITimeoutError", err)
+	}
+	if timeoutErr.Timeout != 50*time.Millisecond {
+		t.Errorf("APITimeoutError.Timeout = %s, want 50ms", timeoutErr.Timeout)
+	}
+
+	// A version that can't be checked in time is allowed
+	if err := ValidateVersion(context.Background(), "6.12.9"); err != nil {
+		t.Errorf("ValidateVersion() error = %v", err)
+	}
+}
+
+func TestFetchReleasesJSONCancelled(t *testing.T) {
+	stallingKernelOrg(t)
+	viper.Set("kernels.api-timeout", "1m")
+	t.Cleanup(viper.Reset)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	var data map[string]any
+	if err := FetchReleasesJSON(ctx, &data); !errors.Is(err, context.Canceled) {
+		t.Errorf("FetchReleasesJSON() error = %v, want context.Canceled", err)
+	}
+	if err := ValidateVersion(ctx, "6.12.9"); !errors.Is(err, context.Canceled) {
+		t.Errorf("ValidateVersion() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Work-Fort/Anvil/pkg/util"
+	"github.com/charmbracelet/log"
+)
+
+// ManifestSchemaVersion is the manifest format written by WriteManifest
+const ManifestSchemaVersion = 1
+
+// ManifestSidecar is the manifest of the most recent build in the artifacts
+// directory (a symlink to its manifest-<version>-<arch>.json), and the copy
+// kept next to an archived kernel
+const ManifestSidecar = "manifest.json"
+
+// Roles of the files listed in a manifest
+const (
+	ManifestRoleKernel           = "kernel"
+	ManifestRoleKernelCompressed = "kernel-compressed"
+	ManifestRoleConfig           = "config"
+	ManifestRoleModules          = "modules"
+	ManifestRoleChecksum         = "checksum"
+)
+
+// Manifest lists the artifacts produced by one build
+type Manifest struct {
+	SchemaVersion int            `json:"schema_version"`
+	KernelVersion string         `json:"kernel_version"`
+	Arch          string         `json:"arch"`
+	Files         []ManifestFile `json:"files"`
+}
+
+// ManifestFile is one artifact, named relative to the manifest's directory
+type ManifestFile struct {
+	Name   string `json:"name"`
+	Role   string `json:"role"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ManifestFileName returns the manifest file of a version and arch in the
+// artifacts directory
+func ManifestFileName(version, arch string) string {
+	return fmt.Sprintf("manifest-%s-%s.json", version, arch)
+}
+
+// WriteManifest writes the manifest of the build described by stats to
+// artifactsDir, listing the kernel, compressed kernel, config, modules
+// tarball (if built) and their checksum files, and points manifest.json at
+// it
+func WriteManifest(artifactsDir string, stats BuildStats) error {
+	manifest := Manifest{
+		SchemaVersion: ManifestSchemaVersion,
+		KernelVersion: stats.KernelVersion,
+		Arch:          stats.Arch,
+	}
+
+	type artifact struct{ path, role, hash string }
+	artifacts := []artifact{
+		{stats.OutputPath, ManifestRoleKernel, stats.UncompressedHash},
+		{stats.CompressedPath, ManifestRoleKernelCompressed, stats.CompressedHash},
+		{filepath.Join(artifactsDir, kernelConfigArtifactName(stats.KernelVersion, stats.Arch)), ManifestRoleConfig, ""},
+	}
+	if stats.ModulesPath != "" {
+		artifacts = append(artifacts, artifact{stats.ModulesPath, ManifestRoleModules, stats.ModulesHash})
+	}
+
+	for _, artifact := range artifacts {
+		file, err := manifestEntry(artifact.path, artifact.role, artifact.hash)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, file)
+
+		for _, algo := range util.ChecksumAlgorithms {
+			sumFile := artifact.path + "." + algo
+			if _, err := os.Stat(sumFile); err != nil {
+				continue
+			}
+			file, err := manifestEntry(sumFile, ManifestRoleChecksum, "")
+			if err != nil {
+				return err
+			}
+			manifest.Files = append(manifest.Files, file)
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	manifestFile := filepath.Join(artifactsDir, ManifestFileName(stats.KernelVersion, stats.Arch))
+	if err := writeArtifactFile(manifestFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return util.ReplaceSymlink(filepath.Base(manifestFile), filepath.Join(artifactsDir, ManifestSidecar))
+}
+
+// manifestEntry describes the artifact at path, hashing it unless its
+// SHA256 is already known
+func manifestEntry(path, role, hash string) (ManifestFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ManifestFile{}, fmt.Errorf("failed to read %s artifact: %w", role, err)
+	}
+	if hash == "" {
+		if hash, err = util.CalculateSHA256(path); err != nil {
+			return ManifestFile{}, fmt.Errorf("failed to hash %s: %w", filepath.Base(path), err)
+		}
+	}
+	return ManifestFile{Name: filepath.Base(path), Role: role, Size: info.Size(), SHA256: hash}, nil
+}
+
+// ReadManifest reads a manifest written by WriteManifest
+func ReadManifest(path string) (Manifest, error) {
+	var manifest Manifest
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return manifest, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("failed to unmarshal manifest: %w", err)
+	}
+	if manifest.SchemaVersion > ManifestSchemaVersion {
+		log.Warnf("Manifest %s uses schema version %d (this anvil supports up to %d); some fields may be ignored", path, manifest.SchemaVersion, ManifestSchemaVersion)
+	}
+	return manifest, nil
+}
+
+// buildManifest returns the manifest written for the build described by
+// stats, or false when there is none (builds from before manifests were
+// written) or it belongs to another build
+func buildManifest(stats BuildStats, arch string) (Manifest, bool) {
+	path := filepath.Join(filepath.Dir(stats.OutputPath), ManifestFileName(stats.KernelVersion, arch))
+	manifest, err := ReadManifest(path)
+	if err != nil || manifest.KernelVersion != stats.KernelVersion || manifest.Arch != arch {
+		return Manifest{}, false
+	}
+	// A later build of the version replaced the manifest
+	for _, file := range manifest.Files {
+		if file.Role == ManifestRoleKernelCompressed && (file.Name != filepath.Base(stats.CompressedPath) ||
+			(stats.CompressedHash != "" && file.SHA256 != stats.CompressedHash)) {
+			return Manifest{}, false
+		}
+	}
+	return manifest, true
+}
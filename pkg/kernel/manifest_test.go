@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+func TestWriteManifest(t *testing.T) {
+	artifactsDir := t.TempDir()
+	kernelPath := filepath.Join(artifactsDir, "vmlinux-6.18.9-x86_64")
+	files := map[string]string{
+		"vmlinux-6.18.9-x86_64":           "ELF",
+		"vmlinux-6.18.9-x86_64.xz":        "XZ",
+		"config-6.18.9-x86_64":            "CONFIG_VIRTIO=y\n",
+		"vmlinux-6.18.9-x86_64.sha256":    "sum\n",
+		"vmlinux-6.18.9-x86_64.xz.sha256": "sum\n",
+		"config-6.18.9-x86_64.sha256":     "sum\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(artifactsDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats := BuildStats{
+		KernelVersion:  "6.18.9",
+		Arch:           "x86_64",
+		OutputPath:     kernelPath,
+		CompressedPath: kernelPath + ".xz",
+	}
+	if err := WriteManifest(artifactsDir, stats); err != nil {
+		t.Fatalf("WriteManifest() error = %v", err)
+	}
+
+	// manifest.json follows the build's own manifest
+	manifest, err := ReadManifest(filepath.Join(artifactsDir, ManifestSidecar))
+	if err != nil {
+		t.Fatalf("ReadManifest() error = %v", err)
+	}
+	if manifest.KernelVersion != "6.18.9" || manifest.Arch != "x86_64" || manifest.SchemaVersion != ManifestSchemaVersion {
+		t.Errorf("manifest = %+v", manifest)
+	}
+
+	roles := map[string]string{}
+	for _, file := range manifest.Files {
+		roles[file.Name] = file.Role
+		want, err := util.CalculateSHA256(filepath.Join(artifactsDir, file.Name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if file.SHA256 != want || file.Size != int64(len(files[file.Name])) {
+			t.Errorf("%s: size %d sha256 %s, want %d %s", file.Name, file.Size, file.SHA256, len(files[file.Name]), want)
+		}
+	}
+	wantRoles := map[string]string{
+		"vmlinux-6.18.9-x86_64":           ManifestRoleKernel,
+		"vmlinux-6.18.9-x86_64.xz":        ManifestRoleKernelCompressed,
+		"config-6.18.9-x86_64":            ManifestRoleConfig,
+		"vmlinux-6.18.9-x86_64.sha256":    ManifestRoleChecksum,
+		"vmlinux-6.18.9-x86_64.xz.sha256": ManifestRoleChecksum,
+		"config-6.18.9-x86_64.sha256":     ManifestRoleChecksum,
+	}
+	if len(roles) != len(wantRoles) {
+		t.Errorf("manifest lists %v, want %v", roles, wantRoles)
+	}
+	for name, role := range wantRoles {
+		if roles[name] != role {
+			t.Errorf("%s has role %q, want %q", name, roles[name], role)
+		}
+	}
+
+	// Archiving copies what the manifest lists, and the manifest itself
+	archiveDir := t.TempDir()
+	if err := ArchiveInstalledKernel(stats, archiveDir); err != nil {
+		t.Fatalf("ArchiveInstalledKernel() error = %v", err)
+	}
+	versionDir := filepath.Join(archiveDir, "x86_64", "6.18.9")
+	for name := range wantRoles {
+		if _, err := os.Stat(filepath.Join(versionDir, name)); err != nil {
+			t.Errorf("%s wasn't archived: %v", name, err)
+		}
+	}
+	if _, err := ReadManifest(filepath.Join(versionDir, ManifestSidecar)); err != nil {
+		t.Errorf("archived manifest: %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/github"
+	"github.com/Work-Fort/Anvil/pkg/util"
+	"github.com/charmbracelet/log"
+)
+
+// DefaultMirrorVersions is the number of releases mirrored when none is specified
+const DefaultMirrorVersions = 5
+
+// MirrorOptions contains options for mirroring GitHub release kernels
+type MirrorOptions struct {
+	Dest             string        // Archive directory to mirror into
+	Versions         int           // Number of latest releases to mirror (default: DefaultMirrorVersions)
+	Arch             string        // x86_64, aarch64, or all (default: all)
+	SkipExisting     bool          // Skip versions already present in Dest
+	ProgressCallback func(float64) // Optional: callback for download progress (0.0 to 1.0)
+	StatusCallback   func(string)  // Optional: callback for status messages
+}
+
+// MirrorResult describes what a mirror run did
+type MirrorResult struct {
+	Mirrored []ArchiveEntry `json:"mirrored"`
+	Skipped  []ArchiveEntry `json:"skipped"`
+	// Dirs lists the arch/version directories written during this run
+	Dirs []string `json:"dirs"`
+}
+
+// Mirror downloads and verifies the latest GitHub release kernels and lays
+// them out in the archive structure (<arch>/<version>/...) under opts.Dest,
+// maintaining index.json. Each version directory keeps the release's
+// SHA256SUMS, SHA256SUMS.asc and signing-key.asc so the upstream signature
+// can still be checked from the mirror.
+func Mirror(opts MirrorOptions, client *github.Client, paths *config.Paths) (*MirrorResult, error) {
+	if opts.Dest == "" {
+		return nil, fmt.Errorf("mirror destination is required")
+	}
+	if opts.Versions <= 0 {
+		opts.Versions = DefaultMirrorVersions
+	}
+	if opts.Arch == "" {
+		opts.Arch = "all"
+	}
+
+	var archs []string
+	switch opts.Arch {
+	case "all":
+		archs = []string{"x86_64", "aarch64"}
+	case "x86_64", "aarch64":
+		archs = []string{opts.Arch}
+	default:
+		return nil, fmt.Errorf("unsupported architecture: %s (supported: x86_64, aarch64, all)", opts.Arch)
+	}
+
+	status := func(msg string) {
+		if opts.StatusCallback != nil {
+			opts.StatusCallback(msg)
+		}
+	}
+
+	perPage := opts.Versions
+	if perPage < 10 {
+		perPage = 10
+	}
+	if perPage > 100 {
+		perPage = 100
+	}
+
+	status("Fetching release list...")
+	parts := strings.Split(config.GitHubRepo, "/")
+	releases, err := client.GetReleases(parts[0], parts[1], perPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch kernel releases: %w", err)
+	}
+	releases = github.SortReleasesBySemver(releases)
+	if len(releases) > opts.Versions {
+		releases = releases[:opts.Versions]
+	}
+
+	if err := os.MkdirAll(opts.Dest, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create mirror directory: %w", err)
+	}
+
+	result := &MirrorResult{
+		Mirrored: []ArchiveEntry{},
+		Skipped:  []ArchiveEntry{},
+		Dirs:     []string{},
+	}
+
+	for _, release := range releases {
+		version := github.StripVersionPrefix(release.TagName)
+
+		for _, arch := range archs {
+			kernelName, err := config.GetKernelNameForArch(arch)
+			if err != nil {
+				return nil, err
+			}
+			filename := releaseKernelAsset(release, kernelName, version, arch)
+			if filename == "" {
+				log.Debugf("Release %s has no %s-%s-%s asset, skipping", version, kernelName, version, arch)
+				continue
+			}
+
+			entry := ArchiveEntry{
+				Arch:    arch,
+				Version: version,
+				Path:    filepath.Join(arch, version, filename),
+			}
+
+			versionDir := filepath.Join(opts.Dest, arch, version)
+			if opts.SkipExisting {
+				if _, err := os.Stat(filepath.Join(versionDir, filename)); err == nil {
+					result.Skipped = append(result.Skipped, entry)
+					continue
+				}
+			}
+
+			status(fmt.Sprintf("Mirroring %s (%s)...", version, arch))
+			if err := mirrorRelease(client, version, filename, versionDir, paths, opts.ProgressCallback, opts.StatusCallback); err != nil {
+				return result, fmt.Errorf("failed to mirror %s (%s): %w", version, arch, err)
+			}
+
+			if err := updateArchiveIndex(opts.Dest, arch, version, entry.Path); err != nil {
+				return result, err
+			}
+
+			result.Mirrored = append(result.Mirrored, entry)
+			result.Dirs = append(result.Dirs, versionDir)
+		}
+	}
+
+	return result, nil
+}
+
+// mirrorRelease downloads and verifies one release asset and writes it, its
+// decompressed kernel and checksum files into versionDir.
+func mirrorRelease(client *github.Client, version, filename, versionDir string, paths *config.Paths, progressCallback func(float64), statusCallback func(string)) error {
+	workDir, err := os.MkdirTemp(paths.CacheDir, "mirror-")
+	if err != nil {
+		return fmt.Errorf("failed to create work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	files, err := fetchVerifiedRelease(client, version, filename, workDir, progressCallback, statusCallback)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	compressedPath := filepath.Join(versionDir, filename)
+	kernelPath := util.TrimCompressionExt(compressedPath)
+
+	if statusCallback != nil {
+		statusCallback("Decompressing kernel...")
+	}
+	if err := util.DecompressWithProgress(files.asset, kernelPath, progressCallback); err != nil {
+		return fmt.Errorf("failed to decompress kernel: %w", err)
+	}
+	if err := util.VerifySHA256File(kernelPath, files.checksums); err != nil {
+		os.Remove(kernelPath)
+		return fmt.Errorf("decompressed kernel checksum verification failed: %w", err)
+	}
+
+	type srcDst struct{ src, dst string }
+	copies := []srcDst{
+		{files.asset, compressedPath},
+		{files.checksums, filepath.Join(versionDir, "SHA256SUMS")},
+		{files.signature, filepath.Join(versionDir, "SHA256SUMS.asc")},
+		{files.key, filepath.Join(versionDir, "signing-key.asc")},
+	}
+	for _, c := range copies {
+		if err := linkOrCopyFile(c.src, c.dst); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", filepath.Base(c.dst), err)
+		}
+	}
+
+	// Per-file checksums, matching the layout written by ArchiveInstalledKernel
+	for _, path := range []string{kernelPath, compressedPath} {
+		hash, err := util.CalculateSHA256(path)
+		if err != nil {
+			return fmt.Errorf("failed to calculate checksum: %w", err)
+		}
+		line := fmt.Sprintf("%s  %s\n", hash, filepath.Base(path))
+		if err := os.WriteFile(path+".sha256", []byte(line), 0644); err != nil {
+			return fmt.Errorf("failed to write checksum file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// PrepareMirrorForSigning replaces the release SHA256SUMS in a mirrored
+// version directory with one generated from its own artifacts, so it can be
+// re-signed with the local key.
+func PrepareMirrorForSigning(versionDir string) error {
+	return generateChecksumSums(versionDir)
+}
+
+// releaseHasAsset reports whether a release contains an asset with the given name
+func releaseHasAsset(release github.Release, name string) bool {
+	for _, asset := range release.Assets {
+		if asset.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseKernelAsset returns the name of the compressed kernel asset for
+// kernelName-version-arch in release, preferring xz over zstd, or "" when
+// the release has neither
+func releaseKernelAsset(release github.Release, kernelName, version, arch string) string {
+	for _, format := range util.CompressionFormats {
+		name := fmt.Sprintf("%s-%s-%s%s", kernelName, version, arch, util.CompressionExt(format))
+		if releaseHasAsset(release, name) {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,227 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/gopenpgp/v3/crypto"
+	"github.com/ProtonMail/gopenpgp/v3/profile"
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/github"
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+// releaseServer serves signed x86_64 kernel releases of each version, both
+// the API's release list and the release assets, and returns a client for
+// it. Assets in tampered are served with their contents replaced.
+func releaseServer(t *testing.T, tampered map[string]bool, versions ...string) *github.Client {
+	t.Helper()
+	key, err := crypto.PGPWithProfile(profile.RFC4880()).KeyGeneration().
+		AddUserId("Test Kernels", "test@example.com").
+		New().GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := key.ToPublic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	armoredKey, err := public.Armor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := crypto.PGPWithProfile(profile.RFC4880()).Sign().SigningKey(key).Detached().New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assets := map[string][]byte{} // by URL path
+	var releases []github.Release
+	for _, version := range versions {
+		dir := t.TempDir()
+		name := "vmlinux-" + version + "-x86_64"
+		kernel := filepath.Join(dir, name)
+		if err := os.WriteFile(kernel, []byte("kernel "+version), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := util.CompressWithProgress(util.CompressionXZ, kernel, kernel+".xz", nil); err != nil {
+			t.Fatal(err)
+		}
+		var sums strings.Builder
+		for _, path := range []string{kernel, kernel + ".xz"} {
+			hash, err := util.CalculateSHA256(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fmt.Fprintf(&sums, "%s  %s\n", hash, filepath.Base(path))
+		}
+		signature, err := signer.Sign([]byte(sums.String()), crypto.Armor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		compressed, err := os.ReadFile(kernel + ".xz")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tampered[version] {
+			compressed = []byte("tampered")
+		}
+
+		prefix := fmt.Sprintf("/%s/releases/download/v%s/", config.GitHubRepo, version)
+		assets[prefix+name+".xz"] = compressed
+		assets[prefix+"SHA256SUMS"] = []byte(sums.String())
+		assets[prefix+"SHA256SUMS.asc"] = signature
+		assets[prefix+"signing-key.asc"] = []byte(armoredKey)
+
+		release := github.Release{TagName: "v" + version}
+		for _, asset := range []string{name + ".xz", "SHA256SUMS", "SHA256SUMS.asc", "signing-key.asc"} {
+			release.Assets = append(release.Assets, github.Asset{Name: asset})
+		}
+		releases = append(releases, release)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == fmt.Sprintf("/repos/%s/releases", config.GitHubRepo) {
+			json.NewEncoder(w).Encode(releases)
+			return
+		}
+		data, ok := assets[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+
+	orig := releaseDownloadURL
+	releaseDownloadURL = srv.URL
+	t.Cleanup(func() { releaseDownloadURL = orig })
+
+	return github.NewClient("", srv.URL)
+}
+
+// mirrorPaths returns paths under a temporary directory with the cache
+// directory mirroring works in
+func mirrorPaths(t *testing.T) *config.Paths {
+	t.Helper()
+	paths := config.PathsUnder(t.TempDir())
+	if err := os.MkdirAll(paths.CacheDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return paths
+}
+
+func TestMirror(t *testing.T) {
+	client := releaseServer(t, nil, "6.1.0", "6.12.9", "6.18.9")
+	paths := mirrorPaths(t)
+	dest := filepath.Join(t.TempDir(), "mirror")
+
+	// Only the newest releases are mirrored, and releases without an
+	// aarch64 kernel are mirrored for x86_64 alone
+	result, err := Mirror(MirrorOptions{Dest: dest, Versions: 2}, client, paths)
+	if err != nil {
+		t.Fatalf("Mirror() error = %v", err)
+	}
+	if got := archiveVersions(result.Mirrored); got != "6.18.9 6.12.9" || len(result.Skipped) != 0 {
+		t.Errorf("Mirror() mirrored %q skipped %v, want 6.18.9 6.12.9", got, result.Skipped)
+	}
+	versionDir := filepath.Join(dest, "x86_64", "6.18.9")
+	if len(result.Dirs) != 2 || result.Dirs[0] != versionDir {
+		t.Errorf("Mirror() dirs = %v", result.Dirs)
+	}
+
+	// The release's verification material is kept next to the kernel
+	for _, name := range []string{
+		"vmlinux-6.18.9-x86_64", "vmlinux-6.18.9-x86_64.xz",
+		"vmlinux-6.18.9-x86_64.sha256", "vmlinux-6.18.9-x86_64.xz.sha256",
+		"SHA256SUMS", "SHA256SUMS.asc", "signing-key.asc",
+	} {
+		if _, err := os.Stat(filepath.Join(versionDir, name)); err != nil {
+			t.Errorf("%s wasn't mirrored: %v", name, err)
+		}
+	}
+	if err := util.VerifySHA256File(filepath.Join(versionDir, "vmlinux-6.18.9-x86_64"), filepath.Join(versionDir, "SHA256SUMS")); err != nil {
+		t.Errorf("mirrored kernel doesn't match the release checksums: %v", err)
+	}
+
+	// and the mirror is an archive anvil can install from
+	if err := downloadFromArchive(dest, "6.12.9", "x86_64", paths); err != nil {
+		t.Fatalf("downloadFromArchive() from the mirror error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(paths.KernelsDir, "6.12.9", "vmlinux-6.12.9-x86_64"))
+	if err != nil || string(data) != "kernel 6.12.9" {
+		t.Errorf("kernel installed from the mirror = %q, %v", data, err)
+	}
+
+	// Versions already in the mirror are skipped
+	result, err = Mirror(MirrorOptions{Dest: dest, Versions: 3, Arch: "x86_64", SkipExisting: true}, client, paths)
+	if err != nil {
+		t.Fatalf("Mirror() error = %v", err)
+	}
+	if got := archiveVersions(result.Mirrored); got != "6.1.0" {
+		t.Errorf("Mirror() mirrored %q, want 6.1.0", got)
+	}
+	if got := archiveVersions(result.Skipped); got != "6.18.9 6.12.9" {
+		t.Errorf("Mirror() skipped %q, want 6.18.9 6.12.9", got)
+	}
+}
+
+func TestMirrorVerificationFailure(t *testing.T) {
+	client := releaseServer(t, map[string]bool{"6.12.9": true}, "6.12.9", "6.18.9")
+	dest := filepath.Join(t.TempDir(), "mirror")
+
+	result, err := Mirror(MirrorOptions{Dest: dest, Arch: "x86_64"}, client, mirrorPaths(t))
+	if err == nil || !strings.Contains(err.Error(), "failed to mirror 6.12.9 (x86_64)") || !strings.Contains(err.Error(), "checksum verification failed") {
+		t.Fatalf("Mirror() error = %v, want a checksum failure for 6.12.9", err)
+	}
+	// Versions mirrored before the failure are kept, the tampered one isn't
+	if got := archiveVersions(result.Mirrored); got != "6.18.9" {
+		t.Errorf("Mirror() mirrored %q before failing, want 6.18.9", got)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "x86_64", "6.12.9")); !os.IsNotExist(err) {
+		t.Error("tampered release was written to the mirror")
+	}
+	data, err := os.ReadFile(filepath.Join(dest, "index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "6.12.9") {
+		t.Errorf("tampered release was indexed:\n%s", data)
+	}
+}
+
+func TestMirrorOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts MirrorOptions
+		want string
+	}{
+		{"no destination", MirrorOptions{}, "mirror destination is required"},
+		{"unsupported arch", MirrorOptions{Dest: t.TempDir(), Arch: "riscv64"}, "unsupported architecture: riscv64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Mirror(tt.opts, github.NewClient("", "http://127.0.0.1:0"), mirrorPaths(t))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Mirror() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// archiveVersions joins the versions of entries, in order
+func archiveVersions(entries []ArchiveEntry) string {
+	versions := make([]string, len(entries))
+	for i, entry := range entries {
+		versions[i] = entry.Version
+	}
+	return strings.Join(versions, " ")
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/Work-Fort/Anvil/pkg/kconfig"
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+// modulesArtifactName returns the modules tarball name for a build
+func modulesArtifactName(version, arch string) string {
+	return fmt.Sprintf("modules-%s-%s.tar.xz", version, arch)
+}
+
+// modulesStagingDir is where modules_install puts the modules tree before
+// it is packaged, next to the source tree in the build directory
+func modulesStagingDir(kernelSrcDir, arch string) string {
+	return filepath.Join(filepath.Dir(kernelSrcDir), "modules-"+arch)
+}
+
+// buildModules compiles the kernel's modules and installs them with
+// make modules_install into the staging directory
+func buildModules(logger *buildLogger, opts BuildOptions, kernelSrcDir string, ctx context.Context) error {
+	cfg, err := kconfig.ParseFile(filepath.Join(kernelSrcDir, ".config"))
+	if err != nil {
+		return fmt.Errorf("failed to read kernel config: %w", err)
+	}
+	if value, _ := cfg.Get("MODULES"); value != "y" {
+		return fmt.Errorf("module build requested but the kernel config doesn't enable CONFIG_MODULES")
+	}
+
+	logger.Info("Building kernel modules...")
+	args, err := compileTargetArgs(opts, "modules")
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("make", args...)
+	cmd.Dir = kernelSrcDir
+	cmd.Stdout = logger.writer
+	cmd.Stderr = logger.writer
+	if err := runCommandWithProcessGroup(ctx, cmd); err != nil {
+		if ctx != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("module build failed: %w", err)
+	}
+
+	staging := modulesStagingDir(kernelSrcDir, opts.Arch)
+	if err := os.RemoveAll(staging); err != nil {
+		return fmt.Errorf("failed to clear module staging directory: %w", err)
+	}
+	logger.Info("Installing kernel modules...")
+	installArgs := append([]string{"modules_install", "INSTALL_MOD_PATH=" + staging}, compileMakeArgs(opts.Arch, opts.Toolchain)...)
+	cmd = exec.Command("make", installArgs...)
+	cmd.Dir = kernelSrcDir
+	cmd.Stdout = logger.writer
+	cmd.Stderr = logger.writer
+	if err := runCommandWithProcessGroup(ctx, cmd); err != nil {
+		if ctx != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("module install failed: %w", err)
+	}
+
+	logger.Info("Kernel modules built successfully")
+	return nil
+}
+
+// packageModules tars the staged lib/modules/<release> tree into
+// artifactsDir and writes its checksums. The build and source symlinks
+// point into the build host's source tree, so they are left out.
+func packageModules(logger *buildLogger, opts BuildOptions, version, kernelSrcDir, artifactsDir string) error {
+	staging := modulesStagingDir(kernelSrcDir, opts.Arch)
+	releases, err := filepath.Glob(filepath.Join(staging, "lib", "modules", "*"))
+	if err != nil {
+		return err
+	}
+	if len(releases) != 1 {
+		return fmt.Errorf("expected one kernel release in %s, found %d", filepath.Join(staging, "lib", "modules"), len(releases))
+	}
+	release := filepath.Base(releases[0])
+
+	logger.Info(fmt.Sprintf("Packaging kernel modules for %s...", release))
+	tarball := filepath.Join(artifactsDir, modulesArtifactName(version, opts.Arch))
+	if err := removeArtifactWithChecksums(tarball); err != nil {
+		return fmt.Errorf("failed to remove previous modules tarball: %w", err)
+	}
+	skip := func(rel string) bool { return rel == "build" || rel == "source" }
+	if err := util.CreateTarXz(releases[0], filepath.Join("lib", "modules", release), tarball, skip); err != nil {
+		return fmt.Errorf("failed to package kernel modules: %w", err)
+	}
+	if err := writeArtifactChecksums(logger, opts, tarball, "kernel modules"); err != nil {
+		return err
+	}
+
+	return os.RemoveAll(staging)
+}
+
+// removeArtifactWithChecksums removes an artifact and its per-file checksums
+func removeArtifactWithChecksums(path string) error {
+	paths := []string{path}
+	for _, algo := range util.ChecksumAlgorithms {
+		paths = append(paths, path+"."+algo)
+	}
+	for _, p := range paths {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// addModulesStats records the modules tarball next to kernelPath in stats
+func addModulesStats(stats *BuildStats, kernelPath, version, arch string) {
+	tarball := filepath.Join(filepath.Dir(kernelPath), modulesArtifactName(version, arch))
+	info, err := os.Stat(tarball)
+	if err != nil {
+		return
+	}
+	stats.ModulesPath = tarball
+	stats.ModulesSize = info.Size()
+	stats.ModulesHash, _ = util.CalculateSHA256(tarball)
+}
@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+// patchesMarker records, inside an extracted source tree, which patches
+// were applied to it so a build with different patches re-extracts
+const patchesMarker = ".anvil-patches"
+
+// resolvePatches checks the patch files of a build up front so a missing
+// patch fails before any work is done, and makes their paths absolute
+// (patches are applied from inside the source tree)
+func resolvePatches(opts *BuildOptions) error {
+	if len(opts.Patches) == 0 {
+		return nil
+	}
+	if _, err := exec.LookPath("patch"); err != nil {
+		return fmt.Errorf("patch not found. Please install patch to apply kernel patches")
+	}
+
+	patches := make([]string, 0, len(opts.Patches))
+	for _, p := range opts.Patches {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return fmt.Errorf("invalid patch path %s: %w", p, err)
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("patch not found: %s", abs)
+			}
+			return fmt.Errorf("cannot read patch: %w", err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("patch is a directory: %s", abs)
+		}
+		patches = append(patches, abs)
+	}
+	opts.Patches = patches
+
+	return nil
+}
+
+// patchHashes returns one "<sha256>  <path>" entry per patch, in the order
+// they are applied
+func patchHashes(patches []string) ([]string, error) {
+	var hashes []string
+	for _, p := range patches {
+		hash, err := util.CalculateSHA256(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash patch %s: %w", filepath.Base(p), err)
+		}
+		hashes = append(hashes, fmt.Sprintf("%s  %s", hash, p))
+	}
+	return hashes, nil
+}
+
+// patchesRecord returns the marker contents for patches: their
+// patchHashes, one per line
+func patchesRecord(patches []string) (string, error) {
+	hashes, err := patchHashes(patches)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, h := range hashes {
+		b.WriteString(h + "\n")
+	}
+	return b.String(), nil
+}
+
+// BuildStatsMatchPatches reports whether the build described by stats
+// applied the same patch contents, in the same order, as patches (none for
+// an unpatched build). Only the contents count, so the same patch given by
+// another path matches.
+func BuildStatsMatchPatches(stats BuildStats, patches []string) bool {
+	hashes, err := patchHashes(patches)
+	if err != nil || len(hashes) != len(stats.Patches) {
+		return false
+	}
+	for i := range hashes {
+		if patchHash(hashes[i]) != patchHash(stats.Patches[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// patchHash returns the SHA256 of a patchHashes entry
+func patchHash(entry string) string {
+	hash, _, _ := strings.Cut(entry, " ")
+	return hash
+}
+
+// cachedBuildMatchesPatches reports whether the stored build of version and
+// arch applied patches. A build without stats is taken as unpatched.
+func cachedBuildMatchesPatches(version, arch string, patches []string, paths *config.Paths) bool {
+	statsFile := cachedBuildStatsFile(version, arch, paths)
+	if statsFile == "" {
+		return len(patches) == 0
+	}
+	stats, err := ReadBuildStats(statsFile)
+	if err != nil {
+		return len(patches) == 0
+	}
+	return BuildStatsMatchPatches(stats, patches)
+}
+
+// sourceTreeMatchesPatches reports whether the extracted tree at
+// kernelSrcDir has exactly patches applied (none for an unpatched tree)
+func sourceTreeMatchesPatches(kernelSrcDir string, patches []string) bool {
+	want, err := patchesRecord(patches)
+	if err != nil {
+		return false
+	}
+	got, err := os.ReadFile(filepath.Join(kernelSrcDir, patchesMarker))
+	if os.IsNotExist(err) {
+		return want == ""
+	}
+	return err == nil && string(got) == want
+}
+
+// applyPatches applies patches in order with patch -p1 inside the freshly
+// extracted kernelSrcDir, streaming patch's output through the logger. The
+// first patch that doesn't apply fails the build with its name and output.
+func applyPatches(logger *buildLogger, patches []string, kernelSrcDir string, ctx context.Context) error {
+	if len(patches) == 0 {
+		return nil
+	}
+
+	record, err := patchesRecord(patches)
+	if err != nil {
+		return err
+	}
+
+	for i, p := range patches {
+		logger.Info(fmt.Sprintf("Applying patch %d/%d: %s", i+1, len(patches), filepath.Base(p)))
+
+		cmd := exec.Command("patch", "-p1", "--batch", "--forward", "-i", p)
+		cmd.Dir = kernelSrcDir
+		output := &tailBuffer{limit: compileOutputTail}
+		cmd.Stdout = io.MultiWriter(logger.writer, output)
+		cmd.Stderr = io.MultiWriter(logger.writer, output)
+
+		if err := runCommandWithProcessGroup(ctx, cmd); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to apply patch %s: %w\n%s", filepath.Base(p), err, strings.TrimSpace(output.String()))
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(kernelSrcDir, patchesMarker), []byte(record), 0644); err != nil {
+		return fmt.Errorf("failed to record applied patches: %w", err)
+	}
+	logger.Info(fmt.Sprintf("Applied %d patch(es)", len(patches)))
+
+	return nil
+}
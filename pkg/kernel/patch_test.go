@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+)
+
+const testPatch = `--- a/Makefile
++++ b/Makefile
+@@ -1,2 +1,2 @@
+ VERSION = 6
+-PATCHLEVEL = 18
++PATCHLEVEL = 19
+`
+
+// patchFixture writes a one-file source tree and a patch for it, returning
+// the tree and the patch path
+func patchFixture(t *testing.T, patch string) (string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("patch"); err != nil {
+		t.Skip("patch not installed")
+	}
+	dir := t.TempDir()
+	tree := filepath.Join(dir, "linux-6.18.9")
+	if err := os.MkdirAll(tree, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tree, "Makefile"), []byte("VERSION = 6\nPATCHLEVEL = 18\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	patchFile := filepath.Join(dir, "0001-bump.patch")
+	if err := os.WriteFile(patchFile, []byte(patch), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return tree, patchFile
+}
+
+func TestResolvePatches(t *testing.T) {
+	_, patchFile := patchFixture(t, testPatch)
+
+	// Relative paths become absolute, since patches run inside the tree
+	t.Chdir(filepath.Dir(patchFile))
+	opts := BuildOptions{Patches: []string{filepath.Base(patchFile)}}
+	if err := resolvePatches(&opts); err != nil {
+		t.Fatalf("resolvePatches() error = %v", err)
+	}
+	if len(opts.Patches) != 1 || opts.Patches[0] != patchFile {
+		t.Errorf("resolvePatches() patches = %v, want [%s]", opts.Patches, patchFile)
+	}
+
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{"missing", filepath.Join(t.TempDir(), "missing.patch"), "patch not found"},
+		{"directory", t.TempDir(), "patch is a directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := BuildOptions{Patches: []string{tt.patch}}
+			err := resolvePatches(&opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("resolvePatches() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyPatches(t *testing.T) {
+	tree, patchFile := patchFixture(t, testPatch)
+	logger := &buildLogger{writer: io.Discard}
+
+	// An unpatched tree matches only an empty patch set
+	if !sourceTreeMatchesPatches(tree, nil) {
+		t.Error("unpatched tree should match no patches")
+	}
+	if sourceTreeMatchesPatches(tree, []string{patchFile}) {
+		t.Error("unpatched tree shouldn't match a patch set")
+	}
+
+	if err := applyPatches(logger, []string{patchFile}, tree, context.Background()); err != nil {
+		t.Fatalf("applyPatches() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(tree, "Makefile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "PATCHLEVEL = 19") {
+		t.Errorf("Makefile wasn't patched:\n%s", data)
+	}
+
+	if !sourceTreeMatchesPatches(tree, []string{patchFile}) {
+		t.Error("patched tree should match its patch set")
+	}
+	if sourceTreeMatchesPatches(tree, nil) {
+		t.Error("patched tree shouldn't match an empty patch set")
+	}
+
+	// Changing the patch's contents invalidates the tree
+	if err := os.WriteFile(patchFile, []byte(testPatch+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if sourceTreeMatchesPatches(tree, []string{patchFile}) {
+		t.Error("tree shouldn't match a patch whose contents changed")
+	}
+}
+
+func TestApplyPatchesFailure(t *testing.T) {
+	tree, patchFile := patchFixture(t, strings.ReplaceAll(testPatch, "PATCHLEVEL = 18", "PATCHLEVEL = 12"))
+	logger := &buildLogger{writer: io.Discard}
+
+	err := applyPatches(logger, []string{patchFile}, tree, context.Background())
+	if err == nil || !strings.Contains(err.Error(), "failed to apply patch 0001-bump.patch") {
+		t.Fatalf("applyPatches() error = %v, want a failure naming the patch", err)
+	}
+	if _, err := os.Stat(filepath.Join(tree, patchesMarker)); !os.IsNotExist(err) {
+		t.Error("a failed patch shouldn't be recorded as applied")
+	}
+}
+
+func TestCachedBuildMatchesPatches(t *testing.T) {
+	_, patchFile := patchFixture(t, testPatch)
+	paths := config.PathsUnder(t.TempDir())
+	artifactsDir := filepath.Join(paths.KernelBuildDir, "artifacts")
+	if err := os.MkdirAll(artifactsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without stats the build is taken as unpatched
+	if !cachedBuildMatchesPatches("6.18.9", "x86_64", nil, paths) {
+		t.Error("build without stats should match no patches")
+	}
+	if cachedBuildMatchesPatches("6.18.9", "x86_64", []string{patchFile}, paths) {
+		t.Error("build without stats shouldn't match a patch set")
+	}
+
+	hashes, err := patchHashes([]string{patchFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := BuildStats{KernelVersion: "6.18.9", Arch: "x86_64", Patches: hashes}
+	if err := writeBuildStats(filepath.Join(artifactsDir, BuildStatsFile("6.18.9", "x86_64")), stats); err != nil {
+		t.Fatal(err)
+	}
+	if !cachedBuildMatchesPatches("6.18.9", "x86_64", []string{patchFile}, paths) {
+		t.Error("patched build should match its patch set")
+	}
+	if cachedBuildMatchesPatches("6.18.9", "x86_64", nil, paths) {
+		t.Error("patched build shouldn't match an unpatched build")
+	}
+
+	// The same patch found by another path still matches
+	copied := filepath.Join(t.TempDir(), "backport.patch")
+	if err := os.WriteFile(copied, []byte(testPatch), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !BuildStatsMatchPatches(stats, []string{copied}) {
+		t.Error("a copy of the applied patch should match")
+	}
+}
@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/charmbracelet/log"
+)
+
+// installTimestampLayout is the suffix InstallBuiltKernel adds to version
+// directories (version-YYYYMMDDTHHmmss)
+const installTimestampLayout = "20060102T150405"
+
+// installedKernel is a kernel version directory with its install time
+type installedKernel struct {
+	version   string
+	installed time.Time
+}
+
+// PruneCandidates returns the installed kernel versions Prune would remove,
+// oldest last. A version is kept if it is one of the keepLatest newest, or
+// newer than olderThan; a zero value disables that rule, and at least one
+// must be set. The default kernel of every architecture is always kept.
+func PruneCandidates(keepLatest int, olderThan time.Duration, paths *config.Paths) ([]string, error) {
+	return pruneCandidates(keepLatest, olderThan, paths, time.Now())
+}
+
+// Prune removes the versions selected by PruneCandidates and returns them
+func Prune(keepLatest int, olderThan time.Duration, paths *config.Paths) ([]string, error) {
+	candidates, err := PruneCandidates(keepLatest, olderThan, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := []string{}
+	for _, version := range candidates {
+		log.Debugf("Pruning kernel %s", version)
+		if err := os.RemoveAll(filepath.Join(paths.KernelsDir, version)); err != nil {
+			return removed, fmt.Errorf("failed to remove kernel %s: %w", version, err)
+		}
+		removed = append(removed, version)
+	}
+	return removed, nil
+}
+
+func pruneCandidates(keepLatest int, olderThan time.Duration, paths *config.Paths, now time.Time) ([]string, error) {
+	if keepLatest < 0 || olderThan < 0 {
+		return nil, fmt.Errorf("keep-latest and older-than must not be negative")
+	}
+	if keepLatest == 0 && olderThan == 0 {
+		return nil, fmt.Errorf("set keep-latest, older-than or both")
+	}
+
+	kernels, err := installedKernels(paths)
+	if err != nil {
+		return nil, err
+	}
+	defaults := defaultKernelVersions(paths)
+
+	candidates := []string{}
+	for i, k := range kernels {
+		switch {
+		case defaults[k.version]:
+			continue
+		case keepLatest > 0 && i < keepLatest:
+			continue
+		case olderThan > 0 && now.Sub(k.installed) < olderThan:
+			continue
+		}
+		candidates = append(candidates, k.version)
+	}
+	return candidates, nil
+}
+
+// installedKernels lists the kernel version directories, newest first. The
+// install time comes from the directory name's timestamp, or the directory's
+// modification time for downloaded kernels, whose names have none.
+func installedKernels(paths *config.Paths) ([]installedKernel, error) {
+	entries, err := os.ReadDir(paths.KernelsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read kernels directory: %w", err)
+	}
+
+	var kernels []installedKernel
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == "default" {
+			continue
+		}
+		installed, ok := parseInstallTimestamp(entry.Name())
+		if !ok {
+			info, err := entry.Info()
+			if err != nil {
+				return nil, fmt.Errorf("failed to stat %s: %w", entry.Name(), err)
+			}
+			installed = info.ModTime()
+		}
+		kernels = append(kernels, installedKernel{version: entry.Name(), installed: installed})
+	}
+
+	slices.SortStableFunc(kernels, func(a, b installedKernel) int {
+		return b.installed.Compare(a.installed)
+	})
+	return kernels, nil
+}
+
+// parseInstallTimestamp reads the timestamp from a "version-YYYYMMDDTHHmmss"
+// directory name
+func parseInstallTimestamp(name string) (time.Time, bool) {
+	i := strings.LastIndex(name, "-")
+	if i < 0 {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(installTimestampLayout, name[i+1:], time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// defaultKernelVersions returns the versions the default kernel symlinks
+// (one per architecture) point into
+func defaultKernelVersions(paths *config.Paths) map[string]bool {
+	defaults := make(map[string]bool)
+	for _, arch := range []string{"x86_64", "aarch64"} {
+		kernelName, err := config.GetKernelNameForArch(arch)
+		if err != nil {
+			continue
+		}
+		target, err := os.Readlink(filepath.Join(paths.DataDir, kernelName))
+		if err != nil {
+			continue
+		}
+		parts := strings.Split(target, "/")
+		for i, part := range parts {
+			if part == "kernels" && i+1 < len(parts) {
+				defaults[parts[i+1]] = true
+				break
+			}
+		}
+	}
+	return defaults
+}
+
+// ParseAge parses an age such as "30d", "2w" or any time.ParseDuration
+// value ("36h")
+func ParseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q (use e.g. 30d, 2w or 12h)", s)
+	}
+	return d, nil
+}
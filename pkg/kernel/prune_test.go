@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+)
+
+// setupInstalledKernels creates kernel directories named like
+// InstallBuiltKernel's, installed daysAgo days before now, and points the
+// x86_64 default symlink at defaultVersion
+func setupInstalledKernels(t *testing.T, now time.Time, daysAgo map[string]int, defaultVersion string) *config.Paths {
+	t.Helper()
+	dir := t.TempDir()
+	paths := &config.Paths{DataDir: dir, KernelsDir: filepath.Join(dir, "kernels")}
+	for version, days := range daysAgo {
+		name := version + "-" + now.AddDate(0, 0, -days).Format(installTimestampLayout)
+		if err := os.MkdirAll(filepath.Join(paths.KernelsDir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if defaultVersion != "" {
+		name := defaultVersion + "-" + now.AddDate(0, 0, -daysAgo[defaultVersion]).Format(installTimestampLayout)
+		target := filepath.Join(paths.KernelsDir, name, "vmlinux-"+name+"-x86_64")
+		if err := os.Symlink(target, filepath.Join(dir, "vmlinux")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return paths
+}
+
+func TestPruneCandidates(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	daysAgo := map[string]int{"6.18.9": 1, "6.18.8": 10, "6.18.7": 40, "6.12.60": 60, "6.6.100": 90}
+	stamp := func(version string) string {
+		return version + "-" + now.AddDate(0, 0, -daysAgo[version]).Format(installTimestampLayout)
+	}
+
+	tests := []struct {
+		name       string
+		keepLatest int
+		olderThan  time.Duration
+		def        string
+		want       []string
+	}{
+		{name: "keep latest", keepLatest: 3, want: []string{stamp("6.12.60"), stamp("6.6.100")}},
+		{name: "older than", olderThan: 30 * 24 * time.Hour, want: []string{stamp("6.18.7"), stamp("6.12.60"), stamp("6.6.100")}},
+		{name: "either rule keeps", keepLatest: 1, olderThan: 50 * 24 * time.Hour, want: []string{stamp("6.12.60"), stamp("6.6.100")}},
+		{name: "default is kept", keepLatest: 1, def: "6.6.100", want: []string{stamp("6.18.8"), stamp("6.18.7"), stamp("6.12.60")}},
+		{name: "nothing to prune", keepLatest: 10, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := setupInstalledKernels(t, now, daysAgo, tt.def)
+			got, err := pruneCandidates(tt.keepLatest, tt.olderThan, paths, now)
+			if err != nil {
+				t.Fatalf("pruneCandidates() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("pruneCandidates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPruneCandidates_NoRules(t *testing.T) {
+	paths := &config.Paths{DataDir: t.TempDir(), KernelsDir: t.TempDir()}
+	if _, err := pruneCandidates(0, 0, paths, time.Now()); err == nil {
+		t.Error("pruneCandidates() without keep-latest or older-than should fail")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Now()
+	paths := setupInstalledKernels(t, now, map[string]int{"6.18.9": 0, "6.18.8": 5}, "")
+
+	removed, err := Prune(1, 0, paths)
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if len(removed) != 1 {
+		t.Fatalf("Prune() removed %v, want one version", removed)
+	}
+	if _, err := os.Stat(filepath.Join(paths.KernelsDir, removed[0])); !os.IsNotExist(err) {
+		t.Errorf("%s should be removed", removed[0])
+	}
+	entries, _ := os.ReadDir(paths.KernelsDir)
+	if len(entries) != 1 {
+		t.Errorf("%d kernels left, want 1", len(entries))
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := map[string]time.Duration{
+		"30d": 30 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+		"36h": 36 * time.Hour,
+		"0d":  0,
+	}
+	for in, want := range tests {
+		got, err := ParseAge(in)
+		if err != nil || got != want {
+			t.Errorf("ParseAge(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "d", "-1d", "soon", "-5h"} {
+		if _, err := ParseAge(in); err == nil {
+			t.Errorf("ParseAge(%q) should fail", in)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// resumeInput is a file that a build resumed from some phase needs, and the
+// phase that produces it
+type resumeInput struct {
+	path     string
+	producer BuildPhase
+}
+
+// checkResumeInputs fails unless an earlier build left everything the
+// phases from resumeFrom on need: the tarball to verify or extract, the
+// source tree to configure, its .config to compile and the built image to
+// package. kernelImage is the image path inside kernelSrcDir; modulesDir is
+// the modules staging directory, or "" when modules aren't built.
+func checkResumeInputs(resumeFrom BuildPhase, kernelTarball, kernelSrcDir, kernelImage, modulesDir string) error {
+	if resumeFrom == PhaseDownload {
+		return nil
+	}
+
+	var inputs []resumeInput
+	if resumeFrom <= PhaseExtract {
+		inputs = append(inputs, resumeInput{kernelTarball, PhaseDownload})
+	} else {
+		inputs = append(inputs, resumeInput{kernelSrcDir, PhaseExtract})
+	}
+	if resumeFrom >= PhaseCompile {
+		inputs = append(inputs, resumeInput{filepath.Join(kernelSrcDir, ".config"), PhaseConfigure})
+	}
+	if resumeFrom >= PhasePackage {
+		inputs = append(inputs, resumeInput{filepath.Join(kernelSrcDir, kernelImage), PhaseCompile})
+		if modulesDir != "" {
+			inputs = append(inputs, resumeInput{modulesDir, PhaseCompile})
+		}
+	}
+
+	for _, input := range inputs {
+		if _, err := os.Stat(input.path); err != nil {
+			return fmt.Errorf("cannot resume from %s: %s is missing (the %s phase didn't finish); resume from %s or earlier", resumeFrom, input.path, input.producer, input.producer)
+		}
+	}
+	return nil
+}
+
+// phaseSkipReason returns why runBuild skips phase, or "" if it runs it.
+// Phases before resumeFrom are skipped, as is a phase whose output (the
+// tarball for download, the source tree for extract) already exists -
+// unless it is the phase the build resumes from, which always runs.
+func phaseSkipReason(phase, resumeFrom BuildPhase, output string) string {
+	if phase < resumeFrom {
+		return fmt.Sprintf("resuming from %s", resumeFrom)
+	}
+	if phase == resumeFrom && resumeFrom != PhaseDownload {
+		return ""
+	}
+	if output == "" {
+		return ""
+	}
+	if _, err := os.Stat(output); err == nil {
+		return fmt.Sprintf("%s already exists", filepath.Base(output))
+	}
+	return ""
+}
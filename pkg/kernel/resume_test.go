@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckResumeInputs(t *testing.T) {
+	dir := t.TempDir()
+	tarball := filepath.Join(dir, "linux-6.12.9.tar.xz")
+	srcDir := filepath.Join(dir, "linux-6.12.9")
+	modulesDir := filepath.Join(dir, "modules-x86_64")
+
+	// A configured source tree that hasn't been compiled yet
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, ".config"), []byte("CONFIG_MODULES=y\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		resumeFrom BuildPhase
+		modulesDir string
+		wantErr    string
+	}{
+		{name: "full build", resumeFrom: PhaseDownload},
+		{name: "verify without tarball", resumeFrom: PhaseVerify, wantErr: "resume from download or earlier"},
+		{name: "configure", resumeFrom: PhaseConfigure},
+		{name: "compile", resumeFrom: PhaseCompile},
+		{name: "package without image", resumeFrom: PhasePackage, wantErr: "vmlinux is missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkResumeInputs(tt.resumeFrom, tarball, srcDir, "vmlinux", tt.modulesDir)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkResumeInputs() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkResumeInputs() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Once compiled, packaging can resume, unless the modules it should
+	// package were never installed
+	if err := os.WriteFile(filepath.Join(srcDir, "vmlinux"), []byte("ELF"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkResumeInputs(PhasePackage, tarball, srcDir, "vmlinux", ""); err != nil {
+		t.Errorf("checkResumeInputs(package) error = %v", err)
+	}
+	if err := checkResumeInputs(PhasePackage, tarball, srcDir, "vmlinux", modulesDir); err == nil {
+		t.Error("checkResumeInputs(package) succeeded without the modules staging directory")
+	}
+}
+
+func TestPhaseSkipReason(t *testing.T) {
+	dir := t.TempDir()
+	srcDir := filepath.Join(dir, "linux-6.12.9")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		phase      BuildPhase
+		resumeFrom BuildPhase
+		output     string
+		want       string
+	}{
+		{name: "runs", phase: PhaseDownload, resumeFrom: PhaseDownload, output: filepath.Join(dir, "linux-6.12.9.tar.xz")},
+		{name: "output exists", phase: PhaseExtract, resumeFrom: PhaseDownload, output: srcDir, want: "linux-6.12.9 already exists"},
+		{name: "before resumed phase", phase: PhaseCompile, resumeFrom: PhasePackage, want: "resuming from package"},
+		{name: "resumed phase runs over its output", phase: PhaseExtract, resumeFrom: PhaseExtract, output: srcDir},
+		{name: "after resumed phase", phase: PhaseExtract, resumeFrom: PhaseVerify, output: srcDir, want: "linux-6.12.9 already exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := phaseSkipReason(tt.phase, tt.resumeFrom, tt.output); got != tt.want {
+				t.Errorf("phaseSkipReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
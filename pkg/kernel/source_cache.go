@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+// sourceCacheDir holds verified source tarballs kept with --keep-tarball.
+// It lives outside build/ so clearing the build cache does not remove it.
+func sourceCacheDir(paths *config.Paths) string {
+	return filepath.Join(paths.KernelBuildDir, "sources")
+}
+
+// cachedTarballName returns the cache filename for a verified tarball,
+// keyed by version and the tarball's SHA256
+func cachedTarballName(version, hash string) string {
+	return fmt.Sprintf("linux-%s-%s.tar.xz", version, hash)
+}
+
+// findCachedTarball returns a cached source tarball for version whose
+// contents still match the hash in its name, or "" if there is none.
+// Entries that fail the check are removed.
+func findCachedTarball(logger *buildLogger, paths *config.Paths, version string) string {
+	prefix := fmt.Sprintf("linux-%s-", version)
+	matches, _ := filepath.Glob(filepath.Join(sourceCacheDir(paths), prefix+"*.tar.xz"))
+
+	for _, path := range matches {
+		expected := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), prefix), ".tar.xz")
+		actual, err := util.CalculateSHA256(path)
+		if err != nil || actual != expected {
+			logger.Warn(fmt.Sprintf("Cached source tarball failed hash check, removing: %s", path))
+			os.Remove(path)
+			continue
+		}
+		return path
+	}
+
+	return ""
+}
+
+// cacheVerifiedTarball stores a verified source tarball in the source cache,
+// replacing any other cached tarball for the same version
+func cacheVerifiedTarball(logger *buildLogger, paths *config.Paths, version, tarball string) error {
+	hash, err := util.CalculateSHA256(tarball)
+	if err != nil {
+		return fmt.Errorf("failed to hash source tarball: %w", err)
+	}
+
+	cacheDir := sourceCacheDir(paths)
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return fmt.Errorf("failed to create source cache directory: %w", err)
+	}
+
+	dst := filepath.Join(cacheDir, cachedTarballName(version, hash))
+	if _, err := os.Stat(dst); err == nil {
+		return nil // Already cached
+	}
+
+	stale, _ := filepath.Glob(filepath.Join(cacheDir, fmt.Sprintf("linux-%s-*.tar.xz", version)))
+	for _, path := range stale {
+		os.Remove(path)
+	}
+
+	if err := linkOrCopyFile(tarball, dst); err != nil {
+		return fmt.Errorf("failed to cache source tarball: %w", err)
+	}
+	logger.Info(fmt.Sprintf("Kept verified source tarball: %s", dst))
+	return nil
+}
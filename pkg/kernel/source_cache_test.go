@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+// cacheTarball caches a tarball with the given contents for version and
+// returns the cached path
+func cacheTarball(t *testing.T, logger *buildLogger, paths *config.Paths, version, contents string) string {
+	t.Helper()
+	tarball := filepath.Join(t.TempDir(), "linux-"+version+".tar.xz")
+	if err := os.WriteFile(tarball, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cacheVerifiedTarball(logger, paths, version, tarball); err != nil {
+		t.Fatalf("cacheVerifiedTarball() error = %v", err)
+	}
+	cached := findCachedTarball(logger, paths, version)
+	if cached == "" {
+		t.Fatalf("tarball for %s wasn't found after caching", version)
+	}
+	return cached
+}
+
+func TestFindCachedTarball(t *testing.T) {
+	paths := config.PathsUnder(t.TempDir())
+	logger := &buildLogger{writer: io.Discard}
+
+	if got := findCachedTarball(logger, paths, "6.1"); got != "" {
+		t.Errorf("findCachedTarball() with an empty cache = %q", got)
+	}
+
+	// Tarballs are found by version and named by their hash
+	minor := cacheTarball(t, logger, paths, "6.1", "linux 6.1")
+	patch := cacheTarball(t, logger, paths, "6.1.5", "linux 6.1.5")
+	if minor == patch {
+		t.Fatalf("6.1 and 6.1.5 share a cached tarball: %s", minor)
+	}
+	hash, err := util.CalculateSHA256(minor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := cachedTarballName("6.1", hash); filepath.Base(minor) != want {
+		t.Errorf("cached tarball = %s, want %s", filepath.Base(minor), want)
+	}
+	if got := findCachedTarball(logger, paths, "6.1.5"); got != patch {
+		t.Errorf("findCachedTarball(6.1.5) = %q, want %q", got, patch)
+	}
+	if got := findCachedTarball(logger, paths, "6.2"); got != "" {
+		t.Errorf("findCachedTarball(6.2) = %q, want none", got)
+	}
+
+	// Caching another tarball of a version replaces the old one
+	replaced := cacheTarball(t, logger, paths, "6.1", "linux 6.1 respun")
+	if replaced == minor {
+		t.Error("a tarball with other contents should be cached under another hash")
+	}
+	if _, err := os.Stat(minor); !os.IsNotExist(err) {
+		t.Error("stale tarball for 6.1 wasn't removed")
+	}
+
+	// A tarball that no longer matches its hash is removed, not used
+	if err := os.WriteFile(replaced, []byte("corrupt"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var logs bytes.Buffer
+	if got := findCachedTarball(&buildLogger{writer: &logs}, paths, "6.1"); got != "" {
+		t.Errorf("findCachedTarball() returned a corrupt tarball: %s", got)
+	}
+	if _, err := os.Stat(replaced); !os.IsNotExist(err) {
+		t.Error("corrupt tarball wasn't removed")
+	}
+	if !strings.Contains(logs.String(), "failed hash check") {
+		t.Errorf("corrupt tarball removal wasn't logged:\n%s", logs.String())
+	}
+	if got := findCachedTarball(logger, paths, "6.1.5"); got != patch {
+		t.Errorf("6.1.5 tarball lost while checking 6.1: %q", got)
+	}
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+)
+
+// GitSource is a git repository to build from instead of a kernel.org
+// release tarball, for -rc kernels and forks that aren't published as
+// tarballs
+type GitSource struct {
+	URL string
+	Ref string // Tag or branch, cloned with --depth 1
+}
+
+// gitRefVersionPattern matches tags named after a kernel version, such as
+// v6.19-rc3 or v6.12.9
+var gitRefVersionPattern = regexp.MustCompile(`^v?(\d+\.\d+(?:\.\d+)?(?:-rc\d+)?)$`)
+
+// gitRefVersion returns the kernel version a ref names, or "" when it
+// doesn't name one (a branch, say)
+func gitRefVersion(ref string) string {
+	if m := gitRefVersionPattern.FindStringSubmatch(ref); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// resolveGitSource checks a git build's options up front. The version
+// defaults to the one the ref names; when it names none, runBuild reads it
+// from the cloned Makefile. Git sources can't be verified against
+// kernel.org's checksums, so the build is recorded as unverified.
+func resolveGitSource(opts *BuildOptions) error {
+	if opts.GitSource == nil {
+		return nil
+	}
+	if opts.GitSource.URL == "" || opts.GitSource.Ref == "" {
+		return fmt.Errorf("building from git needs both a repository URL and a ref")
+	}
+	if opts.SourceTarball != "" {
+		return fmt.Errorf("a git source can't be combined with a source tarball")
+	}
+	if IsVersionAlias(opts.Version) {
+		return fmt.Errorf("%q can't be resolved for a git source; pass the kernel version or leave it out", opts.Version)
+	}
+	if opts.Version == "" {
+		opts.Version = gitRefVersion(opts.GitSource.Ref)
+	}
+	if opts.ResumeFrom > PhaseDownload {
+		if opts.ResumeFrom <= PhaseExtract {
+			return fmt.Errorf("a git build has no %s phase to resume from; resume from %s or later", opts.ResumeFrom, PhaseConfigure)
+		}
+		if opts.Version == "" {
+			return fmt.Errorf("resuming a git build of %s needs its kernel version", opts.GitSource.Ref)
+		}
+	}
+	opts.VerificationLevel = "disabled"
+	return nil
+}
+
+// gitStagingDir is where a git source is cloned when its version is only
+// known from the Makefile, before it moves to its version's build directory
+func gitStagingDir(paths *config.Paths, arch string) string {
+	return filepath.Join(paths.KernelBuildDir, "build", "git-"+arch)
+}
+
+// cloneGitSource shallow-clones the build's git source into dir, replacing
+// whatever is there, streaming git's output through the logger
+func cloneGitSource(logger *buildLogger, opts BuildOptions, dir string, ctx context.Context) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove previous source tree: %w", err)
+	}
+	src := opts.GitSource
+	logger.Info(fmt.Sprintf("Cloning %s from %s...", src.Ref, src.URL))
+	return withPhaseTimeout(ctx, PhaseDownload, opts.DownloadTimeout, func(ctx context.Context) error {
+		cmd := exec.Command("git", "clone", "--depth", "1", "--branch", src.Ref, "--", src.URL, dir)
+		cmd.Stdout = logger.writer
+		cmd.Stderr = logger.writer
+		if err := runCommandWithProcessGroup(ctx, cmd); err != nil {
+			// Don't leave a partial clone for a resumed build to pick up
+			os.RemoveAll(dir)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to clone %s at %s: %w", src.URL, src.Ref, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGitRefVersion(t *testing.T) {
+	tests := map[string]string{
+		"v6.19-rc3": "6.19-rc3",
+		"v6.12.9":   "6.12.9",
+		"6.12":      "6.12",
+		"master":    "",
+		"for-next":  "",
+		"v6.12-foo": "",
+	}
+	for ref, want := range tests {
+		if got := gitRefVersion(ref); got != want {
+			t.Errorf("gitRefVersion(%q) = %q, want %q", ref, got, want)
+		}
+	}
+}
+
+func TestResolveGitSource(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        BuildOptions
+		wantVersion string
+		wantErr     string
+	}{
+		{name: "version from tag", opts: BuildOptions{GitSource: &GitSource{URL: "https://git.example/linux.git", Ref: "v6.19-rc3"}}, wantVersion: "6.19-rc3"},
+		{name: "branch", opts: BuildOptions{GitSource: &GitSource{URL: "https://git.example/linux.git", Ref: "master"}}},
+		{name: "explicit version", opts: BuildOptions{Version: "6.12.9", GitSource: &GitSource{URL: "https://git.example/linux.git", Ref: "my-fork"}}, wantVersion: "6.12.9"},
+		{name: "missing ref", opts: BuildOptions{GitSource: &GitSource{URL: "https://git.example/linux.git"}}, wantErr: "needs both"},
+		{name: "alias", opts: BuildOptions{Version: VersionLatest, GitSource: &GitSource{URL: "https://git.example/linux.git", Ref: "master"}}, wantErr: "can't be resolved"},
+		{name: "with tarball", opts: BuildOptions{SourceTarball: "linux-6.12.9.tar.xz", GitSource: &GitSource{URL: "https://git.example/linux.git", Ref: "master"}}, wantErr: "source tarball"},
+		{name: "resume from extract", opts: BuildOptions{ResumeFrom: PhaseExtract, GitSource: &GitSource{URL: "https://git.example/linux.git", Ref: "v6.19-rc3"}}, wantErr: "no extract phase"},
+		{name: "resume branch", opts: BuildOptions{ResumeFrom: PhaseCompile, GitSource: &GitSource{URL: "https://git.example/linux.git", Ref: "master"}}, wantErr: "needs its kernel version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.VerificationLevel = "high"
+			err := resolveGitSource(&opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("resolveGitSource() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveGitSource() error = %v", err)
+			}
+			if opts.Version != tt.wantVersion {
+				t.Errorf("Version = %q, want %q", opts.Version, tt.wantVersion)
+			}
+			if opts.VerificationLevel != "disabled" {
+				t.Errorf("VerificationLevel = %q, want disabled", opts.VerificationLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// tarballVersion returns the kernel version in a kernel.org tarball name
+// such as linux-6.18.9.tar.xz, or "" when the name doesn't follow it
+func tarballVersion(path string) string {
+	base := filepath.Base(path)
+	if !strings.HasPrefix(base, "linux-") || !strings.HasSuffix(base, ".tar.xz") {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(base, "linux-"), ".tar.xz")
+}
+
+// resolveLocalSource checks the files of an offline build up front so a
+// missing tarball or checksums file fails before any work is done. It
+// fills in the version from the tarball name and, when verification is
+// enabled, defaults ChecksumsFile to sha256sums.asc next to the tarball.
+func resolveLocalSource(opts *BuildOptions) error {
+	if opts.SourceTarball == "" {
+		if opts.ChecksumsFile != "" {
+			return fmt.Errorf("--checksums-file requires --source-tarball")
+		}
+		return nil
+	}
+
+	tarball, err := filepath.Abs(opts.SourceTarball)
+	if err != nil {
+		return fmt.Errorf("invalid source tarball path: %w", err)
+	}
+	info, err := os.Stat(tarball)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("source tarball not found: %s\nDownload linux-<version>.tar.xz from cdn.kernel.org and pass its path with --source-tarball", tarball)
+		}
+		return fmt.Errorf("cannot read source tarball: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("source tarball is a directory: %s", tarball)
+	}
+	opts.SourceTarball = tarball
+
+	nameVersion := tarballVersion(tarball)
+	switch {
+	case opts.Version == "" && nameVersion == "":
+		return fmt.Errorf("cannot tell the kernel version from %s (expected linux-<version>.tar.xz)\nPass the version explicitly", filepath.Base(tarball))
+	case opts.Version == "":
+		opts.Version = nameVersion
+	case nameVersion != "" && nameVersion != opts.Version:
+		return fmt.Errorf("source tarball %s is for kernel %s, but version %s was requested", filepath.Base(tarball), nameVersion, opts.Version)
+	}
+
+	if opts.VerificationLevel == "disabled" {
+		return nil
+	}
+
+	checksums := opts.ChecksumsFile
+	if checksums == "" {
+		checksums = filepath.Join(filepath.Dir(tarball), "sha256sums.asc")
+	}
+	checksums, err = filepath.Abs(checksums)
+	if err != nil {
+		return fmt.Errorf("invalid checksums file path: %w", err)
+	}
+	if _, err := os.Stat(checksums); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("checksums file not found: %s\nCopy sha256sums.asc from cdn.kernel.org/pub/linux/kernel/v%s.x/ next to the tarball, pass it with --checksums-file, or use --verification-level disabled (not recommended)",
+				checksums, strings.Split(opts.Version, ".")[0])
+		}
+		return fmt.Errorf("cannot read checksums file: %w", err)
+	}
+	opts.ChecksumsFile = checksums
+
+	return nil
+}
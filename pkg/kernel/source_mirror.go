@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DefaultSourceMirror is the kernel.org CDN used when no mirror is configured
+const DefaultSourceMirror = "https://cdn.kernel.org/pub/linux/kernel"
+
+// normalizeSourceMirror validates a kernel source mirror base URL and
+// returns it without a trailing slash. The mirror must serve kernel.org's
+// layout below the base (<base>/v<major>.x/linux-<version>.tar.xz).
+func normalizeSourceMirror(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid kernel mirror URL %q: %w", raw, err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return "", fmt.Errorf("invalid kernel mirror URL %q: must be an absolute http or https URL", raw)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid kernel mirror URL %q: missing host", raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("invalid kernel mirror URL %q: query and fragment are not allowed", raw)
+	}
+	return strings.TrimRight(u.String(), "/"), nil
+}
+
+// sourceTarballURL returns the URL of the source tarball for version
+func sourceTarballURL(mirror, majorVersion, version string) string {
+	return fmt.Sprintf("%s/v%s.x/linux-%s.tar.xz", mirror, majorVersion, version)
+}
+
+// sourceChecksumsURL returns the URL of the signed sha256sums.asc covering
+// every tarball of a major version
+func sourceChecksumsURL(mirror, majorVersion string) string {
+	return fmt.Sprintf("%s/v%s.x/sha256sums.asc", mirror, majorVersion)
+}
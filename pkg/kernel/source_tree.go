@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// sourceTreeFiles are paths every kernel source tree has at its top level
+var sourceTreeFiles = []string{"Makefile", "Kconfig", "arch"}
+
+// checkSourceTree sanity-checks an extracted source tree before it is
+// configured: the top-level files must be there and the Makefile's VERSION
+// and PATCHLEVEL must match version. A verified tarball can still extract
+// to a truncated tree after a disk error, which would otherwise only show
+// up as a confusing make failure.
+func checkSourceTree(kernelSrcDir, version string) error {
+	for _, name := range sourceTreeFiles {
+		if _, err := os.Stat(filepath.Join(kernelSrcDir, name)); err != nil {
+			return fmt.Errorf("%s is missing", name)
+		}
+	}
+
+	vars, err := readMakefileVersion(filepath.Join(kernelSrcDir, "Makefile"))
+	if err != nil {
+		return err
+	}
+	// "6.19-rc3" is VERSION 6, PATCHLEVEL 19 (the -rc goes in EXTRAVERSION)
+	release, _, _ := strings.Cut(version, "-")
+	parts := strings.Split(release, ".")
+	if len(parts) < 2 {
+		return fmt.Errorf("invalid kernel version: %s", version)
+	}
+	if vars["VERSION"] != parts[0] || vars["PATCHLEVEL"] != parts[1] {
+		return fmt.Errorf("Makefile is for %s.%s, not %s", vars["VERSION"], vars["PATCHLEVEL"], version)
+	}
+	return nil
+}
+
+// makefileVersionVars are the assignments at the head of a kernel's
+// top-level Makefile that make up its release
+var makefileVersionVars = []string{"VERSION", "PATCHLEVEL", "SUBLEVEL", "EXTRAVERSION"}
+
+// readMakefileVersion reads the makefileVersionVars assignments from a
+// kernel's top-level Makefile. VERSION and PATCHLEVEL must be set.
+func readMakefileVersion(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Makefile: %w", err)
+	}
+	defer f.Close()
+
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() && len(vars) < len(makefileVersionVars) {
+		name, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		if slices.Contains(makefileVersionVars, name) {
+			vars[name] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Makefile: %w", err)
+	}
+	if vars["VERSION"] == "" || vars["PATCHLEVEL"] == "" {
+		return nil, fmt.Errorf("Makefile has no VERSION and PATCHLEVEL")
+	}
+	return vars, nil
+}
+
+// makefileRelease returns the kernel version of the source tree at
+// kernelSrcDir in kernel.org's form: 6.12.9, or 6.19-rc3 and 6.19 when
+// SUBLEVEL is 0. EXTRAVERSION is appended as is.
+func makefileRelease(kernelSrcDir string) (string, error) {
+	vars, err := readMakefileVersion(filepath.Join(kernelSrcDir, "Makefile"))
+	if err != nil {
+		return "", err
+	}
+	release := vars["VERSION"] + "." + vars["PATCHLEVEL"]
+	if sublevel := vars["SUBLEVEL"]; sublevel != "" && sublevel != "0" {
+		release += "." + sublevel
+	}
+	return release + vars["EXTRAVERSION"], nil
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testMakefile = `# SPDX-License-Identifier: GPL-2.0
+VERSION = 6
+PATCHLEVEL = 19
+SUBLEVEL = 0
+EXTRAVERSION = -rc3
+NAME = Baby Opossum Posse
+`
+
+func TestCheckSourceTree(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		omit    string
+		wantErr string
+	}{
+		{name: "release candidate", version: "6.19-rc3"},
+		{name: "stable", version: "6.19.2"},
+		{name: "other series", version: "6.12.9", wantErr: "Makefile is for 6.19, not 6.12.9"},
+		{name: "truncated", version: "6.19-rc3", omit: "arch", wantErr: "arch is missing"},
+		{name: "no Makefile", version: "6.19-rc3", omit: "Makefile", wantErr: "Makefile is missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "Makefile"), []byte(testMakefile), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "Kconfig"), nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Mkdir(filepath.Join(dir, "arch"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if tt.omit != "" {
+				if err := os.RemoveAll(filepath.Join(dir, tt.omit)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := checkSourceTree(dir, tt.version)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkSourceTree() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkSourceTree() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMakefileRelease(t *testing.T) {
+	tests := []struct {
+		name     string
+		makefile string
+		want     string
+	}{
+		{name: "release candidate", makefile: testMakefile, want: "6.19-rc3"},
+		{name: "stable", makefile: "VERSION = 6\nPATCHLEVEL = 12\nSUBLEVEL = 9\nEXTRAVERSION =\n", want: "6.12.9"},
+		{name: "first release of a series", makefile: "VERSION = 6\nPATCHLEVEL = 18\nSUBLEVEL = 0\nEXTRAVERSION =\n", want: "6.18"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "Makefile"), []byte(tt.makefile), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := makefileRelease(dir)
+			if err != nil {
+				t.Fatalf("makefileRelease() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("makefileRelease() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+)
+
+// BuildStatsSidecar is the copy of a build's stats kept next to an
+// installed or archived kernel. In the build artifacts directory it is a
+// symlink to the most recent build's stats file.
+const BuildStatsSidecar = "build-stats.json"
+
+// StoredBuildStats is a build's stats and where they were found
+type StoredBuildStats struct {
+	Source string     `json:"source"` // artifacts, installed or archive
+	Path   string     `json:"path"`
+	Arch   string     `json:"arch"`
+	Stats  BuildStats `json:"stats"`
+}
+
+// FindBuildStats looks up the stored stats for a kernel version built for
+// arch. version may be a kernel version (6.18.9) or an installed version
+// name (6.18.9-20260101T120000). The build artifacts are checked first,
+// then installed kernels (newest first), then the archive in archiveDir
+// (skipped when empty).
+func FindBuildStats(version, arch, archiveDir string, paths *config.Paths) (*StoredBuildStats, error) {
+	// Artifacts: the stats of this version's build for the arch. An
+	// installed version name is matched against every build's timestamp.
+	candidates := []string{cachedBuildStatsFile(version, arch, paths)}
+	if candidates[0] == "" {
+		artifactsDir := filepath.Join(paths.KernelBuildDir, "artifacts")
+		candidates, _ = filepath.Glob(filepath.Join(artifactsDir, BuildStatsFile("*", arch)))
+		candidates = append(candidates, filepath.Join(artifactsDir, legacyBuildStatsFile(arch)))
+	}
+	for _, statsFile := range candidates {
+		if stats, err := ReadBuildStats(statsFile); err == nil && statsMatchVersion(stats, version) {
+			return &StoredBuildStats{Source: "artifacts", Path: statsFile, Arch: arch, Stats: stats}, nil
+		}
+	}
+
+	// Installed kernels carry a sidecar copy
+	for _, dir := range installedVersionDirs(paths.KernelsDir, version) {
+		if installedKernelFile(dir, arch) == "" {
+			continue
+		}
+		sidecar := filepath.Join(dir, BuildStatsSidecar)
+		if stats, err := ReadBuildStats(sidecar); err == nil {
+			return &StoredBuildStats{Source: "installed", Path: sidecar, Arch: arch, Stats: stats}, nil
+		}
+	}
+
+	// Archived kernels are keyed by plain kernel version
+	if archiveDir != "" {
+		sidecar := filepath.Join(archiveDir, arch, version, BuildStatsSidecar)
+		if stats, err := ReadBuildStats(sidecar); err == nil {
+			return &StoredBuildStats{Source: "archive", Path: sidecar, Arch: arch, Stats: stats}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no build stats found for kernel %s (%s)\nStats are kept for kernels built with anvil; downloaded kernels have none", version, arch)
+}
+
+// statsMatchVersion reports whether stats belong to version, given either
+// as the kernel version or as the timestamped installed name
+func statsMatchVersion(stats BuildStats, version string) bool {
+	if stats.KernelVersion == version {
+		return true
+	}
+	return fmt.Sprintf("%s-%s", stats.KernelVersion, stats.BuildTimestamp.Format("20060102T150405")) == version
+}
+
+// installedVersionDirs returns the installed kernel directories for
+// version: the exact directory, or every timestamped build of it, newest
+// first
+func installedVersionDirs(kernelsDir, version string) []string {
+	exact := filepath.Join(kernelsDir, version)
+	if info, err := os.Stat(exact); err == nil && info.IsDir() {
+		return []string{exact}
+	}
+
+	entries, err := os.ReadDir(kernelsDir)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), version+"-") {
+			dirs = append(dirs, filepath.Join(kernelsDir, entry.Name()))
+		}
+	}
+	// Timestamp suffixes sort chronologically
+	sort.Sort(sort.Reverse(sort.StringSlice(dirs)))
+	return dirs
+}
+
+// installedKernelFile returns the kernel image for arch in an installed
+// version directory, or "" when it has none
+func installedKernelFile(dir, arch string) string {
+	kernelName, err := config.GetKernelNameForArch(arch)
+	if err != nil {
+		return ""
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s-%s", kernelName, filepath.Base(dir), arch))
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
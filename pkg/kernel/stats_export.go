@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/charmbracelet/log"
+	goversion "github.com/hashicorp/go-version"
+)
+
+// StatsRow is one build's stats flattened for export. Durations are in
+// seconds so spreadsheets and plotting tools can use them directly.
+type StatsRow struct {
+	File              string    `json:"file"`
+	KernelVersion     string    `json:"kernel_version"`
+	Arch              string    `json:"arch"`
+	BuildTimestamp    time.Time `json:"build_timestamp"`
+	TotalSeconds      float64   `json:"total_seconds"`
+	DownloadSeconds   float64   `json:"download_seconds"`
+	ExtractSeconds    float64   `json:"extract_seconds"`
+	ConfigureSeconds  float64   `json:"configure_seconds"`
+	CompileSeconds    float64   `json:"compile_seconds"`
+	PackageSeconds    float64   `json:"package_seconds"`
+	UncompressedSize  int64     `json:"uncompressed_size"`
+	CompressedSize    int64     `json:"compressed_size"`
+	CompressionRatio  float64   `json:"compression_ratio"` // UncompressedSize / CompressedSize, 0 when unknown
+	UncompressedHash  string    `json:"uncompressed_sha256"`
+	CompressedHash    string    `json:"compressed_sha256"`
+	VerificationLevel string    `json:"verification_level"`
+}
+
+// statsCSVHeader lists the CSV columns, in StatsRow order
+var statsCSVHeader = []string{
+	"file", "kernel_version", "arch", "build_timestamp",
+	"total_seconds", "download_seconds", "extract_seconds", "configure_seconds", "compile_seconds", "package_seconds",
+	"uncompressed_size", "compressed_size", "compression_ratio",
+	"uncompressed_sha256", "compressed_sha256", "verification_level",
+}
+
+// FindStatsFiles returns every build stats file in the build artifacts
+// (build-stats-<version>-<arch>.json, or build-stats-<arch>.json from older
+// versions) and, when archiveDir is set, the archive
+// (<arch>/<version>/build-stats.json)
+func FindStatsFiles(archiveDir string, paths *config.Paths) ([]string, error) {
+	var files []string
+
+	artifacts, err := filepath.Glob(filepath.Join(paths.KernelBuildDir, "artifacts", "build-stats-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, artifacts...)
+
+	if archiveDir != "" {
+		archived, err := filepath.Glob(filepath.Join(archiveDir, "*", "*", BuildStatsSidecar))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, archived...)
+	}
+
+	return files, nil
+}
+
+// StatsRows reads statsFiles into export rows, sorted by kernel version and
+// then build time. Unreadable files are skipped with a warning, and a build
+// found in several files (the artifacts and its archived copy) is listed
+// once.
+func StatsRows(statsFiles []string) []StatsRow {
+	rows := []StatsRow{}
+	seen := map[string]bool{}
+
+	for _, file := range statsFiles {
+		stats, err := ReadBuildStats(file)
+		if err != nil {
+			log.Warnf("Skipping %s: %v", file, err)
+			continue
+		}
+		if stats.KernelVersion == "" {
+			log.Warnf("Skipping %s: no kernel version", file)
+			continue
+		}
+
+		row := StatsRow{
+			File:              file,
+			KernelVersion:     stats.KernelVersion,
+			Arch:              kernelFileArch(stats.OutputPath),
+			BuildTimestamp:    stats.BuildTimestamp,
+			TotalSeconds:      stats.TotalDuration.Seconds(),
+			DownloadSeconds:   stats.DownloadDuration.Seconds(),
+			ExtractSeconds:    stats.ExtractDuration.Seconds(),
+			ConfigureSeconds:  stats.ConfigureDuration.Seconds(),
+			CompileSeconds:    stats.CompileDuration.Seconds(),
+			PackageSeconds:    stats.PackageDuration.Seconds(),
+			UncompressedSize:  stats.UncompressedSize,
+			CompressedSize:    stats.CompressedSize,
+			UncompressedHash:  stats.UncompressedHash,
+			CompressedHash:    stats.CompressedHash,
+			VerificationLevel: stats.VerificationLevel,
+		}
+		if stats.CompressedSize > 0 {
+			row.CompressionRatio = float64(stats.UncompressedSize) / float64(stats.CompressedSize)
+		}
+
+		key := fmt.Sprintf("%s|%s|%s|%s", row.KernelVersion, row.Arch, row.BuildTimestamp.Format(time.RFC3339Nano), row.UncompressedHash)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		rows = append(rows, row)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].KernelVersion != rows[j].KernelVersion {
+			vi, errI := goversion.NewVersion(rows[i].KernelVersion)
+			vj, errJ := goversion.NewVersion(rows[j].KernelVersion)
+			if errI == nil && errJ == nil {
+				return vi.LessThan(vj)
+			}
+			return rows[i].KernelVersion < rows[j].KernelVersion
+		}
+		return rows[i].BuildTimestamp.Before(rows[j].BuildTimestamp)
+	})
+
+	return rows
+}
+
+// ExportStatsCSV writes one CSV row per build in statsFiles, with a header
+// row, to w
+func ExportStatsCSV(statsFiles []string, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(statsCSVHeader); err != nil {
+		return fmt.Errorf("failed to write stats CSV: %w", err)
+	}
+
+	seconds := func(s float64) string { return strconv.FormatFloat(s, 'f', 3, 64) }
+	for _, row := range StatsRows(statsFiles) {
+		timestamp := ""
+		if !row.BuildTimestamp.IsZero() {
+			timestamp = row.BuildTimestamp.Format(time.RFC3339)
+		}
+		record := []string{
+			row.File, row.KernelVersion, row.Arch, timestamp,
+			seconds(row.TotalSeconds), seconds(row.DownloadSeconds), seconds(row.ExtractSeconds),
+			seconds(row.ConfigureSeconds), seconds(row.CompileSeconds), seconds(row.PackageSeconds),
+			strconv.FormatInt(row.UncompressedSize, 10), strconv.FormatInt(row.CompressedSize, 10),
+			strconv.FormatFloat(row.CompressionRatio, 'f', 3, 64),
+			row.UncompressedHash, row.CompressedHash, row.VerificationLevel,
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write stats CSV: %w", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write stats CSV: %w", err)
+	}
+	return nil
+}
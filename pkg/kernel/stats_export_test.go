@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+)
+
+// statsFixture writes build stats to the artifacts of paths and archives a
+// copy of the first build, returning the archive directory
+func statsFixture(t *testing.T, paths *config.Paths, builds ...BuildStats) string {
+	t.Helper()
+	artifactsDir := filepath.Join(paths.KernelBuildDir, "artifacts")
+	if err := os.MkdirAll(artifactsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, stats := range builds {
+		if err := writeBuildStats(filepath.Join(artifactsDir, BuildStatsFile(stats.KernelVersion, stats.Arch)), stats); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	archiveDir := t.TempDir()
+	versionDir := filepath.Join(archiveDir, builds[0].Arch, builds[0].KernelVersion)
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeBuildStats(filepath.Join(versionDir, BuildStatsSidecar), builds[0]); err != nil {
+		t.Fatal(err)
+	}
+	return archiveDir
+}
+
+func TestFindStatsFiles(t *testing.T) {
+	paths := config.PathsUnder(t.TempDir())
+	archiveDir := statsFixture(t, paths,
+		BuildStats{KernelVersion: "6.18.9", Arch: "x86_64"},
+		BuildStats{KernelVersion: "6.12.9", Arch: "aarch64"},
+	)
+	artifactsDir := filepath.Join(paths.KernelBuildDir, "artifacts")
+
+	files, err := FindStatsFiles("", paths)
+	if err != nil {
+		t.Fatalf("FindStatsFiles() error = %v", err)
+	}
+	want := []string{
+		filepath.Join(artifactsDir, "build-stats-6.12.9-aarch64.json"),
+		filepath.Join(artifactsDir, "build-stats-6.18.9-x86_64.json"),
+	}
+	if !slices.Equal(files, want) {
+		t.Errorf("FindStatsFiles() = %v, want %v", files, want)
+	}
+
+	files, err = FindStatsFiles(archiveDir, paths)
+	if err != nil {
+		t.Fatalf("FindStatsFiles() error = %v", err)
+	}
+	want = append(want, filepath.Join(archiveDir, "x86_64", "6.18.9", BuildStatsSidecar))
+	if !slices.Equal(files, want) {
+		t.Errorf("FindStatsFiles() with an archive = %v, want %v", files, want)
+	}
+}
+
+func TestStatsRows(t *testing.T) {
+	paths := config.PathsUnder(t.TempDir())
+	built := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+	archiveDir := statsFixture(t, paths,
+		BuildStats{
+			KernelVersion:    "6.18.9",
+			Arch:             "x86_64",
+			OutputPath:       "/artifacts/vmlinux-6.18.9-x86_64",
+			BuildTimestamp:   built,
+			CompileDuration:  90 * time.Second,
+			UncompressedSize: 3000,
+			CompressedSize:   1000,
+			UncompressedHash: "abc",
+		},
+		BuildStats{
+			KernelVersion:    "6.12.9",
+			Arch:             "aarch64",
+			OutputPath:       "/artifacts/Image-6.12.9-aarch64",
+			BuildTimestamp:   built.Add(time.Hour),
+			UncompressedSize: 2000,
+		},
+	)
+	files, err := FindStatsFiles(archiveDir, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Unreadable files are skipped
+	broken := filepath.Join(t.TempDir(), "build-stats.json")
+	if err := os.WriteFile(broken, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files = append(files, broken)
+
+	rows := StatsRows(files)
+
+	// The archived copy of the 6.18.9 build is listed once, and rows are in
+	// version order
+	if len(rows) != 2 {
+		t.Fatalf("StatsRows() = %d rows, want 2: %+v", len(rows), rows)
+	}
+	if rows[0].KernelVersion != "6.12.9" || rows[1].KernelVersion != "6.18.9" {
+		t.Errorf("StatsRows() versions = %s, %s, want 6.12.9, 6.18.9", rows[0].KernelVersion, rows[1].KernelVersion)
+	}
+	if rows[0].Arch != "aarch64" || rows[1].Arch != "x86_64" {
+		t.Errorf("StatsRows() archs = %s, %s", rows[0].Arch, rows[1].Arch)
+	}
+	if rows[1].CompressionRatio != 3 || rows[1].CompileSeconds != 90 {
+		t.Errorf("6.18.9 row = %+v, want ratio 3 and 90s compile", rows[1])
+	}
+	// Without a compressed size there is no ratio, rather than +Inf
+	if rows[0].CompressionRatio != 0 {
+		t.Errorf("6.12.9 compression ratio = %v, want 0", rows[0].CompressionRatio)
+	}
+}
+
+func TestExportStatsCSV(t *testing.T) {
+	paths := config.PathsUnder(t.TempDir())
+	statsFixture(t, paths, BuildStats{
+		KernelVersion:     "6.18.9",
+		Arch:              "x86_64",
+		OutputPath:        "/artifacts/vmlinux-6.18.9-x86_64",
+		BuildTimestamp:    time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC),
+		TotalDuration:     1500 * time.Millisecond,
+		UncompressedSize:  3000,
+		CompressedSize:    0,
+		UncompressedHash:  "abc",
+		VerificationLevel: "high",
+	})
+	files, err := FindStatsFiles("", paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := ExportStatsCSV(files, &out); err != nil {
+		t.Fatalf("ExportStatsCSV() error = %v", err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("ExportStatsCSV() wrote %d records, want a header and 1 row", len(records))
+	}
+	if !slices.Equal(records[0], statsCSVHeader) {
+		t.Errorf("header = %v, want %v", records[0], statsCSVHeader)
+	}
+
+	want := []string{
+		files[0], "6.18.9", "x86_64", "2026-03-08T12:00:00Z",
+		"1.500", "0.000", "0.000", "0.000", "0.000", "0.000",
+		"3000", "0", "0.000",
+		"abc", "", "high",
+	}
+	if !slices.Equal(records[1], want) {
+		t.Errorf("row = %q, want %q", records[1], want)
+	}
+}
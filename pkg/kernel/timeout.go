@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// String returns the lowercase phase name (e.g. "compile")
+func (p BuildPhase) String() string {
+	switch p {
+	case PhaseDownload:
+		return "download"
+	case PhaseVerify:
+		return "verify"
+	case PhaseExtract:
+		return "extract"
+	case PhaseConfigure:
+		return "configure"
+	case PhaseCompile:
+		return "compile"
+	case PhasePackage:
+		return "package"
+	default:
+		return fmt.Sprintf("phase %d", int(p))
+	}
+}
+
+// ParseBuildPhase is the inverse of BuildPhase.String
+func ParseBuildPhase(name string) (BuildPhase, bool) {
+	for p := PhaseDownload; p <= PhasePackage; p++ {
+		if p.String() == name {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+// PhaseTimeoutError is returned when a build phase runs longer than its
+// configured timeout
+type PhaseTimeoutError struct {
+	Phase   BuildPhase
+	Timeout time.Duration
+}
+
+func (e *PhaseTimeoutError) Error() string {
+	return fmt.Sprintf("%s phase timed out after %s", e.Phase, e.Timeout)
+}
+
+// withPhaseTimeout runs fn with a child context limited to timeout. If the
+// phase deadline (and not the parent context) ends the phase, the error is
+// replaced with a *PhaseTimeoutError. A zero timeout runs fn with ctx as-is.
+func withPhaseTimeout(ctx context.Context, phase BuildPhase, timeout time.Duration, fn func(context.Context) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	phaseCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(phaseCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(phaseCtx.Err(), context.DeadlineExceeded) {
+		return &PhaseTimeoutError{Phase: phase, Timeout: timeout}
+	}
+	return err
+}
+
+// BuildTimeoutError is returned when a whole build runs longer than
+// BuildOptions.Timeout. Cancelling the build's context instead returns the
+// context error, so a timeout can be told apart from a user cancellation.
+type BuildTimeoutError struct {
+	Timeout time.Duration
+	Phase   string // Phase running at the deadline; empty before the download starts
+}
+
+func (e *BuildTimeoutError) Error() string {
+	if e.Phase == "" {
+		return fmt.Sprintf("build timed out after %s", e.Timeout)
+	}
+	return fmt.Sprintf("build timed out after %s in %s phase", e.Timeout, e.Phase)
+}
+
+// buildTimeoutError replaces err with a *BuildTimeoutError when the build
+// deadline (and not the parent context) ended the build
+func buildTimeoutError(parent, buildCtx context.Context, timeout time.Duration, phase string, err error) error {
+	if err != nil && parent.Err() == nil && errors.Is(buildCtx.Err(), context.DeadlineExceeded) {
+		return &BuildTimeoutError{Timeout: timeout, Phase: phase}
+	}
+	return err
+}
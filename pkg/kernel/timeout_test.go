@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBuildTimeoutError(t *testing.T) {
+	buildErr := errors.New("signal: killed")
+
+	// The build deadline passed while the parent is still live
+	parent := context.Background()
+	buildCtx, cancel := context.WithTimeout(parent, time.Nanosecond)
+	defer cancel()
+	<-buildCtx.Done()
+
+	err := buildTimeoutError(parent, buildCtx, time.Hour, "compile", buildErr)
+	var timeoutErr *BuildTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("buildTimeoutError() = %v, want a *BuildTimeoutError", err)
+	}
+	if timeoutErr.Phase != "compile" || timeoutErr.Timeout != time.Hour {
+		t.Errorf("BuildTimeoutError = %+v, want compile phase after 1h", timeoutErr)
+	}
+	if err.Error() != "build timed out after 1h0m0s in compile phase" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+	// Before the first phase starts there is no phase to name
+	err = buildTimeoutError(parent, buildCtx, time.Hour, "", buildErr)
+	if err.Error() != "build timed out after 1h0m0s" {
+		t.Errorf("Error() without a phase = %q", err.Error())
+	}
+
+	// A successful build isn't turned into a timeout
+	if err := buildTimeoutError(parent, buildCtx, time.Hour, "package", nil); err != nil {
+		t.Errorf("buildTimeoutError() of a nil error = %v", err)
+	}
+}
+
+func TestBuildTimeoutErrorParentCancelled(t *testing.T) {
+	// The user cancelled: the build context is done too, but the error is
+	// the context's, not a timeout
+	parent, cancelParent := context.WithCancel(context.Background())
+	buildCtx, cancel := context.WithTimeout(parent, time.Hour)
+	defer cancel()
+	cancelParent()
+
+	err := buildTimeoutError(parent, buildCtx, time.Hour, "compile", context.Canceled)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("buildTimeoutError() = %v, want context.Canceled", err)
+	}
+	var timeoutErr *BuildTimeoutError
+	if errors.As(err, &timeoutErr) {
+		t.Errorf("a cancelled build was reported as a timeout: %v", err)
+	}
+
+	// Likewise when the parent's own deadline passed
+	parent, cancelParent = context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancelParent()
+	buildCtx, cancel = context.WithTimeout(parent, time.Hour)
+	defer cancel()
+	<-buildCtx.Done()
+
+	err = buildTimeoutError(parent, buildCtx, time.Hour, "compile", context.DeadlineExceeded)
+	if errors.As(err, &timeoutErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("buildTimeoutError() after the parent's deadline = %v, want the context error", err)
+	}
+}
+
+func TestWithPhaseTimeout(t *testing.T) {
+	err := withPhaseTimeout(context.Background(), PhaseCompile, time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	var phaseErr *PhaseTimeoutError
+	if !errors.As(err, &phaseErr) || phaseErr.Phase != PhaseCompile {
+		t.Errorf("withPhaseTimeout() = %v, want a compile *PhaseTimeoutError", err)
+	}
+
+	parent, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = withPhaseTimeout(parent, PhaseCompile, time.Hour, func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("withPhaseTimeout() with a cancelled parent = %v, want context.Canceled", err)
+	}
+}
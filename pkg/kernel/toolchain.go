@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Toolchains a kernel can be built with
+const (
+	ToolchainGCC  = "gcc"
+	ToolchainLLVM = "llvm"
+)
+
+// Toolchains lists the supported toolchains
+var Toolchains = []string{ToolchainGCC, ToolchainLLVM}
+
+// resolveToolchain defaults the toolchain to gcc and rejects unknown ones
+func resolveToolchain(opts *BuildOptions) error {
+	switch opts.Toolchain {
+	case "":
+		opts.Toolchain = ToolchainGCC
+	case ToolchainGCC, ToolchainLLVM:
+	default:
+		return fmt.Errorf("invalid toolchain: %s (must be: %s)", opts.Toolchain, strings.Join(Toolchains, ", "))
+	}
+	return nil
+}
+
+// configMakeArgs returns the make variables for config targets
+// (olddefconfig, defconfig). Kconfig probes the compiler, so LLVM=1 has to
+// be passed here as well as to the compile.
+func configMakeArgs(arch, toolchain string) []string {
+	var args []string
+	if arch == "aarch64" {
+		args = append(args, "ARCH=arm64")
+	}
+	if toolchain == ToolchainLLVM {
+		args = append(args, "LLVM=1")
+	}
+	return args
+}
+
+// compileMakeArgs returns the make variables for prepare and the image
+// build. clang cross-compiles from ARCH alone; gcc needs the cross prefix.
+func compileMakeArgs(arch, toolchain string) []string {
+	args := configMakeArgs(arch, toolchain)
+	if arch == "aarch64" && toolchain != ToolchainLLVM {
+		args = append(args, "CROSS_COMPILE=aarch64-linux-gnu-")
+	}
+	return args
+}
+
+// checkLLVMTools verifies clang and ld.lld are installed and, for aarch64,
+// that clang was built with the AArch64 target
+func checkLLVMTools(arch string) error {
+	clang, err := exec.LookPath("clang")
+	if err != nil {
+		return fmt.Errorf("clang not found. Install with: sudo apt-get install clang lld llvm")
+	}
+	if _, err := exec.LookPath("ld.lld"); err != nil {
+		return fmt.Errorf("ld.lld not found. Install with: sudo apt-get install lld")
+	}
+
+	if arch == "aarch64" {
+		out, err := exec.Command(clang, "-print-targets").Output()
+		if err != nil {
+			return fmt.Errorf("failed to list clang targets: %w", err)
+		}
+		if !strings.Contains(string(out), "aarch64") {
+			return fmt.Errorf("clang at %s has no AArch64 target support. Install a full LLVM build with: sudo apt-get install clang lld llvm", clang)
+		}
+	}
+
+	return nil
+}
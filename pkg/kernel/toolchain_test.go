@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeTools puts executable shell scripts named after each tool on a PATH
+// holding nothing else, for checks that only look tools up or run them
+func fakeTools(t *testing.T, tools map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, script := range tools {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+	return dir
+}
+
+func TestResolveToolchain(t *testing.T) {
+	tests := []struct {
+		toolchain string
+		want      string
+		wantErr   bool
+	}{
+		{"", ToolchainGCC, false},
+		{ToolchainGCC, ToolchainGCC, false},
+		{ToolchainLLVM, ToolchainLLVM, false},
+		{"icc", "", true},
+	}
+	for _, tt := range tests {
+		opts := BuildOptions{Toolchain: tt.toolchain}
+		err := resolveToolchain(&opts)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && opts.Toolchain != tt.want) {
+			t.Errorf("resolveToolchain(%q) = %q, %v", tt.toolchain, opts.Toolchain, err)
+		}
+	}
+}
+
+func TestMakeArgs(t *testing.T) {
+	tests := []struct {
+		arch      string
+		toolchain string
+		config    []string
+		compile   []string
+	}{
+		{"x86_64", ToolchainGCC, nil, nil},
+		{"x86_64", ToolchainLLVM, []string{"LLVM=1"}, []string{"LLVM=1"}},
+		{"aarch64", ToolchainGCC, []string{"ARCH=arm64"}, []string{"ARCH=arm64", "CROSS_COMPILE=aarch64-linux-gnu-"}},
+		// clang cross-compiles without a prefix
+		{"aarch64", ToolchainLLVM, []string{"ARCH=arm64", "LLVM=1"}, []string{"ARCH=arm64", "LLVM=1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arch+"/"+tt.toolchain, func(t *testing.T) {
+			if got := configMakeArgs(tt.arch, tt.toolchain); !slices.Equal(got, tt.config) {
+				t.Errorf("configMakeArgs() = %v, want %v", got, tt.config)
+			}
+			if got := compileMakeArgs(tt.arch, tt.toolchain); !slices.Equal(got, tt.compile) {
+				t.Errorf("compileMakeArgs() = %v, want %v", got, tt.compile)
+			}
+		})
+	}
+}
+
+func TestCcacheCompilerArg(t *testing.T) {
+	tests := []struct {
+		arch      string
+		toolchain string
+		want      string
+	}{
+		{"x86_64", ToolchainGCC, "CC=/usr/bin/ccache gcc"},
+		{"aarch64", ToolchainGCC, "CC=/usr/bin/ccache aarch64-linux-gnu-gcc"},
+		{"x86_64", ToolchainLLVM, "CC=/usr/bin/ccache clang"},
+		{"aarch64", ToolchainLLVM, "CC=/usr/bin/ccache clang"},
+	}
+	for _, tt := range tests {
+		if got := ccacheCompilerArg("/usr/bin/ccache", tt.arch, tt.toolchain); got != tt.want {
+			t.Errorf("ccacheCompilerArg(%s, %s) = %q, want %q", tt.arch, tt.toolchain, got, tt.want)
+		}
+	}
+}
+
+func TestCheckLLVMTools(t *testing.T) {
+	const (
+		clangX86   = `echo "  Registered Targets:"; echo "    x86-64 - 64-bit X86: EM64T and AMD64"`
+		clangArm64 = clangX86 + `; echo "    aarch64 - AArch64 (little endian)"`
+	)
+	tests := []struct {
+		name    string
+		arch    string
+		tools   map[string]string
+		wantErr string
+	}{
+		{"no clang", "x86_64", map[string]string{"ld.lld": ""}, "clang not found"},
+		{"no lld", "x86_64", map[string]string{"clang": clangArm64}, "ld.lld not found"},
+		{"x86_64", "x86_64", map[string]string{"clang": clangX86, "ld.lld": ""}, ""},
+		{"aarch64 without the target", "aarch64", map[string]string{"clang": clangX86, "ld.lld": ""}, "has no AArch64 target support"},
+		{"aarch64", "aarch64", map[string]string{"clang": clangArm64, "ld.lld": ""}, ""},
+		{"clang fails", "aarch64", map[string]string{"clang": "exit 1", "ld.lld": ""}, "failed to list clang targets"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeTools(t, tt.tools)
+			err := checkLLVMTools(tt.arch)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkLLVMTools(%s) error = %v", tt.arch, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkLLVMTools(%s) error = %v, want %q", tt.arch, err, tt.wantErr)
+			}
+		})
+	}
+}
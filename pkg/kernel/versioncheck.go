@@ -2,16 +2,14 @@
 package kernel
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
-	"time"
-)
 
-// httpTimeout is the timeout for HTTP requests to kernel.org.
-// Prevents indefinite hangs in CI if kernel.org is slow or unreachable.
-const httpTimeout = 30 * time.Second
+	"github.com/Work-Fort/Anvil/pkg/download"
+)
 
 // VersionCheckResult holds the outcome of a version check.
 type VersionCheckResult struct {
@@ -25,18 +23,21 @@ type VersionCheckResult struct {
 }
 
 // CheckVersion validates that a kernel version is available on kernel.org
-// and has checksums ready for verified builds. If version is empty or "latest",
-// the latest stable version is resolved automatically.
+// and has checksums ready for verified builds. If version is empty or an
+// alias ("latest", "lts"), it is resolved automatically.
 //
 // Returns (*VersionCheckResult, nil) for all check outcomes (including "not buildable").
 // Returns (nil, error) only for hard failures (e.g., cannot reach kernel.org for
-// version resolution).
-func CheckVersion(version string) (*VersionCheckResult, error) {
-	// Resolve "latest" or empty to actual version
-	if version == "" || version == "latest" {
-		resolved, err := GetLatestKernelVersion()
+// version resolution). Cancelling ctx aborts the kernel.org requests.
+func CheckVersion(ctx context.Context, version string) (*VersionCheckResult, error) {
+	// Resolve an alias or empty to actual version
+	if version == "" {
+		version = VersionLatest
+	}
+	if IsVersionAlias(version) {
+		resolved, err := ResolveSourceVersion(ctx, version)
 		if err != nil {
-			return nil, fmt.Errorf("failed to resolve latest kernel version: %w", err)
+			return nil, err
 		}
 		version = resolved
 	}
@@ -52,7 +53,10 @@ func CheckVersion(version string) (*VersionCheckResult, error) {
 	}
 
 	// Step 1: Check if version exists in kernel.org releases
-	if err := ValidateVersion(version); err != nil {
+	if err := ValidateVersion(ctx, version); err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
 		result.Available = false
 		result.Buildable = false
 		result.Message = fmt.Sprintf("Version %s not found in kernel.org releases", version)
@@ -61,8 +65,13 @@ func CheckVersion(version string) (*VersionCheckResult, error) {
 	result.Available = true
 
 	// Step 2: Check if checksums file is accessible and contains this version
-	client := &http.Client{Timeout: httpTimeout}
-	resp, err := client.Get(checksumsURL)
+	// The client's timeout prevents indefinite hangs in CI if kernel.org is
+	// slow or unreachable
+	req, err := http.NewRequestWithContext(ctx, "GET", checksumsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := download.HTTPClient().Do(req)
 	if err != nil {
 		result.ChecksumsReady = false
 		result.Buildable = false
@@ -4,11 +4,12 @@
 package kernel
 
 import (
+	"context"
 	"testing"
 )
 
 func TestCheckVersion_LatestStable(t *testing.T) {
-	result, err := CheckVersion("")
+	result, err := CheckVersion(context.Background(), "")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -25,7 +26,7 @@ func TestCheckVersion_LatestStable(t *testing.T) {
 }
 
 func TestCheckVersion_NonExistent(t *testing.T) {
-	result, err := CheckVersion("99.99.99")
+	result, err := CheckVersion(context.Background(), "99.99.99")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -41,7 +42,7 @@ func TestCheckVersion_NonExistent(t *testing.T) {
 }
 
 func TestCheckVersion_LatestKeyword(t *testing.T) {
-	result, err := CheckVersion("latest")
+	result, err := CheckVersion(context.Background(), "latest")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -0,0 +1,299 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+// DefaultWatchDebounce is how long the watched files must stay unchanged
+// before watch mode rebuilds
+const DefaultWatchDebounce = 2 * time.Second
+
+// watchPollInterval is how often watch mode checks the watched files (a
+// variable so tests can poll faster)
+var watchPollInterval = 500 * time.Millisecond
+
+// Watch builds a kernel once and then watches the kernel config file and the
+// source tree's .config, re-running the configure, compile and package phases
+// whenever either changes. The extracted source tree is reused between
+// rebuilds. Watch returns nil when opts.Context is cancelled.
+func Watch(opts BuildOptions, paths *config.Paths, debounce time.Duration) error {
+	if opts.Arch == "" {
+		arch, err := config.GetArch()
+		if err != nil {
+			return err
+		}
+		opts.Arch = arch
+	}
+	if opts.Arch == "all" {
+		return fmt.Errorf("watch mode supports a single architecture (x86_64 or aarch64)")
+	}
+	if debounce <= 0 {
+		debounce = DefaultWatchDebounce
+	}
+
+	writer := opts.Writer
+	if writer == nil {
+		writer = os.Stdout
+		opts.Writer = writer
+	}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+		opts.Context = ctx
+	}
+	logger := &buildLogger{writer: writer, reporter: opts.Progress}
+	if err := checkRunningAsRoot(logger, opts.AllowRoot, paths.KernelBuildDir); err != nil {
+		return err
+	}
+
+	if err := resolveLocalSource(&opts); err != nil {
+		return err
+	}
+	if err := resolvePatches(&opts); err != nil {
+		return err
+	}
+	if err := resolveCompression(&opts); err != nil {
+		return err
+	}
+	if err := resolveToolchain(&opts); err != nil {
+		return err
+	}
+	if opts.VerificationLevel == "" {
+		opts.VerificationLevel = "high"
+	}
+
+	// Pin the version so every rebuild uses the same source tree
+	if opts.Version == "" {
+		logger.Info("Fetching latest stable kernel version from kernel.org...")
+		version, err := GetLatestKernelVersion(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch latest kernel version: %w", err)
+		}
+		opts.Version = version
+	} else if IsVersionAlias(opts.Version) {
+		version, err := ResolveSourceVersion(ctx, opts.Version)
+		if err != nil {
+			return err
+		}
+		logger.Info(fmt.Sprintf("Resolved kernel %s to %s", opts.Version, version))
+		opts.Version = version
+	}
+
+	kernelSrcDir := filepath.Join(versionBuildDir(paths, opts.Version, opts.Arch), fmt.Sprintf("linux-%s", opts.Version))
+
+	// Initial build, unless the source tree is already extracted (with
+	// the same patches). The build is forced, since an existing kernel
+	// artifact would otherwise skip the extract the watch needs.
+	if _, err := os.Stat(kernelSrcDir); err == nil && !sourceTreeMatchesPatches(kernelSrcDir, opts.Patches) {
+		logger.Info("Source tree was patched differently, re-extracting")
+		if err := os.RemoveAll(kernelSrcDir); err != nil {
+			return fmt.Errorf("failed to remove patched source tree: %w", err)
+		}
+	}
+	if _, err := os.Stat(kernelSrcDir); os.IsNotExist(err) {
+		buildOpts := opts
+		buildOpts.ForceRebuild = true
+		if err := Build(buildOpts, paths); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if _, err := os.Stat(kernelSrcDir); os.IsNotExist(err) {
+			return fmt.Errorf("kernel source tree not found: %s\nClear the build cache with 'anvil clean build' and try again", kernelSrcDir)
+		}
+	} else {
+		logger.Info(fmt.Sprintf("Reusing kernel source tree: %s", kernelSrcDir))
+		if err := rebuildFromSource(logger, opts, paths, kernelSrcDir, true); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			logger.Error(err.Error())
+		}
+	}
+
+	configFile, err := resolveKernelConfigFile(logger, opts)
+	if err != nil {
+		return err
+	}
+	srcConfig := filepath.Join(kernelSrcDir, ".config")
+
+	logger.Info(fmt.Sprintf("Watching %s and %s for changes (Ctrl-C to stop)...", configFile, srcConfig))
+
+	lastConfig := fileModTime(configFile)
+	lastSrcConfig := fileModTime(srcConfig)
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Watch stopped")
+			return nil
+		case <-ticker.C:
+		}
+
+		configChanged := !fileModTime(configFile).Equal(lastConfig)
+		srcConfigChanged := !fileModTime(srcConfig).Equal(lastSrcConfig)
+		if !configChanged && !srcConfigChanged {
+			continue
+		}
+
+		// Debounce: wait until the files stop changing
+		if !waitForQuiet(ctx, debounce, configFile, srcConfig) {
+			logger.Info("Watch stopped")
+			return nil
+		}
+
+		if configChanged {
+			logger.Info(fmt.Sprintf("Detected change in %s, rebuilding...", configFile))
+		} else {
+			logger.Info(fmt.Sprintf("Detected change in %s, rebuilding...", srcConfig))
+		}
+
+		// A change to the repo config is copied over .config; a direct edit of
+		// .config (e.g. via menuconfig) is kept as-is.
+		if err := rebuildFromSource(logger, opts, paths, kernelSrcDir, configChanged); err != nil {
+			if ctx.Err() != nil {
+				logger.Info("Watch stopped")
+				return nil
+			}
+			logger.Error(err.Error())
+		}
+
+		// olddefconfig rewrites .config, so take fresh snapshots after the rebuild
+		lastConfig = fileModTime(configFile)
+		lastSrcConfig = fileModTime(srcConfig)
+		logger.Info("Waiting for changes...")
+	}
+}
+
+// rebuildFromSource runs the configure, compile and package phases against an
+// already extracted source tree and records fresh build stats.
+func rebuildFromSource(logger *buildLogger, opts BuildOptions, paths *config.Paths, kernelSrcDir string, reapplyConfig bool) error {
+	parent := opts.Context
+	ctx := parent
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(parent, opts.Timeout)
+		defer cancel()
+	}
+	buildStartTime := time.Now()
+	artifactsDir := filepath.Join(paths.KernelBuildDir, "artifacts")
+	if err := os.MkdirAll(artifactsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create artifacts directory: %w", err)
+	}
+	if err := checkFreeSpace(logger, paths.KernelBuildDir, config.GetKernelsMinFreeSpaceGB()); err != nil {
+		return err
+	}
+
+	kernelFilename, kernelImage := kernelArtifactNames(opts.Version, opts.Arch)
+	kernelPath := filepath.Join(artifactsDir, kernelFilename)
+
+	_, phaseCallback := opts.progressCallbacks()
+	if phaseCallback != nil {
+		phaseCallback(PhaseConfigure)
+	}
+	configureStart := time.Now()
+	if err := withPhaseTimeout(ctx, PhaseConfigure, opts.ConfigureTimeout, func(ctx context.Context) error {
+		if reapplyConfig {
+			return applyKernelConfig(logger, opts, kernelSrcDir, ctx)
+		}
+		return runOldDefconfig(logger, opts.Arch, opts.Toolchain, kernelSrcDir, ctx)
+	}); err != nil {
+		return buildTimeoutError(parent, ctx, opts.Timeout, PhaseConfigure.String(), err)
+	}
+	configureDuration := time.Since(configureStart)
+
+	if phaseCallback != nil {
+		phaseCallback(PhaseCompile)
+	}
+	compileStart := time.Now()
+	if err := withPhaseTimeout(ctx, PhaseCompile, opts.CompileTimeout, func(ctx context.Context) error {
+		if err := compileKernel(logger, opts, kernelSrcDir, kernelImage, ctx); err != nil {
+			return err
+		}
+		if opts.BuildModules {
+			return buildModules(logger, opts, kernelSrcDir, ctx)
+		}
+		return nil
+	}); err != nil {
+		return buildTimeoutError(parent, ctx, opts.Timeout, PhaseCompile.String(), err)
+	}
+	compileDuration := time.Since(compileStart)
+
+	if phaseCallback != nil {
+		phaseCallback(PhasePackage)
+	}
+	stats, err := packageBuild(logger, opts, opts.Version, kernelSrcDir, kernelImage, artifactsDir, kernelFilename, ctx, func(packageDuration time.Duration) BuildStats {
+		return collectBuildStats(opts.Version, kernelPath, kernelPath+util.CompressionExt(opts.Compression), time.Since(buildStartTime), 0, 0, configureDuration, compileDuration, packageDuration)
+	})
+	if err != nil {
+		return buildTimeoutError(parent, ctx, opts.Timeout, PhasePackage.String(), err)
+	}
+
+	logger.Info("Rebuild completed successfully!")
+
+	if opts.StatsCallback != nil {
+		opts.StatsCallback(stats)
+	}
+
+	return nil
+}
+
+// waitForQuiet blocks until none of the given files has changed for the
+// debounce duration. Returns false if ctx is cancelled first.
+func waitForQuiet(ctx context.Context, debounce time.Duration, files ...string) bool {
+	snapshot := func() []time.Time {
+		times := make([]time.Time, len(files))
+		for i, f := range files {
+			times[i] = fileModTime(f)
+		}
+		return times
+	}
+
+	last := snapshot()
+	quietSince := time.Now()
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+
+		current := snapshot()
+		for i := range current {
+			if !current[i].Equal(last[i]) {
+				quietSince = time.Now()
+				break
+			}
+		}
+		last = current
+
+		if time.Since(quietSince) >= debounce {
+			return true
+		}
+	}
+}
+
+// fileModTime returns the modification time of path, or the zero time if it
+// cannot be stat'd.
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
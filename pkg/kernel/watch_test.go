@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+package kernel
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+// fastWatchPolling shortens watchPollInterval for the test
+func fastWatchPolling(t *testing.T) {
+	t.Helper()
+	orig := watchPollInterval
+	watchPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { watchPollInterval = orig })
+}
+
+func TestWaitForQuiet(t *testing.T) {
+	fastWatchPolling(t)
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "microvm-kernel-x86_64.config")
+	srcConfig := filepath.Join(dir, ".config")
+	if err := os.WriteFile(configFile, []byte("CONFIG_VIRTIO=y\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	const debounce = 200 * time.Millisecond
+
+	// Unchanged files (and one that doesn't exist yet) are quiet after
+	// the debounce
+	start := time.Now()
+	if !waitForQuiet(context.Background(), debounce, configFile, srcConfig) {
+		t.Fatal("waitForQuiet() = false without cancellation")
+	}
+	if elapsed := time.Since(start); elapsed < debounce {
+		t.Errorf("waitForQuiet() returned after %v, before the %v debounce", elapsed, debounce)
+	}
+
+	// Each change, including creating a file, restarts the quiet period
+	done := make(chan time.Time)
+	go func() {
+		for i := range 4 {
+			time.Sleep(debounce / 4)
+			mtime := time.Now().Add(time.Duration(i+1) * time.Second)
+			os.Chtimes(configFile, mtime, mtime)
+		}
+		time.Sleep(debounce / 4)
+		os.WriteFile(srcConfig, []byte("CONFIG_VIRTIO=y\n"), 0644)
+		done <- time.Now()
+	}()
+	if !waitForQuiet(context.Background(), debounce, configFile, srcConfig) {
+		t.Fatal("waitForQuiet() = false without cancellation")
+	}
+	lastChange := <-done
+	if quiet := time.Since(lastChange); quiet < debounce {
+		t.Errorf("waitForQuiet() returned %v after the last change, before the %v debounce", quiet, debounce)
+	}
+}
+
+func TestWaitForQuietCancelled(t *testing.T) {
+	fastWatchPolling(t)
+	configFile := filepath.Join(t.TempDir(), "microvm-kernel-x86_64.config")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	if waitForQuiet(ctx, time.Hour, configFile) {
+		t.Fatal("waitForQuiet() = true after cancellation")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("waitForQuiet() took %v to notice cancellation", elapsed)
+	}
+}
+
+// sourceTarballFixture writes a linux-<version>.tar.xz holding the top of a
+// kernel tree, enough to pass checkSourceTree
+func sourceTarballFixture(t *testing.T, version string) string {
+	t.Helper()
+	major, rest, _ := strings.Cut(version, ".")
+	patchlevel, _, _ := strings.Cut(rest, ".")
+	files := map[string]string{
+		"Makefile":           fmt.Sprintf("VERSION = %s\nPATCHLEVEL = %s\n", major, patchlevel),
+		"Kconfig":            "mainmenu \"Linux\"\n",
+		"arch/x86/Kconfig":   "config X86\n",
+		"arch/arm64/Kconfig": "config ARM64\n",
+	}
+
+	dir := t.TempDir()
+	tarPath := filepath.Join(dir, "linux-"+version+".tar")
+	f, err := os.Create(tarPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw := tar.NewWriter(f)
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		hdr := &tar.Header{Name: "linux-" + version + "/" + name, Mode: 0644, Size: int64(len(files[name])), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(files[name])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := util.CompressWithProgress(util.CompressionXZ, tarPath, tarPath+".xz", nil); err != nil {
+		t.Fatal(err)
+	}
+	return tarPath + ".xz"
+}
+
+func TestWatchRebuildsMissingSourceTree(t *testing.T) {
+	paths := config.PathsUnder(t.TempDir())
+	// The kernel is built, but its source tree was cleaned away
+	cachedBuild(t, paths, "6.18.9", "x86_64")
+	kernelSrcDir := filepath.Join(versionBuildDir(paths, "6.18.9", "x86_64"), "linux-6.18.9")
+
+	// make "builds" vmlinux in the tree it's run in
+	fakeTools(t, map[string]string{
+		"make": `for arg; do [ "$arg" = vmlinux ] && echo "ELF rebuilt" > vmlinux; done; exit 0`,
+		"gcc":  "exit 0",
+	})
+	configFile := filepath.Join(t.TempDir(), "microvm-kernel-x86_64.config")
+	if err := os.WriteFile(configFile, []byte("CONFIG_VIRTIO=y\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Stop watching once the initial build is done
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	opts := BuildOptions{
+		Version:           "6.18.9",
+		Arch:              "x86_64",
+		SourceTarball:     sourceTarballFixture(t, "6.18.9"),
+		VerificationLevel: "disabled",
+		ConfigFile:        configFile,
+		AllowRoot:         true,
+		Writer:            io.Discard,
+		Context:           ctx,
+		StatsCallback:     func(BuildStats) { cancel() },
+	}
+	if err := Watch(opts, paths, time.Millisecond); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(kernelSrcDir, "Makefile")); err != nil {
+		t.Errorf("source tree wasn't extracted: %v", err)
+	}
+	kernelFilename, _ := kernelArtifactNames("6.18.9", "x86_64")
+	data, err := os.ReadFile(filepath.Join(paths.KernelBuildDir, "artifacts", kernelFilename))
+	if err != nil || string(data) != "ELF rebuilt\n" {
+		t.Errorf("artifact = %q, %v, want the rebuilt kernel", data, err)
+	}
+}
@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+package rootfs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Work-Fort/Anvil/pkg/download"
+	"github.com/charmbracelet/log"
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultAlpineMirror is the Alpine CDN used when no mirror is configured
+const DefaultAlpineMirror = "https://dl-cdn.alpinelinux.org"
+
+// normalizeAlpineMirror validates an Alpine mirror base URL and returns it
+// without a trailing slash. Mirrors must use https and keep the CDN's path
+// layout (<base>/alpine/v<version>/releases/<arch>/...).
+func normalizeAlpineMirror(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid Alpine mirror URL %q: %w", raw, err)
+	}
+	if u.Scheme != "https" {
+		return "", fmt.Errorf("invalid Alpine mirror URL %q: must use https", raw)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid Alpine mirror URL %q: missing host", raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("invalid Alpine mirror URL %q: query and fragment are not allowed", raw)
+	}
+	return strings.TrimRight(u.String(), "/"), nil
+}
+
+// alpineMinirootfsName returns the minirootfs tarball filename for a release
+func alpineMinirootfsName(version, patch, arch string) string {
+	return fmt.Sprintf("alpine-minirootfs-%s.%s-%s.tar.gz", version, patch, arch)
+}
+
+// alpineReleasesURL returns the releases directory for version and arch
+// under mirror
+func alpineReleasesURL(mirror, version, arch string) string {
+	return fmt.Sprintf("%s/alpine/v%s/releases/%s", mirror, version, arch)
+}
+
+// Fallback Alpine release used when discovery fails (e.g. offline)
+const (
+	fallbackAlpineVersion = "3.23"
+	fallbackAlpinePatch   = "3"
+)
+
+// alpineDiscoveryTTL is how long a discovered release is reused before the
+// mirror is asked again
+const alpineDiscoveryTTL = 24 * time.Hour
+
+// alpineDiscoveryCacheFile is the cache file name under the anvil cache dir
+const alpineDiscoveryCacheFile = "alpine-releases.json"
+
+// alpineRelease is one entry of a latest-releases.yaml file
+type alpineRelease struct {
+	Flavor  string `yaml:"flavor"`
+	Version string `yaml:"version"`
+	Arch    string `yaml:"arch"`
+}
+
+// cachedAlpineRelease is a discovered release stored in the cache file
+type cachedAlpineRelease struct {
+	Version   string    `json:"version"`
+	Patch     string    `json:"patch"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// DiscoverAlpineRelease returns the newest Alpine minirootfs release for arch
+// from the mirror's latest-releases.yaml, as major.minor version and patch.
+// With version empty the latest stable branch is used; otherwise the newest
+// patch of that branch. Results are cached in cacheDir for a day.
+func DiscoverAlpineRelease(mirror, version, arch, cacheDir string) (string, string, error) {
+	branch := "latest-stable"
+	if version != "" {
+		branch = "v" + version
+	}
+	cacheKey := fmt.Sprintf("%s|%s|%s", mirror, branch, arch)
+	cachePath := filepath.Join(cacheDir, alpineDiscoveryCacheFile)
+
+	cache := readAlpineDiscoveryCache(cachePath)
+	if entry, ok := cache[cacheKey]; ok && time.Since(entry.FetchedAt) < alpineDiscoveryTTL {
+		return entry.Version, entry.Patch, nil
+	}
+
+	releasesURL := fmt.Sprintf("%s/alpine/%s/releases/%s/latest-releases.yaml", mirror, branch, arch)
+	resp, err := download.HTTPClient().Get(releasesURL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to fetch %s: %w", releasesURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("failed to fetch %s: HTTP %d", releasesURL, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read %s: %w", releasesURL, err)
+	}
+
+	discoveredVersion, patch, err := parseLatestReleases(data, arch)
+	if err != nil {
+		return "", "", err
+	}
+
+	cache[cacheKey] = cachedAlpineRelease{Version: discoveredVersion, Patch: patch, FetchedAt: time.Now()}
+	writeAlpineDiscoveryCache(cachePath, cache)
+
+	return discoveredVersion, patch, nil
+}
+
+// parseLatestReleases finds the minirootfs entry for arch in a
+// latest-releases.yaml document and splits its version ("3.23.3") into
+// major.minor and patch
+func parseLatestReleases(data []byte, arch string) (string, string, error) {
+	var releases []alpineRelease
+	if err := yaml.Unmarshal(data, &releases); err != nil {
+		return "", "", fmt.Errorf("failed to parse latest-releases.yaml: %w", err)
+	}
+
+	for _, r := range releases {
+		if r.Flavor != "alpine-minirootfs" || (r.Arch != "" && r.Arch != arch) {
+			continue
+		}
+		parts := strings.Split(r.Version, ".")
+		if len(parts) != 3 {
+			return "", "", fmt.Errorf("unexpected Alpine version format: %q", r.Version)
+		}
+		return parts[0] + "." + parts[1], parts[2], nil
+	}
+
+	return "", "", fmt.Errorf("no alpine-minirootfs release for %s in latest-releases.yaml", arch)
+}
+
+// readAlpineDiscoveryCache loads the discovery cache; a missing or corrupt
+// file yields an empty cache
+func readAlpineDiscoveryCache(path string) map[string]cachedAlpineRelease {
+	cache := make(map[string]cachedAlpineRelease)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		log.Debugf("Ignoring corrupt Alpine discovery cache %s: %v", path, err)
+		return make(map[string]cachedAlpineRelease)
+	}
+	return cache
+}
+
+// writeAlpineDiscoveryCache saves the discovery cache. Failures only cost a
+// refetch next time, so they are logged and ignored.
+func writeAlpineDiscoveryCache(path string, cache map[string]cachedAlpineRelease) {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.Debugf("Failed to create cache directory: %v", err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		log.Debugf("Failed to write Alpine discovery cache: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+package rootfs
+
+import "testing"
+
+func TestNormalizeAlpineMirror(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{name: "default", raw: DefaultAlpineMirror, want: DefaultAlpineMirror},
+		{name: "trailing slash", raw: "https://mirror.example.com/", want: "https://mirror.example.com"},
+		{name: "with path", raw: "https://mirror.example.com/pub/linux/", want: "https://mirror.example.com/pub/linux"},
+		{name: "http", raw: "http://mirror.example.com", wantErr: true},
+		{name: "no host", raw: "https:///alpine", wantErr: true},
+		{name: "query", raw: "https://mirror.example.com/?x=1", wantErr: true},
+		{name: "not a url", raw: "mirror.example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeAlpineMirror(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("normalizeAlpineMirror(%q) = %q, want error", tt.raw, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("normalizeAlpineMirror(%q) error = %v", tt.raw, err)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeAlpineMirror(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLatestReleases(t *testing.T) {
+	const doc = `---
+-
+  title: "Standard"
+  flavor: alpine-standard
+  version: 3.23.3
+  arch: x86_64
+-
+  title: "Mini root filesystem"
+  flavor: alpine-minirootfs
+  branch: v3.23
+  version: 3.23.3
+  arch: x86_64
+  file: alpine-minirootfs-3.23.3-x86_64.tar.gz
+`
+
+	version, patch, err := parseLatestReleases([]byte(doc), "x86_64")
+	if err != nil {
+		t.Fatalf("parseLatestReleases() error = %v", err)
+	}
+	if version != "3.23" || patch != "3" {
+		t.Errorf("parseLatestReleases() = %s, %s, want 3.23, 3", version, patch)
+	}
+
+	if _, _, err := parseLatestReleases([]byte(doc), "aarch64"); err == nil {
+		t.Error("parseLatestReleases() for missing arch: want error")
+	}
+
+	if _, _, err := parseLatestReleases([]byte("not: [valid"), "x86_64"); err == nil {
+		t.Error("parseLatestReleases() for invalid YAML: want error")
+	}
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+package rootfs
+
+import (
+	"debug/elf"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// archSpec describes what a rootfs needs for one target architecture
+type archSpec struct {
+	elfMachine elf.Machine
+	// linkerGuestPath is where the glibc dynamic linker lives in the guest
+	linkerGuestPath string
+	// linkerHostPaths are host locations to copy the linker from, native
+	// path first, then the usual cross-toolchain sysroot paths
+	linkerHostPaths []string
+}
+
+var archSpecs = map[string]archSpec{
+	"x86_64": {
+		elfMachine:      elf.EM_X86_64,
+		linkerGuestPath: "/lib64/ld-linux-x86-64.so.2",
+		linkerHostPaths: []string{
+			"/lib64/ld-linux-x86-64.so.2",
+			"/usr/x86_64-linux-gnu/lib64/ld-linux-x86-64.so.2",
+			"/usr/x86_64-linux-gnu/lib/ld-linux-x86-64.so.2",
+		},
+	},
+	"aarch64": {
+		elfMachine:      elf.EM_AARCH64,
+		linkerGuestPath: "/lib/ld-linux-aarch64.so.1",
+		linkerHostPaths: []string{
+			"/lib/ld-linux-aarch64.so.1",
+			"/usr/aarch64-linux-gnu/lib/ld-linux-aarch64.so.1",
+			"/usr/aarch64-linux-gnu/lib64/ld-linux-aarch64.so.1",
+		},
+	},
+}
+
+// getArchSpec returns the spec for arch or an error for unsupported values
+func getArchSpec(arch string) (archSpec, error) {
+	spec, ok := archSpecs[arch]
+	if !ok {
+		return archSpec{}, fmt.Errorf("unsupported architecture: %s (supported: x86_64, aarch64)", arch)
+	}
+	return spec, nil
+}
+
+// CheckBinaryArch verifies that the ELF binary at path targets arch.
+// Returns a descriptive error for non-ELF files or a machine type mismatch.
+func CheckBinaryArch(path, arch string) error {
+	spec, err := getArchSpec(arch)
+	if err != nil {
+		return err
+	}
+
+	f, err := elf.Open(path)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid ELF binary: %w", path, err)
+	}
+	defer f.Close()
+
+	if f.Machine != spec.elfMachine {
+		return fmt.Errorf("%s is built for %s, not %s", path, elfMachineName(f.Machine), arch)
+	}
+	return nil
+}
+
+// findDynamicLinker returns a host path to the dynamic linker for arch whose
+// ELF machine type matches, or "" when none is installed.
+func findDynamicLinker(spec archSpec) string {
+	for _, path := range spec.linkerHostPaths {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		f, err := elf.Open(path)
+		if err != nil {
+			continue
+		}
+		machine := f.Machine
+		f.Close()
+		if machine == spec.elfMachine {
+			return path
+		}
+	}
+	return ""
+}
+
+// elfMachineName maps an ELF machine type to the arch names used by anvil
+func elfMachineName(m elf.Machine) string {
+	for arch, spec := range archSpecs {
+		if spec.elfMachine == m {
+			return arch
+		}
+	}
+	return strings.TrimPrefix(m.String(), "EM_")
+}
+
+// goArch maps an anvil arch name to the GOARCH value
+func goArch(arch string) string {
+	if arch == "aarch64" {
+		return "arm64"
+	}
+	return "amd64"
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+package rootfs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCheckBinaryArch(t *testing.T) {
+	var hostArch, otherArch string
+	switch runtime.GOARCH {
+	case "amd64":
+		hostArch, otherArch = "x86_64", "aarch64"
+	case "arm64":
+		hostArch, otherArch = "aarch64", "x86_64"
+	default:
+		t.Skipf("unsupported host architecture: %s", runtime.GOARCH)
+	}
+
+	// The running test binary is an ELF for the host arch
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CheckBinaryArch(self, hostArch); err != nil {
+		t.Errorf("CheckBinaryArch(self, %s) = %v, want nil", hostArch, err)
+	}
+
+	err = CheckBinaryArch(self, otherArch)
+	if err == nil {
+		t.Fatalf("CheckBinaryArch(self, %s) should fail on machine mismatch", otherArch)
+	}
+	if !strings.Contains(err.Error(), "built for "+hostArch) {
+		t.Errorf("mismatch error should name the binary's arch, got: %v", err)
+	}
+
+	script := filepath.Join(t.TempDir(), "script.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckBinaryArch(script, hostArch); err == nil {
+		t.Error("CheckBinaryArch should reject non-ELF files")
+	}
+
+	if err := CheckBinaryArch(self, "riscv64"); err == nil {
+		t.Error("CheckBinaryArch should reject unsupported architectures")
+	}
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+package rootfs
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultVsockPort is the port the injected vsock server listens on
+const DefaultVsockPort uint32 = 8000
+
+// vsockPortEnv is read by vsock-server-standalone to pick its listening port.
+// Binaries that don't know it ignore it and keep their own default.
+const vsockPortEnv = "ANVIL_VSOCK_PORT"
+
+// initScriptTemplate is the template for the init script.
+// It is formatted with the binary path (%[1]s), the vsock port env var
+// name (%[2]s) and the port (%[3]d).
+const initScriptTemplate = `#!/bin/sh
+# Init script for Firecracker VM
+
+# Mount essential filesystems
+mount -t proc none /proc
+mount -t sysfs none /sys
+mount -t devtmpfs none /dev
+
+# Setup networking (loopback)
+ip link set lo up
+
+# Print boot info
+echo "=========================================="
+echo "Anvil Firecracker VM"
+echo "Kernel version: $(uname -r)"
+echo "Architecture: $(uname -m)"
+echo "=========================================="
+
+# Start sshd when SSH keys were installed at build time
+if [ -x /usr/sbin/sshd ] && [ -s /root/.ssh/authorized_keys ]; then
+    echo "Starting sshd..."
+    /usr/sbin/sshd -e
+fi
+
+# Start vsock server if binary exists
+if [ -x %[1]s ]; then
+    echo "Starting vsock server..."
+    %[2]s=%[3]d %[1]s &
+    AGENT_PID=$!
+    echo "Server started with PID ${AGENT_PID}"
+else
+    echo "WARNING: Vsock server binary not found at %[1]s"
+fi
+
+# Keep VM running (block forever)
+echo "VM ready - vsock server running on port %[3]d"
+while true; do
+    sleep 1000
+done
+`
+
+var (
+	initScriptStartPort     = regexp.MustCompile(vsockPortEnv + `=(\d+) `)
+	initScriptAdvertisePort = regexp.MustCompile(`vsock server running on port (\d+)`)
+)
+
+// renderInitScript returns the /init script that starts binaryPath
+// listening on port
+func renderInitScript(binaryPath string, port uint32) string {
+	return fmt.Sprintf(initScriptTemplate, binaryPath, vsockPortEnv, port)
+}
+
+// initScriptPort returns the vsock port an init script starts the server
+// on. It fails if the script doesn't start a server or advertises a
+// different port from the one it starts the server with.
+func initScriptPort(script string) (uint32, error) {
+	start := initScriptStartPort.FindStringSubmatch(script)
+	if start == nil {
+		return 0, fmt.Errorf("init script does not set %s", vsockPortEnv)
+	}
+	port, err := strconv.ParseUint(start[1], 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid vsock port in init script: %s", start[1])
+	}
+
+	advertised := initScriptAdvertisePort.FindStringSubmatch(script)
+	if advertised == nil {
+		return 0, fmt.Errorf("init script does not advertise a vsock port")
+	}
+	if advertised[1] != start[1] {
+		return 0, fmt.Errorf("init script starts the vsock server on port %s but advertises port %s", start[1], advertised[1])
+	}
+
+	return uint32(port), nil
+}
+
+// verifyInitScriptPort checks that an init script starts and advertises
+// the vsock server on the expected port
+func verifyInitScriptPort(script string, port uint32) error {
+	got, err := initScriptPort(script)
+	if err != nil {
+		return err
+	}
+	if got != port {
+		return fmt.Errorf("init script uses vsock port %d, expected %d", got, port)
+	}
+	return nil
+}
+
+// loadInitScript reads a custom /init script from path. The kernel execs
+// /init directly, so the script must start with a shebang line.
+func loadInitScript(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read init script: %w", err)
+	}
+	if !strings.HasPrefix(string(data), "#!") {
+		return "", fmt.Errorf("init script %s must start with a shebang (e.g. #!/bin/sh)", path)
+	}
+	return string(data), nil
+}
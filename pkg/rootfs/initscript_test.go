@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+package rootfs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInitScriptPort(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		want    uint32
+		wantErr bool
+	}{
+		{name: "default port", script: renderInitScript("/usr/bin/vsock-server", DefaultVsockPort), want: DefaultVsockPort},
+		{name: "custom port", script: renderInitScript("/usr/bin/vsock-server", 9000), want: 9000},
+		{
+			name:    "advertised port differs",
+			script:  strings.Replace(renderInitScript("/usr/bin/vsock-server", 9000), "running on port 9000", "running on port 8000", 1),
+			wantErr: true,
+		},
+		{name: "no server started", script: "#!/bin/sh\necho \"VM ready - vsock server running on port 8000\"\n", wantErr: true},
+		{name: "no advertised port", script: "#!/bin/sh\nANVIL_VSOCK_PORT=8000 /usr/bin/vsock-server &\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := initScriptPort(tt.script)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("initScriptPort() = %d, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("initScriptPort() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("initScriptPort() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyInitScriptPort(t *testing.T) {
+	script := renderInitScript("/usr/bin/vsock-server", 9000)
+	if err := verifyInitScriptPort(script, 9000); err != nil {
+		t.Errorf("verifyInitScriptPort(9000) error = %v", err)
+	}
+	if err := verifyInitScriptPort(script, DefaultVsockPort); err == nil {
+		t.Error("verifyInitScriptPort(8000) should fail for a script using port 9000")
+	}
+}
+
+func TestLoadInitScript(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "init")
+	script := "#!/bin/sh\nmount -t proc none /proc\nexec /bin/sh\n"
+	if err := os.WriteFile(valid, []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadInitScript(valid)
+	if err != nil {
+		t.Fatalf("loadInitScript() error = %v", err)
+	}
+	if got != script {
+		t.Errorf("loadInitScript() = %q, want %q", got, script)
+	}
+
+	noShebang := filepath.Join(dir, "no-shebang")
+	if err := os.WriteFile(noShebang, []byte("mount -t proc none /proc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadInitScript(noShebang); err == nil || !strings.Contains(err.Error(), "shebang") {
+		t.Errorf("loadInitScript() without shebang error = %v, want shebang error", err)
+	}
+
+	if _, err := loadInitScript(filepath.Join(dir, "missing")); err == nil {
+		t.Error("loadInitScript() of a missing file should fail")
+	}
+}
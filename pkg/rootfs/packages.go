@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+package rootfs
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"libguestfs.org/guestfs"
+)
+
+// packageNamePattern matches an apk package name with an optional version
+// constraint (e.g. "curl" or "openssh=9.9_p2-r0"). Names may not start with
+// "-" so they can't be read as apk options.
+var packageNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+-]*([=<>~]{1,2}[A-Za-z0-9._+-]+)?$`)
+
+// validatePackages checks the names of extra packages to install
+func validatePackages(packages []string) error {
+	for _, pkg := range packages {
+		if !packageNamePattern.MatchString(pkg) {
+			return fmt.Errorf("invalid Alpine package name: %q", pkg)
+		}
+	}
+	return nil
+}
+
+// installPackages runs apk add inside the mounted rootfs to install extra
+// packages. The appliance needs network access (set before Launch). The
+// host's resolv.conf is put in place for the install so apk can resolve the
+// repositories, then the guest's own file (if any) is restored.
+func installPackages(g *guestfs.Guestfs, packages []string, logger *rootfsLogger) error {
+	logger.Info(fmt.Sprintf("Installing Alpine packages: %s", strings.Join(packages, ", ")))
+
+	restoreResolvConf, err := stageResolvConf(g)
+	if err != nil {
+		return err
+	}
+
+	args := append([]string{"/sbin/apk", "add", "--no-cache", "--no-progress"}, packages...)
+	output, cmdErr := g.Command(args)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line != "" {
+			logger.Info("apk: " + line)
+		}
+	}
+
+	if err := restoreResolvConf(); err != nil {
+		return err
+	}
+	if cmdErr != nil {
+		return fmt.Errorf("apk add failed: %w", cmdErr)
+	}
+	return nil
+}
+
+// stageResolvConf copies the host's /etc/resolv.conf into the guest and
+// returns a function that puts the guest's original back
+func stageResolvConf(g *guestfs.Guestfs) (func() error, error) {
+	const path = "/etc/resolv.conf"
+
+	hostResolv, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read host %s for package installation: %w", path, err)
+	}
+
+	var original []byte
+	hadOriginal, err := g.Is_file(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check guest %s: %w", path, err)
+	}
+	if hadOriginal {
+		if original, err = g.Read_file(path); err != nil {
+			return nil, fmt.Errorf("failed to read guest %s: %w", path, err)
+		}
+	}
+
+	if err := g.Write(path, hostResolv); err != nil {
+		return nil, fmt.Errorf("failed to write guest %s: %w", path, err)
+	}
+
+	return func() error {
+		if hadOriginal {
+			if err := g.Write(path, original); err != nil {
+				return fmt.Errorf("failed to restore guest %s: %w", path, err)
+			}
+			return nil
+		}
+		if err := g.Rm(path); err != nil {
+			return fmt.Errorf("failed to remove guest %s: %w", path, err)
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,17 @@
+// SPDX-License-Identifier: Apache-2.0
+package rootfs
+
+import "testing"
+
+func TestValidatePackages(t *testing.T) {
+	valid := []string{"curl", "ca-certificates", "openssh", "py3-pip", "libstdc++", "openssh=9.9_p2-r0", "busybox>=1.36"}
+	if err := validatePackages(valid); err != nil {
+		t.Errorf("validatePackages(%v) error = %v", valid, err)
+	}
+
+	for _, pkg := range []string{"", "-X", "--allow-untrusted", "curl wget", "curl;reboot", "../curl", "curl="} {
+		if err := validatePackages([]string{pkg}); err == nil {
+			t.Errorf("validatePackages(%q) should reject", pkg)
+		}
+	}
+}
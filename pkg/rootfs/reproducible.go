@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: Apache-2.0
+package rootfs
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	"libguestfs.org/guestfs"
+)
+
+// reproducibleLabel is the ext4 label of reproducible rootfs images
+const reproducibleLabel = "anvil-rootfs"
+
+// reproducibleSettings holds the fixed values used for a reproducible image
+type reproducibleSettings struct {
+	uuid  string
+	epoch int64 // Unix time every file timestamp is set to
+}
+
+// newReproducibleSettings derives the filesystem UUID from seed and reads the
+// timestamp from SOURCE_DATE_EPOCH (default: the Unix epoch)
+func newReproducibleSettings(seed string) (*reproducibleSettings, error) {
+	var epoch int64
+	if value := os.Getenv("SOURCE_DATE_EPOCH"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid SOURCE_DATE_EPOCH: %q", value)
+		}
+		epoch = parsed
+	}
+	return &reproducibleSettings{uuid: seedUUID(seed), epoch: epoch}, nil
+}
+
+// seedUUID returns a name-based (version 5 layout) UUID derived from seed
+func seedUUID(seed string) string {
+	sum := sha256.Sum256([]byte("anvil-rootfs:" + seed))
+	b := sum[:16]
+	b[6] = (b[6] & 0x0f) | 0x50 // version 5
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// sortTarball rewrites a gzipped tarball with its entries sorted by name and
+// every timestamp set to epoch, so extraction order and file times don't
+// depend on how the upstream archive was built.
+func sortTarball(src, dst string, epoch int64) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	defer gz.Close()
+
+	type entry struct {
+		header *tar.Header
+		data   []byte
+	}
+	var entries []entry
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", src, err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		entries = append(entries, entry{header: header, data: data})
+	}
+
+	// Parents sort before their children since "a" < "a/b"
+	sort.SliceStable(entries, func(i, j int) bool {
+		return path.Clean(entries[i].header.Name) < path.Clean(entries[j].header.Name)
+	})
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gzw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gzw)
+	mtime := time.Unix(epoch, 0)
+	for _, e := range entries {
+		header := *e.header
+		header.ModTime = mtime
+		header.AccessTime = time.Time{}
+		header.ChangeTime = time.Time{}
+		header.PAXRecords = nil
+		header.Format = tar.FormatUnknown
+		if err := tw.WriteHeader(&header); err != nil {
+			return fmt.Errorf("failed to write %s: %w", header.Name, err)
+		}
+		if _, err := tw.Write(e.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", header.Name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gzw.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// normalizeTimestamps sets the access and modification time of every file
+// in the mounted guest filesystem to epoch
+func normalizeTimestamps(g *guestfs.Guestfs, epoch int64) error {
+	files, err := g.Find("/")
+	if err != nil {
+		return fmt.Errorf("failed to list rootfs files: %w", err)
+	}
+
+	paths := append([]string{"/"}, files...)
+	for i, p := range paths {
+		if i > 0 {
+			p = "/" + p
+		}
+		if err := g.Utimens(p, epoch, 0, epoch, 0); err != nil {
+			return fmt.Errorf("failed to set timestamp of %s: %w", p, err)
+		}
+	}
+	return nil
+}
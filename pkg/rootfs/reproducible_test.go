@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+package rootfs
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestSeedUUID(t *testing.T) {
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	a := seedUUID("3.23.3/x86_64")
+	if !uuidPattern.MatchString(a) {
+		t.Errorf("seedUUID() = %q, not a version 5 UUID", a)
+	}
+	if b := seedUUID("3.23.3/x86_64"); a != b {
+		t.Errorf("seedUUID() not stable: %q != %q", a, b)
+	}
+	if c := seedUUID("3.23.3/aarch64"); a == c {
+		t.Errorf("seedUUID() gave the same UUID for different seeds: %q", a)
+	}
+}
+
+// writeTestTarball writes a gzipped tarball with the given entries in order
+func writeTestTarball(t *testing.T, path string, names []string) {
+	t.Helper()
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for i, name := range names {
+		header := &tar.Header{Name: name, Mode: 0644, ModTime: time.Unix(int64(1000+i), 0)}
+		if name[len(name)-1] == '/' {
+			header.Typeflag = tar.TypeDir
+			header.Mode = 0755
+		} else {
+			header.Typeflag = tar.TypeReg
+			header.Size = int64(len(name))
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(name)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	tw.Close()
+	gzw.Close()
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSortTarball(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.tar.gz")
+	writeTestTarball(t, src, []string{"usr/", "usr/bin/", "etc/", "usr/bin/sh", "etc/hosts", "bin/"})
+
+	dst := filepath.Join(dir, "sorted.tar.gz")
+	if err := sortTarball(src, dst, 42); err != nil {
+		t.Fatalf("sortTarball() error = %v", err)
+	}
+
+	f, err := os.Open(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, header.Name)
+		if !header.ModTime.Equal(time.Unix(42, 0)) {
+			t.Errorf("%s mtime = %v, want %v", header.Name, header.ModTime, time.Unix(42, 0))
+		}
+		if header.Typeflag == tar.TypeReg {
+			data, _ := io.ReadAll(tr)
+			if string(data) != header.Name {
+				t.Errorf("%s content = %q, want %q", header.Name, data, header.Name)
+			}
+		}
+	}
+
+	want := []string{"bin/", "etc/", "etc/hosts", "usr/", "usr/bin/", "usr/bin/sh"}
+	if len(names) != len(want) {
+		t.Fatalf("entries = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("entries = %v, want %v", names, want)
+		}
+	}
+
+	// The same input always produces the same bytes
+	again := filepath.Join(dir, "again.tar.gz")
+	if err := sortTarball(src, again, 42); err != nil {
+		t.Fatal(err)
+	}
+	first, _ := os.ReadFile(dst)
+	second, _ := os.ReadFile(again)
+	if !bytes.Equal(first, second) {
+		t.Error("sortTarball() output is not deterministic")
+	}
+}
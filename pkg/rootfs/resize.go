@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+package rootfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"libguestfs.org/guestfs"
+)
+
+// Resize grows an existing rootfs image to newSizeMB and expands its ext4
+// filesystem to fill it. Only growing is supported, so the data already in
+// the image always fits; a size at or below the current image size is
+// rejected before anything is changed. If expanding the filesystem fails,
+// the file is truncated back to its original size.
+func Resize(imagePath string, newSizeMB int) error {
+	info, err := os.Stat(imagePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("rootfs image not found: %s", imagePath)
+		}
+		return fmt.Errorf("failed to stat rootfs image: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("rootfs image is a directory: %s", imagePath)
+	}
+
+	if filepath.Ext(imagePath) == "."+FormatSquashfs {
+		return fmt.Errorf("cannot resize %s: squashfs images are read-only, recreate it instead", imagePath)
+	}
+
+	if err := checkResize(info.Size(), newSizeMB); err != nil {
+		return err
+	}
+
+	oldSize := info.Size()
+	newSize := int64(newSizeMB) * 1024 * 1024
+	if err := os.Truncate(imagePath, newSize); err != nil {
+		return fmt.Errorf("failed to grow image file: %w", err)
+	}
+
+	if err := resizeFilesystem(imagePath); err != nil {
+		if truncErr := os.Truncate(imagePath, oldSize); truncErr != nil {
+			return fmt.Errorf("failed to resize filesystem: %w (restoring original size also failed: %v)", err, truncErr)
+		}
+		return fmt.Errorf("failed to resize filesystem: %w", err)
+	}
+
+	return nil
+}
+
+// checkResize rejects a new size that does not grow an image of
+// currentBytes
+func checkResize(currentBytes int64, newSizeMB int) error {
+	if newSizeMB <= 0 {
+		return fmt.Errorf("invalid size: %dMB", newSizeMB)
+	}
+	currentMB := float64(currentBytes) / (1024 * 1024)
+	if int64(newSizeMB)*1024*1024 <= currentBytes {
+		return fmt.Errorf("new size %dMB must be larger than the current image size (%.0fMB); shrinking is not supported", newSizeMB, currentMB)
+	}
+	return nil
+}
+
+// resizeFilesystem checks the image's ext4 filesystem and grows it to fill
+// the (already enlarged) device
+func resizeFilesystem(imagePath string) error {
+	g, err := guestfs.Create()
+	if err != nil {
+		return fmt.Errorf("failed to create guestfs handle: %w", err)
+	}
+	defer g.Close()
+
+	absPath, err := filepath.Abs(imagePath)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	if err := g.Add_drive(absPath, &guestfs.OptargsAdd_drive{
+		Format_is_set: true,
+		Format:        "raw",
+	}); err != nil {
+		return fmt.Errorf("failed to add drive: %w", err)
+	}
+
+	if err := g.Launch(); err != nil {
+		return fmt.Errorf("failed to launch guestfs: %w", err)
+	}
+
+	devices, err := g.List_devices()
+	if err != nil {
+		return fmt.Errorf("failed to list devices: %w", err)
+	}
+	if len(devices) == 0 {
+		return fmt.Errorf("no devices found")
+	}
+	device := devices[0]
+
+	// resize2fs refuses to run on a filesystem that hasn't been checked
+	if err := g.E2fsck_f(device); err != nil {
+		return fmt.Errorf("filesystem check failed: %w", err)
+	}
+	if err := g.Resize2fs(device); err != nil {
+		return fmt.Errorf("resize2fs failed: %w", err)
+	}
+
+	if err := g.Shutdown(); err != nil {
+		return fmt.Errorf("failed to shutdown: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+package rootfs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckResize(t *testing.T) {
+	const mb = 1024 * 1024
+	tests := []struct {
+		name      string
+		current   int64
+		newSizeMB int
+		wantErr   string
+	}{
+		{"grow", 512 * mb, 1024, ""},
+		{"grow by one MB", 512 * mb, 513, ""},
+		{"same size", 512 * mb, 512, "must be larger"},
+		{"shrink", 512 * mb, 256, "must be larger"},
+		{"zero", 512 * mb, 0, "invalid size"},
+		{"negative", 512 * mb, -1, "invalid size"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkResize(tt.current, tt.newSizeMB)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkResize() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkResize() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResizeRejectsBeforeTouchingImage(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := Resize(filepath.Join(dir, "missing.ext4"), 1024); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Resize(missing) error = %v, want not found", err)
+	}
+
+	image := filepath.Join(dir, "rootfs.ext4")
+	if err := os.WriteFile(image, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(image, 2*1024*1024); err != nil {
+		t.Fatal(err)
+	}
+	if err := Resize(image, 1); err == nil {
+		t.Error("Resize() to a smaller size should fail")
+	}
+	info, err := os.Stat(image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 2*1024*1024 {
+		t.Errorf("image size = %d after rejected resize, want unchanged", info.Size())
+	}
+}
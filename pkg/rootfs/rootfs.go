@@ -5,12 +5,14 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/download"
 	"github.com/Work-Fort/Anvil/pkg/firecracker/embedded"
+	"github.com/Work-Fort/Anvil/pkg/util"
 	"libguestfs.org/guestfs"
 )
 
@@ -23,23 +25,57 @@ const (
 	PhaseFormat
 	PhasePopulate
 	PhaseInjectBinary
+	PhaseSquashfs
 	PhaseComplete
 )
 
+// String returns the phase name reported to a ProgressReporter
+func (p CreatePhase) String() string {
+	switch p {
+	case PhaseDownload:
+		return "download"
+	case PhaseCreate:
+		return "create"
+	case PhaseFormat:
+		return "format"
+	case PhasePopulate:
+		return "populate"
+	case PhaseInjectBinary:
+		return "inject-binary"
+	case PhaseSquashfs:
+		return "squashfs"
+	case PhaseComplete:
+		return "complete"
+	default:
+		return fmt.Sprintf("phase %d", int(p))
+	}
+}
+
 // CreateOptions contains options for creating a rootfs
 type CreateOptions struct {
-	OutputPath     string
-	SizeMB         int
-	AlpineVersion  string            // e.g., "3.23"
-	AlpinePatch    string            // e.g., "3"
-	Writer         io.Writer         // Optional: custom writer for output (for TUI streaming)
-	PhaseCallback  func(CreatePhase) // Optional: callback for phase transitions
-	StatsCallback  func(CreateStats) // Optional: callback for final statistics
-	Context        context.Context   // Optional: context for cancellation
-	ForceOverwrite bool              // Overwrite existing file
-	InjectBinary   bool              // Whether to inject binary into rootfs
-	BinaryPath     string            // Path to binary to inject (default: current executable)
-	BinaryDestPath string            // Destination path in rootfs (default: /usr/bin/anvil)
+	OutputPath       string
+	SizeMB           int
+	AlpineVersion    string                // e.g., "3.23" (default: latest stable)
+	AlpinePatch      string                // e.g., "3" (default: latest for AlpineVersion)
+	AlpineMirror     string                // Base URL of the Alpine mirror (default: rootfs.alpine-mirror)
+	Arch             string                // Target architecture: x86_64 or aarch64 (default: host)
+	Writer           io.Writer             // Optional: custom writer for output (for TUI streaming)
+	PhaseCallback    func(CreatePhase)     // Optional: callback for phase transitions
+	ProgressCallback func(float64)         // Optional: callback for Alpine download progress (0.0 to 1.0)
+	Progress         util.ProgressReporter // Optional: receives phases, download progress and log messages
+	StatsCallback    func(CreateStats)     // Optional: callback for final statistics
+	Context          context.Context       // Optional: context for cancellation
+	ForceOverwrite   bool                  // Overwrite existing file
+	InjectBinary     bool                  // Whether to inject binary into rootfs
+	BinaryPath       string                // Path to binary to inject (default: current executable)
+	BinaryDestPath   string                // Destination path in rootfs (default: /usr/bin/anvil)
+	VsockPort        uint32                // Port the init script starts the vsock server on (default: 8000)
+	InitScriptPath   string                // Custom /init script to use instead of the built-in template
+	Format           string                // Image format: ext4 or squashfs (default: ext4)
+	AuthorizedKeys   []string              // OpenSSH public keys (or files of them) for root; adds openssh-server to Packages
+	Reproducible     bool                  // Build a deterministic image (fixed UUID, label, order and timestamps)
+	Seed             string                // Seed for the reproducible filesystem UUID (default: Alpine release and arch)
+	Packages         []string              // Extra Alpine packages to apk add into the rootfs (target arch must match the host)
 }
 
 // CreateStats contains statistics about a completed rootfs creation
@@ -49,68 +85,59 @@ type CreateStats struct {
 	SizeMB         int
 	CreateTime     time.Time
 	AlpineVersion  string
+	Arch           string
 	BinaryInjected bool
+	VsockPort      uint32
+	Reproducible   bool
+	Packages       []string
+	Format         string
+	SSHKeys        int
+	SHA256         string // Checksum of the image, also written to <OutputPath>.sha256
 }
 
-// rootfsLogger wraps a writer to emit structured log messages for TUI
+// rootfsLogger wraps a writer to emit structured log messages for TUI, and
+// forwards them to reporter when one is set
 type rootfsLogger struct {
-	writer io.Writer
+	writer   io.Writer
+	reporter util.ProgressReporter
+}
+
+func (rl *rootfsLogger) log(level, prefix, msg string) {
+	rl.writer.Write([]byte(fmt.Sprintf("[%s] %s\n", prefix, msg)))
+	if rl.reporter != nil {
+		rl.reporter.Log(level, msg)
+	}
 }
 
 func (rl *rootfsLogger) Info(msg string) {
-	rl.writer.Write([]byte(fmt.Sprintf("[INFO] %s\n", msg)))
+	rl.log(util.LevelInfo, "INFO", msg)
 }
 
 func (rl *rootfsLogger) Warn(msg string) {
-	rl.writer.Write([]byte(fmt.Sprintf("[WARN] %s\n", msg)))
+	rl.log(util.LevelWarn, "WARN", msg)
 }
 
 func (rl *rootfsLogger) Error(msg string) {
-	rl.writer.Write([]byte(fmt.Sprintf("[ERROR] %s\n", msg)))
+	rl.log(util.LevelError, "ERROR", msg)
 }
 
 func (rl *rootfsLogger) Debug(msg string) {
-	rl.writer.Write([]byte(fmt.Sprintf("[DEBUG] %s\n", msg)))
+	rl.log(util.LevelDebug, "DEBUG", msg)
 }
 
-// initScriptTemplate is the template for the init script
-// It will be formatted with the binary path
-const initScriptTemplate = `#!/bin/sh
-# Init script for Firecracker VM
-
-# Mount essential filesystems
-mount -t proc none /proc
-mount -t sysfs none /sys
-mount -t devtmpfs none /dev
-
-# Setup networking (loopback)
-ip link set lo up
-
-# Print boot info
-echo "=========================================="
-echo "Anvil Firecracker VM"
-echo "Kernel version: $(uname -r)"
-echo "Architecture: $(uname -m)"
-echo "=========================================="
-
-# Start vsock server if binary exists
-if [ -x %s ]; then
-    echo "Starting vsock server..."
-    %s &
-    AGENT_PID=$!
-    echo "Server started with PID ${AGENT_PID}"
-else
-    echo "WARNING: Vsock server binary not found at %s"
-fi
-
-# Keep VM running (block forever)
-echo "VM ready - vsock server running on port 8000"
-while true; do
-    sleep 1000
-done
-`
+// RemoveImage deletes a rootfs image and its .sha256 checksum file, if any
+func RemoveImage(path string) error {
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	if err := os.Remove(path + ".sha256"); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s.sha256: %w", path, err)
+	}
+	return nil
+}
 
-// Clean removes all rootfs images (*.ext4 files) from the given data directory.
+// Clean removes all rootfs images (*.ext4 and *.squashfs files) and their
+// .sha256 checksum files from the given data directory.
 // Returns the list of removed filenames.
 func Clean(dataDir string) ([]string, error) {
 	entries, err := os.ReadDir(dataDir)
@@ -123,13 +150,13 @@ func Clean(dataDir string) ([]string, error) {
 
 	var removed []string
 	for _, entry := range entries {
-		if entry.IsDir() || filepath.Ext(entry.Name()) != ".ext4" {
+		if entry.IsDir() || !IsImageFile(entry.Name()) {
 			continue
 		}
 
 		path := filepath.Join(dataDir, entry.Name())
-		if err := os.Remove(path); err != nil {
-			return nil, fmt.Errorf("failed to remove %s: %w", path, err)
+		if err := RemoveImage(path); err != nil {
+			return nil, err
 		}
 		removed = append(removed, entry.Name())
 	}
@@ -153,11 +180,14 @@ func Create(opts CreateOptions) error {
 	if opts.SizeMB == 0 {
 		opts.SizeMB = 512 // 512MB like frontier
 	}
-	if opts.AlpineVersion == "" {
-		opts.AlpineVersion = "3.23"
+	if opts.AlpineVersion == "" && opts.AlpinePatch != "" {
+		return fmt.Errorf("alpine patch %s given without an Alpine version", opts.AlpinePatch)
 	}
-	if opts.AlpinePatch == "" {
-		opts.AlpinePatch = "3"
+	if opts.Format == "" {
+		opts.Format = FormatExt4
+	}
+	if err := validateFormat(opts.Format); err != nil {
+		return err
 	}
 	if opts.Writer == nil {
 		opts.Writer = os.Stdout
@@ -165,24 +195,106 @@ func Create(opts CreateOptions) error {
 	if opts.Context == nil {
 		opts.Context = context.Background()
 	}
+	if opts.Arch == "" {
+		hostArch, err := config.GetArch()
+		if err != nil {
+			return err
+		}
+		opts.Arch = hostArch
+	}
+	spec, err := getArchSpec(opts.Arch)
+	if err != nil {
+		return err
+	}
+	if len(opts.AuthorizedKeys) > 0 {
+		keys, err := resolveAuthorizedKeys(opts.AuthorizedKeys)
+		if err != nil {
+			return err
+		}
+		opts.AuthorizedKeys = keys
+		opts.Packages = withSSHServer(opts.Packages)
+	}
+	if len(opts.Packages) > 0 {
+		if err := validatePackages(opts.Packages); err != nil {
+			return err
+		}
+		// apk runs in the libguestfs appliance, which can only execute
+		// binaries for the host arch
+		hostArch, err := config.GetArch()
+		if err != nil {
+			return err
+		}
+		if opts.Arch != hostArch {
+			return fmt.Errorf("cannot install packages into a %s rootfs on a %s host", opts.Arch, hostArch)
+		}
+	}
+	if opts.AlpineMirror == "" {
+		opts.AlpineMirror = config.GetRootfsAlpineMirror()
+	}
+	mirror, err := normalizeAlpineMirror(opts.AlpineMirror)
+	if err != nil {
+		return err
+	}
 	// Always set a default binary dest path for the init script template,
 	// even when not injecting. An empty path produces invalid shell syntax.
 	if opts.BinaryDestPath == "" {
 		opts.BinaryDestPath = "/usr/bin/vsock-server"
 	}
+	if opts.VsockPort == 0 {
+		opts.VsockPort = DefaultVsockPort
+	}
+	var customInit string
+	if opts.InitScriptPath != "" {
+		customInit, err = loadInitScript(opts.InitScriptPath)
+		if err != nil {
+			return err
+		}
+	}
 	if opts.InjectBinary {
 		if opts.BinaryPath == "" {
-			// Extract the embedded static vsock-server binary
-			vsockPath, cleanup, err := embedded.ExtractVsockServer()
+			// Extract the embedded static vsock-server binary for the target arch
+			vsockPath, cleanup, err := embedded.ExtractVsockServerForArch(opts.Arch)
 			if err != nil {
 				return fmt.Errorf("failed to extract embedded vsock-server: %w", err)
 			}
 			defer cleanup()
+			if err := CheckBinaryArch(vsockPath, opts.Arch); err != nil {
+				return fmt.Errorf("no embedded vsock-server for %s: %w\n\n"+
+					"Build one and pass it with --binary-path:\n"+
+					"  GOARCH=%s CGO_ENABLED=0 go build -o vsock-server-%s ./cmd/vsock-server-standalone",
+					opts.Arch, err, goArch(opts.Arch), opts.Arch)
+			}
 			opts.BinaryPath = vsockPath
+		} else if err := CheckBinaryArch(opts.BinaryPath, opts.Arch); err != nil {
+			// Injecting a binary for the wrong arch produces a rootfs that boots
+			// but cannot start the agent, so fail early
+			return fmt.Errorf("cannot inject binary into %s rootfs: %w", opts.Arch, err)
 		}
 	}
 
-	logger := &rootfsLogger{writer: opts.Writer}
+	logger := &rootfsLogger{writer: opts.Writer, reporter: opts.Progress}
+	phaseCallback := opts.phaseCallback()
+
+	// Explicit version and patch are used as given; anything unset is
+	// discovered from the mirror
+	if opts.AlpinePatch == "" {
+		if err := resolveAlpineRelease(&opts, mirror, logger); err != nil {
+			return err
+		}
+	}
+
+	var repro *reproducibleSettings
+	if opts.Reproducible {
+		seed := opts.Seed
+		if seed == "" {
+			seed = fmt.Sprintf("%s.%s/%s", opts.AlpineVersion, opts.AlpinePatch, opts.Arch)
+		}
+		repro, err = newReproducibleSettings(seed)
+		if err != nil {
+			return err
+		}
+		logger.Info(fmt.Sprintf("Reproducible image: UUID %s, timestamps %s", repro.uuid, time.Unix(repro.epoch, 0).UTC().Format(time.RFC3339)))
+	}
 
 	// Check if output file already exists
 	if !opts.ForceOverwrite {
@@ -198,56 +310,111 @@ func Create(opts CreateOptions) error {
 	}
 
 	// Phase 1: Download Alpine tarball
-	if opts.PhaseCallback != nil {
-		opts.PhaseCallback(PhaseDownload)
+	if phaseCallback != nil {
+		phaseCallback(PhaseDownload)
 	}
 
-	alpineURL := fmt.Sprintf("https://dl-cdn.alpinelinux.org/alpine/v%s/releases/x86_64/alpine-minirootfs-%s.%s-x86_64.tar.gz",
-		opts.AlpineVersion, opts.AlpineVersion, opts.AlpinePatch)
+	alpineName := alpineMinirootfsName(opts.AlpineVersion, opts.AlpinePatch, opts.Arch)
+	alpineURL := alpineReleasesURL(mirror, opts.AlpineVersion, opts.Arch) + "/" + alpineName
 
-	logger.Info(fmt.Sprintf("Downloading Alpine Linux %s.%s...", opts.AlpineVersion, opts.AlpinePatch))
-	alpineTarball := filepath.Join(os.TempDir(), "alpine-minirootfs.tar.gz")
-	defer os.Remove(alpineTarball)
+	logger.Info(fmt.Sprintf("Downloading Alpine Linux %s.%s (%s)...", opts.AlpineVersion, opts.AlpinePatch, opts.Arch))
+	if mirror != DefaultAlpineMirror {
+		logger.Info(fmt.Sprintf("Using Alpine mirror %s", mirror))
+	}
+	downloadDir, err := os.MkdirTemp("", "anvil-rootfs-")
+	if err != nil {
+		return fmt.Errorf("failed to create download directory: %w", err)
+	}
+	defer os.RemoveAll(downloadDir)
+	alpineTarball := filepath.Join(downloadDir, alpineName)
 
-	if err := downloadFile(alpineURL, alpineTarball); err != nil {
+	if err := download.FileWithOptions(alpineURL, alpineTarball, &download.Options{
+		ProgressCallback: opts.progressCallback(),
+		Context:          opts.Context,
+	}); err != nil {
 		return fmt.Errorf("failed to download Alpine tarball: %w", err)
 	}
 
+	// Verify against the published .sha256 so a mirror can't serve a
+	// tampered or truncated tarball
+	checksumFile := alpineTarball + ".sha256"
+	if err := download.FileWithOptions(alpineURL+".sha256", checksumFile, &download.Options{Context: opts.Context}); err != nil {
+		return fmt.Errorf("failed to download Alpine tarball checksum: %w", err)
+	}
+	if err := util.VerifySHA256File(alpineTarball, checksumFile); err != nil {
+		return fmt.Errorf("failed to verify Alpine tarball: %w", err)
+	}
+	logger.Info("Alpine tarball checksum verified")
+
+	// Extract in a fixed order with fixed timestamps
+	if repro != nil {
+		sortedTarball := filepath.Join(downloadDir, "sorted-"+alpineName)
+		if err := sortTarball(alpineTarball, sortedTarball, repro.epoch); err != nil {
+			return fmt.Errorf("failed to normalize Alpine tarball: %w", err)
+		}
+		alpineTarball = sortedTarball
+	}
+
+	// A squashfs image is packed from a populated ext4 staging image next
+	// to the output, which is removed afterwards
+	imagePath := opts.OutputPath
+	if opts.Format == FormatSquashfs {
+		imagePath = filepath.Join(outputDir, "."+filepath.Base(opts.OutputPath)+".staging")
+		defer os.Remove(imagePath)
+	}
+
 	// Phase 2: Create empty image
-	if opts.PhaseCallback != nil {
-		opts.PhaseCallback(PhaseCreate)
+	if phaseCallback != nil {
+		phaseCallback(PhaseCreate)
 	}
 
 	logger.Info(fmt.Sprintf("Creating %dMB empty image...", opts.SizeMB))
-	if err := createEmptyImage(opts.OutputPath, opts.SizeMB); err != nil {
+	if err := createEmptyImage(imagePath, opts.SizeMB); err != nil {
 		return fmt.Errorf("failed to create empty image: %w", err)
 	}
 
 	// Phase 3: Format and populate with libguestfs
-	if opts.PhaseCallback != nil {
-		opts.PhaseCallback(PhaseFormat)
+	if phaseCallback != nil {
+		phaseCallback(PhaseFormat)
 	}
 
 	logger.Info("Formatting as ext4 and populating rootfs...")
-	if err := formatAndPopulateRootfs(opts.OutputPath, alpineTarball, opts.BinaryDestPath, logger, opts.PhaseCallback); err != nil {
+	if err := formatAndPopulateRootfs(imagePath, alpineTarball, opts.BinaryDestPath, opts.VsockPort, customInit, spec, opts.Packages, opts.AuthorizedKeys, repro, logger, phaseCallback); err != nil {
 		return fmt.Errorf("failed to format and populate rootfs: %w", err)
 	}
 
 	// Phase 5: Inject binary if requested
 	if opts.InjectBinary {
-		if opts.PhaseCallback != nil {
-			opts.PhaseCallback(PhaseInjectBinary)
+		if phaseCallback != nil {
+			phaseCallback(PhaseInjectBinary)
 		}
 
 		logger.Info(fmt.Sprintf("Injecting vsock server binary to %s...", opts.BinaryDestPath))
-		if err := injectBinaryWithLibguestfs(opts.OutputPath, opts.BinaryPath, opts.BinaryDestPath, logger); err != nil {
+		if err := injectBinaryWithLibguestfs(imagePath, opts.BinaryPath, opts.BinaryDestPath, repro, logger); err != nil {
 			return fmt.Errorf("failed to inject binary: %w", err)
 		}
 	}
 
+	if opts.Format == FormatSquashfs {
+		if phaseCallback != nil {
+			phaseCallback(PhaseSquashfs)
+		}
+
+		if err := squashImage(imagePath, opts.OutputPath, logger); err != nil {
+			return fmt.Errorf("failed to create squashfs image: %w", err)
+		}
+	}
+
+	// Record a checksum so copies of the image can be verified (and signed)
+	checksum, err := writeImageChecksum(opts.OutputPath)
+	if err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("SHA256: %s", checksum))
+
 	// Phase 6: Complete
-	if opts.PhaseCallback != nil {
-		opts.PhaseCallback(PhaseComplete)
+	if phaseCallback != nil {
+		phaseCallback(PhaseComplete)
 	}
 
 	// Call stats callback if provided
@@ -258,7 +425,14 @@ func Create(opts CreateOptions) error {
 			SizeMB:         opts.SizeMB,
 			CreateTime:     time.Now(),
 			AlpineVersion:  fmt.Sprintf("%s.%s", opts.AlpineVersion, opts.AlpinePatch),
+			Arch:           opts.Arch,
 			BinaryInjected: opts.InjectBinary,
+			VsockPort:      opts.VsockPort,
+			Reproducible:   opts.Reproducible,
+			Packages:       opts.Packages,
+			Format:         opts.Format,
+			SSHKeys:        len(opts.AuthorizedKeys),
+			SHA256:         checksum,
 		})
 	}
 
@@ -266,26 +440,73 @@ func Create(opts CreateOptions) error {
 	return nil
 }
 
-// downloadFile downloads a file from a URL to a local path
-func downloadFile(url, filepath string) error {
-	resp, err := http.Get(url)
-	if err != nil {
-		return err
+// phaseCallback returns the phase callback to report through:
+// PhaseCallback, plus Progress when set
+func (opts CreateOptions) phaseCallback() func(CreatePhase) {
+	if opts.Progress == nil {
+		return opts.PhaseCallback
+	}
+	return func(p CreatePhase) {
+		if opts.PhaseCallback != nil {
+			opts.PhaseCallback(p)
+		}
+		opts.Progress.SetPhase(p.String())
 	}
-	defer resp.Body.Close()
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+// progressCallback returns the download progress callback to report
+// through: ProgressCallback, plus Progress when set
+func (opts CreateOptions) progressCallback() func(float64) {
+	if opts.Progress == nil {
+		return opts.ProgressCallback
+	}
+	report := util.ReportFraction(opts.Progress)
+	return func(fraction float64) {
+		if opts.ProgressCallback != nil {
+			opts.ProgressCallback(fraction)
+		}
+		report(fraction)
+	}
+}
+
+// resolveAlpineRelease fills in opts.AlpineVersion and opts.AlpinePatch from
+// the mirror's latest-releases.yaml. When discovery fails and no version
+// was requested, the built-in fallback release is used.
+func resolveAlpineRelease(opts *CreateOptions, mirror string, logger *rootfsLogger) error {
+	cacheDir := os.TempDir()
+	if config.GlobalPaths != nil {
+		cacheDir = config.GlobalPaths.CacheDir
 	}
 
-	out, err := os.Create(filepath)
+	version, patch, err := DiscoverAlpineRelease(mirror, opts.AlpineVersion, opts.Arch, cacheDir)
 	if err != nil {
-		return err
+		if opts.AlpineVersion != "" {
+			return fmt.Errorf("failed to discover latest Alpine %s patch release (pass --alpine-patch to skip discovery): %w", opts.AlpineVersion, err)
+		}
+		logger.Warn(fmt.Sprintf("Alpine release discovery failed, using %s.%s: %v", fallbackAlpineVersion, fallbackAlpinePatch, err))
+		opts.AlpineVersion = fallbackAlpineVersion
+		opts.AlpinePatch = fallbackAlpinePatch
+		return nil
 	}
-	defer out.Close()
 
-	_, err = io.Copy(out, resp.Body)
-	return err
+	opts.AlpineVersion = version
+	opts.AlpinePatch = patch
+	logger.Info(fmt.Sprintf("Resolved Alpine release %s.%s", version, patch))
+	return nil
+}
+
+// writeImageChecksum computes the SHA256 of the image at path and writes it
+// to <path>.sha256 in sha256sum format
+func writeImageChecksum(path string) (string, error) {
+	hash, err := util.CalculateSHA256(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate rootfs checksum: %w", err)
+	}
+	line := fmt.Sprintf("%s  %s\n", hash, filepath.Base(path))
+	if err := os.WriteFile(path+".sha256", []byte(line), 0644); err != nil {
+		return "", fmt.Errorf("failed to write rootfs checksum file: %w", err)
+	}
+	return hash, nil
 }
 
 // createEmptyImage creates an empty file of the specified size in MB
@@ -306,8 +527,12 @@ func createEmptyImage(path string, sizeMB int) error {
 	return nil
 }
 
-// formatAndPopulateRootfs formats the image as ext4 and populates it using libguestfs
-func formatAndPopulateRootfs(imagePath, alpineTarball, binaryDestPath string, logger *rootfsLogger, phaseCallback func(CreatePhase)) error {
+// formatAndPopulateRootfs formats the image as ext4 and populates it using
+// libguestfs, installing packages with apk and root's SSH authorized keys
+// when given. A non-empty customInit is written to /init instead of the
+// built-in template. A non-nil repro fixes the label, UUID and file
+// timestamps.
+func formatAndPopulateRootfs(imagePath, alpineTarball, binaryDestPath string, vsockPort uint32, customInit string, spec archSpec, packages, authorizedKeys []string, repro *reproducibleSettings, logger *rootfsLogger, phaseCallback func(CreatePhase)) error {
 	// Create guestfs handle
 	g, err := guestfs.Create()
 	if err != nil {
@@ -329,6 +554,13 @@ func formatAndPopulateRootfs(imagePath, alpineTarball, binaryDestPath string, lo
 		return fmt.Errorf("failed to add drive: %w", err)
 	}
 
+	// apk needs to reach the Alpine repositories
+	if len(packages) > 0 {
+		if err := g.Set_network(true); err != nil {
+			return fmt.Errorf("failed to enable appliance network: %w", err)
+		}
+	}
+
 	// Launch the appliance
 	logger.Info("Launching libguestfs appliance...")
 	if err := g.Launch(); err != nil {
@@ -347,9 +579,18 @@ func formatAndPopulateRootfs(imagePath, alpineTarball, binaryDestPath string, lo
 
 	// Format device as ext4
 	logger.Info("Formatting device as ext4...")
-	if err := g.Mkfs("ext4", device, nil); err != nil {
+	var mkfsOpts *guestfs.OptargsMkfs
+	if repro != nil {
+		mkfsOpts = &guestfs.OptargsMkfs{Label_is_set: true, Label: reproducibleLabel}
+	}
+	if err := g.Mkfs("ext4", device, mkfsOpts); err != nil {
 		return fmt.Errorf("failed to format device as ext4: %w", err)
 	}
+	if repro != nil {
+		if err := g.Set_uuid(device, repro.uuid); err != nil {
+			return fmt.Errorf("failed to set filesystem UUID: %w", err)
+		}
+	}
 
 	// Trigger populate phase callback
 	if phaseCallback != nil {
@@ -371,26 +612,41 @@ func formatAndPopulateRootfs(imagePath, alpineTarball, binaryDestPath string, lo
 		return fmt.Errorf("failed to extract tarball: %w", err)
 	}
 
+	if len(packages) > 0 {
+		if err := installPackages(g, packages, logger); err != nil {
+			return err
+		}
+	}
+
+	if len(authorizedKeys) > 0 {
+		if err := installAuthorizedKeys(g, authorizedKeys, logger); err != nil {
+			return err
+		}
+	}
+
 	// Copy required libraries for dynamically linked binaries
 	logger.Info("Copying required glibc libraries...")
 
-	// Create /lib64 directory for glibc compatibility
-	if err := g.Mkdir_p("/lib64"); err != nil {
-		return fmt.Errorf("failed to create /lib64: %w", err)
+	// Create the linker directory (/lib64 on x86_64) for glibc compatibility
+	linkerDir := filepath.Dir(spec.linkerGuestPath)
+	if err := g.Mkdir_p(linkerDir); err != nil {
+		return fmt.Errorf("failed to create %s: %w", linkerDir, err)
 	}
 
-	// Copy ld-linux-x86-64.so.2 (dynamic linker) from host
-	if err := g.Upload("/lib64/ld-linux-x86-64.so.2", "/lib64/ld-linux-x86-64.so.2"); err != nil {
+	// Copy the target arch's dynamic linker from the host (or its cross sysroot)
+	if hostLinker := findDynamicLinker(spec); hostLinker == "" {
+		logger.Warn(fmt.Sprintf("No %s dynamic linker found on host, binary may not work if dynamically linked", filepath.Base(spec.linkerGuestPath)))
+	} else if err := g.Upload(hostLinker, spec.linkerGuestPath); err != nil {
 		logger.Warn("Failed to copy dynamic linker, binary may not work if dynamically linked")
 	}
 
-	// Create init script
-	logger.Info("Creating init script...")
-	// Generate init script with the configured binary path
-	// Use empty string if no binary path configured (binary injection disabled)
-	initScript := fmt.Sprintf(initScriptTemplate, binaryDestPath, binaryDestPath, binaryDestPath)
-	if err := g.Write("/init", []byte(initScript)); err != nil {
-		return fmt.Errorf("failed to write init script: %w", err)
+	if customInit != "" {
+		logger.Info("Writing custom init script...")
+		if err := g.Write("/init", []byte(customInit)); err != nil {
+			return fmt.Errorf("failed to write init script: %w", err)
+		}
+	} else if err := writeTemplateInitScript(g, binaryDestPath, vsockPort, logger); err != nil {
+		return err
 	}
 
 	// Make init executable (mode 0755)
@@ -408,6 +664,13 @@ func formatAndPopulateRootfs(imagePath, alpineTarball, binaryDestPath string, lo
 		return fmt.Errorf("failed to write inittab: %w", err)
 	}
 
+	if repro != nil {
+		logger.Info("Normalizing file timestamps...")
+		if err := normalizeTimestamps(g, repro.epoch); err != nil {
+			return err
+		}
+	}
+
 	// Unmount and shutdown
 	logger.Info("Finalizing rootfs...")
 	if err := g.Umount_all(); err != nil {
@@ -421,8 +684,32 @@ func formatAndPopulateRootfs(imagePath, alpineTarball, binaryDestPath string, lo
 	return nil
 }
 
-// injectBinaryWithLibguestfs injects a binary into the rootfs using libguestfs
-func injectBinaryWithLibguestfs(imagePath, binaryPath, binaryDestPath string, logger *rootfsLogger) error {
+// writeTemplateInitScript writes the built-in /init script, which starts
+// the vsock server at binaryDestPath on vsockPort
+func writeTemplateInitScript(g *guestfs.Guestfs, binaryDestPath string, vsockPort uint32, logger *rootfsLogger) error {
+	logger.Info("Creating init script...")
+	// Generate init script with the configured binary path and vsock port
+	initScript := renderInitScript(binaryDestPath, vsockPort)
+	if err := g.Write("/init", []byte(initScript)); err != nil {
+		return fmt.Errorf("failed to write init script: %w", err)
+	}
+
+	// Read the script back so the port the guest advertises at boot is
+	// guaranteed to be the one the server is started on
+	written, err := g.Cat("/init")
+	if err != nil {
+		return fmt.Errorf("failed to read back init script: %w", err)
+	}
+	if err := verifyInitScriptPort(written, vsockPort); err != nil {
+		return fmt.Errorf("init script check failed: %w", err)
+	}
+	logger.Info(fmt.Sprintf("Init script starts vsock server on port %d", vsockPort))
+	return nil
+}
+
+// injectBinaryWithLibguestfs injects a binary into the rootfs using libguestfs.
+// A non-nil repro resets file timestamps changed by the upload.
+func injectBinaryWithLibguestfs(imagePath, binaryPath, binaryDestPath string, repro *reproducibleSettings, logger *rootfsLogger) error {
 	// Create guestfs handle
 	g, err := guestfs.Create()
 	if err != nil {
@@ -486,6 +773,12 @@ func injectBinaryWithLibguestfs(imagePath, binaryPath, binaryDestPath string, lo
 		return fmt.Errorf("failed to chmod binary: %w", err)
 	}
 
+	if repro != nil {
+		if err := normalizeTimestamps(g, repro.epoch); err != nil {
+			return err
+		}
+	}
+
 	// Unmount and shutdown
 	if err := g.Umount_all(); err != nil {
 		return fmt.Errorf("failed to unmount: %w", err)
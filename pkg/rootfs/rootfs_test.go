@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+package rootfs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+func TestProgressCallbackReportsToBoth(t *testing.T) {
+	events := make(chan util.ProgressEvent, 16)
+	var fractions []float64
+	opts := CreateOptions{
+		ProgressCallback: func(f float64) { fractions = append(fractions, f) },
+		Progress:         util.NewChanProgressReporter(events),
+	}
+
+	progress := opts.progressCallback()
+	progress(0.5)
+	progress(1)
+	close(events)
+
+	if len(fractions) != 2 || fractions[1] != 1 {
+		t.Errorf("ProgressCallback got %v, want [0.5 1]", fractions)
+	}
+	var last util.ProgressEvent
+	for e := range events {
+		if e.Kind != util.ProgressEventProgress {
+			t.Fatalf("unexpected event %+v", e)
+		}
+		last = e
+	}
+	if last.Total == 0 || last.Done != last.Total {
+		t.Errorf("last progress = %d/%d, want complete", last.Done, last.Total)
+	}
+}
+
+func TestProgressCallbackWithoutReporter(t *testing.T) {
+	if (CreateOptions{}).progressCallback() != nil {
+		t.Error("progressCallback() should be nil without ProgressCallback or Progress")
+	}
+}
+
+func TestCreatePhaseString(t *testing.T) {
+	tests := []struct {
+		phase CreatePhase
+		want  string
+	}{
+		{PhaseDownload, "download"},
+		{PhasePopulate, "populate"},
+		{PhaseInjectBinary, "inject-binary"},
+		{PhaseSquashfs, "squashfs"},
+		{PhaseComplete, "complete"},
+		{CreatePhase(42), "phase 42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.phase.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteImageChecksum(t *testing.T) {
+	image := filepath.Join(t.TempDir(), "alpine-rootfs.ext4")
+	if err := os.WriteFile(image, []byte("rootfs"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := writeImageChecksum(image)
+	if err != nil {
+		t.Fatalf("writeImageChecksum() error = %v", err)
+	}
+	// sha256 of "rootfs"
+	const want = "3c47ef972d531d524daa15fa33dd885dd23de6221bbd10a29eb42ecfcf2ef422"
+	if hash != want {
+		t.Errorf("writeImageChecksum() = %q, want %q", hash, want)
+	}
+
+	content, err := os.ReadFile(image + ".sha256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != hash+"  alpine-rootfs.ext4\n" {
+		t.Errorf("checksum file = %q, want sha256sum format", content)
+	}
+	if err := util.VerifySHA256File(image, image+".sha256"); err != nil {
+		t.Errorf("VerifySHA256File() error = %v", err)
+	}
+}
+
+func TestCleanRemovesChecksumFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.ext4", "a.ext4.sha256", "b.squashfs", "keep.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := Clean(dir)
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if len(removed) != 2 {
+		t.Errorf("Clean() removed %v, want the two images", removed)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || entries[0].Name() != "keep.txt" {
+		t.Errorf("left behind %v, want only keep.txt", entries)
+	}
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+package rootfs
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"libguestfs.org/guestfs"
+)
+
+// Rootfs image formats
+const (
+	FormatExt4     = "ext4"
+	FormatSquashfs = "squashfs"
+)
+
+// imageExtensions are the file extensions of rootfs images in the data
+// directory, one per format
+var imageExtensions = []string{"." + FormatExt4, "." + FormatSquashfs}
+
+// IsImageFile reports whether name has the extension of a rootfs image
+// (.ext4 or .squashfs)
+func IsImageFile(name string) bool {
+	ext := filepath.Ext(name)
+	for _, imageExt := range imageExtensions {
+		if ext == imageExt {
+			return true
+		}
+	}
+	return false
+}
+
+// validateFormat checks a CreateOptions.Format value
+func validateFormat(format string) error {
+	switch format {
+	case FormatExt4, FormatSquashfs:
+		return nil
+	}
+	return fmt.Errorf("unsupported rootfs format: %s (supported: %s, %s)", format, FormatExt4, FormatSquashfs)
+}
+
+// squashImage packs the filesystem of the populated ext4 image at
+// imagePath into a compressed squashfs file at outputPath
+func squashImage(imagePath, outputPath string, logger *rootfsLogger) error {
+	g, err := guestfs.Create()
+	if err != nil {
+		return fmt.Errorf("failed to create guestfs handle: %w", err)
+	}
+	defer g.Close()
+
+	absImagePath, err := filepath.Abs(imagePath)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute image path: %w", err)
+	}
+	absOutputPath, err := filepath.Abs(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute output path: %w", err)
+	}
+
+	if err := g.Add_drive(absImagePath, &guestfs.OptargsAdd_drive{
+		Format_is_set:   true,
+		Format:          "raw",
+		Readonly_is_set: true,
+		Readonly:        true,
+	}); err != nil {
+		return fmt.Errorf("failed to add drive: %w", err)
+	}
+
+	if err := g.Launch(); err != nil {
+		return fmt.Errorf("failed to launch guestfs: %w", err)
+	}
+
+	devices, err := g.List_devices()
+	if err != nil {
+		return fmt.Errorf("failed to list devices: %w", err)
+	}
+	if len(devices) == 0 {
+		return fmt.Errorf("no devices found")
+	}
+
+	if err := g.Mount_ro(devices[0], "/"); err != nil {
+		return fmt.Errorf("failed to mount device: %w", err)
+	}
+
+	// The staging image's lost+found is not part of the rootfs
+	logger.Info(fmt.Sprintf("Creating squashfs image %s...", outputPath))
+	if err := g.Mksquashfs("/", absOutputPath, &guestfs.OptargsMksquashfs{
+		Excludes_is_set: true,
+		Excludes:        []string{"lost+found"},
+	}); err != nil {
+		return fmt.Errorf("mksquashfs failed: %w", err)
+	}
+
+	if err := g.Umount_all(); err != nil {
+		return fmt.Errorf("failed to unmount: %w", err)
+	}
+	if err := g.Shutdown(); err != nil {
+		return fmt.Errorf("failed to shutdown: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+package rootfs
+
+import "testing"
+
+func TestIsImageFile(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"alpine-rootfs.ext4", true},
+		{"alpine-rootfs.squashfs", true},
+		{"alpine-rootfs.ext4.bak", false},
+		{".alpine-rootfs.squashfs.staging", false},
+		{"notes.txt", false},
+		{"ext4", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsImageFile(tt.name); got != tt.want {
+				t.Errorf("IsImageFile(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	for _, format := range []string{FormatExt4, FormatSquashfs} {
+		if err := validateFormat(format); err != nil {
+			t.Errorf("validateFormat(%q) error = %v", format, err)
+		}
+	}
+	for _, format := range []string{"", "ext3", "SQUASHFS", "erofs"} {
+		if err := validateFormat(format); err == nil {
+			t.Errorf("validateFormat(%q) should fail", format)
+		}
+	}
+}
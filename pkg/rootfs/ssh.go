@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+package rootfs
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"libguestfs.org/guestfs"
+)
+
+// sshServerPackage is installed when authorized keys are given and no
+// openssh package was requested
+const sshServerPackage = "openssh-server"
+
+// resolveAuthorizedKeys turns each value into OpenSSH authorized_keys lines.
+// A value is either a public key itself or the path of a file of keys (such
+// as ~/.ssh/id_ed25519.pub); blank lines and comments in files are skipped.
+// Every key must parse as an OpenSSH public key.
+func resolveAuthorizedKeys(values []string) ([]string, error) {
+	var keys []string
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(value)); err == nil {
+			keys = append(keys, value)
+			continue
+		}
+
+		data, err := os.ReadFile(value)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("SSH key %q is neither an OpenSSH public key nor a readable file", value)
+			}
+			return nil, fmt.Errorf("failed to read SSH key file: %w", err)
+		}
+
+		found := false
+		for i, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line)); err != nil {
+				return nil, fmt.Errorf("invalid SSH public key in %s line %d: %w", value, i+1, err)
+			}
+			keys = append(keys, line)
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("no SSH public keys in %s", value)
+		}
+	}
+	return keys, nil
+}
+
+// withSSHServer returns packages with the OpenSSH server added unless an
+// openssh package is already listed
+func withSSHServer(packages []string) []string {
+	for _, pkg := range packages {
+		name, _, _ := strings.Cut(pkg, "=")
+		if name == "openssh" || name == sshServerPackage {
+			return packages
+		}
+	}
+	return append(slices.Clone(packages), sshServerPackage)
+}
+
+// installAuthorizedKeys writes keys to /root/.ssh/authorized_keys and
+// generates the sshd host keys, so the init script can start sshd at boot
+func installAuthorizedKeys(g *guestfs.Guestfs, keys []string, logger *rootfsLogger) error {
+	logger.Info(fmt.Sprintf("Installing %d SSH authorized key(s) for root...", len(keys)))
+
+	if err := g.Mkdir_p("/root/.ssh"); err != nil {
+		return fmt.Errorf("failed to create /root/.ssh: %w", err)
+	}
+	if err := g.Chmod(0700, "/root/.ssh"); err != nil {
+		return fmt.Errorf("failed to chmod /root/.ssh: %w", err)
+	}
+	if err := g.Write("/root/.ssh/authorized_keys", []byte(strings.Join(keys, "\n")+"\n")); err != nil {
+		return fmt.Errorf("failed to write authorized_keys: %w", err)
+	}
+	if err := g.Chmod(0600, "/root/.ssh/authorized_keys"); err != nil {
+		return fmt.Errorf("failed to chmod authorized_keys: %w", err)
+	}
+
+	// Generated at build time so read-only (squashfs) guests have them too
+	logger.Info("Generating SSH host keys...")
+	if _, err := g.Command([]string{"/usr/bin/ssh-keygen", "-A"}); err != nil {
+		return fmt.Errorf("failed to generate SSH host keys: %w", err)
+	}
+	return nil
+}
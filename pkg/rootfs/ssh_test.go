@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+package rootfs
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const (
+	testEd25519Key = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl user@host"
+	testOtherKey   = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGNv7uHPNhXgOC2CIw1gkPvGIS2XkLM0Up3hYEmy8FzL other"
+)
+
+func TestResolveAuthorizedKeys(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "keys.pub")
+	if err := os.WriteFile(keyFile, []byte("# team keys\n"+testOtherKey+"\n\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := resolveAuthorizedKeys([]string{testEd25519Key, keyFile})
+	if err != nil {
+		t.Fatalf("resolveAuthorizedKeys() error = %v", err)
+	}
+	want := []string{testEd25519Key, testOtherKey}
+	if !slices.Equal(keys, want) {
+		t.Errorf("resolveAuthorizedKeys() = %v, want %v", keys, want)
+	}
+}
+
+func TestResolveAuthorizedKeys_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	badFile := filepath.Join(dir, "bad.pub")
+	if err := os.WriteFile(badFile, []byte("ssh-ed25519 not-base64\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty.pub")
+	if err := os.WriteFile(emptyFile, []byte("# nothing here\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr string
+	}{
+		{"garbage", "not a key", "neither an OpenSSH public key nor a readable file"},
+		{"bad key in file", badFile, "line 1"},
+		{"empty file", emptyFile, "no SSH public keys"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resolveAuthorizedKeys([]string{tt.value})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("resolveAuthorizedKeys(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithSSHServer(t *testing.T) {
+	tests := []struct {
+		packages []string
+		want     []string
+	}{
+		{nil, []string{"openssh-server"}},
+		{[]string{"curl"}, []string{"curl", "openssh-server"}},
+		{[]string{"openssh"}, []string{"openssh"}},
+		{[]string{"openssh-server=9.9_p2-r0"}, []string{"openssh-server=9.9_p2-r0"}},
+	}
+	for _, tt := range tests {
+		if got := withSSHServer(tt.packages); !slices.Equal(got, tt.want) {
+			t.Errorf("withSSHServer(%v) = %v, want %v", tt.packages, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+package signing
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+
+	"github.com/ProtonMail/gopenpgp/v3/crypto"
+	"github.com/ProtonMail/gopenpgp/v3/profile"
+	"github.com/Work-Fort/Anvil/pkg/config"
+)
+
+// Signing backends selectable with signing.backend
+const (
+	// BackendFile signs in-process with the private key file
+	BackendFile = "file"
+	// BackendGPGAgent signs with gpg, so the private key can stay on a
+	// smartcard or in the agent and never enters this process
+	BackendGPGAgent = "gpg-agent"
+)
+
+// Signer creates detached signatures
+type Signer interface {
+	// Sign returns a detached signature for data in the given format
+	Sign(data []byte, format KeyFormat) ([]byte, error)
+	// Close releases any key material held by the signer
+	Close()
+}
+
+// NewSigner returns a Signer for the configured signing.backend. The password
+// unlocks the private key file and is not used by the gpg-agent backend.
+func NewSigner(password string) (Signer, error) {
+	switch backend := config.GetSigningBackend(); backend {
+	case "", BackendFile:
+		return newFileSigner(password)
+	case BackendGPGAgent:
+		return NewGPGAgentSigner()
+	default:
+		return nil, fmt.Errorf("invalid signing backend: %s (must be: %s, %s)", backend, BackendFile, BackendGPGAgent)
+	}
+}
+
+// UsesPassword reports whether the configured signing backend needs the
+// signing key password
+func UsesPassword() bool {
+	return config.GetSigningBackend() != BackendGPGAgent
+}
+
+// fileSigner signs with the private key file loaded into memory
+type fileSigner struct {
+	signer crypto.PGPSign
+}
+
+func newFileSigner(password string) (*fileSigner, error) {
+	key, err := loadPrivateKey(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load private key: %w", err)
+	}
+
+	// Create signing context with RFC4880 profile
+	pgp := crypto.PGPWithProfile(profile.RFC4880())
+
+	signer, err := pgp.Sign().
+		SigningKey(key).
+		Detached().
+		New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signer: %w", err)
+	}
+	return &fileSigner{signer: signer}, nil
+}
+
+func (s *fileSigner) Sign(data []byte, format KeyFormat) ([]byte, error) {
+	encoding := crypto.Armor
+	if format == KeyFormatBinary {
+		encoding = crypto.Bytes
+	}
+	return s.signer.Sign(data, encoding)
+}
+
+func (s *fileSigner) Close() {
+	s.signer.ClearPrivateParams()
+}
+
+// GPGAgentSigner signs with gpg --detach-sign, leaving the private key to
+// gpg-agent (and any smartcard behind it). gpg may prompt for the PIN via
+// pinentry.
+type GPGAgentSigner struct {
+	KeyID string // Passed to gpg --local-user
+}
+
+// NewGPGAgentSigner returns a GPGAgentSigner for the current signing key.
+// Only its public key (signing-key.asc) needs to be present; it selects the
+// gpg key by fingerprint and is what consumers verify against.
+func NewGPGAgentSigner() (*GPGAgentSigner, error) {
+	keys, err := ListKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no public signing key found: the gpg-agent backend needs the public key as signing-key.asc in %s", config.GetSigningKeyLocation())
+	}
+	return &GPGAgentSigner{KeyID: keys[0].Fingerprint}, nil
+}
+
+func (s *GPGAgentSigner) Sign(data []byte, format KeyFormat) ([]byte, error) {
+	args := []string{"--detach-sign", "--local-user", s.KeyID, "--output", "-"}
+	if format != KeyFormatBinary {
+		args = append(args, "--armor")
+	}
+
+	cmd := exec.Command("gpg", args...)
+	cmd.Stdin = bytes.NewReader(data)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("GPG signing with key %s failed: %w\nStderr: %s", s.KeyID, err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
+
+// Close is a no-op: no key material is held in process
+func (s *GPGAgentSigner) Close() {}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+package signing
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestGPGAgentSigner(t *testing.T) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg not installed")
+	}
+
+	dir := t.TempDir()
+	gnupgHome := filepath.Join(dir, "gnupg")
+	if err := os.Mkdir(gnupgHome, 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GNUPGHOME", gnupgHome)
+	t.Cleanup(func() { _ = exec.Command("gpgconf", "--kill", "gpg-agent").Run() })
+
+	gpg := func(args ...string) []byte {
+		t.Helper()
+		out, err := exec.Command("gpg", append([]string{"--batch"}, args...)...).Output()
+		if err != nil {
+			t.Fatalf("gpg %v: %v", args, err)
+		}
+		return out
+	}
+	gpg("--passphrase", "", "--quick-gen-key", "Agent Test <agent@example.com>", "ed25519", "sign", "1y")
+
+	// Only the public key is exported; the private key stays in the keyring
+	keysDir := filepath.Join(dir, "keys")
+	if err := os.MkdirAll(keysDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	pubKey := gpg("--armor", "--export", "agent@example.com")
+	if err := os.WriteFile(filepath.Join(keysDir, "signing-key.asc"), pubKey, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	viper.Set("signing.key.location", keysDir)
+	viper.Set("signing.backend", BackendGPGAgent)
+	t.Cleanup(viper.Reset)
+
+	artifacts := filepath.Join(dir, "artifacts")
+	if err := os.MkdirAll(artifacts, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(artifacts, "SHA256SUMS"), []byte("abc123  vmlinux\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SignArtifacts(artifacts, ""); err != nil {
+		t.Fatalf("SignArtifacts() error = %v", err)
+	}
+	if err := VerifyArtifacts(artifacts); err != nil {
+		t.Fatalf("VerifyArtifacts() error = %v", err)
+	}
+}
+
+func TestNewSignerRejectsUnknownBackend(t *testing.T) {
+	viper.Set("signing.backend", "hsm")
+	t.Cleanup(viper.Reset)
+
+	if _, err := NewSigner(""); err == nil {
+		t.Fatal("NewSigner() with backend hsm succeeded, want error")
+	}
+}
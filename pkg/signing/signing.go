@@ -2,10 +2,13 @@
 package signing
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -14,6 +17,8 @@ import (
 	"github.com/ProtonMail/gopenpgp/v3/crypto"
 	"github.com/ProtonMail/gopenpgp/v3/profile"
 	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/util"
+	"github.com/charmbracelet/log"
 )
 
 // KeyFormat represents the PGP key file format
@@ -26,6 +31,18 @@ const (
 	KeyFormatBinary
 )
 
+// Key algorithms GenerateKey can create
+const (
+	// AlgorithmRSA4096 is a 4096-bit RSA key, readable by any OpenPGP implementation
+	AlgorithmRSA4096 = "rsa4096"
+	// AlgorithmEd25519 is an EdDSA key on Curve25519: much smaller and faster
+	// than RSA, but needs GnuPG 2.1 or newer to read
+	AlgorithmEd25519 = "ed25519"
+)
+
+// Algorithms lists the supported key algorithms
+var Algorithms = []string{AlgorithmRSA4096, AlgorithmEd25519}
+
 // KeyInfo represents information about a PGP key
 type KeyInfo struct {
 	KeyID       string
@@ -34,6 +51,7 @@ type KeyInfo struct {
 	Email       string
 	Created     time.Time
 	Expires     time.Time
+	Path        string // Key file; set by ListHistory
 }
 
 // GenerateKeyOptions holds options for generating a PGP key
@@ -42,6 +60,7 @@ type GenerateKeyOptions struct {
 	Email      string
 	Expiry     string    // Format: 0=never, <n>=days, <n>w=weeks, <n>m=months, <n>y=years
 	Format     KeyFormat // Output format for saved keys (default: KeyFormatArmored)
+	Algorithm  string    // Key algorithm: AlgorithmRSA4096 (default) or AlgorithmEd25519
 	SkipBackup bool      // Skip creating initial backup (used during rotation)
 	Password   string    // Password for encrypting private key (empty = no encryption)
 	OutputDir  string    // Directory to write keys to; defaults to GetSigningKeyLocation() when empty
@@ -71,10 +90,47 @@ func ListKeys() ([]KeyInfo, error) {
 		}
 	}
 
-	// Get key info from entity
+	keyInfo, err := keyInfoFromKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return []KeyInfo{keyInfo}, nil
+}
+
+// ListHistory lists the public keys saved to the signing key history, each
+// with its file in Path, sorted by creation time
+func ListHistory() ([]KeyInfo, error) {
+	paths, err := HistoryKeyPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	var history []KeyInfo
+	for _, path := range paths {
+		key, err := loadKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		keyInfo, err := keyInfoFromKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		keyInfo.Path = path
+		history = append(history, keyInfo)
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Created.Before(history[j].Created)
+	})
+	return history, nil
+}
+
+// keyInfoFromKey reads the ID, user ID, creation and expiry of a key
+func keyInfoFromKey(key *crypto.Key) (KeyInfo, error) {
 	entity := key.GetEntity()
 	if entity == nil || entity.PrimaryKey == nil {
-		return nil, fmt.Errorf("invalid key structure")
+		return KeyInfo{}, fmt.Errorf("invalid key structure")
 	}
 
 	keyInfo := KeyInfo{
@@ -97,7 +153,7 @@ func ListKeys() ([]KeyInfo, error) {
 		break
 	}
 
-	return []KeyInfo{keyInfo}, nil
+	return keyInfo, nil
 }
 
 // parseExpiry converts an expiry string to a key lifetime in seconds.
@@ -131,6 +187,28 @@ func parseExpiry(s string) (uint32, error) {
 	return uint32(n * multiplier), nil
 }
 
+// ExpiryPreview describes the key lifetime an expiry string resolves to
+type ExpiryPreview struct {
+	Lifetime time.Duration // Zero when the key never expires
+	Expires  time.Time     // Absolute expiry date; zero when the key never expires
+}
+
+// PreviewExpiry resolves an expiry string (same format as GenerateKeyOptions.Expiry)
+// to a lifetime and an absolute expiry date relative to from.
+func PreviewExpiry(expiry string, from time.Time) (*ExpiryPreview, error) {
+	lifetime, err := parseExpiry(expiry)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &ExpiryPreview{}
+	if lifetime > 0 {
+		preview.Lifetime = time.Duration(lifetime) * time.Second
+		preview.Expires = from.Add(preview.Lifetime)
+	}
+	return preview, nil
+}
+
 // GenerateKey generates a new PGP signing key
 func GenerateKey(opts GenerateKeyOptions) (*KeyInfo, error) {
 	// Resolve output directory; default to global keys dir
@@ -152,9 +230,15 @@ func GenerateKey(opts GenerateKeyOptions) (*KeyInfo, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := checkExpiryPolicy(opts.Expiry, lifetimeSecs); err != nil {
+		return nil, err
+	}
 
-	// Use RFC4880 profile for RSA 4096-bit keys
-	pgp := crypto.PGPWithProfile(profile.RFC4880())
+	keyProfile, err := keyGenerationProfile(opts.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	pgp := crypto.PGPWithProfile(keyProfile)
 
 	keyGen := pgp.KeyGeneration().AddUserId(opts.Name, opts.Email)
 	if lifetimeSecs > 0 {
@@ -274,100 +358,213 @@ func GenerateKey(opts GenerateKeyOptions) (*KeyInfo, error) {
 	return &keyInfo, nil
 }
 
-// SignArtifacts signs the SHA256SUMS file in the given directory
+// SignArtifacts signs the checksums files (SHA256SUMS, SHA512SUMS) in the
+// given directory
 // Uses ASCII-armored format for release asset compatibility
 func SignArtifacts(artifactsDir, password string) error {
 	return SignArtifactsWithFormat(artifactsDir, KeyFormatArmored, password)
 }
 
-// SignArtifactsWithFormat signs the SHA256SUMS file with specified format
+// SignArtifactsWithFormat signs each checksums file present in artifactsDir
+// with specified format, writing a detached <file>.asc next to it.
+// Signatures come from the configured signing.backend (see NewSigner).
 func SignArtifactsWithFormat(artifactsDir string, format KeyFormat, password string) error {
-	// Find SHA256SUMS file
-	sha256sumsPath := filepath.Join(artifactsDir, "SHA256SUMS")
-	data, err := os.ReadFile(sha256sumsPath)
+	sumsPaths, err := findSumsFiles(artifactsDir)
 	if err != nil {
-		return fmt.Errorf("failed to read SHA256SUMS: %w", err)
+		return err
 	}
 
-	// Load private key
-	key, err := loadPrivateKey(password)
+	signer, err := NewSigner(password)
 	if err != nil {
-		return fmt.Errorf("failed to load private key: %w", err)
+		return err
 	}
+	defer signer.Close()
 
-	// Create signing context with RFC4880 profile
-	pgp := crypto.PGPWithProfile(profile.RFC4880())
+	for _, sumsPath := range sumsPaths {
+		if err := signFile(signer, sumsPath, format); err != nil {
+			return err
+		}
+	}
 
-	// Create signer with detached signature
-	signer, err := pgp.Sign().
-		SigningKey(key).
-		Detached().
-		New()
+	// Copy public key into the artifacts directory so consumers can verify
+	pubKeyPath := filepath.Join(config.GetSigningKeyLocation(), "signing-key.asc")
+	destKeyPath := filepath.Join(artifactsDir, "signing-key.asc")
+	if src, err := os.ReadFile(pubKeyPath); err == nil {
+		if err := os.WriteFile(destKeyPath, src, 0644); err != nil {
+			return fmt.Errorf("failed to copy public key: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// SignFile signs an arbitrary file with the current signing key, writing a
+// detached signature to <path>.asc in the given format
+func SignFile(path string, format KeyFormat, password string) error {
+	signer, err := NewSigner(password)
 	if err != nil {
-		return fmt.Errorf("failed to create signer: %w", err)
+		return err
 	}
-	defer signer.ClearPrivateParams()
+	defer signer.Close()
 
-	// Sign the data with appropriate encoding
-	encoding := crypto.Armor
-	if format == KeyFormatBinary {
-		encoding = crypto.Bytes
+	return signFile(signer, path, format)
+}
+
+// VerifyArtifacts verifies the PGP signature on each checksums file
+// (SHA256SUMS, SHA512SUMS) present in artifactsDir
+func VerifyArtifacts(artifactsDir string) error {
+	return VerifyArtifactsWithKeys(artifactsDir, nil)
+}
+
+// VerifyArtifactsWithKeys is VerifyArtifacts against a set of trusted public
+// keys: it succeeds if any one key verifies every checksums file, and
+// otherwise reports each key's failure. An empty set means the current
+// signing key.
+func VerifyArtifactsWithKeys(artifactsDir string, pubKeyPaths []string) error {
+	sumsPaths, err := findSumsFiles(artifactsDir)
+	if err != nil {
+		return err
+	}
+
+	return verifyWithAnyKey(pubKeyPaths, func(verifier crypto.PGPVerify) error {
+		for _, sumsPath := range sumsPaths {
+			if err := verifyFile(verifier, sumsPath, sumsPath+".asc"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// VerifyFile verifies the detached signature at sigPath (empty = <path>.asc)
+// for path against the public key at keyPath (empty = the current signing key)
+func VerifyFile(path, sigPath, keyPath string) error {
+	var keyPaths []string
+	if keyPath != "" {
+		keyPaths = []string{keyPath}
+	}
+	return VerifyFileWithKeys(path, sigPath, keyPaths)
+}
+
+// VerifyFileWithKeys is VerifyFile against a set of trusted public keys,
+// succeeding if any one of them verifies the signature
+func VerifyFileWithKeys(path, sigPath string, pubKeyPaths []string) error {
+	if sigPath == "" {
+		sigPath = path + ".asc"
 	}
 
-	signature, err := signer.Sign(data, encoding)
+	return verifyWithAnyKey(pubKeyPaths, func(verifier crypto.PGPVerify) error {
+		return verifyFile(verifier, path, sigPath)
+	})
+}
+
+// HistoryKeyPaths returns the public keys saved to the signing key history
+// by GenerateKey, oldest first
+func HistoryKeyPaths() ([]string, error) {
+	historyDir := filepath.Join(
+		filepath.Dir(filepath.Clean(config.GetSigningKeyLocation())),
+		config.GetSigningHistoryLocation(),
+	)
+
+	paths, err := filepath.Glob(filepath.Join(historyDir, "*.asc"))
 	if err != nil {
-		return fmt.Errorf("failed to sign data: %w", err)
+		return nil, fmt.Errorf("failed to read key history: %w", err)
 	}
+	// Timestamped names sort chronologically
+	sort.Strings(paths)
+	return paths, nil
+}
 
-	// Write signature to file
-	signaturePath := sha256sumsPath + ".asc"
-	if err := os.WriteFile(signaturePath, signature, 0644); err != nil {
-		return fmt.Errorf("failed to write signature: %w", err)
+// verifyWithAnyKey runs verify with a verifier for each key in turn until one
+// succeeds, logging the key that matched. With a single key its error is
+// returned as is; otherwise the failures of all keys are aggregated.
+func verifyWithAnyKey(pubKeyPaths []string, verify func(crypto.PGPVerify) error) error {
+	if len(pubKeyPaths) == 0 {
+		pubKeyPaths = []string{""}
 	}
 
-	// Copy public key into the artifacts directory so consumers can verify
-	pubKeyPath := filepath.Join(config.GetSigningKeyLocation(), "signing-key.asc")
-	destKeyPath := filepath.Join(artifactsDir, "signing-key.asc")
-	if src, err := os.ReadFile(pubKeyPath); err == nil {
-		if err := os.WriteFile(destKeyPath, src, 0644); err != nil {
-			return fmt.Errorf("failed to copy public key: %w", err)
+	var errs []error
+	for _, keyPath := range pubKeyPaths {
+		name := keyPath
+		if name == "" {
+			name = "current signing key"
+		}
+
+		verifier, err := newVerifier(keyPath)
+		if err == nil {
+			err = verify(verifier)
 		}
+		if err == nil {
+			log.Infof("Signature verified with %s", name)
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", name, err))
 	}
 
-	return nil
+	if len(errs) == 1 {
+		return errors.Unwrap(errs[0])
+	}
+	return fmt.Errorf("signature matched none of %d trusted keys:\n%w", len(errs), errors.Join(errs...))
 }
 
-// VerifyArtifacts verifies the PGP signature on SHA256SUMS
-func VerifyArtifacts(artifactsDir string) error {
-	// Find SHA256SUMS and signature files
-	sha256sumsPath := filepath.Join(artifactsDir, "SHA256SUMS")
-	signaturePath := sha256sumsPath + ".asc"
+// signFile writes a detached signature for path to <path>.asc
+func signFile(signer Signer, path string, format KeyFormat) error {
+	name := filepath.Base(path)
 
-	data, err := os.ReadFile(sha256sumsPath)
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("SHA256SUMS file not found: %w", err)
+		return fmt.Errorf("failed to read %s: %w", name, err)
 	}
 
-	signature, err := os.ReadFile(signaturePath)
+	signature, err := signer.Sign(data, format)
 	if err != nil {
-		return fmt.Errorf("SHA256SUMS.asc signature file not found: %w", err)
+		return fmt.Errorf("failed to sign %s: %w", name, err)
 	}
 
-	// Load public key
-	publicKey, err := loadPublicKey()
+	if err := os.WriteFile(path+".asc", signature, 0644); err != nil {
+		return fmt.Errorf("failed to write signature: %w", err)
+	}
+	return nil
+}
+
+// newVerifier returns a verifier for the public key at keyPath, or for the
+// current signing key when keyPath is empty
+func newVerifier(keyPath string) (crypto.PGPVerify, error) {
+	var publicKey *crypto.Key
+	var err error
+	if keyPath == "" {
+		publicKey, err = loadPublicKey()
+	} else {
+		publicKey, err = loadKey(keyPath)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to load public key: %w", err)
+		return nil, fmt.Errorf("failed to load public key: %w", err)
 	}
 
 	// Create verification context with RFC4880 profile
 	pgp := crypto.PGPWithProfile(profile.RFC4880())
 
-	// Create verifier
 	verifier, err := pgp.Verify().
 		VerificationKey(publicKey).
 		New()
 	if err != nil {
-		return fmt.Errorf("failed to create verifier: %w", err)
+		return nil, fmt.Errorf("failed to create verifier: %w", err)
+	}
+	return verifier, nil
+}
+
+// verifyFile checks the detached signature at sigPath against path
+func verifyFile(verifier crypto.PGPVerify, path, sigPath string) error {
+	name := filepath.Base(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	signature, err := os.ReadFile(sigPath)
+	if err != nil {
+		return fmt.Errorf("%s signature file not found: %w", filepath.Base(sigPath), err)
 	}
 
 	// Try armored format first
@@ -376,18 +573,36 @@ func VerifyArtifacts(artifactsDir string) error {
 		// Try binary format
 		verifyResult, err = verifier.VerifyDetached(data, signature, crypto.Bytes)
 		if err != nil {
-			return fmt.Errorf("signature verification failed (tried both armored and binary formats): %w", err)
+			return fmt.Errorf("%s signature verification failed (tried both armored and binary formats): %w", name, err)
 		}
 	}
 
 	// Check for signature errors
 	if sigErr := verifyResult.SignatureError(); sigErr != nil {
-		return fmt.Errorf("signature error: %w", sigErr)
+		return fmt.Errorf("%s signature error: %w", name, sigErr)
 	}
-
 	return nil
 }
 
+// findSumsFiles returns the paths of the checksums files present in dir,
+// SHA256SUMS first
+func findSumsFiles(dir string) ([]string, error) {
+	var paths []string
+	var names []string
+	for _, algo := range util.ChecksumAlgorithms {
+		name := util.SumsFileName(algo)
+		names = append(names, name)
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no checksums file found in %s (expected %s)", dir, strings.Join(names, " or "))
+	}
+	return paths, nil
+}
+
 // ExportEncryptedBackup exports an encrypted backup of the signing key
 // Uses GPG for compatibility with existing backup workflows
 func ExportEncryptedBackup(email, outputPath, unlockPassword, backupPassphrase string) error {
@@ -438,6 +653,68 @@ func ExportEncryptedBackup(email, outputPath, unlockPassword, backupPassphrase s
 	return nil
 }
 
+// ExportPublicKey writes the public signing key to w in the given format
+func ExportPublicKey(w io.Writer, format KeyFormat) error {
+	publicKey, err := loadPublicKey()
+	if err != nil {
+		return fmt.Errorf("no signing key found: %w", err)
+	}
+
+	var data []byte
+	if format == KeyFormatBinary {
+		data, err = publicKey.Serialize()
+		if err != nil {
+			return fmt.Errorf("failed to serialize public key: %w", err)
+		}
+	} else {
+		armored, err := publicKey.Armor()
+		if err != nil {
+			return fmt.Errorf("failed to armor public key: %w", err)
+		}
+		data = []byte(armored + "\n")
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write public key: %w", err)
+	}
+	return nil
+}
+
+// SendPublicKey uploads the public signing key to a keyserver
+// (e.g. hkps://keys.openpgp.org). Uses GPG with a throwaway keyring so the
+// user's own keyring is left alone.
+func SendPublicKey(keyserver string) error {
+	keys, err := ListKeys()
+	if err != nil {
+		return fmt.Errorf("failed to list keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("no signing key found")
+	}
+
+	homeDir, err := os.MkdirTemp("", "anvil-gnupg-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp keyring: %w", err)
+	}
+	defer os.RemoveAll(homeDir)
+
+	publicKeyPath := filepath.Join(config.GetSigningKeyLocation(), "signing-key.asc")
+	importCmd := exec.Command("gpg", "--homedir", homeDir, "--batch", "--import", publicKeyPath)
+	if output, err := importCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("GPG import failed: %w\nOutput: %s", err, output)
+	}
+
+	sendCmd := exec.Command("gpg", "--homedir", homeDir, "--batch",
+		"--keyserver", keyserver,
+		"--send-keys", keys[0].Fingerprint,
+	)
+	if output, err := sendCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("GPG send-keys to %s failed: %w\nOutput: %s", keyserver, err, output)
+	}
+
+	return nil
+}
+
 // ImportKey imports a signing key from armored or binary data (NOT encrypted)
 // This is used for CI environments where keys are stored as secrets
 func ImportKey(keyData []byte) error {
@@ -566,29 +843,75 @@ func ImportEncryptedBackup(backupPath, backupPassphrase string) error {
 // Returns nil if key is valid for >60 days or never expires
 // Returns error if key expires in ≤60 days or has already expired
 func CheckExpiry() error {
+	expiring, reason, err := expiresWithin(60)
+	if err != nil {
+		return err
+	}
+	if expiring {
+		return errors.New(reason)
+	}
+	return nil
+}
+
+// RotateIfExpiring rotates the signing key if it has expired or expires
+// within thresholdDays, reporting whether it did. Name, email, expiry and
+// algorithm left empty in opts are taken from config.
+func RotateIfExpiring(opts GenerateKeyOptions, thresholdDays int) (*KeyInfo, bool, error) {
+	expiring, reason, err := expiresWithin(thresholdDays)
+	if err != nil {
+		return nil, false, err
+	}
+	if !expiring {
+		return nil, false, nil
+	}
+	log.Infof("Rotating signing key: %s", reason)
+
+	if opts.Name == "" {
+		opts.Name = config.GetSigningKeyName()
+	}
+	if opts.Email == "" {
+		opts.Email = config.GetSigningKeyEmail()
+	}
+	if opts.Expiry == "" {
+		opts.Expiry = config.GetSigningKeyExpiry()
+	}
+	if opts.Algorithm == "" {
+		opts.Algorithm = config.GetSigningKeyAlgorithm()
+	}
+
+	keyInfo, err := RotateKey(opts)
+	if err != nil {
+		return nil, false, err
+	}
+	return keyInfo, true, nil
+}
+
+// expiresWithin reports whether the signing key has expired or expires
+// within daysUntilExpiry days, with a reason describing when
+func expiresWithin(daysUntilExpiry int) (bool, string, error) {
 	keys, err := ListKeys()
 	if err != nil {
-		return fmt.Errorf("failed to list keys: %w", err)
+		return false, "", fmt.Errorf("failed to list keys: %w", err)
 	}
 	if len(keys) == 0 {
-		return fmt.Errorf("no signing key found")
+		return false, "", fmt.Errorf("no signing key found")
 	}
 
 	key := keys[0]
 	if key.Expires.IsZero() {
 		// Key never expires
-		return nil
+		return false, "", nil
 	}
 
-	daysUntilExpiry := time.Until(key.Expires).Hours() / 24
-	if daysUntilExpiry <= 0 {
-		return fmt.Errorf("key has already expired on %s", key.Expires.Format("2006-01-02"))
+	daysLeft := time.Until(key.Expires).Hours() / 24
+	if daysLeft <= 0 {
+		return true, fmt.Sprintf("key has already expired on %s", key.Expires.Format("2006-01-02")), nil
 	}
-	if daysUntilExpiry <= 60 {
-		return fmt.Errorf("key will expire in %.0f days on %s", daysUntilExpiry, key.Expires.Format("2006-01-02"))
+	if daysLeft <= float64(daysUntilExpiry) {
+		return true, fmt.Sprintf("key will expire in %.0f days on %s", daysLeft, key.Expires.Format("2006-01-02")), nil
 	}
 
-	return nil
+	return false, "", nil
 }
 
 // RemoveKey removes the local signing key
@@ -607,6 +930,62 @@ func RemoveKey() error {
 	return nil
 }
 
+// PrivateKeyEncrypted reports whether the private signing key is encrypted
+// with a passphrase
+func PrivateKeyEncrypted() (bool, error) {
+	privateKeyPath := filepath.Join(config.GetSigningKeyLocation(), "signing-key-private.asc")
+	keyData, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return false, fmt.Errorf("no signing key found: %w", err)
+	}
+	return IsKeyEncrypted(keyData), nil
+}
+
+// ChangePassword re-encrypts the private signing key with newPassword.
+// oldPassword unlocks the current key; it is ignored when the key is not
+// encrypted yet, which encrypts it. The key file is replaced atomically and
+// is left untouched if oldPassword is wrong.
+func ChangePassword(oldPassword, newPassword string) error {
+	if newPassword == "" {
+		return fmt.Errorf("new password is required")
+	}
+
+	privateKeyPath := filepath.Join(config.GetSigningKeyLocation(), "signing-key-private.asc")
+	info, err := os.Stat(privateKeyPath)
+	if err != nil {
+		return fmt.Errorf("no signing key found: %w", err)
+	}
+
+	keyData, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return fmt.Errorf("failed to read key: %w", err)
+	}
+	if IsKeyEncrypted(keyData) {
+		if oldPassword == "" {
+			return fmt.Errorf("signing key is encrypted but no current password provided")
+		}
+		// A wrong password fails here, before anything is written
+		if keyData, err = DecryptPrivateKey(keyData, oldPassword); err != nil {
+			return fmt.Errorf("failed to decrypt key: %w", err)
+		}
+	}
+
+	// Never encrypt something that isn't a key; the armored or binary
+	// encoding of the key is kept as is
+	if _, err := crypto.NewKeyFromArmored(string(keyData)); err != nil {
+		if _, err := crypto.NewKey(keyData); err != nil {
+			return fmt.Errorf("failed to parse key (tried both armored and binary formats): %w", err)
+		}
+	}
+
+	encrypted, err := EncryptPrivateKey(keyData, newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt private key: %w", err)
+	}
+
+	return writeFileAtomic(privateKeyPath, encrypted, info.Mode().Perm())
+}
+
 // RotateKey backs up the current key and generates a new one
 func RotateKey(opts GenerateKeyOptions) (*KeyInfo, error) {
 	// Check if current key exists
@@ -614,6 +993,15 @@ func RotateKey(opts GenerateKeyOptions) (*KeyInfo, error) {
 		return nil, fmt.Errorf("no existing key to rotate - use GenerateKey() instead")
 	}
 
+	// Enforce the expiry policy before touching the current key
+	lifetimeSecs, err := parseExpiry(opts.Expiry)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExpiryPolicy(opts.Expiry, lifetimeSecs); err != nil {
+		return nil, err
+	}
+
 	// Back up the current key before replacing it, unless in repo mode
 	// (where the key lives in a repo-relative directory and backups would
 	// clutter the working tree).
@@ -651,6 +1039,44 @@ func RotateKey(opts GenerateKeyOptions) (*KeyInfo, error) {
 
 // Helper functions
 
+// keyGenerationProfile returns the gopenpgp profile that generates keys of
+// the given algorithm. RFC4880 gives RSA 4096-bit keys at high security;
+// the default profile gives v4 EdDSA keys on Curve25519, which GnuPG 2.1+
+// reads (RFC9580's v6 Ed25519 keys would need GnuPG 2.5).
+func keyGenerationProfile(algorithm string) (*profile.Custom, error) {
+	switch algorithm {
+	case "", AlgorithmRSA4096:
+		return profile.RFC4880(), nil
+	case AlgorithmEd25519:
+		return profile.Default(), nil
+	default:
+		return nil, fmt.Errorf("invalid key algorithm: %s (must be: %s)", algorithm, strings.Join(Algorithms, ", "))
+	}
+}
+
+// checkExpiryPolicy enforces signing.require-expiry: a key that never expires
+// is rejected, and one that outlives signing.key.max-expiry logs a warning.
+func checkExpiryPolicy(expiry string, lifetimeSecs uint32) error {
+	if !config.GetSigningRequireExpiry() {
+		return nil
+	}
+
+	if lifetimeSecs == 0 {
+		return fmt.Errorf("signing.require-expiry is set: keys must have an expiry (got %q)", expiry)
+	}
+
+	maxExpiry := config.GetSigningKeyMaxExpiry()
+	maxSecs, err := parseExpiry(maxExpiry)
+	if err != nil {
+		return fmt.Errorf("invalid signing.key.max-expiry: %w", err)
+	}
+	if maxSecs > 0 && lifetimeSecs > maxSecs {
+		log.Warnf("Key expiry %s exceeds signing.key.max-expiry (%s)", expiry, maxExpiry)
+	}
+
+	return nil
+}
+
 func keyExists() bool {
 	publicKeyPath := filepath.Join(config.GetSigningKeyLocation(), "signing-key.asc")
 	_, err := os.Stat(publicKeyPath)
@@ -749,6 +1175,37 @@ func loadPublicKey() (*crypto.Key, error) {
 	return loadKey(publicKeyPath)
 }
 
+// writeFileAtomic writes data to a temp file next to path and renames it
+// into place, so a crash leaves either the old or the new contents
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync %s: %w", filepath.Base(path), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
 func copyFile(src, dst string) error {
 	data, err := os.ReadFile(src)
 	if err != nil {
@@ -0,0 +1,512 @@
+// SPDX-License-Identifier: Apache-2.0
+package signing
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/ProtonMail/gopenpgp/v3/crypto"
+	"github.com/spf13/viper"
+)
+
+func TestPreviewExpiry(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		expiry   string
+		lifetime time.Duration
+		wantErr  bool
+	}{
+		{name: "never", expiry: "0", lifetime: 0},
+		{name: "empty means never", expiry: "", lifetime: 0},
+		{name: "bare number is days", expiry: "10", lifetime: 10 * 24 * time.Hour},
+		{name: "days", expiry: "30d", lifetime: 30 * 24 * time.Hour},
+		{name: "weeks", expiry: "2w", lifetime: 14 * 24 * time.Hour},
+		{name: "months", expiry: "18m", lifetime: 540 * 24 * time.Hour},
+		{name: "years", expiry: "1y", lifetime: 365 * 24 * time.Hour},
+		{name: "invalid suffix", expiry: "5x", wantErr: true},
+		{name: "negative", expiry: "-1d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preview, err := PreviewExpiry(tt.expiry, from)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PreviewExpiry(%q) error = %v, wantErr %v", tt.expiry, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if preview.Lifetime != tt.lifetime {
+				t.Errorf("PreviewExpiry(%q) lifetime = %v, want %v", tt.expiry, preview.Lifetime, tt.lifetime)
+			}
+
+			if tt.lifetime == 0 {
+				if !preview.Expires.IsZero() {
+					t.Errorf("PreviewExpiry(%q) expires = %v, want zero", tt.expiry, preview.Expires)
+				}
+				return
+			}
+			if want := from.Add(tt.lifetime); !preview.Expires.Equal(want) {
+				t.Errorf("PreviewExpiry(%q) expires = %v, want %v", tt.expiry, preview.Expires, want)
+			}
+		})
+	}
+}
+
+func TestCheckExpiryPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		requireExpiry bool
+		expiry        string
+		wantErr       bool
+	}{
+		{name: "policy off allows never", requireExpiry: false, expiry: "0"},
+		{name: "policy on rejects never", requireExpiry: true, expiry: "0", wantErr: true},
+		{name: "policy on rejects empty", requireExpiry: true, expiry: "", wantErr: true},
+		{name: "policy on allows expiring key", requireExpiry: true, expiry: "1y"},
+		{name: "policy on allows key over max with warning", requireExpiry: true, expiry: "5y"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("signing.require-expiry", tt.requireExpiry)
+			viper.Set("signing.key.max-expiry", "2y")
+			t.Cleanup(viper.Reset)
+
+			lifetime, err := parseExpiry(tt.expiry)
+			if err != nil {
+				t.Fatalf("parseExpiry(%q) error = %v", tt.expiry, err)
+			}
+
+			err = checkExpiryPolicy(tt.expiry, lifetime)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkExpiryPolicy(%q) error = %v, wantErr %v", tt.expiry, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFindSumsFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "sha256 only", files: []string{"SHA256SUMS"}, want: []string{"SHA256SUMS"}},
+		{name: "sha512 only", files: []string{"SHA512SUMS"}, want: []string{"SHA512SUMS"}},
+		{name: "both", files: []string{"SHA512SUMS", "SHA256SUMS"}, want: []string{"SHA256SUMS", "SHA512SUMS"}},
+		{name: "none", files: []string{"vmlinux"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, f := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := findSumsFiles(dir)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("findSumsFiles() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("findSumsFiles() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("findSumsFiles() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if filepath.Base(got[i]) != tt.want[i] {
+					t.Errorf("findSumsFiles()[%d] = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		want      packet.PublicKeyAlgorithm
+	}{
+		{algorithm: AlgorithmRSA4096, want: packet.PubKeyAlgoRSA},
+		{algorithm: AlgorithmEd25519, want: packet.PubKeyAlgoEdDSA},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			dir := t.TempDir()
+			viper.Set("signing.key.location", filepath.Join(dir, "keys"))
+			viper.Set("signing.history.location", "history")
+			t.Cleanup(viper.Reset)
+
+			_, err := GenerateKey(GenerateKeyOptions{
+				Name:       "Test",
+				Email:      "test@example.com",
+				Expiry:     "1y",
+				Algorithm:  tt.algorithm,
+				SkipBackup: true,
+			})
+			if err != nil {
+				t.Fatalf("GenerateKey() error = %v", err)
+			}
+
+			publicKey, err := loadPublicKey()
+			if err != nil {
+				t.Fatalf("loadPublicKey() error = %v", err)
+			}
+			if got := publicKey.GetEntity().PrimaryKey.PubKeyAlgo; got != tt.want {
+				t.Errorf("key algorithm = %v, want %v", got, tt.want)
+			}
+
+			artifacts := filepath.Join(dir, "artifacts")
+			if err := os.MkdirAll(artifacts, 0755); err != nil {
+				t.Fatal(err)
+			}
+			sums := filepath.Join(artifacts, "SHA256SUMS")
+			if err := os.WriteFile(sums, []byte("abc123  vmlinux\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := SignArtifacts(artifacts, ""); err != nil {
+				t.Fatalf("SignArtifacts() error = %v", err)
+			}
+			if err := VerifyArtifacts(artifacts); err != nil {
+				t.Fatalf("VerifyArtifacts() error = %v", err)
+			}
+
+			if err := os.WriteFile(sums, []byte("def456  vmlinux\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyArtifacts(artifacts); err == nil {
+				t.Error("VerifyArtifacts() succeeded after the checksums file changed")
+			}
+		})
+	}
+}
+
+func TestGenerateKeyRejectsUnknownAlgorithm(t *testing.T) {
+	viper.Set("signing.key.location", filepath.Join(t.TempDir(), "keys"))
+	t.Cleanup(viper.Reset)
+
+	_, err := GenerateKey(GenerateKeyOptions{Name: "Test", Email: "test@example.com", Algorithm: "dsa"})
+	if err == nil {
+		t.Fatal("GenerateKey() with algorithm dsa succeeded, want error")
+	}
+}
+
+func TestSignVerifyFile(t *testing.T) {
+	dir := t.TempDir()
+	keysDir := filepath.Join(dir, "keys")
+	viper.Set("signing.key.location", keysDir)
+	viper.Set("signing.history.location", "history")
+	t.Cleanup(viper.Reset)
+
+	_, err := GenerateKey(GenerateKeyOptions{
+		Name:       "Test",
+		Email:      "test@example.com",
+		Expiry:     "1y",
+		Algorithm:  AlgorithmEd25519,
+		SkipBackup: true,
+	})
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	notes := filepath.Join(dir, "RELEASE-NOTES.md")
+	if err := os.WriteFile(notes, []byte("# 6.1.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SignFile(notes, KeyFormatArmored, ""); err != nil {
+		t.Fatalf("SignFile() error = %v", err)
+	}
+	if _, err := os.Stat(notes + ".asc"); err != nil {
+		t.Fatalf("signature not written: %v", err)
+	}
+
+	// Verify against an explicit public key, as a consumer would
+	pubKey := filepath.Join(keysDir, "signing-key.asc")
+	if err := VerifyFile(notes, "", pubKey); err != nil {
+		t.Fatalf("VerifyFile() error = %v", err)
+	}
+
+	// A detached signature can live anywhere
+	moved := filepath.Join(dir, "notes.sig")
+	if err := os.Rename(notes+".asc", moved); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyFile(notes, moved, pubKey); err != nil {
+		t.Fatalf("VerifyFile() with signature path error = %v", err)
+	}
+
+	if err := os.WriteFile(notes, []byte("# 6.1.1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyFile(notes, moved, pubKey); err == nil {
+		t.Error("VerifyFile() succeeded after the file changed")
+	}
+}
+
+func TestVerifyArtifactsWithKeys(t *testing.T) {
+	dir := t.TempDir()
+	keysDir := filepath.Join(dir, "keys")
+	viper.Set("signing.key.location", keysDir)
+	viper.Set("signing.history.location", "history")
+	t.Cleanup(viper.Reset)
+
+	opts := GenerateKeyOptions{
+		Name:       "Test",
+		Email:      "test@example.com",
+		Expiry:     "1y",
+		Algorithm:  AlgorithmEd25519,
+		SkipBackup: true,
+	}
+	if _, err := GenerateKey(opts); err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	oldKey := filepath.Join(dir, "old-key.asc")
+	if err := copyFile(filepath.Join(keysDir, "signing-key.asc"), oldKey); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RemoveKey(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GenerateKey(opts); err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	newKey := filepath.Join(keysDir, "signing-key.asc")
+
+	artifacts := filepath.Join(dir, "artifacts")
+	if err := os.MkdirAll(artifacts, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(artifacts, "SHA256SUMS"), []byte("abc123  vmlinux\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SignArtifacts(artifacts, ""); err != nil {
+		t.Fatalf("SignArtifacts() error = %v", err)
+	}
+
+	if err := VerifyArtifactsWithKeys(artifacts, []string{oldKey}); err == nil {
+		t.Error("VerifyArtifactsWithKeys() succeeded with only the old key")
+	}
+	if err := VerifyArtifactsWithKeys(artifacts, []string{oldKey, newKey}); err != nil {
+		t.Errorf("VerifyArtifactsWithKeys() with old and new key error = %v", err)
+	}
+
+	// Every key's failure is reported when none matches
+	missing := filepath.Join(dir, "missing.asc")
+	err := VerifyArtifactsWithKeys(artifacts, []string{oldKey, missing})
+	if err == nil {
+		t.Fatal("VerifyArtifactsWithKeys() succeeded with no matching key")
+	}
+	for _, key := range []string{oldKey, missing} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("error %q does not mention %s", err, key)
+		}
+	}
+
+	history, err := HistoryKeyPaths()
+	if err != nil {
+		t.Fatalf("HistoryKeyPaths() error = %v", err)
+	}
+	if len(history) == 0 {
+		t.Fatal("HistoryKeyPaths() returned no keys")
+	}
+	if err := VerifyArtifactsWithKeys(artifacts, history); err != nil {
+		t.Errorf("VerifyArtifactsWithKeys() with history keys error = %v", err)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	keysDir := filepath.Join(t.TempDir(), "keys")
+	viper.Set("signing.key.location", keysDir)
+	viper.Set("signing.history.location", "history")
+	t.Cleanup(viper.Reset)
+
+	_, err := GenerateKey(GenerateKeyOptions{
+		Name:       "Test",
+		Email:      "test@example.com",
+		Expiry:     "1y",
+		Algorithm:  AlgorithmEd25519,
+		SkipBackup: true,
+	})
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	// An unencrypted key gets encrypted
+	if err := ChangePassword("", "first-password"); err != nil {
+		t.Fatalf("ChangePassword() on unencrypted key error = %v", err)
+	}
+	if encrypted, err := PrivateKeyEncrypted(); err != nil || !encrypted {
+		t.Fatalf("PrivateKeyEncrypted() = %v, %v; want true", encrypted, err)
+	}
+
+	privateKeyPath := filepath.Join(keysDir, "signing-key-private.asc")
+	before, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ChangePassword("wrong-password", "second-password"); err == nil {
+		t.Fatal("ChangePassword() with wrong password succeeded")
+	}
+	after, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(before) != string(after) {
+		t.Error("ChangePassword() with wrong password modified the key file")
+	}
+
+	if err := ChangePassword("first-password", "second-password"); err != nil {
+		t.Fatalf("ChangePassword() error = %v", err)
+	}
+	if _, err := loadPrivateKey("first-password"); err == nil {
+		t.Error("old password still unlocks the key")
+	}
+	if _, err := loadPrivateKey("second-password"); err != nil {
+		t.Errorf("new password does not unlock the key: %v", err)
+	}
+
+	info, err := os.Stat(privateKeyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("key file permissions = %o, want 600", perm)
+	}
+}
+
+func TestExportPublicKey(t *testing.T) {
+	viper.Set("signing.key.location", filepath.Join(t.TempDir(), "keys"))
+	viper.Set("signing.history.location", "history")
+	t.Cleanup(viper.Reset)
+
+	info, err := GenerateKey(GenerateKeyOptions{
+		Name:       "Test",
+		Email:      "test@example.com",
+		Algorithm:  AlgorithmEd25519,
+		SkipBackup: true,
+	})
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	for _, format := range []KeyFormat{KeyFormatArmored, KeyFormatBinary} {
+		var buf bytes.Buffer
+		if err := ExportPublicKey(&buf, format); err != nil {
+			t.Fatalf("ExportPublicKey(%v) error = %v", format, err)
+		}
+
+		var key *crypto.Key
+		if format == KeyFormatArmored {
+			key, err = crypto.NewKeyFromArmored(buf.String())
+		} else {
+			key, err = crypto.NewKey(buf.Bytes())
+		}
+		if err != nil {
+			t.Fatalf("exported key (format %v) does not parse: %v", format, err)
+		}
+		if key.IsPrivate() {
+			t.Errorf("exported key (format %v) contains private material", format)
+		}
+		if got := strings.ToUpper(key.GetFingerprint()); got != info.Fingerprint {
+			t.Errorf("exported fingerprint = %s, want %s", got, info.Fingerprint)
+		}
+	}
+}
+
+func TestListHistory(t *testing.T) {
+	viper.Set("signing.key.location", filepath.Join(t.TempDir(), "keys"))
+	viper.Set("signing.history.location", "history")
+	t.Cleanup(viper.Reset)
+
+	if history, err := ListHistory(); err != nil || len(history) != 0 {
+		t.Fatalf("ListHistory() with no keys = %v, %v; want empty", history, err)
+	}
+
+	info, err := GenerateKey(GenerateKeyOptions{
+		Name:       "Test",
+		Email:      "test@example.com",
+		Expiry:     "30d",
+		Algorithm:  AlgorithmEd25519,
+		SkipBackup: true,
+	})
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	history, err := ListHistory()
+	if err != nil {
+		t.Fatalf("ListHistory() error = %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("ListHistory() returned %d keys, want 1", len(history))
+	}
+
+	got := history[0]
+	if got.Fingerprint != info.Fingerprint {
+		t.Errorf("Fingerprint = %s, want %s", got.Fingerprint, info.Fingerprint)
+	}
+	if got.Path == "" {
+		t.Error("Path not set")
+	}
+	if want := got.Created.Add(30 * 24 * time.Hour); !got.Expires.Equal(want) {
+		t.Errorf("Expires = %v, want %v", got.Expires, want)
+	}
+}
+
+func TestRotateIfExpiring(t *testing.T) {
+	viper.Set("signing.key.location", filepath.Join(t.TempDir(), "keys"))
+	viper.Set("signing.history.location", "history")
+	t.Cleanup(viper.Reset)
+
+	opts := GenerateKeyOptions{
+		Name:       "Test",
+		Email:      "test@example.com",
+		Expiry:     "30d",
+		Algorithm:  AlgorithmEd25519,
+		SkipBackup: true,
+	}
+	original, err := GenerateKey(opts)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	_, rotated, err := RotateIfExpiring(opts, 10)
+	if err != nil {
+		t.Fatalf("RotateIfExpiring(10) error = %v", err)
+	}
+	if rotated {
+		t.Fatal("RotateIfExpiring(10) rotated a key valid for 30 days")
+	}
+
+	info, rotated, err := RotateIfExpiring(opts, 60)
+	if err != nil {
+		t.Fatalf("RotateIfExpiring(60) error = %v", err)
+	}
+	if !rotated {
+		t.Fatal("RotateIfExpiring(60) did not rotate a key expiring in 30 days")
+	}
+	if info.Fingerprint == original.Fingerprint {
+		t.Error("rotated key has the original fingerprint")
+	}
+}
@@ -4,10 +4,10 @@ package ui
 import (
 	"bufio"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,6 +19,7 @@ import (
 	"charm.land/lipgloss/v2"
 	"github.com/Work-Fort/Anvil/pkg/config"
 	"github.com/Work-Fort/Anvil/pkg/kernel"
+	"github.com/Work-Fort/Anvil/pkg/util"
 	"github.com/charmbracelet/log"
 )
 
@@ -41,18 +42,19 @@ const (
 type BuildKernelCallbacks struct {
 	// BuildFn starts a kernel build with the given options.
 	BuildFn func(opts kernel.BuildOptions) error
-	// CheckCachedFn checks for a cached build. Returns (hasCached, statsFile, error).
+	// CheckCachedFn checks for a cached build of a version. Returns (hasCached, statsFile, error).
 	CheckCachedFn func(version string) (bool, string, error)
+	// ListCachedFn lists the cached builds for the wizard's architecture, newest first.
+	ListCachedFn func() ([]kernel.BuildStats, error)
 	// ReadStatsFn reads build statistics from a stats file.
 	ReadStatsFn func(path string) (kernel.BuildStats, error)
 	// CheckInstalledFn checks if a build is already installed. Returns (isInstalled, version, error).
 	CheckInstalledFn func(stats kernel.BuildStats) (bool, string, error)
-	// InstallFn installs a built kernel. Returns (installedVersion, error).
-	InstallFn func(stats kernel.BuildStats, setAsDefault bool) (string, error)
-	// ArchiveFn archives an installed kernel to the given directory.
-	ArchiveFn func(stats kernel.BuildStats, archiveDir string) error
-	// ClearBuildCacheFn clears the build cache directories.
-	ClearBuildCacheFn func() error
+	// InstallFn installs a built kernel, reporting copy progress (0.0 to 1.0).
+	// Returns (installedVersion, error).
+	InstallFn func(stats kernel.BuildStats, setAsDefault bool, progressCallback func(float64)) (string, error)
+	// ArchiveFn archives an installed kernel to the given directory, reporting copy progress.
+	ArchiveFn func(stats kernel.BuildStats, archiveDir string, progressCallback func(float64)) error
 	// GetArchiveLocationFn returns the archive directory, or "" if not configured.
 	GetArchiveLocationFn func() string
 }
@@ -70,9 +72,16 @@ type BuildKernelWizard struct {
 	currentBuildPhase BuildKernelPhase // Which phase the build is actually on
 
 	// Version selection (Phase 0)
-	versions        []list.Item
-	versionList     list.Model
-	selectedVersion string
+	allVersions        []string             // Every listed version, newest first
+	longterm           map[string]bool      // Series kernel.org marks as longterm
+	cachedAt           map[string]time.Time // Build time of each cached version
+	versionFilter      versionFilter
+	versionList        list.Model
+	selectedVersion    string
+	preselectedVersion string          // Given on the command line; built without showing the list
+	fetchErr           error           // kernel.org timed out; shown with a retry hint
+	fetchCtx           context.Context // Cancelled when the user quits, aborting the fetch
+	fetchCancel        context.CancelFunc
 
 	// Build options
 	arch              string
@@ -103,11 +112,15 @@ type BuildKernelWizard struct {
 	kernelSetAsDefault bool
 	installingKernel   bool
 	installError       error
+	installStage       string  // "Installing" or "Archiving" while the copy runs
+	installPercent     float64 // Copy progress for the current stage
+	installProgressBar progress.Model
+	installProgressCh  chan InstallProgressMsg
+	installDoneCh      chan InstallKernelMsg
 
 	// UI state
 	quitting           bool
 	err                error
-	confirmingNewBuild bool
 	confirmingInstall  bool
 	confirmForm        *ConfirmationForm
 	loadingCachedBuild bool
@@ -127,22 +140,30 @@ type BuildKernelWizard struct {
 type versionItem struct {
 	version     string
 	isLatest    bool
+	isCached    bool
 	description string
 }
 
 func (v versionItem) FilterValue() string { return v.version }
 func (v versionItem) Title() string {
+	title := v.version
 	if v.isLatest {
-		return v.version + " (latest)"
+		title += " (latest)"
 	}
-	return v.version
+	if v.isCached {
+		title += " (cached)"
+	}
+	return title
 }
 func (v versionItem) Description() string { return v.description }
 
-// FetchVersionsMsg contains available kernel versions
+// FetchVersionsMsg contains available kernel versions and the cached builds
 type FetchVersionsMsg struct {
 	Versions []string
+	Longterm []string // Longterm series, e.g. "6.12"
+	Cached   []kernel.BuildStats
 	Error    error
+	TimedOut bool // Error is a *kernel.APITimeoutError, which can be retried
 }
 
 // VersionSelectedMsg indicates user selected a version
@@ -183,6 +204,39 @@ type BuildStats struct {
 	OutputPath        string
 	CompressedPath    string
 	BuildTimestamp    time.Time // Timestamp when build completed
+	VerificationLevel string
+	ModulesPath       string
+	ModulesSize       int64
+	ModulesHash       string
+
+	// Build is the stats as the build recorded them, passed on unchanged
+	// when the kernel is installed or archived
+	Build kernel.BuildStats
+}
+
+// newBuildStats returns the UI stats for a build's stats
+func newBuildStats(stats kernel.BuildStats) BuildStats {
+	return BuildStats{
+		TotalDuration:     stats.TotalDuration,
+		DownloadDuration:  stats.DownloadDuration,
+		ExtractDuration:   stats.ExtractDuration,
+		ConfigureDuration: stats.ConfigureDuration,
+		CompileDuration:   stats.CompileDuration,
+		PackageDuration:   stats.PackageDuration,
+		UncompressedSize:  stats.UncompressedSize,
+		CompressedSize:    stats.CompressedSize,
+		UncompressedHash:  stats.UncompressedHash,
+		CompressedHash:    stats.CompressedHash,
+		KernelVersion:     stats.KernelVersion,
+		OutputPath:        stats.OutputPath,
+		CompressedPath:    stats.CompressedPath,
+		BuildTimestamp:    stats.BuildTimestamp,
+		VerificationLevel: stats.VerificationLevel,
+		ModulesPath:       stats.ModulesPath,
+		ModulesSize:       stats.ModulesSize,
+		ModulesHash:       stats.ModulesHash,
+		Build:             stats,
+	}
 }
 
 // DownloadProgressMsg contains download progress updates
@@ -198,19 +252,27 @@ type InstallKernelMsg struct {
 	Error            error
 }
 
+// InstallProgressMsg reports copy progress while installing or archiving a kernel
+type InstallProgressMsg struct {
+	Stage   string
+	Percent float64
+}
+
 // CachedBuildLoadedMsg signals a cached build was loaded
 type CachedBuildLoadedMsg struct {
-	Stats kernel.BuildStats
-	Error error
+	Stats       kernel.BuildStats
+	Output      []string                       // Saved build log, if any
+	PhaseOutput map[kernel.BuildPhase][]string // Saved build log split by phase
+	Error       error
 }
 
-// NewBuildStartedMsg signals the cache was cleared and a new build can start
-type NewBuildStartedMsg struct {
-	Error error
-}
+// NewBuildStartedMsg signals the wizard should return to version selection
+type NewBuildStartedMsg struct{}
 
-// NewBuildKernelWizard creates a new kernel build wizard with tabs
-func NewBuildKernelWizard(theme config.Theme, callbacks BuildKernelCallbacks, arch, verificationLevel, configFile string, forceRebuild bool) *BuildKernelWizard {
+// NewBuildKernelWizard creates a new kernel build wizard with tabs. With a
+// version, the wizard skips version selection and builds it straight away
+// (or shows its cached build).
+func NewBuildKernelWizard(theme config.Theme, callbacks BuildKernelCallbacks, version, arch, verificationLevel, configFile string, forceRebuild bool) *BuildKernelWizard {
 
 	// Create spinners for each tab
 	spinners := make([]spinner.Model, 8)
@@ -242,6 +304,8 @@ func NewBuildKernelWizard(theme config.Theme, callbacks BuildKernelCallbacks, ar
 	// Create viewport for scrollable build output
 	vp := viewport.New()
 
+	fetchCtx, fetchCancel := context.WithCancel(context.Background())
+
 	return &BuildKernelWizard{
 		theme:     theme,
 		callbacks: callbacks,
@@ -255,84 +319,113 @@ func NewBuildKernelWizard(theme config.Theme, callbacks BuildKernelCallbacks, ar
 			{Title: "Package", State: TabPending, Spinner: spinners[6]},
 			{Title: "Complete", State: TabPending, Spinner: spinners[7]},
 		},
-		activePhase:       PhaseSelectVersion,
-		currentBuildPhase: PhaseSelectVersion,
-		versionList:       l,
-		arch:              arch,
-		verificationLevel: verificationLevel,
-		configFile:        configFile,
-		buildOutput:       []string{},
-		phaseOutput:       make(map[BuildKernelPhase][]string),
-		progressBar:       prog,
-		viewport:          vp,
-		forceRebuild:      forceRebuild,
+		activePhase:        PhaseSelectVersion,
+		currentBuildPhase:  PhaseSelectVersion,
+		versionList:        l,
+		preselectedVersion: version,
+		fetchCtx:           fetchCtx,
+		fetchCancel:        fetchCancel,
+		arch:               arch,
+		verificationLevel:  verificationLevel,
+		configFile:         configFile,
+		buildOutput:        []string{},
+		phaseOutput:        make(map[BuildKernelPhase][]string),
+		progressBar:        prog,
+		installProgressBar: progress.New(progress.WithColors(theme.Primary, theme.Secondary)),
+		viewport:           vp,
+		forceRebuild:       forceRebuild,
 	}
 }
 
 // Init initializes the wizard
 func (m *BuildKernelWizard) Init() tea.Cmd {
-	// Skip cached build if force rebuild is requested
-	if !m.forceRebuild {
-		// Check if a cached build exists
-		hasCached, statsFile, err := m.callbacks.CheckCachedFn("")
+	// Start all spinners and fetch versions, or go straight to the
+	// preselected version
+	cmds := make([]tea.Cmd, len(m.tabs)+1)
+	for i := range m.tabs {
+		cmds[i] = m.tabs[i].Spinner.Tick
+	}
+	if m.preselectedVersion != "" {
+		cmds[len(m.tabs)] = m.startPreselectedBuild()
+	} else {
+		cmds[len(m.tabs)] = m.fetchKernelVersions
+	}
+	return tea.Batch(cmds...)
+}
+
+// startPreselectedBuild shows the cached build of the preselected version,
+// unless a rebuild is forced, and otherwise starts building it
+func (m *BuildKernelWizard) startPreselectedBuild() tea.Cmd {
+	if !m.forceRebuild && m.callbacks.CheckCachedFn != nil {
+		hasCached, statsFile, err := m.callbacks.CheckCachedFn(m.preselectedVersion)
 		if err != nil {
 			log.Debugf("Error checking cached build: %v", err)
 		}
-
 		if hasCached && statsFile != "" {
-			// Load cached build stats - skip version selection
-			log.Debugf("Found cached build, loading stats from: %s", statsFile)
+			log.Debugf("Version %s has a cached build, loading stats from: %s", m.preselectedVersion, statsFile)
 			m.loadingCachedBuild = true
 			return m.loadCachedBuild(statsFile)
 		}
-	} else {
-		log.Debugf("Force rebuild requested, skipping cached build check")
-	}
-
-	// Start all spinners and fetch versions
-	cmds := make([]tea.Cmd, len(m.tabs)+1)
-	for i := range m.tabs {
-		cmds[i] = m.tabs[i].Spinner.Tick
 	}
-	cmds[len(m.tabs)] = fetchKernelVersions
-	return tea.Batch(cmds...)
+	return m.beginBuild(m.preselectedVersion)
 }
 
-// fetchKernelVersions fetches available kernel versions
-func fetchKernelVersions() tea.Msg {
-	versions, err := getKernelVersions()
-	if err != nil {
-		return FetchVersionsMsg{Error: err}
-	}
-	return FetchVersionsMsg{Versions: versions}
+// beginBuild moves from version selection to the download phase and
+// starts building version
+func (m *BuildKernelWizard) beginBuild(version string) tea.Cmd {
+	m.selectedVersion = version
+	m.buildStarted = true
+
+	// Transition to download phase
+	m.tabs[PhaseSelectVersion].State = TabComplete
+	m.tabs[PhaseDownload].State = TabActive
+	m.activePhase = PhaseDownload
+	m.currentBuildPhase = PhaseDownload
+
+	log.Debugf("Version selected: %s, starting build", m.selectedVersion)
+
+	// Start build process
+	return m.startBuild()
 }
 
-// getKernelVersions fetches kernel versions from kernel.org
-func getKernelVersions() ([]string, error) {
-	resp, err := http.Get("https://www.kernel.org/releases.json")
+// fetchKernelVersions fetches available kernel versions and lists the
+// cached builds (skipped when a rebuild is forced)
+func (m *BuildKernelWizard) fetchKernelVersions() tea.Msg {
+	versions, longterm, err := getKernelVersions(m.fetchCtx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch kernel.org API: %w", err)
+		var timeoutErr *kernel.APITimeoutError
+		return FetchVersionsMsg{Error: err, TimedOut: errors.As(err, &timeoutErr)}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("kernel.org API returned status: %s", resp.Status)
+	var cached []kernel.BuildStats
+	if m.forceRebuild {
+		log.Debugf("Force rebuild requested, skipping cached build check")
+	} else if m.callbacks.ListCachedFn != nil {
+		cached, err = m.callbacks.ListCachedFn()
+		if err != nil {
+			log.Debugf("Error listing cached builds: %v", err)
+		}
 	}
+	return FetchVersionsMsg{Versions: versions, Longterm: longterm, Cached: cached}
+}
 
-	// Parse the full releases structure
+// getKernelVersions fetches kernel versions from kernel.org, retrying
+// transient failures (kernels.download.retries) within kernels.api-timeout.
+// It also returns the series of the versions kernel.org lists as longterm.
+func getKernelVersions(ctx context.Context) ([]string, []string, error) {
 	var data map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, fmt.Errorf("failed to parse kernel.org API response: %w", err)
+	if err := kernel.FetchReleasesJSON(ctx, &data); err != nil {
+		return nil, nil, err
 	}
 
 	// Extract releases array
 	releases, ok := data["releases"].([]interface{})
 	if !ok {
-		return nil, fmt.Errorf("unexpected API structure")
+		return nil, nil, fmt.Errorf("unexpected API structure")
 	}
 
 	// Extract version strings
-	var versions []string
+	var versions, longterm []string
 	for _, release := range releases {
 		releaseMap, ok := release.(map[string]interface{})
 		if !ok {
@@ -342,15 +435,18 @@ func getKernelVersions() ([]string, error) {
 			// Filter out "next-" versions for cleaner list
 			if !strings.HasPrefix(ver, "next-") {
 				versions = append(versions, ver)
+				if moniker, _ := releaseMap["moniker"].(string); moniker == "longterm" {
+					longterm = append(longterm, util.KernelSeries(ver))
+				}
 			}
 		}
 	}
 
 	if len(versions) == 0 {
-		return nil, fmt.Errorf("no versions found")
+		return nil, nil, fmt.Errorf("no versions found")
 	}
 
-	return versions, nil
+	return versions, longterm, nil
 }
 
 // Update handles messages
@@ -413,6 +509,14 @@ func (m *BuildKernelWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyPressMsg:
+		// While the list's filter input is open, keys are typed into it
+		if m.activePhase == PhaseSelectVersion && !m.buildStarted &&
+			m.versionList.FilterState() == list.Filtering && msg.String() != "ctrl+c" {
+			var cmd tea.Cmd
+			m.versionList, cmd = m.versionList.Update(msg)
+			return m, cmd
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q", "esc":
 			// If confirming install, cancel the confirmation
@@ -421,15 +525,9 @@ func (m *BuildKernelWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.confirmingInstall = false
 				return m, nil
 			}
-			// If confirming new build, cancel the confirmation
-			if m.confirmingNewBuild {
-				log.Debugf("User cancelled new build confirmation")
-				m.confirmingNewBuild = false
-				return m, nil
-			}
-
 			m.quitting = true
 			log.Debugf("User quit during phase=%d, buildStarted=%v", m.activePhase, m.buildStarted)
+			m.fetchCancel()
 
 			// If on completion screen, just quit
 			if m.activePhase == PhaseComplete {
@@ -461,27 +559,30 @@ func (m *BuildKernelWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "n", "N":
-			// Handle N key on completion screen (only for new build confirmation)
-			if m.activePhase == PhaseComplete {
-				// If confirming new build, N means cancel
-				if m.confirmingNewBuild {
-					log.Debugf("User cancelled new build")
-					m.confirmingNewBuild = false
-					return m, nil
-				}
-				// Otherwise, ask for new build confirmation
-				log.Debugf("User requested new build, asking for confirmation")
-				m.confirmingNewBuild = true
-				return m, nil
+		case "r", "R":
+			// Retry fetching versions after kernel.org timed out
+			if m.activePhase == PhaseSelectVersion && m.fetchErr != nil {
+				log.Debugf("Retrying kernel version fetch")
+				m.fetchErr = nil
+				return m, m.fetchKernelVersions
+			}
+			return m, nil
+
+		case "f", "F":
+			// Cycle which versions are listed (all, latest per series, longterm)
+			if m.activePhase == PhaseSelectVersion && !m.buildStarted {
+				m.versionFilter = m.versionFilter.next()
+				log.Debugf("Version filter: %s", m.versionFilter)
+				m.versionList.ResetSelected()
+				return m, m.versionList.SetItems(m.versionItems())
 			}
 			return m, nil
 
-		case "y", "Y":
-			// Handle Y key on completion screen (only for new build confirmation)
-			if m.activePhase == PhaseComplete && m.confirmingNewBuild {
-				log.Debugf("User confirmed new build, clearing cache")
-				m.confirmingNewBuild = false
+		case "n", "N":
+			// Handle N key on completion screen: back to version selection.
+			// Cached builds are kept, so nothing needs confirming.
+			if m.activePhase == PhaseComplete && !m.installingKernel {
+				log.Debugf("User requested new build")
 				return m, m.startNewBuild()
 			}
 			return m, nil
@@ -492,19 +593,20 @@ func (m *BuildKernelWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				selected := m.versionList.SelectedItem()
 				if selected != nil {
 					if vItem, ok := selected.(versionItem); ok {
-						m.selectedVersion = vItem.version
-						m.buildStarted = true
-
-						// Transition to download phase
-						m.tabs[PhaseSelectVersion].State = TabComplete
-						m.tabs[PhaseDownload].State = TabActive
-						m.activePhase = PhaseDownload
-						m.currentBuildPhase = PhaseDownload
-
-						log.Debugf("Version selected: %s, starting build", m.selectedVersion)
-
-						// Start build process
-						return m, m.startBuild()
+						// Show a cached build of the version instead of rebuilding it
+						if vItem.isCached {
+							hasCached, statsFile, err := m.callbacks.CheckCachedFn(vItem.version)
+							if err != nil {
+								log.Debugf("Error checking cached build: %v", err)
+							}
+							if hasCached && statsFile != "" {
+								log.Debugf("Version %s has a cached build, loading stats from: %s", vItem.version, statsFile)
+								m.loadingCachedBuild = true
+								return m, m.loadCachedBuild(statsFile)
+							}
+						}
+
+						return m, m.beginBuild(vItem.version)
 					}
 				}
 			}
@@ -591,22 +693,38 @@ func (m *BuildKernelWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case FetchVersionsMsg:
+		if msg.TimedOut {
+			m.fetchErr = msg.Error
+			return m, nil
+		}
 		if msg.Error != nil {
 			m.err = msg.Error
 			return m, tea.Quit
 		}
 
-		// Convert versions to list items
-		items := make([]list.Item, len(msg.Versions))
-		for i, v := range msg.Versions {
-			items[i] = versionItem{
-				version:     v,
-				isLatest:    i == 0,
-				description: fmt.Sprintf("Kernel version %s", v),
+		// Cached builds are marked in the list; versions kernel.org no longer
+		// lists are added, and everything is sorted newest first
+		cachedAt := make(map[string]time.Time, len(msg.Cached))
+		for _, stats := range msg.Cached {
+			if _, ok := cachedAt[stats.KernelVersion]; !ok {
+				cachedAt[stats.KernelVersion] = stats.BuildTimestamp
+			}
+		}
+		versions := slices.Clone(msg.Versions)
+		for _, stats := range msg.Cached {
+			if !slices.Contains(versions, stats.KernelVersion) {
+				versions = append(versions, stats.KernelVersion)
 			}
 		}
-		m.versions = items
-		return m, m.versionList.SetItems(items)
+		util.SortKernelVersions(versions)
+
+		m.allVersions = versions
+		m.cachedAt = cachedAt
+		m.longterm = make(map[string]bool, len(msg.Longterm))
+		for _, series := range msg.Longterm {
+			m.longterm[series] = true
+		}
+		return m, m.versionList.SetItems(m.versionItems())
 
 	case BuildStreamMsg:
 		// Build started, store channels and begin listening
@@ -707,23 +825,7 @@ func (m *BuildKernelWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.tabs[PhaseCompile].State = TabComplete
 		m.tabs[PhasePackage].State = TabComplete
 
-		// Convert kernel.BuildStats to UI BuildStats
-		m.buildStats = BuildStats{
-			TotalDuration:     msg.Stats.TotalDuration,
-			DownloadDuration:  msg.Stats.DownloadDuration,
-			ExtractDuration:   msg.Stats.ExtractDuration,
-			ConfigureDuration: msg.Stats.ConfigureDuration,
-			CompileDuration:   msg.Stats.CompileDuration,
-			PackageDuration:   msg.Stats.PackageDuration,
-			UncompressedSize:  msg.Stats.UncompressedSize,
-			CompressedSize:    msg.Stats.CompressedSize,
-			UncompressedHash:  msg.Stats.UncompressedHash,
-			CompressedHash:    msg.Stats.CompressedHash,
-			KernelVersion:     msg.Stats.KernelVersion,
-			OutputPath:        msg.Stats.OutputPath,
-			CompressedPath:    msg.Stats.CompressedPath,
-			BuildTimestamp:    msg.Stats.BuildTimestamp,
-		}
+		m.buildStats = newBuildStats(msg.Stats)
 
 		m.activePhase = PhaseComplete
 		m.currentBuildPhase = PhaseComplete
@@ -752,6 +854,11 @@ func (m *BuildKernelWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.progressBar, cmd = m.progressBar.Update(msg)
 		return m, cmd
 
+	case InstallProgressMsg:
+		m.installStage = msg.Stage
+		m.installPercent = msg.Percent
+		return m, waitForInstallProgress(m.installProgressCh, m.installDoneCh)
+
 	case InstallKernelMsg:
 		// Kernel installation complete
 		m.installingKernel = false
@@ -772,12 +879,16 @@ func (m *BuildKernelWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		if msg.Error != nil {
 			log.Debugf("Failed to load cached build: %v", msg.Error)
+			// Build the preselected version instead
+			if m.preselectedVersion != "" {
+				return m, m.beginBuild(m.preselectedVersion)
+			}
 			// Continue with normal flow - fetch versions
 			cmds := make([]tea.Cmd, len(m.tabs)+1)
 			for i := range m.tabs {
 				cmds[i] = m.tabs[i].Spinner.Tick
 			}
-			cmds[len(m.tabs)] = fetchKernelVersions
+			cmds[len(m.tabs)] = m.fetchKernelVersions
 			return m, tea.Batch(cmds...)
 		}
 
@@ -792,23 +903,7 @@ func (m *BuildKernelWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.tabs[PhaseCompile].State = TabComplete
 		m.tabs[PhasePackage].State = TabComplete
 
-		// Convert kernel.BuildStats to UI BuildStats
-		m.buildStats = BuildStats{
-			TotalDuration:     msg.Stats.TotalDuration,
-			DownloadDuration:  msg.Stats.DownloadDuration,
-			ExtractDuration:   msg.Stats.ExtractDuration,
-			ConfigureDuration: msg.Stats.ConfigureDuration,
-			CompileDuration:   msg.Stats.CompileDuration,
-			PackageDuration:   msg.Stats.PackageDuration,
-			UncompressedSize:  msg.Stats.UncompressedSize,
-			CompressedSize:    msg.Stats.CompressedSize,
-			UncompressedHash:  msg.Stats.UncompressedHash,
-			CompressedHash:    msg.Stats.CompressedHash,
-			KernelVersion:     msg.Stats.KernelVersion,
-			OutputPath:        msg.Stats.OutputPath,
-			CompressedPath:    msg.Stats.CompressedPath,
-			BuildTimestamp:    msg.Stats.BuildTimestamp,
-		}
+		m.buildStats = newBuildStats(msg.Stats)
 
 		// Set to completion screen
 		m.activePhase = PhaseComplete
@@ -818,6 +913,17 @@ func (m *BuildKernelWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.selectedVersion = msg.Stats.KernelVersion
 		m.isCachedBuild = true // Mark as cached build (no actual build ran)
 
+		// Show the saved output of the build in each phase's tab
+		m.buildOutput = msg.Output
+		m.phaseOutput = make(map[BuildKernelPhase][]string)
+		for phase, lines := range msg.PhaseOutput {
+			m.phaseOutput[BuildKernelPhase(phase+1)] = lines // +1 to skip PhaseSelectVersion
+		}
+		if m.viewportReady {
+			m.viewport.SetContent(strings.Join(m.buildOutput, "\n"))
+			m.viewport.GotoBottom()
+		}
+
 		// Check if this build is already installed
 		if isInstalled, installedVer, err := m.callbacks.CheckInstalledFn(msg.Stats); err == nil && isInstalled {
 			m.kernelInstalled = true
@@ -828,13 +934,6 @@ func (m *BuildKernelWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case NewBuildStartedMsg:
-		// Cache cleared, restart wizard
-		if msg.Error != nil {
-			log.Debugf("Failed to clear cache: %v", msg.Error)
-			m.err = msg.Error
-			return m, nil
-		}
-
 		log.Debugf("Starting new build, resetting wizard")
 
 		// Reset wizard state to initial
@@ -845,12 +944,14 @@ func (m *BuildKernelWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.phaseOutput = make(map[BuildKernelPhase][]string)
 		m.manualTabMode = false
 		m.selectedVersion = ""
+		m.preselectedVersion = "" // Pick the next build from the list
 		m.kernelInstalled = false
 		m.installedVersion = ""
 		m.installingKernel = false
 		m.installError = nil
 		m.progressPercent = 0
 		m.buildStats = BuildStats{}
+		m.isCachedBuild = false
 
 		// Reset all tab states
 		m.tabs[PhaseSelectVersion].State = TabActive
@@ -858,8 +959,8 @@ func (m *BuildKernelWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.tabs[i].State = TabPending
 		}
 
-		// Fetch versions again
-		return m, fetchKernelVersions
+		// Fetch versions and cached builds again
+		return m, m.fetchKernelVersions
 	}
 
 	// Update list if on version select phase
@@ -917,9 +1018,22 @@ func (m *BuildKernelWizard) startBuild() tea.Cmd {
 			// Create pipe for capturing build output
 			pr, pw := io.Pipe()
 
+			// Save the full output, so a failed build can be inspected
+			// after exiting and a cached build can show it again
+			var output io.Writer = pw
+			buildLog, err := kernel.CreateBuildLog(m.selectedVersion)
+			if err != nil {
+				log.Debugf("Not saving build log: %v", err)
+			} else {
+				output = io.MultiWriter(pw, buildLog)
+			}
+
 			// Run kernel build in another goroutine
 			go func() {
 				defer pw.Close()
+				if buildLog != nil {
+					defer buildLog.Close()
+				}
 
 				// Progress callback for downloads
 				progressCallback := func(percent float64) {
@@ -932,6 +1046,9 @@ func (m *BuildKernelWizard) startBuild() tea.Cmd {
 
 				// Phase callback for phase transitions
 				phaseCallback := func(phase kernel.BuildPhase) {
+					if buildLog != nil {
+						buildLog.StartPhase(phase)
+					}
 					select {
 					case phaseChan <- phase:
 					default:
@@ -954,7 +1071,7 @@ func (m *BuildKernelWizard) startBuild() tea.Cmd {
 					Arch:              m.arch,
 					VerificationLevel: m.verificationLevel,
 					ConfigFile:        m.configFile,
-					Writer:            pw,               // Stream output to pipe for TUI
+					Writer:            output,           // Stream output to pipe for TUI (and the build log)
 					ProgressCallback:  progressCallback, // Download progress callback
 					PhaseCallback:     phaseCallback,    // Phase transition callback
 					StatsCallback:     statsCallback,    // Build stats callback
@@ -966,7 +1083,7 @@ func (m *BuildKernelWizard) startBuild() tea.Cmd {
 				// Run actual kernel build - output will stream through pw
 				if err := m.callbacks.BuildFn(opts); err != nil {
 					// Write error to pipe so it gets captured
-					pw.Write([]byte(fmt.Sprintf("[ERROR] Build failed: %s\n", err.Error())))
+					output.Write([]byte(fmt.Sprintf("[ERROR] Build failed: %s\n", err.Error())))
 				}
 			}()
 
@@ -1118,11 +1235,9 @@ func (m *BuildKernelWizard) View() tea.View {
 	// Help footer using theme helper
 	var helpContent string
 	if m.activePhase == PhaseSelectVersion && !m.buildStarted {
-		helpContent = "[↑↓] Navigate  •  [/] Filter  •  [ENTER] Select  •  [ESC] Quit"
+		helpContent = fmt.Sprintf("[↑↓] Navigate  •  [/] Filter  •  [F] Show: %s  •  [ENTER] Select  •  [ESC] Quit", m.versionFilter)
 	} else if m.activePhase == PhaseComplete {
-		if m.confirmingNewBuild {
-			helpContent = "⚠ Clear build cache and start new? [Y] Yes  •  [N] No"
-		} else if m.kernelInstalled {
+		if m.kernelInstalled {
 			helpContent = "[N] Start New Build  •  [Q/ESC] Exit"
 		} else if m.installingKernel {
 			helpContent = "Installing kernel..."
@@ -1175,6 +1290,11 @@ func (m *BuildKernelWizard) getPhaseContent(phase BuildKernelPhase) string {
 			return theme.SuccessMessage("✓ Version selected: " + m.selectedVersion)
 		}
 
+		if m.fetchErr != nil {
+			return theme.ErrorIndicator() + " " + m.fetchErr.Error() + "\n\n" +
+				theme.WaitingIndicator() + " Press r to retry or q to quit"
+		}
+
 		// Show version list
 		return m.versionList.View()
 
@@ -1366,7 +1486,12 @@ func (m *BuildKernelWizard) renderBuildStats() string {
 
 	var installStatus string
 	if m.installingKernel {
-		installStatus = installStatusStyle.Render(theme.WaitingIndicator() + " Installing kernel...")
+		stage := m.installStage
+		if stage == "" {
+			stage = "Installing"
+		}
+		installStatus = installStatusStyle.Render(fmt.Sprintf("%s %s kernel... %.0f%%", theme.WaitingIndicator(), stage, m.installPercent*100)) +
+			"\n" + m.installProgressBar.ViewAs(m.installPercent)
 	} else if m.kernelInstalled {
 		if m.kernelSetAsDefault {
 			installStatus = theme.SuccessMessage(fmt.Sprintf("Kernel installed: %s (set as default)", m.installedVersion))
@@ -1393,8 +1518,6 @@ func (m *BuildKernelWizard) renderBuildStats() string {
 	var footer string
 	if m.confirmingInstall {
 		footer = warningStyle.Render(theme.WarningIndicator() + " Set as default kernel? [Y] Yes / [N] No (install only)")
-	} else if m.confirmingNewBuild {
-		footer = warningStyle.Render(theme.WarningIndicator() + " This will clear the build cache. Continue? [Y/N]")
 	} else if m.kernelInstalled {
 		footer = footerStyle.Render("Press N to start a new build, or ESC/Q to exit")
 	} else if m.installingKernel {
@@ -1423,80 +1546,113 @@ func (m *BuildKernelWizard) renderBuildStats() string {
 // ErrUserCancelled is returned when the user cancels the wizard
 var ErrUserCancelled = fmt.Errorf("cancelled by user")
 
-// loadCachedBuild loads a cached build from stats file
+// loadCachedBuild loads a cached build from stats file, along with its
+// saved build log when there is one
 func (m *BuildKernelWizard) loadCachedBuild(statsFile string) tea.Cmd {
 	return func() tea.Msg {
 		stats, err := m.callbacks.ReadStatsFn(statsFile)
 		if err != nil {
 			return CachedBuildLoadedMsg{Error: err}
 		}
-		return CachedBuildLoadedMsg{Stats: stats}
+		output, phaseOutput, err := kernel.ReadBuildLog(kernel.BuildLogPath(stats.KernelVersion))
+		if err != nil {
+			log.Debugf("No saved build log for %s: %v", stats.KernelVersion, err)
+		}
+		return CachedBuildLoadedMsg{Stats: stats, Output: output, PhaseOutput: phaseOutput}
 	}
 }
 
-// startNewBuild clears the build cache and restarts the wizard
+// startNewBuild restarts the wizard at version selection. Cached builds
+// are kept and listed there.
 func (m *BuildKernelWizard) startNewBuild() tea.Cmd {
 	return func() tea.Msg {
-		if err := m.callbacks.ClearBuildCacheFn(); err != nil {
-			return NewBuildStartedMsg{Error: err}
-		}
-		log.Debugf("Build cache cleared")
 		return NewBuildStartedMsg{}
 	}
 }
 
-// installKernel installs the built kernel to the kernels directory
+// installKernel installs the built kernel to the kernels directory.
+// Copy progress is streamed back as InstallProgressMsg until InstallKernelMsg.
 func (m *BuildKernelWizard) installKernel(setAsDefault bool) tea.Cmd {
-	return func() tea.Msg {
-		// Convert UI BuildStats to kernel.BuildStats
-		kernelStats := kernel.BuildStats{
-			TotalDuration:     m.buildStats.TotalDuration,
-			DownloadDuration:  m.buildStats.DownloadDuration,
-			ExtractDuration:   m.buildStats.ExtractDuration,
-			ConfigureDuration: m.buildStats.ConfigureDuration,
-			CompileDuration:   m.buildStats.CompileDuration,
-			PackageDuration:   m.buildStats.PackageDuration,
-			UncompressedSize:  m.buildStats.UncompressedSize,
-			CompressedSize:    m.buildStats.CompressedSize,
-			UncompressedHash:  m.buildStats.UncompressedHash,
-			CompressedHash:    m.buildStats.CompressedHash,
-			KernelVersion:     m.buildStats.KernelVersion,
-			OutputPath:        m.buildStats.OutputPath,
-			CompressedPath:    m.buildStats.CompressedPath,
-			BuildTimestamp:    m.buildStats.BuildTimestamp,
+	progressCh := make(chan InstallProgressMsg, 10)
+	doneCh := make(chan InstallKernelMsg, 1)
+	m.installProgressCh = progressCh
+	m.installDoneCh = doneCh
+	m.installStage = "Installing"
+	m.installPercent = 0
+
+	// Install what the build recorded, so the sidecars keep every field
+	kernelStats := m.buildStats.Build
+
+	// progressFor returns a non-blocking progress callback for a stage
+	progressFor := func(stage string) func(float64) {
+		return func(percent float64) {
+			select {
+			case progressCh <- InstallProgressMsg{Stage: stage, Percent: percent}:
+			default:
+				// Don't block if channel is full
+			}
 		}
+	}
+
+	go func() {
+		defer close(progressCh)
 
 		// Install kernel with timestamp
-		installedVersion, err := m.callbacks.InstallFn(kernelStats, setAsDefault)
+		installedVersion, err := m.callbacks.InstallFn(kernelStats, setAsDefault, progressFor("Installing"))
 		if err != nil {
-			return InstallKernelMsg{
+			doneCh <- InstallKernelMsg{
 				Success: false,
 				Error:   err,
 			}
+			return
 		}
 
 		// Archive to repo-local directory if configured
 		if archiveDir := m.callbacks.GetArchiveLocationFn(); archiveDir != "" {
-			if err := m.callbacks.ArchiveFn(kernelStats, archiveDir); err != nil {
-				return InstallKernelMsg{
+			if err := m.callbacks.ArchiveFn(kernelStats, archiveDir, progressFor("Archiving")); err != nil {
+				doneCh <- InstallKernelMsg{
 					Success: false,
 					Error:   fmt.Errorf("install succeeded but archiving failed: %w", err),
 				}
+				return
 			}
 		}
 
-		return InstallKernelMsg{
+		doneCh <- InstallKernelMsg{
 			Success:          true,
 			InstalledVersion: installedVersion,
 			SetAsDefault:     setAsDefault,
 		}
+	}()
+
+	return waitForInstallProgress(progressCh, doneCh)
+}
+
+// waitForInstallProgress waits for the next install progress update or completion
+func waitForInstallProgress(progressCh chan InstallProgressMsg, doneCh chan InstallKernelMsg) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case msg, ok := <-progressCh:
+			if !ok {
+				return <-doneCh
+			}
+			return msg
+		case msg := <-doneCh:
+			return msg
+		}
 	}
 }
 
 // RunBuildKernelWizard runs the kernel build wizard
-// This handles the ENTIRE build process: selection + build + progress
-func RunBuildKernelWizard(theme config.Theme, callbacks BuildKernelCallbacks, arch, verificationLevel, configFile string, forceRebuild bool) error {
-	m := NewBuildKernelWizard(theme, callbacks, arch, verificationLevel, configFile, forceRebuild)
+// This handles the ENTIRE build process: selection + build + progress.
+// A non-empty version skips the selection.
+func RunBuildKernelWizard(theme config.Theme, callbacks BuildKernelCallbacks, version, arch, verificationLevel, configFile string, forceRebuild bool) error {
+	if !HasTTY() {
+		return fmt.Errorf("kernel build wizard %w; pass --version", ErrNoTTY)
+	}
+
+	m := NewBuildKernelWizard(theme, callbacks, version, arch, verificationLevel, configFile, forceRebuild)
+	defer m.fetchCancel()
 	p := tea.NewProgram(m)
 
 	finalModel, err := p.Run()
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"github.com/Work-Fort/Anvil/pkg/kernel"
+)
+
+func TestBuildKernelWizardPreselectedVersion(t *testing.T) {
+	cached := map[string]string{"6.12.9": "/tmp/build-stats-6.12.9-x86_64.json"}
+	callbacks := BuildKernelCallbacks{
+		CheckCachedFn: func(v string) (bool, string, error) {
+			statsFile, ok := cached[v]
+			return ok, statsFile, nil
+		},
+	}
+
+	// A version without a cached build goes straight to the download
+	m := NewBuildKernelWizard(config.CurrentTheme, callbacks, "6.18.9", "x86_64", "", "", false)
+	defer m.fetchCancel()
+	m.Init()
+	if !m.buildStarted || m.selectedVersion != "6.18.9" || m.activePhase != PhaseDownload {
+		t.Errorf("wizard didn't start building 6.18.9: buildStarted=%v selectedVersion=%q activePhase=%d",
+			m.buildStarted, m.selectedVersion, m.activePhase)
+	}
+	if m.tabs[PhaseSelectVersion].State != TabComplete {
+		t.Error("version selection tab should be complete")
+	}
+
+	// A cached build is shown instead of rebuilt
+	m = NewBuildKernelWizard(config.CurrentTheme, callbacks, "6.12.9", "x86_64", "", "", false)
+	defer m.fetchCancel()
+	m.Init()
+	if m.buildStarted || !m.loadingCachedBuild {
+		t.Errorf("wizard should load the cached build of 6.12.9: buildStarted=%v loadingCachedBuild=%v",
+			m.buildStarted, m.loadingCachedBuild)
+	}
+
+	// unless a rebuild is forced
+	m = NewBuildKernelWizard(config.CurrentTheme, callbacks, "6.12.9", "x86_64", "", "", true)
+	defer m.fetchCancel()
+	m.Init()
+	if !m.buildStarted || m.loadingCachedBuild {
+		t.Errorf("forced rebuild of 6.12.9 didn't start: buildStarted=%v loadingCachedBuild=%v",
+			m.buildStarted, m.loadingCachedBuild)
+	}
+
+	// Without a version the wizard waits for a selection
+	m = NewBuildKernelWizard(config.CurrentTheme, callbacks, "", "x86_64", "", "", false)
+	defer m.fetchCancel()
+	m.Init()
+	if m.buildStarted || m.activePhase != PhaseSelectVersion {
+		t.Errorf("wizard without a version started a build: activePhase=%d", m.activePhase)
+	}
+}
+
+func TestBuildKernelWizardInstallKeepsBuildStats(t *testing.T) {
+	var installed, archived kernel.BuildStats
+	callbacks := BuildKernelCallbacks{
+		InstallFn: func(stats kernel.BuildStats, setAsDefault bool, progressCallback func(float64)) (string, error) {
+			installed = stats
+			return "6.18.9-20260105T100000", nil
+		},
+		GetArchiveLocationFn: func() string { return t.TempDir() },
+		ArchiveFn: func(stats kernel.BuildStats, archiveDir string, progressCallback func(float64)) error {
+			archived = stats
+			return nil
+		},
+	}
+	m := NewBuildKernelWizard(config.CurrentTheme, callbacks, "", "x86_64", "", "", false)
+	defer m.fetchCancel()
+
+	stats := kernel.BuildStats{
+		SchemaVersion: kernel.BuildStatsSchemaVersion,
+		KernelVersion: "6.18.9",
+		Arch:          "x86_64",
+		Patches:       []string{"0123abcd  /src/0001-fix.patch"},
+		OutputPath:    "/tmp/artifacts/vmlinux-6.18.9-x86_64",
+	}
+	m.Update(BuildCompleteMsg{Success: true, Stats: stats})
+
+	// Drain the install until it's done
+	cmd := m.installKernel(false)
+	for {
+		if done, ok := cmd().(InstallKernelMsg); ok {
+			if !done.Success {
+				t.Fatalf("install failed: %v", done.Error)
+			}
+			break
+		}
+	}
+
+	for name, got := range map[string]kernel.BuildStats{"installed": installed, "archived": archived} {
+		if !reflect.DeepEqual(got, stats) {
+			t.Errorf("%s stats = %+v, want %+v", name, got, stats)
+		}
+	}
+}
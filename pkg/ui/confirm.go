@@ -7,8 +7,28 @@ import (
 	"github.com/charmbracelet/huh"
 )
 
-// Confirm shows a yes/no confirmation dialog using huh
+// assumeYes makes Confirm answer yes without prompting (--assume-yes)
+var assumeYes bool
+
+// SetAssumeYes sets whether Confirm answers yes without prompting.
+// TypedConfirm is not affected.
+func SetAssumeYes(v bool) {
+	assumeYes = v
+}
+
+// AssumeYes reports whether standard confirmations are answered yes
+func AssumeYes() bool {
+	return assumeYes
+}
+
+// Confirm shows a yes/no confirmation dialog using huh. With --assume-yes
+// it prints the prompt and returns true without asking.
 func Confirm(prompt string) (bool, error) {
+	if assumeYes {
+		fmt.Printf("%s yes (--assume-yes)\n", prompt)
+		return true, nil
+	}
+
 	var confirmed bool
 
 	form := huh.NewForm(
@@ -27,7 +47,9 @@ func Confirm(prompt string) (bool, error) {
 	return confirmed, nil
 }
 
-// TypedConfirm shows a confirmation that requires typing a specific phrase
+// TypedConfirm shows a confirmation that requires typing a specific phrase.
+// It always prompts, even with --assume-yes; commands that use it skip it
+// only with their own --force flag.
 func TypedConfirm(prompt, expectedInput string) (bool, error) {
 	var input string
 
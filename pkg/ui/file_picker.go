@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+package ui
+
+import (
+	"github.com/charmbracelet/huh"
+)
+
+// PickFile shows a file picker rooted at dir and returns the selected file path
+func PickFile(title, description, dir string) (string, error) {
+	var path string
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewFilePicker().
+				Title(title).
+				Description(description).
+				CurrentDirectory(dir).
+				FileAllowed(true).
+				DirAllowed(false).
+				Picking(true).
+				Height(15).
+				Value(&path),
+		),
+	)
+
+	err := form.Run()
+	if err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
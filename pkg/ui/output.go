@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"charm.land/lipgloss/v2"
+	"github.com/Work-Fort/Anvil/pkg/config"
+	"golang.org/x/term"
+)
+
+// Output prints the results of non-interactive commands. The styled form
+// uses the theme's colors; the plain form prints the same text without ANSI
+// codes, for pipes, CI logs and --use-tui=false.
+type Output interface {
+	Info(msg string)
+	Success(msg string)
+	Warn(msg string)
+	Error(msg string)
+	// Itemize prints a bulleted list of items, e.g. removed files
+	Itemize(items ...string)
+	// Blank prints an empty line between sections
+	Blank()
+	// Println prints a line assembled with Render
+	Println(line string)
+	// Render applies style to text; plain output returns text unchanged
+	Render(style lipgloss.Style, text string) string
+}
+
+// NewOutput returns an Output on stdout, styled when stdout is a terminal
+// and use-tui is enabled
+func NewOutput(theme config.Theme) Output {
+	styled := term.IsTerminal(int(os.Stdout.Fd())) && config.GetUseTUI()
+	return NewWriterOutput(os.Stdout, theme, styled)
+}
+
+// NewWriterOutput returns an Output on w
+func NewWriterOutput(w io.Writer, theme config.Theme, styled bool) Output {
+	return &output{w: w, theme: theme, styled: styled}
+}
+
+type output struct {
+	w      io.Writer
+	theme  config.Theme
+	styled bool
+}
+
+func (o *output) Info(msg string) {
+	o.Println(o.Render(o.theme.InfoStyle(), "ℹ "+msg))
+}
+
+func (o *output) Success(msg string) {
+	o.Println(o.Render(o.theme.SuccessStyle(), "✓ "+msg))
+}
+
+func (o *output) Warn(msg string) {
+	o.Println(o.Render(o.theme.WarningStyle(), "⚠ "+msg))
+}
+
+func (o *output) Error(msg string) {
+	o.Println(o.Render(o.theme.ErrorStyle(), "✗ "+msg))
+}
+
+func (o *output) Itemize(items ...string) {
+	for _, item := range items {
+		o.Println(o.Render(o.theme.SubtleStyle(), "  • ") + o.Render(o.theme.ErrorStyle(), item))
+	}
+}
+
+func (o *output) Blank() {
+	fmt.Fprintln(o.w)
+}
+
+func (o *output) Println(line string) {
+	fmt.Fprintln(o.w, line)
+}
+
+func (o *output) Render(style lipgloss.Style, text string) string {
+	if !o.styled {
+		return text
+	}
+	return style.Render(text)
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Work-Fort/Anvil/pkg/config"
+)
+
+func TestOutput(t *testing.T) {
+	theme := config.CurrentTheme
+
+	var plain bytes.Buffer
+	out := NewWriterOutput(&plain, theme, false)
+	out.Success("Cache cleaned")
+	out.Blank()
+	out.Itemize("linux-6.18.9.tar.xz", "tmp")
+	out.Info("Nothing else")
+	out.Warn("Careful")
+	out.Error("Failed")
+
+	want := "✓ Cache cleaned\n\n  • linux-6.18.9.tar.xz\n  • tmp\nℹ Nothing else\n⚠ Careful\n✗ Failed\n"
+	if plain.String() != want {
+		t.Errorf("plain output = %q, want %q", plain.String(), want)
+	}
+
+	// The styled form prints exactly what the theme helpers render
+	var styled bytes.Buffer
+	out = NewWriterOutput(&styled, theme, true)
+	out.Success("Cache cleaned")
+	out.Itemize("tmp")
+	want = theme.SuccessMessage("Cache cleaned") + "\n" +
+		theme.SubtleStyle().Render("  • ") + theme.ErrorStyle().Render("tmp") + "\n"
+	if styled.String() != want {
+		t.Errorf("styled output = %q, want %q", styled.String(), want)
+	}
+	if strings.Contains(plain.String(), "\x1b[") {
+		t.Error("plain output should not contain ANSI escape codes")
+	}
+}
@@ -0,0 +1,19 @@
+// SPDX-License-Identifier: Apache-2.0
+package ui
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// ErrNoTTY is returned when a full-screen program is started without a terminal
+var ErrNoTTY = errors.New("requires a TTY")
+
+// HasTTY reports whether stdin and stdout are both terminals. The
+// bubbletea programs need both: without stdin they hang waiting for
+// keys, and without stdout their escape codes end up in pipes and logs.
+func HasTTY() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
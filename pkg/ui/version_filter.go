@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+package ui
+
+import (
+	"fmt"
+
+	"charm.land/bubbles/v2/list"
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+// versionFilter selects which kernel versions the build wizard lists
+type versionFilter int
+
+const (
+	filterAllVersions versionFilter = iota
+	filterLatestPatch               // Newest version of each major.minor series
+	filterLongterm                  // Versions of kernel.org's longterm series
+)
+
+func (f versionFilter) String() string {
+	switch f {
+	case filterLatestPatch:
+		return "latest per series"
+	case filterLongterm:
+		return "longterm only"
+	default:
+		return "all versions"
+	}
+}
+
+// next cycles all → latest per series → longterm → all
+func (f versionFilter) next() versionFilter {
+	return (f + 1) % 3
+}
+
+// filterVersions applies f to versions, which must be sorted newest first.
+// longterm holds the series ("6.12") kernel.org marks as longterm.
+func filterVersions(versions []string, longterm map[string]bool, f versionFilter) []string {
+	var filtered []string
+	seen := make(map[string]bool)
+	for _, v := range versions {
+		series := util.KernelSeries(v)
+		switch f {
+		case filterLatestPatch:
+			if seen[series] {
+				continue
+			}
+			seen[series] = true
+		case filterLongterm:
+			if !longterm[series] {
+				continue
+			}
+		}
+		filtered = append(filtered, v)
+	}
+	return filtered
+}
+
+// latestStableVersion returns the newest version that isn't a release
+// candidate, which is what building "latest" picks
+func latestStableVersion(versions []string) string {
+	for _, v := range versions {
+		if !util.IsPrereleaseVersion(v) {
+			return v
+		}
+	}
+	return ""
+}
+
+// versionItems builds the version list items for the current filter. The
+// latest marker comes from the full sorted list, so it doesn't move when
+// the filter hides versions.
+func (m *BuildKernelWizard) versionItems() []list.Item {
+	latest := latestStableVersion(m.allVersions)
+	versions := filterVersions(m.allVersions, m.longterm, m.versionFilter)
+
+	items := make([]list.Item, len(versions))
+	for i, v := range versions {
+		item := versionItem{
+			version:     v,
+			isLatest:    v == latest,
+			description: fmt.Sprintf("Kernel version %s", v),
+		}
+		if m.longterm[util.KernelSeries(v)] {
+			item.description = fmt.Sprintf("Kernel version %s (longterm)", v)
+		}
+		if builtAt, ok := m.cachedAt[v]; ok {
+			item.isCached = true
+			item.description = fmt.Sprintf("Cached build from %s", builtAt.Format("2006-01-02 15:04"))
+		}
+		items[i] = item
+	}
+	return items
+}
@@ -499,6 +499,10 @@ func (m VersionSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					case "DEL":
 						if item, ok := m.downloadedList.SelectedItem().(VersionItem); ok {
 							m.selectedVersion = item.version
+							if AssumeYes() {
+								m.currentState = stateDeleting
+								return m, m.performDelete()
+							}
 							m.confirmForm = NewConfirmationForm(
 								m.theme,
 								"confirm",
@@ -707,6 +711,10 @@ func RunVersionSelector(
 	reloadFn func() ([]string, []string, error),
 	getDefaultVerFn func() string,
 ) error {
+	if !HasTTY() {
+		return fmt.Errorf("interactive version selector %w; pass a version", ErrNoTTY)
+	}
+
 	model := NewVersionSelector(theme, target, downloaded, available, downloadFn, setDefaultFn, deleteFn, reloadFn, getDefaultVerFn)
 	p := tea.NewProgram(model)
 
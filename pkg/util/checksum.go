@@ -4,8 +4,10 @@ package util
 import (
 	"bufio"
 	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/hex"
 	"fmt"
+	"hash"
 	"io"
 	"os"
 	"path/filepath"
@@ -14,18 +16,59 @@ import (
 	"github.com/charmbracelet/log"
 )
 
+// Supported checksum algorithms
+const (
+	ChecksumSHA256 = "sha256"
+	ChecksumSHA512 = "sha512"
+)
+
+// ChecksumAlgorithms lists the supported checksum algorithms, default first
+var ChecksumAlgorithms = []string{ChecksumSHA256, ChecksumSHA512}
+
+// ValidateChecksumAlgorithm returns an error for unsupported algorithms
+func ValidateChecksumAlgorithm(algo string) error {
+	if _, err := newHash(algo); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SumsFileName returns the name of the combined checksums file for algo,
+// e.g. SHA256SUMS or SHA512SUMS
+func SumsFileName(algo string) string {
+	return strings.ToUpper(algo) + "SUMS"
+}
+
+// newHash returns a fresh hash for the named algorithm
+func newHash(algo string) (hash.Hash, error) {
+	switch strings.ToLower(algo) {
+	case ChecksumSHA256:
+		return sha256.New(), nil
+	case ChecksumSHA512:
+		return sha512.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported checksum algorithm: %s (supported: %s)", algo, strings.Join(ChecksumAlgorithms, ", "))
+	}
+}
+
 // VerifySHA256File verifies a file against a SHA256SUMS file
 func VerifySHA256File(filePath, checksumsPath string) error {
-	log.Debugf("Verifying SHA256 checksum for %s", filePath)
+	return VerifyChecksumFile(filePath, checksumsPath, ChecksumSHA256)
+}
+
+// VerifyChecksumFile verifies a file against a checksums file (SHA256SUMS,
+// SHA512SUMS, ...) using the given algorithm
+func VerifyChecksumFile(filePath, checksumsPath, algo string) error {
+	log.Debugf("Verifying %s checksum for %s", strings.ToUpper(algo), filePath)
 
 	// Calculate file hash
-	fileHash, err := CalculateSHA256(filePath)
+	fileHash, err := CalculateChecksum(filePath, algo)
 	if err != nil {
 		return fmt.Errorf("failed to calculate file hash: %w", err)
 	}
 
 	// Read checksums file
-	checksums, err := ParseSHA256SUMSFile(checksumsPath)
+	checksums, err := ParseChecksumsFile(checksumsPath)
 	if err != nil {
 		return fmt.Errorf("failed to read checksums file: %w", err)
 	}
@@ -50,22 +93,37 @@ func VerifySHA256File(filePath, checksumsPath string) error {
 
 // CalculateSHA256 calculates the SHA256 hash of a file
 func CalculateSHA256(filePath string) (string, error) {
+	return CalculateChecksum(filePath, ChecksumSHA256)
+}
+
+// CalculateChecksum calculates the hash of a file with the given algorithm
+func CalculateChecksum(filePath, algo string) (string, error) {
+	h, err := newHash(algo)
+	if err != nil {
+		return "", err
+	}
+
 	file, err := os.Open(filePath)
 	if err != nil {
 		return "", fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
-	hash := sha256.New()
-	if _, err := io.Copy(hash, file); err != nil {
+	if _, err := io.Copy(h, file); err != nil {
 		return "", fmt.Errorf("failed to calculate hash: %w", err)
 	}
 
-	return hex.EncodeToString(hash.Sum(nil)), nil
+	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
 // ParseSHA256SUMSFile parses a SHA256SUMS file and returns a map of filename -> hash
 func ParseSHA256SUMSFile(path string) (map[string]string, error) {
+	return ParseChecksumsFile(path)
+}
+
+// ParseChecksumsFile parses a sha256sum/sha512sum style checksums file and
+// returns a map of filename -> hash
+func ParseChecksumsFile(path string) (map[string]string, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open checksums file: %w", err)
@@ -81,7 +139,7 @@ func ParseSHA256SUMSFile(path string) (map[string]string, error) {
 			continue
 		}
 
-		// SHA256SUMS format: "hash  filename" or "hash *filename"
+		// Format: "hash  filename" or "hash *filename"
 		// Split on whitespace
 		parts := strings.Fields(line)
 		if len(parts) < 2 {
@@ -9,11 +9,100 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/ulikunitz/xz"
+	"github.com/ulikunitz/xz/lzma"
 )
 
+// xzWriterConfig pins every xz encoder setting. The xz format stores no
+// timestamps or file names, so with fixed settings identical input always
+// compresses to identical bytes and the compressed hash in build stats is
+// stable across runs (and across library defaults changing).
+var xzWriterConfig = xz.WriterConfig{
+	Properties: &lzma.Properties{LC: 3, LP: 0, PB: 2},
+	DictCap:    8 * 1024 * 1024,
+	BufSize:    4096,
+	BlockSize:  1<<63 - 1, // single block
+	CheckSum:   xz.CRC64,
+	Matcher:    lzma.HashTable4,
+}
+
+// Supported kernel compression formats
+const (
+	CompressionXZ   = "xz"
+	CompressionZstd = "zstd"
+)
+
+// CompressionFormats lists the supported compression formats, default first
+var CompressionFormats = []string{CompressionXZ, CompressionZstd}
+
+// ValidateCompression returns an error for unsupported compression formats
+func ValidateCompression(format string) error {
+	if CompressionExt(format) == "" {
+		return fmt.Errorf("unsupported compression: %s (supported: %s)", format, strings.Join(CompressionFormats, ", "))
+	}
+	return nil
+}
+
+// CompressionExt returns the file extension for a compression format
+// (".xz" or ".zst"), or "" for an unsupported format
+func CompressionExt(format string) string {
+	switch format {
+	case CompressionXZ:
+		return ".xz"
+	case CompressionZstd:
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+// CompressionFromPath returns the compression format of a file from its
+// extension, or "" when it isn't a supported compressed file
+func CompressionFromPath(path string) string {
+	for _, format := range CompressionFormats {
+		if strings.HasSuffix(path, CompressionExt(format)) {
+			return format
+		}
+	}
+	return ""
+}
+
+// TrimCompressionExt returns path without its compression extension
+func TrimCompressionExt(path string) string {
+	if format := CompressionFromPath(path); format != "" {
+		return strings.TrimSuffix(path, CompressionExt(format))
+	}
+	return path
+}
+
+// CompressWithProgress compresses src to dst in the given format
+func CompressWithProgress(format, src, dst string, progressCallback func(float64)) error {
+	switch format {
+	case CompressionXZ:
+		return CompressXZWithProgress(src, dst, progressCallback)
+	case CompressionZstd:
+		return CompressZstdWithProgress(src, dst, progressCallback)
+	default:
+		return ValidateCompression(format)
+	}
+}
+
+// DecompressWithProgress decompresses src to dst, picking the format from
+// src's extension
+func DecompressWithProgress(src, dst string, progressCallback func(float64)) error {
+	switch CompressionFromPath(src) {
+	case CompressionXZ:
+		return DecompressXZWithProgress(src, dst, progressCallback)
+	case CompressionZstd:
+		return DecompressZstdWithProgress(src, dst, progressCallback)
+	default:
+		return fmt.Errorf("unsupported compressed file: %s (expected %s or %s)", src, CompressionExt(CompressionXZ), CompressionExt(CompressionZstd))
+	}
+}
+
 // CompressXZ compresses a file using xz compression
 func CompressXZ(src, dst string) error {
 	return CompressXZWithProgress(src, dst, nil)
@@ -44,8 +133,8 @@ func CompressXZWithProgress(src, dst string, progressCallback func(float64)) err
 	}
 	defer dstFile.Close()
 
-	// Create xz writer with maximum compression
-	xzWriter, err := xz.NewWriter(dstFile)
+	// Create xz writer with deterministic settings
+	xzWriter, err := xzWriterConfig.NewWriter(dstFile)
 	if err != nil {
 		return fmt.Errorf("failed to create xz writer: %w", err)
 	}
@@ -418,3 +507,89 @@ func ExtractTarXzWithProgress(src, dstDir string, progressCallback func(float64)
 	log.Debugf("Successfully extracted archive to %s", dstDir)
 	return nil
 }
+
+// CreateTarXz writes the tree under srcDir to a tar.xz archive at dst, with
+// entry names prefixed by prefix (e.g. "lib/modules/6.18.9"). Entries are in
+// lexical order with owners cleared and timestamps zeroed, so the same tree
+// always produces the same archive. skip, when set, excludes entries by
+// their path relative to srcDir.
+func CreateTarXz(srcDir, prefix, dst string, skip func(rel string) bool) error {
+	log.Debugf("Archiving %s to %s", srcDir, dst)
+
+	dstFile, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer dstFile.Close()
+
+	xzWriter, err := xzWriterConfig.NewWriter(dstFile)
+	if err != nil {
+		return fmt.Errorf("failed to create xz writer: %w", err)
+	}
+	tarWriter := tar.NewWriter(xzWriter)
+
+	// filepath.Walk visits entries in lexical order
+	err = filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		if rel != "." && skip != nil && skip(rel) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(prefix, rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		header.Uid, header.Gid = 0, 0
+		header.Uname, header.Gname = "", ""
+		header.ModTime = time.Unix(0, 0)
+		header.AccessTime, header.ChangeTime = time.Time{}, time.Time{}
+		header.PAXRecords = nil
+		header.Format = tar.FormatUnknown
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s: %w", header.Name, err)
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := io.Copy(tarWriter, f); err != nil {
+			return fmt.Errorf("failed to write %s: %w", header.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", srcDir, err)
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := xzWriter.Close(); err != nil {
+		return fmt.Errorf("failed to flush compressed data: %w", err)
+	}
+	return dstFile.Close()
+}
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCompressDeterministic(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{name: "empty", content: nil},
+		{name: "small", content: []byte("anvil kernel\n")},
+		{name: "repetitive", content: bytes.Repeat([]byte("vmlinux build "), 64*1024)},
+	}
+
+	for _, format := range CompressionFormats {
+		for _, tt := range tests {
+			t.Run(format+"/"+tt.name, func(t *testing.T) {
+				dir := t.TempDir()
+
+				// Same bytes under different names and modification times
+				var hashes []string
+				for i, name := range []string{"vmlinux-a", "vmlinux-b"} {
+					src := filepath.Join(dir, name)
+					if err := os.WriteFile(src, tt.content, 0644); err != nil {
+						t.Fatal(err)
+					}
+					mtime := time.Date(2026, 1, 1+i, 0, 0, 0, 0, time.UTC)
+					if err := os.Chtimes(src, mtime, mtime); err != nil {
+						t.Fatal(err)
+					}
+
+					dst := src + CompressionExt(format)
+					if err := CompressWithProgress(format, src, dst, nil); err != nil {
+						t.Fatalf("compress: %v", err)
+					}
+					hash, err := CalculateSHA256(dst)
+					if err != nil {
+						t.Fatal(err)
+					}
+					hashes = append(hashes, hash)
+
+					out := filepath.Join(dir, name+".out")
+					if err := DecompressWithProgress(dst, out, nil); err != nil {
+						t.Fatalf("decompress: %v", err)
+					}
+					got, err := os.ReadFile(out)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !bytes.Equal(got, tt.content) {
+						t.Fatalf("round trip mismatch: got %d bytes, want %d", len(got), len(tt.content))
+					}
+				}
+
+				if hashes[0] != hashes[1] {
+					t.Errorf("compressed hashes differ for identical input: %s != %s", hashes[0], hashes[1])
+				}
+			})
+		}
+	}
+}
+
+func TestCompressionFromPath(t *testing.T) {
+	tests := []struct {
+		path       string
+		wantFormat string
+		wantTrim   string
+	}{
+		{path: "vmlinux-6.18.9-x86_64.xz", wantFormat: CompressionXZ, wantTrim: "vmlinux-6.18.9-x86_64"},
+		{path: "Image-6.18.9-aarch64.zst", wantFormat: CompressionZstd, wantTrim: "Image-6.18.9-aarch64"},
+		{path: "vmlinux-6.18.9-x86_64", wantFormat: "", wantTrim: "vmlinux-6.18.9-x86_64"},
+		{path: "vmlinux-6.18.9-x86_64.xz.sha256", wantFormat: "", wantTrim: "vmlinux-6.18.9-x86_64.xz.sha256"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := CompressionFromPath(tt.path); got != tt.wantFormat {
+				t.Errorf("CompressionFromPath() = %q, want %q", got, tt.wantFormat)
+			}
+			if got := TrimCompressionExt(tt.path); got != tt.wantTrim {
+				t.Errorf("TrimCompressionExt() = %q, want %q", got, tt.wantTrim)
+			}
+		})
+	}
+}
+
+func TestCreateTarXz(t *testing.T) {
+	src := t.TempDir()
+	files := map[string]string{
+		"modules.dep":                  "kernel/fs/ext4.ko:\n",
+		"kernel/fs/ext4.ko":            "ext4",
+		"kernel/drivers/net/virtio.ko": "virtio",
+	}
+	for name, content := range files {
+		path := filepath.Join(src, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("/usr/src/linux", filepath.Join(src, "build")); err != nil {
+		t.Fatal(err)
+	}
+	skip := func(rel string) bool { return rel == "build" }
+
+	dir := t.TempDir()
+	var hashes []string
+	for i := range 2 {
+		// Different modification times must not change the archive
+		mtime := time.Date(2026, 1, 1+i, 0, 0, 0, 0, time.UTC)
+		if err := os.Chtimes(filepath.Join(src, "modules.dep"), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		dst := filepath.Join(dir, fmt.Sprintf("modules-%d.tar.xz", i))
+		if err := CreateTarXz(src, "lib/modules/6.18.9", dst, skip); err != nil {
+			t.Fatalf("CreateTarXz: %v", err)
+		}
+		hash, err := CalculateSHA256(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, hash)
+	}
+	if hashes[0] != hashes[1] {
+		t.Errorf("archive hashes differ for identical trees: %s != %s", hashes[0], hashes[1])
+	}
+
+	out := t.TempDir()
+	if err := ExtractTarXz(filepath.Join(dir, "modules-0.tar.xz"), out); err != nil {
+		t.Fatalf("ExtractTarXz: %v", err)
+	}
+	for name, content := range files {
+		got, err := os.ReadFile(filepath.Join(out, "lib/modules/6.18.9", name))
+		if err != nil {
+			t.Fatalf("missing %s: %v", name, err)
+		}
+		if string(got) != content {
+			t.Errorf("%s = %q, want %q", name, got, content)
+		}
+	}
+	if _, err := os.Lstat(filepath.Join(out, "lib/modules/6.18.9/build")); !os.IsNotExist(err) {
+		t.Errorf("skipped entry was archived (err = %v)", err)
+	}
+}
@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: Apache-2.0
+package util
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFreeSpace(t *testing.T) {
+	free, err := FreeSpace(t.TempDir())
+	if err != nil {
+		t.Fatalf("FreeSpace: %v", err)
+	}
+	if free <= 0 {
+		t.Errorf("FreeSpace = %d, want > 0", free)
+	}
+
+	if _, err := FreeSpace(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("FreeSpace on a missing path should fail")
+	}
+}
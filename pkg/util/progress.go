@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+package util
+
+// Log levels passed to ProgressReporter.Log
+const (
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+// ProgressReporter receives progress from long-running operations (kernel
+// builds, kernel downloads, rootfs creation) independently of how it is
+// displayed. Implementations must be safe to call from the goroutine
+// running the operation.
+type ProgressReporter interface {
+	// SetPhase marks the start of a named phase, e.g. "download" or "compile"
+	SetPhase(name string)
+	// SetProgress reports progress within the current phase. total is 0
+	// when unknown.
+	SetProgress(done, total int64)
+	// Log emits a message at one of the Level* levels
+	Log(level, msg string)
+}
+
+// NopProgressReporter discards all progress
+type NopProgressReporter struct{}
+
+func (NopProgressReporter) SetPhase(string)          {}
+func (NopProgressReporter) SetProgress(int64, int64) {}
+func (NopProgressReporter) Log(string, string)       {}
+
+// ProgressEventKind identifies which ProgressReporter method produced an event
+type ProgressEventKind int
+
+const (
+	ProgressEventPhase ProgressEventKind = iota
+	ProgressEventProgress
+	ProgressEventLog
+)
+
+// ProgressEvent is one call on a ChanProgressReporter
+type ProgressEvent struct {
+	Kind    ProgressEventKind
+	Phase   string // ProgressEventPhase
+	Done    int64  // ProgressEventProgress
+	Total   int64  // ProgressEventProgress
+	Level   string // ProgressEventLog
+	Message string // ProgressEventLog
+}
+
+// ChanProgressReporter sends every call as a ProgressEvent on a channel,
+// for UIs that consume progress on their own goroutine (e.g. a bubbletea
+// program). Phase and log events block until received; progress events are
+// dropped when the channel is full so a slow UI never stalls the operation.
+type ChanProgressReporter struct {
+	events chan<- ProgressEvent
+}
+
+// NewChanProgressReporter creates a reporter that sends on events. The
+// caller owns the channel and closes it once the operation has returned.
+func NewChanProgressReporter(events chan<- ProgressEvent) *ChanProgressReporter {
+	return &ChanProgressReporter{events: events}
+}
+
+func (r *ChanProgressReporter) SetPhase(name string) {
+	r.events <- ProgressEvent{Kind: ProgressEventPhase, Phase: name}
+}
+
+func (r *ChanProgressReporter) SetProgress(done, total int64) {
+	select {
+	case r.events <- ProgressEvent{Kind: ProgressEventProgress, Done: done, Total: total}:
+	default:
+	}
+}
+
+func (r *ChanProgressReporter) Log(level, msg string) {
+	r.events <- ProgressEvent{Kind: ProgressEventLog, Level: level, Message: msg}
+}
+
+// CallbackProgressReporter adapts the older progress and status callbacks
+// to a ProgressReporter: phases go to Status, progress to Progress as a
+// fraction (0.0 to 1.0), and log messages are dropped. Either callback may
+// be nil.
+type CallbackProgressReporter struct {
+	Progress func(float64)
+	Status   func(string)
+}
+
+func (r CallbackProgressReporter) SetPhase(name string) {
+	if r.Status != nil {
+		r.Status(name)
+	}
+}
+
+func (r CallbackProgressReporter) SetProgress(done, total int64) {
+	if r.Progress != nil && total > 0 {
+		r.Progress(float64(done) / float64(total))
+	}
+}
+
+func (CallbackProgressReporter) Log(string, string) {}
+
+// fractionScale is the resolution ReportFraction reports fractions at
+const fractionScale = 1000
+
+// ReportFraction returns a fractional progress callback (0.0 to 1.0) that
+// forwards to r, for code that reports progress as a fraction. A nil r
+// returns nil.
+func ReportFraction(r ProgressReporter) func(float64) {
+	if r == nil {
+		return nil
+	}
+	return func(fraction float64) {
+		r.SetProgress(int64(fraction*fractionScale), fractionScale)
+	}
+}
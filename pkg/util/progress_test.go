@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChanProgressReporter(t *testing.T) {
+	events := make(chan ProgressEvent, 3)
+	r := NewChanProgressReporter(events)
+
+	r.SetPhase("download")
+	r.SetProgress(5, 10)
+	r.Log(LevelInfo, "downloading")
+	// The channel is full: progress is dropped instead of blocking
+	r.SetProgress(10, 10)
+	close(events)
+
+	var got []ProgressEvent
+	for e := range events {
+		got = append(got, e)
+	}
+	want := []ProgressEvent{
+		{Kind: ProgressEventPhase, Phase: "download"},
+		{Kind: ProgressEventProgress, Done: 5, Total: 10},
+		{Kind: ProgressEventLog, Level: LevelInfo, Message: "downloading"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %+v, want %+v", got, want)
+	}
+}
+
+func TestCallbackProgressReporter(t *testing.T) {
+	tests := []struct {
+		name        string
+		done, total int64
+		want        []float64
+	}{
+		{name: "half", done: 5, total: 10, want: []float64{0.5}},
+		{name: "complete", done: 10, total: 10, want: []float64{1}},
+		{name: "unknown total", done: 5, total: 0, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []float64
+			var status []string
+			r := CallbackProgressReporter{
+				Progress: func(f float64) { got = append(got, f) },
+				Status:   func(s string) { status = append(status, s) },
+			}
+			r.SetPhase("verify")
+			r.SetProgress(tt.done, tt.total)
+			r.Log(LevelWarn, "ignored")
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("progress = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(status, []string{"verify"}) {
+				t.Errorf("status = %v, want [verify]", status)
+			}
+		})
+	}
+
+	// Nil callbacks are allowed
+	CallbackProgressReporter{}.SetPhase("verify")
+	CallbackProgressReporter{}.SetProgress(1, 2)
+}
+
+func TestReportFraction(t *testing.T) {
+	if ReportFraction(nil) != nil {
+		t.Error("ReportFraction(nil) should return nil")
+	}
+
+	var got float64
+	report := ReportFraction(CallbackProgressReporter{Progress: func(f float64) { got = f }})
+	report(0.25)
+	if got != 0.25 {
+		t.Errorf("fraction = %v, want 0.25", got)
+	}
+}
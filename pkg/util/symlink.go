@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ReplaceSymlink points the symlink at path to target. The new link is
+// created under a temporary name next to path and renamed over it, so path
+// always resolves to either the old target or the new one, even if the
+// process dies halfway.
+func ReplaceSymlink(target, path string) error {
+	tmp, err := tempSymlink(target, path)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// tempSymlink creates a symlink to target in path's directory, under a
+// name that won't collide with another process doing the same
+func tempSymlink(target, path string) (string, error) {
+	tmp := filepath.Join(filepath.Dir(path), fmt.Sprintf(".%s.%d.tmp", filepath.Base(path), os.Getpid()))
+	// A link left by a crashed run of this pid would make Symlink fail
+	os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return "", err
+	}
+	return tmp, nil
+}
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReplaceSymlink(t *testing.T) {
+	dir := t.TempDir()
+	oldTarget := filepath.Join(dir, "vmlinux-6.1.0")
+	newTarget := filepath.Join(dir, "vmlinux-6.12.9")
+	for _, target := range []string{oldTarget, newTarget} {
+		if err := os.WriteFile(target, []byte(filepath.Base(target)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link := filepath.Join(dir, "vmlinux")
+
+	// No link yet
+	if err := ReplaceSymlink(oldTarget, link); err != nil {
+		t.Fatalf("ReplaceSymlink() error = %v", err)
+	}
+	assertReadsAs(t, link, "vmlinux-6.1.0")
+
+	// Interrupted after creating the new link but before the rename: the
+	// old default must still be in place
+	tmp, err := tempSymlink(newTarget, link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertReadsAs(t, link, "vmlinux-6.1.0")
+
+	// The next run replaces the leftover temporary link and swaps
+	if err := ReplaceSymlink(newTarget, link); err != nil {
+		t.Fatalf("ReplaceSymlink() error = %v", err)
+	}
+	assertReadsAs(t, link, "vmlinux-6.12.9")
+	if _, err := os.Lstat(tmp); !os.IsNotExist(err) {
+		t.Errorf("temporary link %s left behind", tmp)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("directory has %d entries, want the two kernels and the link", len(entries))
+	}
+}
+
+func assertReadsAs(t *testing.T, path, want string) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %v", path, err)
+	}
+	if string(got) != want {
+		t.Errorf("%s resolves to %q, want %q", path, got, want)
+	}
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+package util
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// kernelVersion is a parsed "6.12.9" or "6.19-rc3" style version
+type kernelVersion struct {
+	segments []int
+	rc       int // Release candidate number, 0 for a release
+}
+
+// parseKernelVersion parses dotted numeric versions with an optional
+// "-rcN" suffix and "v" prefix
+func parseKernelVersion(v string) (kernelVersion, bool) {
+	base, pre, hasPre := strings.Cut(strings.TrimPrefix(v, "v"), "-")
+	var parsed kernelVersion
+	for _, part := range strings.Split(base, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return kernelVersion{}, false
+		}
+		parsed.segments = append(parsed.segments, n)
+	}
+	if len(parsed.segments) < 2 {
+		return kernelVersion{}, false
+	}
+	if hasPre {
+		n, err := strconv.Atoi(strings.TrimPrefix(pre, "rc"))
+		if !strings.HasPrefix(pre, "rc") || err != nil || n < 1 {
+			return kernelVersion{}, false
+		}
+		parsed.rc = n
+	}
+	return parsed, true
+}
+
+func (v kernelVersion) compare(o kernelVersion) int {
+	for i := 0; i < max(len(v.segments), len(o.segments)); i++ {
+		var a, b int
+		if i < len(v.segments) {
+			a = v.segments[i]
+		}
+		if i < len(o.segments) {
+			b = o.segments[i]
+		}
+		if c := cmp.Compare(a, b); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case v.rc == o.rc:
+		return 0
+	case v.rc == 0:
+		return 1
+	case o.rc == 0:
+		return -1
+	}
+	return cmp.Compare(v.rc, o.rc)
+}
+
+// CompareKernelVersions compares two version strings such as "6.12.9",
+// "v1.14.1" or "6.19-rc3" and returns -1, 0 or 1. Release candidates sort
+// before their release and by number (rc2 before rc10). Other semver
+// pre-releases are compared as semver; strings that don't parse as
+// versions fall back to a plain string comparison.
+func CompareKernelVersions(a, b string) int {
+	ka, okA := parseKernelVersion(a)
+	kb, okB := parseKernelVersion(b)
+	if okA && okB {
+		return ka.compare(kb)
+	}
+
+	va, errA := version.NewVersion(strings.TrimPrefix(a, "v"))
+	vb, errB := version.NewVersion(strings.TrimPrefix(b, "v"))
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+	return va.Compare(vb)
+}
+
+// SortKernelVersions sorts versions newest first
+func SortKernelVersions(versions []string) {
+	slices.SortStableFunc(versions, func(a, b string) int {
+		return CompareKernelVersions(b, a)
+	})
+}
+
+// IsPrereleaseVersion reports whether v is a pre-release such as "6.19-rc3"
+func IsPrereleaseVersion(v string) bool {
+	parsed, err := version.NewVersion(strings.TrimPrefix(v, "v"))
+	return err == nil && parsed.Prerelease() != ""
+}
+
+// KernelSeries returns the major.minor series of a version ("6.12.9" ->
+// "6.12"), or v itself if it doesn't parse
+func KernelSeries(v string) string {
+	parsed, err := version.NewVersion(strings.TrimPrefix(v, "v"))
+	if err != nil {
+		return v
+	}
+	segments := parsed.Segments()
+	return fmt.Sprintf("%d.%d", segments[0], segments[1])
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+package util
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompareKernelVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"6.12.9", "6.12.10", -1},
+		{"6.12.10", "6.12.9", 1},
+		{"6.9", "6.12", -1},
+		{"6.12", "6.12.0", 0},
+		{"v1.14.1", "1.14.1", 0},
+		{"6.19-rc3", "6.19", -1},
+		{"6.19-rc3", "6.18.9", 1},
+		{"6.19-rc2", "6.19-rc10", -1},
+		{"next-20260105", "next-20260106", -1},
+		{"1.0.0-beta", "1.0.0", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareKernelVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareKernelVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSortKernelVersions(t *testing.T) {
+	versions := []string{"6.6.70", "6.19-rc3", "6.12.9", "6.18.9", "6.12.10", "5.15.180"}
+	SortKernelVersions(versions)
+	want := []string{"6.19-rc3", "6.18.9", "6.12.10", "6.12.9", "6.6.70", "5.15.180"}
+	if !slices.Equal(versions, want) {
+		t.Errorf("SortKernelVersions() = %v, want %v", versions, want)
+	}
+}
+
+func TestKernelSeries(t *testing.T) {
+	tests := map[string]string{
+		"6.12.9":   "6.12",
+		"6.12":     "6.12",
+		"6.19-rc3": "6.19",
+		"v1.14.1":  "1.14",
+		"garbage":  "garbage",
+	}
+	for in, want := range tests {
+		if got := KernelSeries(in); got != want {
+			t.Errorf("KernelSeries(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestIsPrereleaseVersion(t *testing.T) {
+	if !IsPrereleaseVersion("6.19-rc3") {
+		t.Error("6.19-rc3 should be a pre-release")
+	}
+	if IsPrereleaseVersion("6.18.9") || IsPrereleaseVersion("garbage") {
+		t.Error("6.18.9 and garbage should not be pre-releases")
+	}
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+package util
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/charmbracelet/log"
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdEncoderOptions pins the zstd encoder settings. A single encoder
+// goroutine and a fixed level make the output, and so the compressed hash
+// in build stats, identical for identical input.
+var zstdEncoderOptions = []zstd.EOption{
+	zstd.WithEncoderLevel(zstd.SpeedBetterCompression),
+	zstd.WithEncoderConcurrency(1),
+	zstd.WithEncoderCRC(true),
+}
+
+// CompressZstd compresses a file using zstd compression
+func CompressZstd(src, dst string) error {
+	return CompressZstdWithProgress(src, dst, nil)
+}
+
+// CompressZstdWithProgress compresses a file with progress tracking
+func CompressZstdWithProgress(src, dst string, progressCallback func(float64)) error {
+	log.Debugf("Compressing %s to %s", src, dst)
+
+	// Open source file
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open source file: %w", err)
+	}
+	defer srcFile.Close()
+
+	// Get source file size for progress tracking
+	srcInfo, err := srcFile.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to get source file info: %w", err)
+	}
+	uncompressedSize := srcInfo.Size()
+
+	// Create destination file
+	dstFile, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create destination file: %w", err)
+	}
+	defer dstFile.Close()
+
+	// Create zstd writer with deterministic settings
+	zstdWriter, err := zstd.NewWriter(dstFile, zstdEncoderOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to create zstd writer: %w", err)
+	}
+	defer zstdWriter.Close()
+
+	// Wrap source file with progress reader if callback provided
+	var reader io.Reader = srcFile
+	if progressCallback != nil {
+		reader = &progressReader{
+			reader:   srcFile,
+			total:    uncompressedSize,
+			read:     0,
+			callback: progressCallback,
+			lastPct:  -1.0,
+		}
+	}
+
+	// Copy data through zstd compressor
+	if _, err := io.Copy(zstdWriter, reader); err != nil {
+		return fmt.Errorf("failed to compress file: %w", err)
+	}
+
+	// Ensure all data is flushed
+	if err := zstdWriter.Close(); err != nil {
+		return fmt.Errorf("failed to flush compressed data: %w", err)
+	}
+
+	log.Debugf("Successfully compressed %s to %s", src, dst)
+	return nil
+}
+
+// DecompressZstd decompresses a zstd file to a destination path
+func DecompressZstd(src, dst string) error {
+	return DecompressZstdWithProgress(src, dst, nil)
+}
+
+// DecompressZstdWithProgress decompresses a zstd file with progress tracking
+func DecompressZstdWithProgress(src, dst string, progressCallback func(float64)) error {
+	log.Debugf("Decompressing %s to %s", src, dst)
+
+	// Open source file
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open source file: %w", err)
+	}
+	defer srcFile.Close()
+
+	// Get source file size for progress tracking
+	srcInfo, err := srcFile.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to get source file info: %w", err)
+	}
+	compressedSize := srcInfo.Size()
+
+	// Wrap source file with progress reader to track compressed bytes read
+	var reader io.Reader = srcFile
+	if progressCallback != nil {
+		reader = &progressReader{
+			reader:   srcFile,
+			total:    compressedSize,
+			read:     0,
+			callback: progressCallback,
+			lastPct:  -1.0,
+		}
+	}
+
+	// Create zstd reader
+	zstdReader, err := zstd.NewReader(reader)
+	if err != nil {
+		return fmt.Errorf("failed to create zstd reader: %w", err)
+	}
+	defer zstdReader.Close()
+
+	// Create destination file
+	dstFile, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create destination file: %w", err)
+	}
+	defer dstFile.Close()
+
+	// Decompress
+	if _, err := io.Copy(dstFile, zstdReader); err != nil {
+		return fmt.Errorf("failed to decompress: %w", err)
+	}
+
+	// Ensure 100% is reported
+	if progressCallback != nil {
+		progressCallback(1.0)
+	}
+
+	log.Debugf("Successfully decompressed to %s", dst)
+	return nil
+}
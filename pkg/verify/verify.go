@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+package verify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ProtonMail/gopenpgp/v3/crypto"
+	"github.com/ProtonMail/gopenpgp/v3/profile"
+	"github.com/Work-Fort/Anvil/pkg/util"
+)
+
+// SignedArtifact verifies a downloaded file: its SHA256 must match its line
+// in sumsFile (a SHA256SUMS file), and sigFile must be a valid detached
+// signature of sumsFile made by the public key at pubKeyPath. The signature
+// and key may each be armored or binary.
+func SignedArtifact(file, sumsFile, sigFile, pubKeyPath string) error {
+	if err := util.VerifySHA256File(file, sumsFile); err != nil {
+		return fmt.Errorf("checksum verification failed: %w", err)
+	}
+	if err := Signature(sumsFile, sigFile, pubKeyPath); err != nil {
+		return fmt.Errorf("PGP signature verification failed: %w", err)
+	}
+	return nil
+}
+
+// Signature verifies that sigFile is a valid detached signature of file made
+// by the public key at pubKeyPath
+func Signature(file, sigFile, pubKeyPath string) error {
+	if pubKeyPath == "" {
+		return fmt.Errorf("no public key to verify %s against", filepath.Base(file))
+	}
+	key, err := loadPublicKey(pubKeyPath)
+	if err != nil {
+		return err
+	}
+
+	verifier, err := crypto.PGPWithProfile(profile.RFC4880()).Verify().
+		VerificationKey(key).
+		New()
+	if err != nil {
+		return fmt.Errorf("failed to create verifier: %w", err)
+	}
+
+	name := filepath.Base(file)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	signature, err := os.ReadFile(sigFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(sigFile), err)
+	}
+
+	// Try armored format first
+	result, err := verifier.VerifyDetached(data, signature, crypto.Armor)
+	if err != nil {
+		// Try binary format
+		result, err = verifier.VerifyDetached(data, signature, crypto.Bytes)
+		if err != nil {
+			return fmt.Errorf("%s signature could not be read (tried both armored and binary formats): %w", name, err)
+		}
+	}
+	if sigErr := result.SignatureError(); sigErr != nil {
+		return fmt.Errorf("%s is not signed by %s: %w", name, filepath.Base(pubKeyPath), sigErr)
+	}
+	return nil
+}
+
+// loadPublicKey reads an armored or binary public key
+func loadPublicKey(path string) (*crypto.Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+	if key, err := crypto.NewKeyFromArmored(string(data)); err == nil {
+		return key, nil
+	}
+	key, err := crypto.NewKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a readable PGP public key: %w", filepath.Base(path), err)
+	}
+	return key, nil
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+package verify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/gopenpgp/v3/crypto"
+	"github.com/ProtonMail/gopenpgp/v3/profile"
+)
+
+// writeTestKey generates a signing key and writes its public half to
+// dir/name, returning the private key
+func writeTestKey(t *testing.T, dir, name string) *crypto.Key {
+	t.Helper()
+	key, err := crypto.PGPWithProfile(profile.RFC4880()).KeyGeneration().
+		AddUserId("Test Kernels", "test@example.com").
+		New().GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := key.ToPublic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	armored, err := public.Armor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(armored), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// signDetached writes an armored detached signature of path to path.asc
+func signDetached(t *testing.T, key *crypto.Key, path string) {
+	t.Helper()
+	signer, err := crypto.PGPWithProfile(profile.RFC4880()).Sign().SigningKey(key).Detached().New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := signer.Sign(data, crypto.Armor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+".asc", signature, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSignedArtifact(t *testing.T) {
+	dir := t.TempDir()
+	key := writeTestKey(t, dir, "signing-key.asc")
+	writeTestKey(t, dir, "other-key.asc")
+
+	artifact := filepath.Join(dir, "vmlinux-6.12.9-x86_64.xz")
+	content := []byte("kernel image")
+	if err := os.WriteFile(artifact, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(content)
+	sums := filepath.Join(dir, "SHA256SUMS")
+	if err := os.WriteFile(sums, []byte(fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), filepath.Base(artifact))), 0644); err != nil {
+		t.Fatal(err)
+	}
+	signDetached(t, key, sums)
+
+	if err := SignedArtifact(artifact, sums, sums+".asc", filepath.Join(dir, "signing-key.asc")); err != nil {
+		t.Errorf("SignedArtifact() error = %v", err)
+	}
+
+	// A valid signature by a key other than the expected one is rejected
+	err := SignedArtifact(artifact, sums, sums+".asc", filepath.Join(dir, "other-key.asc"))
+	if err == nil || !strings.Contains(err.Error(), "signature verification failed") {
+		t.Errorf("SignedArtifact(other key) error = %v, want a signature failure", err)
+	}
+
+	// So is a file that doesn't match its checksum
+	if err := os.WriteFile(artifact, []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = SignedArtifact(artifact, sums, sums+".asc", filepath.Join(dir, "signing-key.asc"))
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("SignedArtifact(tampered) error = %v, want a checksum mismatch", err)
+	}
+}
+
+func TestSignatureTamperedSums(t *testing.T) {
+	dir := t.TempDir()
+	key := writeTestKey(t, dir, "signing-key.asc")
+
+	sums := filepath.Join(dir, "SHA256SUMS")
+	if err := os.WriteFile(sums, []byte("abc  vmlinux\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	signDetached(t, key, sums)
+	if err := os.WriteFile(sums, []byte("def  vmlinux\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Signature(sums, sums+".asc", filepath.Join(dir, "signing-key.asc")); err == nil {
+		t.Error("Signature() accepted a checksums file changed after signing")
+	}
+	if err := Signature(sums, sums+".asc", ""); err == nil {
+		t.Error("Signature() succeeded without a public key")
+	}
+}
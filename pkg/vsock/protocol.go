@@ -33,8 +33,32 @@ type JSONRPCError struct {
 const (
 	MethodPing = "ping"
 	MethodPong = "pong"
+	MethodExec = "exec" // Supported by the embedded standalone server
+	MethodInfo = "info" // Supported by the embedded standalone server
 )
 
+// ExecParams represents parameters for an exec request
+type ExecParams struct {
+	Cmd     string   `json:"cmd"`
+	Args    []string `json:"args,omitempty"`
+	Timeout int      `json:"timeout,omitempty"` // Seconds (default: server exec timeout)
+}
+
+// ExecResult represents the result of an exec request. A command killed on
+// timeout has ExitCode -1 and TimedOut set.
+type ExecResult struct {
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exit_code"`
+	TimedOut bool   `json:"timed_out,omitempty"`
+}
+
+// InfoResult represents the result of an info request
+type InfoResult struct {
+	KernelVersion string  `json:"kernel_version"`
+	UptimeSeconds float64 `json:"uptime_seconds"`
+}
+
 // PingParams represents parameters for a ping request
 type PingParams struct {
 	Message string `json:"message"`